@@ -112,9 +112,9 @@ build_server() {
     
     # Build the MCP server binary with version information
     print_status "Building MCP server binary..."
-    LDFLAGS="-X github.com/maximilien/maestro-mcp/src/pkg/config.Version=$VERSION"
-    LDFLAGS="$LDFLAGS -X github.com/maximilien/maestro-mcp/src/pkg/config.GitCommit=$GIT_COMMIT"
-    LDFLAGS="$LDFLAGS -X github.com/maximilien/maestro-mcp/src/pkg/config.BuildTime=$BUILD_TIME"
+    LDFLAGS="-X github.com/AI4quantum/maestro-mcp/src/pkg/config.Version=$VERSION"
+    LDFLAGS="$LDFLAGS -X github.com/AI4quantum/maestro-mcp/src/pkg/config.GitCommit=$GIT_COMMIT"
+    LDFLAGS="$LDFLAGS -X github.com/AI4quantum/maestro-mcp/src/pkg/config.BuildTime=$BUILD_TIME"
     
     if ! go build -ldflags "$LDFLAGS" -o bin/maestro-mcp ./src/main.go; then
         print_error "MCP server build failed"
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -13,8 +14,12 @@ import (
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("MAESTRO_MCP_CONFIG"),
+		"path to the config file (overrides the default search path; env: MAESTRO_MCP_CONFIG)")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -30,8 +35,11 @@ func main() {
 		}
 	}()
 
+	buildInfo := cfg.BuildInfo()
 	logger.Info("Starting Maestro MCP Server",
-		zap.String("version", cfg.Version),
+		zap.String("version", buildInfo.Version),
+		zap.String("git_commit", buildInfo.GitCommit),
+		zap.String("build_time", buildInfo.BuildTime),
 		zap.String("host", cfg.Server.Host),
 		zap.Int("port", cfg.Server.Port))
 
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/AI4quantum/maestro-mcp/src/pkg/config.GitCommit=$(git rev-parse HEAD)"
+var (
+	Version   string
+	GitCommit string
+	BuildTime string
+)
+
+// BuildInfo describes the running build
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// BuildInfo returns the running build's metadata. Values injected with ldflags
+// take precedence; otherwise the commit and build time fall back to the VCS
+// stamp embedded by the Go toolchain and the version to the configured one.
+func (c *Config) BuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = embedded.GoVersion
+		for _, setting := range embedded.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitCommit == "" {
+					info.GitCommit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+		if info.Version == "" && embedded.Main.Version != "" && embedded.Main.Version != "(devel)" {
+			info.Version = embedded.Main.Version
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = c.Version
+	}
+	if info.GitCommit == "" {
+		info.GitCommit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
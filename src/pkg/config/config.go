@@ -3,13 +3,22 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
+// redactedValue replaces secrets in redacted configuration copies
+const redactedValue = "[REDACTED]"
+
+// envRefPattern matches ${ENV_VAR} references in secret config values
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
 // Config represents the application configuration
 type Config struct {
 	Version  string         `mapstructure:"version"`
@@ -21,11 +30,39 @@ type Config struct {
 
 // ServerConfig contains server-related configuration
 type ServerConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	Host              string        `mapstructure:"host"`
+	Port              int           `mapstructure:"port"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	AccessLog         bool          `mapstructure:"access_log"`
+	MaxConnections    int           `mapstructure:"max_connections"`
+	DisableKeepAlives bool          `mapstructure:"disable_keep_alives"`
+
+	// Compression gzips responses for clients that accept it
+	Compression CompressionConfig `mapstructure:"compression"`
+
+	// WaitForBackends holds startup until the databases declared under
+	// mcp.databases answer a probe
+	WaitForBackends WaitForBackendsConfig `mapstructure:"wait_for_backends"`
+}
+
+// CompressionConfig configures gzip response compression. Responses shorter
+// than min_size bytes are sent uncompressed, since gzip gains little on them.
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	MinSize int  `mapstructure:"min_size"`
+}
+
+// WaitForBackendsConfig configures the startup gate that probes the
+// config-declared databases before the server starts listening
+type WaitForBackendsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Timeout bounds the whole wait; startup fails if a backend is still
+	// unreachable when it passes
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Interval is the pause between probe rounds
+	Interval time.Duration `mapstructure:"interval"`
 }
 
 // DatabaseConfig contains database-related configuration
@@ -46,14 +83,331 @@ type LoggingConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
 	Output string `mapstructure:"output"`
+
+	// ToolLevels sets, by tool name, the level a tool's handler logs its
+	// routine messages at instead of info, e.g. debug for count_documents
+	ToolLevels map[string]string `mapstructure:"tool_levels"`
+}
+
+// ValidateLogLevel reports whether level names a log level
+func ValidateLogLevel(level string) error {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "error":
+		return nil
+	default:
+		return fmt.Errorf("unsupported log level '%s'; use debug, info, warn, or error", level)
+	}
 }
 
 // MCPConfig contains MCP-specific configuration
 type MCPConfig struct {
-	ToolTimeout time.Duration            `mapstructure:"tool_timeout"`
-	Timeouts    map[string]time.Duration `mapstructure:"timeouts"`
-	Embedding   EmbeddingConfig          `mapstructure:"embedding"`
-	VectorDB    VectorDBConfig           `mapstructure:"vector_db"`
+	ToolTimeout     time.Duration            `mapstructure:"tool_timeout"`
+	Timeouts        map[string]time.Duration `mapstructure:"timeouts"`
+	Embedding       EmbeddingConfig          `mapstructure:"embedding"`
+	VectorDB        VectorDBConfig           `mapstructure:"vector_db"`
+	Databases       []DatabaseInstanceConfig `mapstructure:"databases"`
+	QueryCache      QueryCacheConfig         `mapstructure:"query_cache"`
+	Idempotency     IdempotencyConfig        `mapstructure:"idempotency"`
+	Snapshot        SnapshotConfig           `mapstructure:"snapshot"`
+	Reingest        ReingestConfig           `mapstructure:"reingest"`
+	Resources       ResourcesConfig          `mapstructure:"resources"`
+	Prompts         []PromptConfig           `mapstructure:"prompts"`
+	CircuitBreaker  CircuitBreakerConfig     `mapstructure:"circuit_breaker"`
+	Audit           AuditConfig              `mapstructure:"audit"`
+	SoftDelete      SoftDeleteConfig         `mapstructure:"soft_delete"`
+	TTLSweep        TTLSweepConfig           `mapstructure:"ttl_sweep"`
+	Rerank          RerankConfig             `mapstructure:"rerank"`
+	Recency         RecencyConfig            `mapstructure:"recency"`
+	Preprocess      PreprocessConfig         `mapstructure:"preprocess"`
+	Expansion       ExpansionConfig          `mapstructure:"expansion"`
+	WriteValidation WriteValidationConfig    `mapstructure:"write_validation"`
+	Concurrency     ConcurrencyConfig        `mapstructure:"concurrency"`
+	Search          LimitConfig              `mapstructure:"search"`
+	List            LimitConfig              `mapstructure:"list"`
+	// PerClientConcurrency caps the tool calls each client has in flight
+	PerClientConcurrency PerClientConcurrencyConfig `mapstructure:"per_client_concurrency"`
+	// ContentDedup hashes document text on write and drops duplicates
+	ContentDedup ContentDedupConfig `mapstructure:"content_dedup"`
+	// EnabledTools, when set, exposes only the named tools; DisabledTools
+	// hides tools and takes precedence
+	EnabledTools  []string `mapstructure:"enabled_tools"`
+	DisabledTools []string `mapstructure:"disabled_tools"`
+}
+
+// LimitConfig bounds how many results a tool returns. default_limit applies
+// when a request sets no limit, and larger requests are clamped to max_limit.
+// Zero leaves the tool's built-in default or no maximum.
+type LimitConfig struct {
+	DefaultLimit int `mapstructure:"default_limit"`
+	MaxLimit     int `mapstructure:"max_limit"`
+}
+
+// ConcurrencyConfig caps the operations in flight against each vector database.
+// A max_concurrent_operations of 0 disables the cap; calls beyond it wait up to
+// wait_timeout for a slot before failing, or fail at once when it is 0.
+type ConcurrencyConfig struct {
+	MaxConcurrentOperations int           `mapstructure:"max_concurrent_operations"`
+	WaitTimeout             time.Duration `mapstructure:"wait_timeout"`
+}
+
+// PerClientConcurrencyConfig caps the tool calls each client has in flight.
+// Clients are told apart by client_header when the request carries it and by
+// remote IP address otherwise. A max_in_flight of 0 disables the cap; calls
+// beyond it wait up to wait_timeout for one of the client's calls to finish
+// before failing with 429, or fail at once when it is 0.
+type PerClientConcurrencyConfig struct {
+	MaxInFlight  int           `mapstructure:"max_in_flight"`
+	WaitTimeout  time.Duration `mapstructure:"wait_timeout"`
+	ClientHeader string        `mapstructure:"client_header"`
+}
+
+// ContentDedupConfig controls content hashing on write. With store_hash each
+// written document records the SHA-256 of its text under
+// metadata.content_hash. Mode decides what happens to a document whose text
+// hash is already stored: "off" writes it anyway, "skip" drops it, and
+// "merge_urls" drops it and adds its URL to the stored document's
+// metadata.duplicate_urls. Any mode but off stores the hash.
+type ContentDedupConfig struct {
+	StoreHash bool   `mapstructure:"store_hash"`
+	Mode      string `mapstructure:"mode"`
+}
+
+// Content deduplication modes
+const (
+	ContentDedupOff       = "off"
+	ContentDedupSkip      = "skip"
+	ContentDedupMergeURLs = "merge_urls"
+)
+
+// Enabled reports whether duplicates are dropped on write
+func (c ContentDedupConfig) Enabled() bool {
+	return c.Mode == ContentDedupSkip || c.Mode == ContentDedupMergeURLs
+}
+
+// WriteValidationConfig relaxes the emptiness checks applied to written documents,
+// for teams that intentionally store metadata-only records
+type WriteValidationConfig struct {
+	AllowEmptyText bool `mapstructure:"allow_empty_text"`
+	AllowEmptyURL  bool `mapstructure:"allow_empty_url"`
+}
+
+// RecencyConfig tunes the recency_weight option of query and search. A
+// document half_life old keeps half of the recency part of its score.
+type RecencyConfig struct {
+	HalfLife time.Duration `mapstructure:"half_life"`
+}
+
+// PreprocessConfig lists the cleaning steps applied, in order, to document and
+// query text before embedding: strip_html, normalize_whitespace, lowercase.
+// No steps leaves text untouched.
+type PreprocessConfig struct {
+	Steps []string `mapstructure:"steps"`
+}
+
+// ExpansionConfig configures the expand option of query. Each synonyms entry
+// maps a query term to the alternatives appended when it appears.
+type ExpansionConfig struct {
+	Synonyms map[string][]string `mapstructure:"synonyms"`
+}
+
+// RerankConfig contains configuration for the re-rank endpoint used by the rerank
+// option of query and search. Re-ranking is available when url is set.
+type RerankConfig struct {
+	URL        string        `mapstructure:"url"`
+	APIKey     string        `mapstructure:"api_key"`
+	Model      string        `mapstructure:"model"`
+	Candidates int           `mapstructure:"candidates"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
+
+// TTLSweepConfig contains configuration for the background sweep of expired documents
+type TTLSweepConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// SoftDeleteConfig contains tombstone-based soft delete configuration
+type SoftDeleteConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	PurgeAfter time.Duration `mapstructure:"purge_after"`
+}
+
+// AuditConfig contains audit logging configuration for mutating tool calls
+type AuditConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Sink       string `mapstructure:"sink"`
+	Path       string `mapstructure:"path"`
+	MaxEntries int    `mapstructure:"max_entries"`
+}
+
+// Supported audit sinks
+const (
+	AuditSinkMemory = "memory"
+	AuditSinkFile   = "file"
+)
+
+// CircuitBreakerConfig contains per-backend circuit breaker configuration
+type CircuitBreakerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	ResetTimeout     time.Duration `mapstructure:"reset_timeout"`
+	MaxResetTimeout  time.Duration `mapstructure:"max_reset_timeout"`
+}
+
+// QueryCacheConfig contains query result cache configuration
+type QueryCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	MaxSize int           `mapstructure:"max_size"`
+}
+
+// IdempotencyConfig controls how long writes made with an idempotency_key are
+// remembered. A window of 0 disables idempotency keys; max_keys caps how many
+// are held, dropping the oldest first, and 0 leaves it unbounded.
+type IdempotencyConfig struct {
+	Window  time.Duration `mapstructure:"window"`
+	MaxKeys int           `mapstructure:"max_keys"`
+}
+
+// SnapshotConfig controls the snapshots list_documents and export take for
+// paginated scans. A snapshot pins the IDs of at most max_documents documents,
+// read at consistency_level, and is kept for ttl after its last page. At most
+// max_snapshots are held, dropping the oldest first. Zero values fall back to
+// the built-in defaults.
+type SnapshotConfig struct {
+	TTL              time.Duration `mapstructure:"ttl"`
+	MaxSnapshots     int           `mapstructure:"max_snapshots"`
+	MaxDocuments     int           `mapstructure:"max_documents"`
+	ConsistencyLevel string        `mapstructure:"consistency_level"`
+}
+
+// ReingestConfig sets how the reingest tool replaces a collection's documents
+// when a call does not say. With shadow, documents are loaded into a new
+// collection that replaces the live one once complete; without, the live
+// collection is emptied and reloaded in place.
+type ReingestConfig struct {
+	Shadow bool `mapstructure:"shadow"`
+}
+
+// ResourcesConfig controls the MCP resources the server exposes. Every
+// collection is listed; with ListDocuments, up to MaxDocuments documents of
+// each collection are listed too. Any document can be read by its URI either
+// way. Without Enabled, no resources are exposed or advertised.
+type ResourcesConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	ListDocuments bool `mapstructure:"list_documents"`
+	MaxDocuments  int  `mapstructure:"max_documents"`
+}
+
+// PromptConfig declares an MCP prompt template. Template names its arguments
+// as {name} placeholders; {documents} is replaced with the results of the
+// query tool run with the db_name and query arguments, so a template using it
+// must declare both. Limit caps the documents retrieved, and a limit argument
+// overrides it.
+type PromptConfig struct {
+	Name        string                 `mapstructure:"name"`
+	Description string                 `mapstructure:"description"`
+	Arguments   []PromptArgumentConfig `mapstructure:"arguments"`
+	Template    string                 `mapstructure:"template"`
+	Limit       int                    `mapstructure:"limit"`
+}
+
+// PromptArgumentConfig declares an argument of a prompt template
+type PromptArgumentConfig struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Required    bool   `mapstructure:"required"`
+}
+
+// PromptDocumentsPlaceholder is the template placeholder filled with retrieved documents
+const PromptDocumentsPlaceholder = "documents"
+
+// promptPlaceholder matches a {name} placeholder in a prompt template
+var promptPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// PromptPlaceholders returns the names of the placeholders in a prompt template
+func PromptPlaceholders(template string) []string {
+	var names []string
+	for _, match := range promptPlaceholder.FindAllStringSubmatch(template, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// RenderPrompt replaces each placeholder in a prompt template with its value.
+// Placeholders without a value are left as they are.
+func RenderPrompt(template string, values map[string]string) string {
+	return promptPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := values[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// validatePrompt checks that a prompt's placeholders are declared arguments
+func validatePrompt(prompt PromptConfig) error {
+	if prompt.Template == "" {
+		return fmt.Errorf("template is required")
+	}
+	if prompt.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+
+	declared := make(map[string]bool, len(prompt.Arguments))
+	for i, argument := range prompt.Arguments {
+		if argument.Name == "" {
+			return fmt.Errorf("arguments[%d]: name is required", i)
+		}
+		if argument.Name == PromptDocumentsPlaceholder {
+			return fmt.Errorf("arguments[%d]: '%s' is filled by the server and cannot be an argument", i, PromptDocumentsPlaceholder)
+		}
+		if declared[argument.Name] {
+			return fmt.Errorf("arguments[%d]: duplicate argument '%s'", i, argument.Name)
+		}
+		declared[argument.Name] = true
+	}
+
+	for _, name := range PromptPlaceholders(prompt.Template) {
+		if name == PromptDocumentsPlaceholder {
+			if !declared["db_name"] || !declared["query"] {
+				return fmt.Errorf("a template using {%s} must declare db_name and query arguments", PromptDocumentsPlaceholder)
+			}
+			continue
+		}
+		if !declared[name] {
+			return fmt.Errorf("template placeholder {%s} is not a declared argument", name)
+		}
+	}
+	return nil
+}
+
+// Read consistency levels, from freshest to fastest. Strong reads see every
+// acknowledged write; bounded reads may lag by a few seconds; session reads see
+// the writes of the same client; eventually reads may lag arbitrarily.
+const (
+	ConsistencyStrong     = "strong"
+	ConsistencyBounded    = "bounded"
+	ConsistencySession    = "session"
+	ConsistencyEventually = "eventually"
+)
+
+// ValidateConsistencyLevel reports whether level names a read consistency level
+func ValidateConsistencyLevel(level string) error {
+	switch strings.ToLower(level) {
+	case ConsistencyStrong, ConsistencyBounded, ConsistencySession, ConsistencyEventually:
+		return nil
+	default:
+		return fmt.Errorf("unsupported consistency level '%s'; use strong, bounded, session, or eventually", level)
+	}
+}
+
+// DatabaseInstanceConfig declares a vector database instance registered at startup
+type DatabaseInstanceConfig struct {
+	Name       string `mapstructure:"name"`
+	Type       string `mapstructure:"type"`
+	Collection string `mapstructure:"collection"`
+	ReadOnly   bool   `mapstructure:"read_only"`
 }
 
 // EmbeddingConfig contains embedding-related configuration
@@ -63,65 +417,468 @@ type EmbeddingConfig struct {
 	APIKey     string `mapstructure:"api_key"`
 	URL        string `mapstructure:"url"`
 	VectorSize int    `mapstructure:"vector_size"`
+	Metric     string `mapstructure:"metric"`
+	Normalize  bool   `mapstructure:"normalize"`
+
+	// NamedVectors declares additional vector spaces, by name, with their dimensions
+	NamedVectors map[string]int `mapstructure:"named_vectors"`
+
+	// MaxVectorSize is the largest dimension a collection may be set up or
+	// created with, guarding against a misconfigured model; 0 disables the check
+	MaxVectorSize int `mapstructure:"max_vector_size"`
+
+	// Warmup issues a tiny embed call at startup to establish the connection and
+	// validate the key and model; with WarmupStrict a failure aborts startup
+	Warmup       bool `mapstructure:"warmup"`
+	WarmupStrict bool `mapstructure:"warmup_strict"`
+
+	// Fallback controls how queries and writes degrade while the provider is down
+	Fallback EmbeddingFallbackConfig `mapstructure:"fallback"`
+
+	// Writes embeds written documents that carry no vector on the server
+	Writes EmbeddingWritesConfig `mapstructure:"writes"`
+
+	// Cache keeps recent embeddings by model and text hash
+	Cache EmbeddingCacheConfig `mapstructure:"cache"`
+
+	// ModelField names the collection schema field recording the provider,
+	// model, and dimension a collection was set up with; empty disables the
+	// record and the check. OnModelMismatch is "refuse" or "warn" when the
+	// active embedding differs from the recorded one.
+	ModelField      string `mapstructure:"model_field"`
+	OnModelMismatch string `mapstructure:"on_model_mismatch"`
+}
+
+// Embedding model mismatch policies
+const (
+	ModelMismatchRefuse = "refuse"
+	ModelMismatchWarn   = "warn"
+)
+
+// EmbeddingWritesConfig controls embedding on the server of written documents
+// that carry no vector. Documents are embedded in sub-batches of BatchSize, and
+// a failed sub-batch is retried up to MaxRetries times, RetryBackoff apart and
+// doubling, independently of the others. Documents whose sub-batch never
+// succeeds are reported as errors while the rest are written.
+type EmbeddingWritesConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+}
+
+// EmbeddingCacheConfig controls the in-memory cache of embeddings, keyed by
+// model and a hash of the text. Entries live for TTL, and at most MaxSize are
+// held, dropping the least recently used first. Concurrent embed calls for the
+// same text share one provider request whether or not the cache is enabled.
+type EmbeddingCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	MaxSize int           `mapstructure:"max_size"`
+}
+
+// EmbeddingFallbackConfig controls graceful degradation while the embedding
+// provider is unreachable. The provider is marked down after FailureThreshold
+// consecutive failed embed calls and rechecked every RecheckInterval. While it
+// is down, KeywordSearch answers queries by matching terms in document text,
+// and QueueWrites appends writes to the file at QueuePath until it recovers.
+type EmbeddingFallbackConfig struct {
+	KeywordSearch    bool          `mapstructure:"keyword_search"`
+	QueueWrites      bool          `mapstructure:"queue_writes"`
+	QueuePath        string        `mapstructure:"queue_path"`
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	RecheckInterval  time.Duration `mapstructure:"recheck_interval"`
+	KeywordScanLimit int           `mapstructure:"keyword_scan_limit"`
+}
+
+// Supported vector similarity metrics
+const (
+	MetricCosine = "cosine"
+	MetricIP     = "ip"
+	MetricL2     = "l2"
+)
+
+// EmbeddingProfile is the metric and normalization an embedding model pairs best with
+type EmbeddingProfile struct {
+	Metric    string `json:"metric"`
+	Normalize bool   `json:"normalize"`
+}
+
+// Where the metric of a resolved embedding profile came from
+const (
+	ProfileSourceConfig  = "config"
+	ProfileSourceModel   = "model"
+	ProfileSourceDefault = "default"
+)
+
+// embeddingProfiles maps known embedding models, by lowercase name, to their
+// recommended profile. Models trained for dot-product scoring use ip without
+// normalization so vector magnitudes keep their meaning.
+var embeddingProfiles = map[string]EmbeddingProfile{
+	"text-embedding-ada-002":                              {Metric: MetricCosine},
+	"text-embedding-3-small":                              {Metric: MetricCosine},
+	"text-embedding-3-large":                              {Metric: MetricCosine},
+	"nomic-embed-text":                                    {Metric: MetricCosine, Normalize: true},
+	"mxbai-embed-large":                                   {Metric: MetricCosine, Normalize: true},
+	"all-minilm":                                          {Metric: MetricCosine, Normalize: true},
+	"sentence-transformers/all-minilm-l6-v2":              {Metric: MetricCosine, Normalize: true},
+	"sentence-transformers/all-mpnet-base-v2":             {Metric: MetricCosine, Normalize: true},
+	"baai/bge-small-en-v1.5":                              {Metric: MetricIP, Normalize: true},
+	"baai/bge-base-en-v1.5":                               {Metric: MetricIP, Normalize: true},
+	"baai/bge-large-en-v1.5":                              {Metric: MetricIP, Normalize: true},
+	"sentence-transformers/msmarco-distilbert-base-tas-b": {Metric: MetricIP},
+	"sentence-transformers/multi-qa-mpnet-base-dot-v1":    {Metric: MetricIP},
+}
+
+// ModelProfile returns the recommended profile for the configured model, if it is a known one
+func (e EmbeddingConfig) ModelProfile() (EmbeddingProfile, bool) {
+	profile, ok := embeddingProfiles[strings.ToLower(e.Model)]
+	return profile, ok
+}
+
+// ResolvedProfile returns the metric and normalization in effect and where
+// the metric came from. An explicit metric wins over the model's profile,
+// which wins over cosine. Normalization is on when configured or recommended.
+func (e EmbeddingConfig) ResolvedProfile() (EmbeddingProfile, string) {
+	modelProfile, known := e.ModelProfile()
+	resolved := EmbeddingProfile{Metric: MetricCosine, Normalize: e.Normalize}
+	source := ProfileSourceDefault
+	if known {
+		resolved.Metric = modelProfile.Metric
+		resolved.Normalize = resolved.Normalize || modelProfile.Normalize
+		source = ProfileSourceModel
+	}
+	if e.Metric != "" {
+		resolved.Metric = strings.ToLower(e.Metric)
+		source = ProfileSourceConfig
+	}
+	return resolved, source
+}
+
+// EffectiveMetric returns the similarity metric in effect for new collections
+func (e EmbeddingConfig) EffectiveMetric() string {
+	profile, _ := e.ResolvedProfile()
+	return profile.Metric
 }
 
-// VectorDBConfig contains vector database configuration
+// RequiresNormalization reports whether vectors should be L2-normalized before
+// insert and search, which only matters for angle-based metrics
+func (e EmbeddingConfig) RequiresNormalization() bool {
+	profile, _ := e.ResolvedProfile()
+	if !profile.Normalize {
+		return false
+	}
+	switch profile.Metric {
+	case MetricCosine, MetricIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// VectorDBConfig contains vector database configuration. AllowMock enables the
+// in-memory "mock" backend for demos and CI outside development.
 type VectorDBConfig struct {
-	Type     string         `mapstructure:"type"`
-	Milvus   MilvusConfig   `mapstructure:"milvus"`
-	Weaviate WeaviateConfig `mapstructure:"weaviate"`
+	Type      string         `mapstructure:"type"`
+	Milvus    MilvusConfig   `mapstructure:"milvus"`
+	Weaviate  WeaviateConfig `mapstructure:"weaviate"`
+	AllowMock bool           `mapstructure:"allow_mock"`
+	AutoSetup bool           `mapstructure:"auto_setup"`
+
+	// CollectionTemplate names collections created without a collection_name,
+	// e.g. tenant_{tenant}_docs, from {db_name}, {db_type}, and the variables
+	// passed in collection_vars
+	CollectionTemplate string `mapstructure:"collection_template"`
 }
 
 // MilvusConfig contains Milvus-specific configuration
 type MilvusConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
-	Database string `mapstructure:"database"`
+	Host     string          `mapstructure:"host"`
+	Port     int             `mapstructure:"port"`
+	Username string          `mapstructure:"username"`
+	Password string          `mapstructure:"password"`
+	Database string          `mapstructure:"database"`
+	TLS      MilvusTLSConfig `mapstructure:"tls"`
+
+	// MaxInsertBytes caps the estimated payload size of a single insert request
+	MaxInsertBytes int `mapstructure:"max_insert_bytes"`
+
+	// Limits overrides the built-in document limits checked before writes
+	Limits DocumentLimitsConfig `mapstructure:"limits"`
+
+	// Index configures the vector index built when a collection is set up
+	Index MilvusIndexConfig `mapstructure:"index"`
+
+	// ConsistencyLevel is the consistency of searches, queries, counts, and
+	// listings that do not ask for one: strong, bounded, session, or
+	// eventually. Empty leaves the client default.
+	ConsistencyLevel string `mapstructure:"consistency_level"`
+
+	// Readiness holds setup until a new collection can be queried
+	Readiness MilvusReadinessConfig `mapstructure:"readiness"`
+}
+
+// MilvusReadinessConfig bounds the wait after a collection is created for its
+// index to finish building and the collection to load, polling every Interval.
+// A zero Timeout skips the wait.
+type MilvusReadinessConfig struct {
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// Milvus vector index types
+const (
+	MilvusIndexFlat    = "FLAT"
+	MilvusIndexIVFFlat = "IVF_FLAT"
+	MilvusIndexIVFSQ8  = "IVF_SQ8"
+	MilvusIndexHNSW    = "HNSW"
+)
+
+// Built-in Milvus index parameters, used for any parameter left unset
+const (
+	DefaultMilvusNList          = 1024
+	DefaultMilvusM              = 16
+	DefaultMilvusEfConstruction = 200
+)
+
+// MilvusIndexParams selects a vector index type and its build parameters.
+// NList applies to the IVF indexes; M and EfConstruction apply to HNSW.
+type MilvusIndexParams struct {
+	Type           string `mapstructure:"type"`
+	NList          int    `mapstructure:"nlist"`
+	M              int    `mapstructure:"m"`
+	EfConstruction int    `mapstructure:"ef_construction"`
+}
+
+// MilvusIndexConfig holds the default index parameters and per-collection
+// overrides, keyed by collection name. An override replaces the default
+// parameters as a whole.
+type MilvusIndexConfig struct {
+	MilvusIndexParams `mapstructure:",squash"`
+	Collections       map[string]MilvusIndexParams `mapstructure:"collections"`
+}
+
+// ForCollection returns the index parameters of a collection with unset
+// parameters filled in. Collection names are matched case-insensitively,
+// since configuration keys are lowercased when loaded.
+func (c MilvusIndexConfig) ForCollection(collectionName string) MilvusIndexParams {
+	params := c.MilvusIndexParams
+	for name, override := range c.Collections {
+		if strings.EqualFold(name, collectionName) {
+			params = override
+			break
+		}
+	}
+	return params.withDefaults()
+}
+
+// withDefaults fills unset parameters of the selected index type
+func (p MilvusIndexParams) withDefaults() MilvusIndexParams {
+	p.Type = strings.ToUpper(p.Type)
+	switch p.Type {
+	case "":
+		p.Type = MilvusIndexIVFFlat
+		fallthrough
+	case MilvusIndexIVFFlat, MilvusIndexIVFSQ8:
+		if p.NList == 0 {
+			p.NList = DefaultMilvusNList
+		}
+	case MilvusIndexHNSW:
+		if p.M == 0 {
+			p.M = DefaultMilvusM
+		}
+		if p.EfConstruction == 0 {
+			p.EfConstruction = DefaultMilvusEfConstruction
+		}
+	}
+	return p
+}
+
+// Validate checks the index type and that its parameters are within the
+// ranges Milvus accepts
+func (p MilvusIndexParams) Validate() error {
+	switch strings.ToUpper(p.Type) {
+	case "", MilvusIndexIVFFlat, MilvusIndexIVFSQ8:
+		if p.NList < 0 || p.NList > 65536 {
+			return fmt.Errorf("nlist must be between 1 and 65536: %d", p.NList)
+		}
+		if p.M != 0 || p.EfConstruction != 0 {
+			return fmt.Errorf("m and ef_construction only apply to HNSW indexes")
+		}
+	case MilvusIndexHNSW:
+		if p.M < 0 || p.M > 2048 || (p.M > 0 && p.M < 2) {
+			return fmt.Errorf("m must be between 2 and 2048: %d", p.M)
+		}
+		if p.EfConstruction < 0 {
+			return fmt.Errorf("ef_construction must be positive: %d", p.EfConstruction)
+		}
+		if p.NList != 0 {
+			return fmt.Errorf("nlist only applies to IVF indexes")
+		}
+	case MilvusIndexFlat:
+		if p.NList != 0 || p.M != 0 || p.EfConstruction != 0 {
+			return fmt.Errorf("FLAT indexes take no parameters")
+		}
+	default:
+		return fmt.Errorf("unsupported index type '%s'; use %s, %s, %s, or %s", p.Type, MilvusIndexFlat, MilvusIndexIVFFlat, MilvusIndexIVFSQ8, MilvusIndexHNSW)
+	}
+	return nil
+}
+
+// DocumentLimitsConfig overrides a backend's built-in document limits, in
+// bytes or fields. Zero keeps the built-in limit and a negative value removes it.
+type DocumentLimitsConfig struct {
+	MaxTextBytes      int `mapstructure:"max_text_bytes"`
+	MaxURLBytes       int `mapstructure:"max_url_bytes"`
+	MaxMetadataBytes  int `mapstructure:"max_metadata_bytes"`
+	MaxMetadataFields int `mapstructure:"max_metadata_fields"`
+}
+
+// MilvusTLSConfig contains TLS options for gRPC connections to remote Milvus clusters
+type MilvusTLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CACert             string `mapstructure:"ca_cert"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
 }
 
 // WeaviateConfig contains Weaviate-specific configuration
 type WeaviateConfig struct {
-	URL     string        `mapstructure:"url"`
-	APIKey  string        `mapstructure:"api_key"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	URL         string        `mapstructure:"url"`
+	AuthType    string        `mapstructure:"auth_type"`
+	APIKey      string        `mapstructure:"api_key"`
+	BearerToken string        `mapstructure:"bearer_token"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+
+	// Limits overrides the built-in document limits checked before writes
+	Limits DocumentLimitsConfig `mapstructure:"limits"`
+
+	// MetadataSchema declares the fields of the metadata object property, so
+	// they are created as nested properties that can be filtered on. Each key
+	// maps to a data type (text, number, int, boolean, date) or to a map of
+	// the fields of a nested object.
+	MetadataSchema map[string]interface{} `mapstructure:"metadata_schema"`
+
+	// ServerVersion pins the Weaviate server version, e.g. 1.18.2, instead of
+	// detecting it from the meta endpoint. It decides whether the url
+	// property is declared as string or, from 1.19, as text.
+	ServerVersion string `mapstructure:"server_version"`
+}
+
+// Weaviate authentication modes
+const (
+	WeaviateAuthNone   = "none"
+	WeaviateAuthAPIKey = "api_key"
+	WeaviateAuthBearer = "bearer"
+)
+
+// ResolvedAuthType returns the effective authentication mode. When auth_type is
+// not set it is inferred from whichever credential is configured, falling back
+// to anonymous access.
+func (w WeaviateConfig) ResolvedAuthType() string {
+	switch {
+	case w.AuthType != "":
+		return strings.ToLower(w.AuthType)
+	case w.APIKey != "":
+		return WeaviateAuthAPIKey
+	case w.BearerToken != "":
+		return WeaviateAuthBearer
+	default:
+		return WeaviateAuthNone
+	}
+}
+
+// AuthHeaders returns the HTTP headers used to authenticate against Weaviate.
+// Anonymous access yields no headers at all, since some instances reject empty
+// auth headers.
+func (w WeaviateConfig) AuthHeaders() (map[string]string, error) {
+	switch w.ResolvedAuthType() {
+	case WeaviateAuthNone:
+		return map[string]string{}, nil
+	case WeaviateAuthAPIKey:
+		if w.APIKey == "" {
+			return nil, fmt.Errorf("weaviate api_key is required for auth_type %s", WeaviateAuthAPIKey)
+		}
+		return map[string]string{"X-API-Key": w.APIKey}, nil
+	case WeaviateAuthBearer:
+		if w.BearerToken == "" {
+			return nil, fmt.Errorf("weaviate bearer_token is required for auth_type %s", WeaviateAuthBearer)
+		}
+		return map[string]string{"Authorization": "Bearer " + w.BearerToken}, nil
+	default:
+		return nil, fmt.Errorf("unsupported weaviate auth_type: %s", w.AuthType)
+	}
 }
 
 // Load loads configuration from various sources
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath("/etc/maestro-mcp")
+	return LoadFile("")
+}
+
+// LoadFile loads configuration from an explicit config file path, bypassing the
+// default search path. An empty path falls back to searching the default locations.
+func LoadFile(path string) (*Config, error) {
+	v := viper.New()
+
+	if path != "" {
+		// An explicitly requested file must exist, unlike the searched default
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		v.AddConfigPath("./config")
+		v.AddConfigPath("/etc/maestro-mcp")
+	}
 
 	// Set default values
-	setDefaults()
+	setDefaults(v)
 
 	// Enable environment variable support
-	viper.AutomaticEnv()
-	viper.SetEnvPrefix("MAESTRO_MCP")
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	v.SetEnvPrefix("MAESTRO_MCP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Load .env file if it exists
 	if err := loadEnvFile(); err != nil {
 		return nil, fmt.Errorf("failed to load .env file: %w", err)
 	}
 
-	// Read config file (optional)
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+	// Read config file (optional unless explicitly provided)
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok || path != "" {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
 		// Config file not found is OK, we'll use defaults and env vars
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Carry timeouts set under renamed categories over to their replacements,
+	// unless those are set too
+	for old, replacements := range RenamedTimeouts {
+		timeout, set := config.MCP.Timeouts[old]
+		if !set {
+			continue
+		}
+		for _, category := range replacements {
+			if !v.InConfig("mcp.timeouts." + category) {
+				config.MCP.Timeouts[category] = timeout
+			}
+		}
+	}
+
+	// Resolve ${ENV_VAR} references so secrets can stay out of config files
+	if err := config.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -131,47 +888,172 @@ func Load() (*Config, error) {
 }
 
 // setDefaults sets default configuration values
-func setDefaults() {
-	viper.SetDefault("version", "0.0.1")
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("version", "0.0.1")
 
 	// Server defaults
-	viper.SetDefault("server.host", "localhost")
-	viper.SetDefault("server.port", 8030)
-	viper.SetDefault("server.read_timeout", "30s")
-	viper.SetDefault("server.write_timeout", "30s")
-	viper.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.host", "localhost")
+	v.SetDefault("server.port", 8030)
+	v.SetDefault("server.read_timeout", "30s")
+	v.SetDefault("server.write_timeout", "30s")
+	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.access_log", true)
+	v.SetDefault("server.max_connections", 0)
+	v.SetDefault("server.disable_keep_alives", false)
+	v.SetDefault("server.compression.enabled", true)
+	v.SetDefault("server.compression.min_size", 1024)
+	v.SetDefault("server.wait_for_backends.enabled", false)
+	v.SetDefault("server.wait_for_backends.timeout", "60s")
+	v.SetDefault("server.wait_for_backends.interval", "2s")
 
 	// Database defaults
-	viper.SetDefault("database.type", "postgres")
-	viper.SetDefault("database.host", "localhost")
-	viper.SetDefault("database.port", 5432)
-	viper.SetDefault("database.database", "maestro")
-	viper.SetDefault("database.ssl_mode", "disable")
-	viper.SetDefault("database.max_connections", 25)
-	viper.SetDefault("database.max_idle_connections", 5)
+	v.SetDefault("database.type", "postgres")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.database", "maestro")
+	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.max_connections", 25)
+	v.SetDefault("database.max_idle_connections", 5)
 
 	// Logging defaults
-	viper.SetDefault("logging.level", "info")
-	viper.SetDefault("logging.format", "json")
-	viper.SetDefault("logging.output", "stdout")
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.tool_levels", map[string]string{})
+	v.SetDefault("logging.format", "json")
+	v.SetDefault("logging.output", "stdout")
 
 	// MCP defaults
-	viper.SetDefault("mcp.tool_timeout", "15s")
-	viper.SetDefault("mcp.timeouts.health", "30s")
-	viper.SetDefault("mcp.timeouts.query", "30s")
-	viper.SetDefault("mcp.timeouts.write", "900s")
-	viper.SetDefault("mcp.timeouts.delete", "60s")
+	v.SetDefault("mcp.tool_timeout", "15s")
+	v.SetDefault("mcp.timeouts.health", "30s")
+	v.SetDefault("mcp.timeouts.query", "30s")
+	v.SetDefault("mcp.timeouts.setup_database", "60s")
+	v.SetDefault("mcp.timeouts.write_single", "60s")
+	v.SetDefault("mcp.timeouts.write_bulk", "900s")
+	v.SetDefault("mcp.timeouts.delete_single", "60s")
+	v.SetDefault("mcp.timeouts.delete_bulk", "900s")
+	v.SetDefault("mcp.timeouts.migrate", "900s")
+	v.SetDefault("mcp.timeouts.export", "900s")
+	v.SetDefault("mcp.timeouts.import", "900s")
+	v.SetDefault("mcp.timeouts.reingest", "900s")
+	v.SetDefault("mcp.timeouts.embedding", "30s")
+
+	// Query cache defaults
+	v.SetDefault("mcp.query_cache.enabled", false)
+	v.SetDefault("mcp.query_cache.ttl", "60s")
+	v.SetDefault("mcp.query_cache.max_size", 1000)
+
+	// Idempotency key defaults
+	v.SetDefault("mcp.idempotency.window", "24h")
+	v.SetDefault("mcp.idempotency.max_keys", 10000)
+
+	// Pagination snapshot defaults
+	v.SetDefault("mcp.snapshot.ttl", "10m")
+	v.SetDefault("mcp.snapshot.max_snapshots", 100)
+	v.SetDefault("mcp.snapshot.max_documents", 100000)
+	v.SetDefault("mcp.snapshot.consistency_level", ConsistencyStrong)
+
+	// Reingest loads into a shadow collection unless told otherwise
+	v.SetDefault("mcp.reingest.shadow", true)
+	v.SetDefault("mcp.resources.enabled", true)
+	v.SetDefault("mcp.resources.list_documents", false)
+	v.SetDefault("mcp.resources.max_documents", 100)
+
+	// Circuit breaker defaults
+	v.SetDefault("mcp.circuit_breaker.enabled", true)
+	v.SetDefault("mcp.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("mcp.circuit_breaker.reset_timeout", "30s")
+	v.SetDefault("mcp.circuit_breaker.max_reset_timeout", "5m")
+
+	// Per-database concurrency defaults
+	v.SetDefault("mcp.concurrency.max_concurrent_operations", 0)
+	v.SetDefault("mcp.concurrency.wait_timeout", "5s")
+	v.SetDefault("mcp.per_client_concurrency.max_in_flight", 0)
+	v.SetDefault("mcp.per_client_concurrency.wait_timeout", "0s")
+	v.SetDefault("mcp.per_client_concurrency.client_header", "")
+
+	// Content deduplication defaults
+	v.SetDefault("mcp.content_dedup.store_hash", false)
+	v.SetDefault("mcp.content_dedup.mode", ContentDedupOff)
+
+	// Result limit defaults
+	v.SetDefault("mcp.search.default_limit", 5)
+	v.SetDefault("mcp.search.max_limit", 100)
+	v.SetDefault("mcp.list.default_limit", 10)
+	v.SetDefault("mcp.list.max_limit", 1000)
+
+	// Tool filtering defaults (all tools exposed)
+	v.SetDefault("mcp.enabled_tools", []string{})
+	v.SetDefault("mcp.disabled_tools", []string{})
+
+	// Audit log defaults
+	v.SetDefault("mcp.audit.enabled", false)
+	v.SetDefault("mcp.audit.sink", "memory")
+	v.SetDefault("mcp.audit.path", "audit.log")
+	v.SetDefault("mcp.audit.max_entries", 1000)
+
+	// Soft delete defaults
+	v.SetDefault("mcp.soft_delete.enabled", false)
+	v.SetDefault("mcp.soft_delete.purge_after", "720h")
+
+	// TTL sweep defaults
+	v.SetDefault("mcp.ttl_sweep.enabled", false)
+	v.SetDefault("mcp.ttl_sweep.interval", "5m")
+
+	// Write validation defaults
+	v.SetDefault("mcp.write_validation.allow_empty_text", false)
+	v.SetDefault("mcp.write_validation.allow_empty_url", false)
+
+	// Rerank defaults; re-ranking stays off until a url is configured
+	v.SetDefault("mcp.rerank.url", "")
+	v.SetDefault("mcp.rerank.model", "")
+	v.SetDefault("mcp.rerank.candidates", 50)
+	v.SetDefault("mcp.rerank.timeout", "10s")
+
+	// Recency boost defaults
+	v.SetDefault("mcp.recency.half_life", "720h")
+
+	// Text preprocessing is off until steps are listed
+	v.SetDefault("mcp.preprocess.steps", []string{})
+
+	// Query expansion has no synonyms until some are configured
+	v.SetDefault("mcp.expansion.synonyms", map[string][]string{})
 
 	// Embedding defaults
-	viper.SetDefault("mcp.embedding.provider", "openai")
-	viper.SetDefault("mcp.embedding.model", "text-embedding-ada-002")
-	viper.SetDefault("mcp.embedding.vector_size", 1536)
+	v.SetDefault("mcp.embedding.provider", "openai")
+	v.SetDefault("mcp.embedding.model", "text-embedding-ada-002")
+	v.SetDefault("mcp.embedding.vector_size", 1536)
+	v.SetDefault("mcp.embedding.metric", "")
+	v.SetDefault("mcp.embedding.normalize", false)
+	v.SetDefault("mcp.embedding.max_vector_size", 16384)
+	v.SetDefault("mcp.embedding.warmup", false)
+	v.SetDefault("mcp.embedding.warmup_strict", false)
+	v.SetDefault("mcp.embedding.fallback.keyword_search", false)
+	v.SetDefault("mcp.embedding.fallback.queue_writes", false)
+	v.SetDefault("mcp.embedding.fallback.queue_path", "embedding_queue.jsonl")
+	v.SetDefault("mcp.embedding.fallback.failure_threshold", 3)
+	v.SetDefault("mcp.embedding.fallback.recheck_interval", "30s")
+	v.SetDefault("mcp.embedding.fallback.keyword_scan_limit", 10000)
+	v.SetDefault("mcp.embedding.writes.enabled", false)
+	v.SetDefault("mcp.embedding.writes.batch_size", 32)
+	v.SetDefault("mcp.embedding.writes.max_retries", 2)
+	v.SetDefault("mcp.embedding.writes.retry_backoff", "500ms")
+	v.SetDefault("mcp.embedding.cache.enabled", false)
+	v.SetDefault("mcp.embedding.cache.ttl", "5m")
+	v.SetDefault("mcp.embedding.cache.max_size", 10000)
+	v.SetDefault("mcp.embedding.model_field", "embedding_model")
+	v.SetDefault("mcp.embedding.on_model_mismatch", ModelMismatchRefuse)
 
 	// Vector DB defaults
-	viper.SetDefault("mcp.vector_db.type", "milvus")
-	viper.SetDefault("mcp.vector_db.milvus.host", "localhost")
-	viper.SetDefault("mcp.vector_db.milvus.port", 19530)
-	viper.SetDefault("mcp.vector_db.weaviate.timeout", "10s")
+	v.SetDefault("mcp.vector_db.type", "milvus")
+	v.SetDefault("mcp.vector_db.allow_mock", false)
+	v.SetDefault("mcp.vector_db.auto_setup", false)
+	v.SetDefault("mcp.vector_db.collection_template", "")
+	v.SetDefault("mcp.vector_db.milvus.host", "localhost")
+	v.SetDefault("mcp.vector_db.milvus.port", 19530)
+	v.SetDefault("mcp.vector_db.milvus.max_insert_bytes", 32*1024*1024)
+	v.SetDefault("mcp.vector_db.milvus.consistency_level", ConsistencyBounded)
+	v.SetDefault("mcp.vector_db.milvus.readiness.timeout", "30s")
+	v.SetDefault("mcp.vector_db.milvus.readiness.interval", "500ms")
+	v.SetDefault("mcp.vector_db.weaviate.timeout", "10s")
 }
 
 // loadEnvFile loads environment variables from .env file
@@ -187,32 +1069,139 @@ func loadEnvFile() error {
 	}
 	defer file.Close()
 
-	// Simple .env file parser
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
+		key, value, ok := parseEnvLine(scanner.Text())
+		if !ok {
 			continue
 		}
 
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
+		// Variables already present in the environment take precedence
+		if _, exists := os.LookupEnv(key); exists {
 			continue
 		}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+		os.Setenv(key, value)
+	}
+
+	return scanner.Err()
+}
+
+// parseEnvLine parses a single KEY=value line from a .env file. It accepts an
+// optional leading "export", strips inline comments outside quotes, and
+// unescapes quotes inside quoted values.
+func parseEnvLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	if rest, found := strings.CutPrefix(line, "export"); found && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		line = strings.TrimSpace(rest)
+	}
+
+	key, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", "", false
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return key, "", true
+	}
+
+	if quote := value[0]; quote == '"' || quote == '\'' {
+		if unquoted, ok := unquoteEnvValue(value[1:], quote); ok {
+			return key, unquoted, true
+		}
+		// Unterminated quote: keep the raw value
+		return key, value, true
+	}
+
+	// Unquoted values end at an inline comment
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			value = strings.TrimSpace(value[:i])
+			break
+		}
+	}
+
+	return key, value, true
+}
 
-		// Remove quotes if present
-		if len(value) >= 2 && ((value[0] == '"' && value[len(value)-1] == '"') ||
-			(value[0] == '\'' && value[len(value)-1] == '\'')) {
-			value = value[1 : len(value)-1]
+// unquoteEnvValue reads a quoted value up to its closing quote, resolving
+// backslash escapes. Anything after the closing quote (e.g. a comment) is ignored.
+func unquoteEnvValue(value string, quote byte) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value) && (value[i+1] == quote || value[i+1] == '\\'):
+			b.WriteByte(value[i+1])
+			i++
+		case c == '\\' && quote == '"' && i+1 < len(value) && value[i+1] == 'n':
+			b.WriteByte('\n')
+			i++
+		case c == quote:
+			return b.String(), true
+		default:
+			b.WriteByte(c)
 		}
+	}
+	return "", false
+}
 
-		os.Setenv(key, value)
+// secretFields returns pointers to the configuration fields holding secrets
+func (c *Config) secretFields() []*string {
+	return []*string{
+		&c.Database.Password,
+		&c.MCP.Embedding.APIKey,
+		&c.MCP.VectorDB.Milvus.Password,
+		&c.MCP.VectorDB.Weaviate.APIKey,
+		&c.MCP.VectorDB.Weaviate.BearerToken,
+		&c.MCP.Rerank.APIKey,
 	}
+}
 
-	return scanner.Err()
+// resolveSecrets expands ${ENV_VAR} references in secret fields from the environment
+func (c *Config) resolveSecrets() error {
+	for _, field := range c.secretFields() {
+		var missing string
+		*field = envRefPattern.ReplaceAllStringFunc(*field, func(ref string) string {
+			name := envRefPattern.FindStringSubmatch(ref)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return value
+		})
+		if missing != "" {
+			return fmt.Errorf("environment variable %s referenced in config is not set", missing)
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with all secrets masked, safe
+// for logging. Passwords embedded in endpoint URLs are masked too.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, field := range redacted.secretFields() {
+		if *field != "" {
+			*field = redactedValue
+		}
+	}
+	for _, field := range []*string{&redacted.MCP.Embedding.URL, &redacted.MCP.Rerank.URL, &redacted.MCP.VectorDB.Weaviate.URL} {
+		if parsed, err := url.Parse(*field); err == nil && parsed.User != nil {
+			*field = parsed.Redacted()
+		}
+	}
+	return &redacted
 }
 
 // Validate validates the configuration
@@ -221,6 +1210,65 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	if c.MCP.Concurrency.MaxConcurrentOperations < 0 {
+		return fmt.Errorf("mcp concurrency max_concurrent_operations must not be negative: %d", c.MCP.Concurrency.MaxConcurrentOperations)
+	}
+	if c.MCP.Concurrency.WaitTimeout < 0 {
+		return fmt.Errorf("mcp concurrency wait_timeout must not be negative: %s", c.MCP.Concurrency.WaitTimeout)
+	}
+	if c.MCP.PerClientConcurrency.MaxInFlight < 0 {
+		return fmt.Errorf("mcp per_client_concurrency max_in_flight must not be negative: %d", c.MCP.PerClientConcurrency.MaxInFlight)
+	}
+	if c.MCP.PerClientConcurrency.WaitTimeout < 0 {
+		return fmt.Errorf("mcp per_client_concurrency wait_timeout must not be negative: %s", c.MCP.PerClientConcurrency.WaitTimeout)
+	}
+
+	switch c.MCP.ContentDedup.Mode {
+	case "", ContentDedupOff, ContentDedupSkip, ContentDedupMergeURLs:
+	default:
+		return fmt.Errorf("invalid content_dedup mode: %s (must be %s, %s, or %s)",
+			c.MCP.ContentDedup.Mode, ContentDedupOff, ContentDedupSkip, ContentDedupMergeURLs)
+	}
+
+	for name, limits := range map[string]LimitConfig{"search": c.MCP.Search, "list": c.MCP.List} {
+		if limits.DefaultLimit < 0 || limits.MaxLimit < 0 {
+			return fmt.Errorf("mcp %s limits must not be negative", name)
+		}
+		if limits.MaxLimit > 0 && limits.DefaultLimit > limits.MaxLimit {
+			return fmt.Errorf("mcp %s default_limit %d exceeds max_limit %d", name, limits.DefaultLimit, limits.MaxLimit)
+		}
+	}
+
+	if c.MCP.Idempotency.Window < 0 || c.MCP.Idempotency.MaxKeys < 0 {
+		return fmt.Errorf("mcp idempotency window and max_keys must not be negative")
+	}
+
+	snapshot := c.MCP.Snapshot
+	if snapshot.TTL < 0 || snapshot.MaxSnapshots < 0 || snapshot.MaxDocuments < 0 {
+		return fmt.Errorf("mcp snapshot ttl, max_snapshots, and max_documents must not be negative")
+	}
+	if snapshot.ConsistencyLevel != "" {
+		if err := ValidateConsistencyLevel(snapshot.ConsistencyLevel); err != nil {
+			return fmt.Errorf("invalid mcp snapshot consistency_level: %w", err)
+		}
+	}
+
+	if c.MCP.Recency.HalfLife < 0 {
+		return fmt.Errorf("mcp recency half_life must not be negative: %s", c.MCP.Recency.HalfLife)
+	}
+
+	if c.Server.MaxConnections < 0 {
+		return fmt.Errorf("server max_connections must not be negative: %d", c.Server.MaxConnections)
+	}
+
+	if c.Server.Compression.MinSize < 0 {
+		return fmt.Errorf("server compression min_size must not be negative: %d", c.Server.Compression.MinSize)
+	}
+
+	if wait := c.Server.WaitForBackends; wait.Enabled && (wait.Timeout <= 0 || wait.Interval <= 0) {
+		return fmt.Errorf("server wait_for_backends timeout and interval must be positive when enabled")
+	}
+
 	if c.Database.Type == "" {
 		return fmt.Errorf("database type is required")
 	}
@@ -229,6 +1277,34 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("vector database type is required")
 	}
 
+	// Index parameters apply to every Milvus database, including those
+	// created at runtime, so they are checked whatever the default type
+	if err := c.MCP.VectorDB.Milvus.Index.Validate(); err != nil {
+		return fmt.Errorf("invalid milvus index: %w", err)
+	}
+	for name, params := range c.MCP.VectorDB.Milvus.Index.Collections {
+		if err := params.Validate(); err != nil {
+			return fmt.Errorf("invalid milvus index for collection '%s': %w", name, err)
+		}
+	}
+	if level := c.MCP.VectorDB.Milvus.ConsistencyLevel; level != "" {
+		if err := ValidateConsistencyLevel(level); err != nil {
+			return fmt.Errorf("invalid milvus consistency_level: %w", err)
+		}
+	}
+	if readiness := c.MCP.VectorDB.Milvus.Readiness; readiness.Timeout < 0 {
+		return fmt.Errorf("milvus readiness timeout must not be negative")
+	} else if readiness.Timeout > 0 && readiness.Interval <= 0 {
+		return fmt.Errorf("milvus readiness interval must be positive when a timeout is set")
+	} else if setup := c.GetTimeout("setup_database"); readiness.Timeout > setup {
+		return fmt.Errorf("milvus readiness timeout of %s must not exceed mcp.timeouts.setup_database of %s", readiness.Timeout, setup)
+	}
+	if template := c.MCP.VectorDB.CollectionTemplate; template != "" {
+		if rest := promptPlaceholder.ReplaceAllString(template, ""); strings.ContainsAny(rest, "{}") {
+			return fmt.Errorf("invalid vector_db collection_template '%s': variables must be written as {name}", template)
+		}
+	}
+
 	// Validate vector database specific configs
 	switch c.MCP.VectorDB.Type {
 	case "milvus":
@@ -238,17 +1314,181 @@ func (c *Config) Validate() error {
 		if c.MCP.VectorDB.Milvus.Port <= 0 || c.MCP.VectorDB.Milvus.Port > 65535 {
 			return fmt.Errorf("invalid milvus port: %d", c.MCP.VectorDB.Milvus.Port)
 		}
+		if tls := c.MCP.VectorDB.Milvus.TLS; tls.Enabled && tls.CACert == "" && !tls.InsecureSkipVerify {
+			return fmt.Errorf("milvus tls.ca_cert is required when TLS is enabled (or set tls.insecure_skip_verify)")
+		}
 	case "weaviate":
 		if c.MCP.VectorDB.Weaviate.URL == "" {
 			return fmt.Errorf("weaviate URL is required")
 		}
+		if _, err := c.MCP.VectorDB.Weaviate.AuthHeaders(); err != nil {
+			return err
+		}
+	case "mock":
+		if !c.MockAllowed() {
+			return fmt.Errorf("the mock vector database requires mcp.vector_db.allow_mock outside development")
+		}
 	default:
 		return fmt.Errorf("unsupported vector database type: %s", c.MCP.VectorDB.Type)
 	}
 
+	switch strings.ToLower(c.MCP.Embedding.Metric) {
+	case "", MetricCosine, MetricIP, MetricL2:
+	default:
+		return fmt.Errorf("unsupported embedding metric: %s", c.MCP.Embedding.Metric)
+	}
+
+	if fallback := c.MCP.Embedding.Fallback; fallback.QueueWrites && fallback.QueuePath == "" {
+		return fmt.Errorf("embedding fallback queue_path is required when queue_writes is enabled")
+	}
+	if c.MCP.Embedding.Fallback.FailureThreshold < 0 {
+		return fmt.Errorf("embedding fallback failure_threshold must not be negative")
+	}
+	if c.MCP.Embedding.Fallback.RecheckInterval < 0 {
+		return fmt.Errorf("embedding fallback recheck_interval must not be negative")
+	}
+
+	switch c.MCP.Embedding.ModelField {
+	case "name", "class", "fields", "properties", "embedding", "vectorizer", "dimension", "metric_type", "index", "vectorIndexConfig", "vectorConfig":
+		return fmt.Errorf("embedding model_field '%s' collides with a collection schema field", c.MCP.Embedding.ModelField)
+	}
+	for tool, level := range c.Logging.ToolLevels {
+		if err := ValidateLogLevel(level); err != nil {
+			return fmt.Errorf("invalid logging tool_levels for '%s': %w", tool, err)
+		}
+	}
+
+	if c.MCP.Embedding.MaxVectorSize < 0 {
+		return fmt.Errorf("embedding max_vector_size must not be negative: %d", c.MCP.Embedding.MaxVectorSize)
+	}
+	if writes := c.MCP.Embedding.Writes; writes.Enabled && writes.BatchSize <= 0 {
+		return fmt.Errorf("embedding writes batch_size must be positive when enabled: %d", writes.BatchSize)
+	}
+	if writes := c.MCP.Embedding.Writes; writes.MaxRetries < 0 || writes.RetryBackoff < 0 {
+		return fmt.Errorf("embedding writes max_retries and retry_backoff must not be negative")
+	}
+	if cache := c.MCP.Embedding.Cache; cache.Enabled {
+		if cache.TTL <= 0 {
+			return fmt.Errorf("embedding cache ttl must be positive when the cache is enabled")
+		}
+		if cache.MaxSize <= 0 {
+			return fmt.Errorf("embedding cache max_size must be positive when the cache is enabled")
+		}
+	}
+
+	switch c.MCP.Embedding.OnModelMismatch {
+	case "", ModelMismatchRefuse, ModelMismatchWarn:
+	default:
+		return fmt.Errorf("invalid embedding on_model_mismatch: %s (must be %s or %s)",
+			c.MCP.Embedding.OnModelMismatch, ModelMismatchRefuse, ModelMismatchWarn)
+	}
+
+	for name, dimension := range c.MCP.Embedding.NamedVectors {
+		switch name {
+		case "", "id", "url", "text", "metadata", "vector":
+			return fmt.Errorf("invalid named vector name: '%s'", name)
+		}
+		if dimension <= 0 {
+			return fmt.Errorf("named vector '%s' must have a positive dimension", name)
+		}
+	}
+
+	if c.MCP.QueryCache.Enabled {
+		if c.MCP.QueryCache.TTL <= 0 {
+			return fmt.Errorf("query cache ttl must be positive when the cache is enabled")
+		}
+		if c.MCP.QueryCache.MaxSize <= 0 {
+			return fmt.Errorf("query cache max_size must be positive when the cache is enabled")
+		}
+	}
+
+	if c.MCP.CircuitBreaker.Enabled {
+		if c.MCP.CircuitBreaker.FailureThreshold <= 0 {
+			return fmt.Errorf("circuit breaker failure_threshold must be positive when the breaker is enabled")
+		}
+		if c.MCP.CircuitBreaker.ResetTimeout <= 0 {
+			return fmt.Errorf("circuit breaker reset_timeout must be positive when the breaker is enabled")
+		}
+	}
+
+	if c.MCP.Audit.Enabled {
+		switch c.MCP.Audit.Sink {
+		case AuditSinkMemory:
+		case AuditSinkFile:
+			if c.MCP.Audit.Path == "" {
+				return fmt.Errorf("audit path is required for the file sink")
+			}
+		default:
+			return fmt.Errorf("unsupported audit sink: %s", c.MCP.Audit.Sink)
+		}
+		if c.MCP.Audit.MaxEntries <= 0 {
+			return fmt.Errorf("audit max_entries must be positive when auditing is enabled")
+		}
+	}
+
+	if c.MCP.SoftDelete.PurgeAfter < 0 {
+		return fmt.Errorf("soft delete purge_after must not be negative")
+	}
+
+	if c.MCP.TTLSweep.Enabled && c.MCP.TTLSweep.Interval <= 0 {
+		return fmt.Errorf("ttl_sweep interval must be positive when the sweep is enabled")
+	}
+
+	if c.MCP.Rerank.URL != "" && c.MCP.Rerank.Candidates <= 0 {
+		return fmt.Errorf("rerank candidates must be positive when a rerank url is set")
+	}
+
+	prompts := make(map[string]bool, len(c.MCP.Prompts))
+	for i, prompt := range c.MCP.Prompts {
+		if prompt.Name == "" {
+			return fmt.Errorf("mcp.prompts[%d]: name is required", i)
+		}
+		if prompts[prompt.Name] {
+			return fmt.Errorf("mcp.prompts[%d]: duplicate prompt name '%s'", i, prompt.Name)
+		}
+		prompts[prompt.Name] = true
+		if err := validatePrompt(prompt); err != nil {
+			return fmt.Errorf("mcp.prompts[%d] '%s': %w", i, prompt.Name, err)
+		}
+	}
+
+	// Validate databases declared for registration at startup
+	seen := make(map[string]bool, len(c.MCP.Databases))
+	for i, db := range c.MCP.Databases {
+		if db.Name == "" {
+			return fmt.Errorf("mcp.databases[%d]: name is required", i)
+		}
+		if seen[db.Name] {
+			return fmt.Errorf("mcp.databases[%d]: duplicate database name '%s'", i, db.Name)
+		}
+		seen[db.Name] = true
+
+		if !IsSupportedVectorDBType(db.Type) {
+			return fmt.Errorf("mcp.databases[%d]: unsupported vector database type: %s", i, db.Type)
+		}
+		if db.Type == "mock" && !c.MockAllowed() {
+			return fmt.Errorf("mcp.databases[%d]: the mock vector database requires mcp.vector_db.allow_mock outside development", i)
+		}
+	}
+
 	return nil
 }
 
+// SupportedVectorDBTypes lists the vector database backends the server can create
+var SupportedVectorDBTypes = []string{"weaviate", "milvus", "mock"}
+
+// IsSupportedVectorDBType reports whether dbType names a known vector database backend
+func IsSupportedVectorDBType(dbType string) bool {
+	return slices.Contains(SupportedVectorDBTypes, dbType)
+}
+
+// RenamedTimeouts maps the timeout categories replaced when single and bulk
+// operations were split to the categories that replace them
+var RenamedTimeouts = map[string][]string{
+	"write":  {"write_bulk"},
+	"delete": {"delete_single", "delete_bulk"},
+}
+
 // GetTimeout returns the timeout for a specific operation category
 func (c *Config) GetTimeout(category string) time.Duration {
 	if timeout, exists := c.MCP.Timeouts[category]; exists {
@@ -257,7 +1497,22 @@ func (c *Config) GetTimeout(category string) time.Duration {
 	return c.MCP.ToolTimeout
 }
 
+// ToolEnabled reports whether a tool is exposed: it must be listed in
+// mcp.enabled_tools when that is set, and must not be in mcp.disabled_tools
+func (c *Config) ToolEnabled(name string) bool {
+	if slices.Contains(c.MCP.DisabledTools, name) {
+		return false
+	}
+	return len(c.MCP.EnabledTools) == 0 || slices.Contains(c.MCP.EnabledTools, name)
+}
+
 // IsDevelopment returns true if running in development mode
 func (c *Config) IsDevelopment() bool {
 	return strings.ToLower(c.Logging.Level) == "debug"
 }
+
+// MockAllowed reports whether the in-memory mock backend may be used: in
+// development, or when mcp.vector_db.allow_mock is set
+func (c *Config) MockAllowed() bool {
+	return c.MCP.VectorDB.AllowMock || c.IsDevelopment()
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// durationType is the type of duration fields, shown as strings such as 30s
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Settings returns the configuration as nested maps keyed by the names used in
+// config files and environment variables, with durations written as strings
+// such as 30s. Call it on a Redacted copy before showing it to anyone.
+func (c *Config) Settings() map[string]interface{} {
+	return settingsValue(reflect.ValueOf(*c)).(map[string]interface{})
+}
+
+// settingsValue converts a configuration value for Settings
+func settingsValue(v reflect.Value) interface{} {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		settings := make(map[string]interface{}, v.NumField())
+		addStructSettings(settings, v)
+		return settings
+	case reflect.Map:
+		settings := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			settings[fmt.Sprint(iter.Key().Interface())] = settingsValue(iter.Value())
+		}
+		return settings
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			values[i] = settingsValue(v.Index(i))
+		}
+		return values
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return settingsValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
+
+// addStructSettings adds the exported fields of a struct to settings under
+// their mapstructure names, merging squashed embedded structs into it
+func addStructSettings(settings map[string]interface{}, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if options == "squash" && v.Field(i).Kind() == reflect.Struct {
+			addStructSettings(settings, v.Field(i))
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		settings[name] = settingsValue(v.Field(i))
+	}
+}
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"go.uber.org/zap"
+)
+
+// AuditEntry records a single mutating tool call
+type AuditEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Tool        string    `json:"tool"`
+	DBName      string    `json:"db_name,omitempty"`
+	DocumentIDs []string  `json:"document_ids,omitempty"`
+	RequestID   string    `json:"request_id,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Duration    string    `json:"duration"`
+}
+
+// AuditFilter selects entries returned by the audit log
+type AuditFilter struct {
+	Tool      string
+	DBName    string
+	RequestID string
+	Since     time.Time
+	Limit     int
+}
+
+// auditLog keeps the most recent audit entries in memory and optionally
+// appends every entry to a JSONL file
+type auditLog struct {
+	mutex      sync.Mutex
+	entries    []AuditEntry
+	maxEntries int
+	file       *os.File
+	encoder    *json.Encoder
+}
+
+// newAuditLog creates an audit log for the configured sink
+func newAuditLog(cfg config.AuditConfig) (*auditLog, error) {
+	log := &auditLog{maxEntries: cfg.MaxEntries}
+
+	if cfg.Sink == config.AuditSinkFile {
+		file, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file: %w", err)
+		}
+		log.file = file
+		log.encoder = json.NewEncoder(file)
+	}
+
+	return log, nil
+}
+
+// record appends an entry, dropping the oldest in-memory entry when full
+func (a *auditLog) record(entry AuditEntry) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.entries = append(a.entries, entry)
+	if len(a.entries) > a.maxEntries {
+		a.entries = a.entries[len(a.entries)-a.maxEntries:]
+	}
+
+	if a.encoder != nil {
+		if err := a.encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write audit entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// query returns matching entries, newest first
+func (a *auditLog) query(filter AuditFilter) []AuditEntry {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	matches := make([]AuditEntry, 0)
+	for i := len(a.entries) - 1; i >= 0; i-- {
+		entry := a.entries[i]
+		if filter.Tool != "" && entry.Tool != filter.Tool {
+			continue
+		}
+		if filter.DBName != "" && entry.DBName != filter.DBName {
+			continue
+		}
+		if filter.RequestID != "" && entry.RequestID != filter.RequestID {
+			continue
+		}
+		if !filter.Since.IsZero() && entry.Timestamp.Before(filter.Since) {
+			continue
+		}
+
+		matches = append(matches, entry)
+		if filter.Limit > 0 && len(matches) >= filter.Limit {
+			break
+		}
+	}
+	return matches
+}
+
+// close closes the file sink, if any
+func (a *auditLog) close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.file == nil {
+		return nil
+	}
+	err := a.file.Close()
+	a.file = nil
+	a.encoder = nil
+	return err
+}
+
+// auditMiddleware records every call to a mutating tool, successful or not
+func (s *Server) auditMiddleware(next ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		name := ToolNameFromContext(ctx)
+		tool, exists := s.GetTool(name)
+		if !exists || !tool.Mutating {
+			return next(ctx, args)
+		}
+
+		start := time.Now()
+		result, err := next(ctx, args)
+
+		entry := AuditEntry{
+			Timestamp:   start.UTC(),
+			Tool:        name,
+			DBName:      auditDBName(args),
+			DocumentIDs: auditDocumentIDs(args),
+			RequestID:   RequestIDFromContext(ctx),
+			Status:      "success",
+			Duration:    time.Since(start).String(),
+		}
+		if err != nil {
+			entry.Status = "error"
+			entry.Error = err.Error()
+		}
+
+		if recordErr := s.auditLog.record(entry); recordErr != nil {
+			s.logger.Error("Failed to record audit entry",
+				zap.String("tool", name),
+				zap.Error(recordErr))
+		}
+
+		return result, err
+	}
+}
+
+// auditDBName returns the database a tool call modified
+func auditDBName(args map[string]interface{}) string {
+	if dbName, ok := args["db_name"].(string); ok {
+		return dbName
+	}
+	if target, ok := args["target_db"].(string); ok {
+		return target
+	}
+	return ""
+}
+
+// auditDocumentIDs returns the document IDs named in a tool call's arguments
+func auditDocumentIDs(args map[string]interface{}) []string {
+	var ids []string
+	if id, ok := args["document_id"].(string); ok {
+		ids = append(ids, id)
+	}
+	if list, ok := args["document_ids"].([]interface{}); ok {
+		for _, item := range list {
+			if id, ok := item.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// maxBatchQueries caps the number of queries in one batch_query call
+const maxBatchQueries = 50
+
+// batchQueryParallelism bounds how many queries of a batch run at once
+const batchQueryParallelism = 4
+
+// batchQuery is one validated query of a batch_query call
+type batchQuery struct {
+	query  string
+	text   string // the query after preprocessing, as embedded and searched
+	limit  int
+	note   string
+	filter vectordb.MetadataFilter
+}
+
+// handleBatchQuery handles the batch_query tool. Every query is validated
+// before any runs; the queries then search concurrently and the result sets
+// are returned in input order. A failing query reports its error in its own
+// slot without failing the batch.
+func (s *Server) handleBatchQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	raw, ok := args["queries"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, invalidArgumentf("queries is required and must be a non-empty array")
+	}
+	if len(raw) > maxBatchQueries {
+		return nil, invalidArgumentf("queries has %d entries; at most %d are allowed", len(raw), maxBatchQueries)
+	}
+
+	queries := make([]batchQuery, len(raw))
+	for i, item := range raw {
+		query, err := s.batchQueryFromArgs(item)
+		if err != nil {
+			return nil, invalidArgumentf("queries[%d]: %v", i, err)
+		}
+		queries[i] = query
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+	defer cancel()
+
+	// With the embedding provider down, the queries are answered by keyword
+	// search instead, including when embedding them is what found it down
+	keyword := s.keywordFallback()
+	var vectors map[string][]float64
+	if !keyword {
+		vectors, err = s.embedQueries(queryCtx, queries)
+		if err != nil && !s.keywordFallback() {
+			return nil, err
+		}
+		keyword = err != nil
+	}
+
+	results := make([]map[string]interface{}, len(queries))
+	semaphore := make(chan struct{}, batchQueryParallelism)
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query batchQuery) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			if keyword {
+				results[i] = s.runKeywordBatchQuery(queryCtx, db, query)
+				return
+			}
+			results[i] = runBatchQuery(queryCtx, db, query, vectors[query.text])
+		}(i, query)
+	}
+	wg.Wait()
+
+	s.toolLogger(ctx).Info("Executed batch query",
+		zap.String("db_name", dbName),
+		zap.Int("queries", len(queries)),
+		zap.Int("embedded", len(vectors)),
+		zap.Bool("keyword", keyword))
+
+	response := map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	}
+	if keyword {
+		response["search_mode"] = "keyword"
+		response["fallback_note"] = keywordFallbackNote
+	}
+	return response, nil
+}
+
+// batchQueryFromArgs validates one entry of the queries argument
+func (s *Server) batchQueryFromArgs(item interface{}) (batchQuery, error) {
+	args, ok := item.(map[string]interface{})
+	if !ok {
+		return batchQuery{}, fmt.Errorf("must be an object")
+	}
+
+	query, ok := args["query"].(string)
+	if !ok || query == "" {
+		return batchQuery{}, fmt.Errorf("query is required and must be a string")
+	}
+
+	limit, note, err := parseLimit(args, s.config.MCP.Search, 5)
+	if err != nil {
+		return batchQuery{}, err
+	}
+
+	filter, err := parseFilter(args)
+	if err != nil {
+		return batchQuery{}, err
+	}
+
+	return batchQuery{query: query, text: s.preprocessQuery(query), limit: limit, note: note, filter: filter}, nil
+}
+
+// embedQueries embeds each distinct preprocessed query text once with the configured
+// embedder, so repeated queries share a single embedding call. Without an
+// embedder it returns nil and the backend embeds each query itself.
+func (s *Server) embedQueries(ctx context.Context, queries []batchQuery) (map[string][]float64, error) {
+	if s.embedder == nil {
+		return nil, nil
+	}
+
+	texts := make([]string, 0, len(queries))
+	seen := make(map[string]bool, len(queries))
+	for _, query := range queries {
+		if !seen[query.text] {
+			seen[query.text] = true
+			texts = append(texts, query.text)
+		}
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("embedding"))
+	defer cancel()
+
+	embeddings, err := s.embedder.Embed(embedCtx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed queries: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("failed to embed queries: got %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+
+	vectors := make(map[string][]float64, len(texts))
+	for i, text := range texts {
+		vectors[text] = embeddings[i]
+	}
+	return vectors, nil
+}
+
+// runBatchQuery searches for one query of a batch and renders its result set
+func runBatchQuery(ctx context.Context, db vectordb.VectorDatabase, query batchQuery, vector []float64) map[string]interface{} {
+	searchCtx := withFilter(ctx, query.filter)
+	if vector != nil {
+		searchCtx = vectordb.WithQueryVector(searchCtx, vector)
+	}
+
+	entry := map[string]interface{}{"query": query.query}
+	results, err := db.Search(searchCtx, query.text, query.limit, "")
+	if err != nil {
+		entry["error"] = fmt.Sprintf("failed to search vector database: %v", err)
+		return entry
+	}
+
+	results = stripResultVectors(results)
+	entry["results"] = results
+	entry["count"] = len(results)
+	if query.note != "" {
+		entry["note"] = query.note
+	}
+	return entry
+}
+
+// runKeywordBatchQuery answers one query of a batch by keyword search while
+// the embedding provider is down
+func (s *Server) runKeywordBatchQuery(ctx context.Context, db vectordb.VectorDatabase, query batchQuery) map[string]interface{} {
+	entry := map[string]interface{}{"query": query.query}
+	results, err := s.keywordSearch(withFilter(ctx, query.filter), db, "", query.query, query.limit)
+	if err != nil {
+		entry["error"] = err.Error()
+		return entry
+	}
+
+	results = stripResultVectors(results)
+	entry["results"] = results
+	entry["count"] = len(results)
+	if query.note != "" {
+		entry["note"] = query.note
+	}
+	return entry
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+)
+
+// queryCache is an in-memory LRU cache of query results with per-entry expiry
+type queryCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*list.Element
+	order   *list.List
+	hits    uint64
+	misses  uint64
+}
+
+// cacheEntry is a single cached query result
+type cacheEntry struct {
+	key       string
+	dbName    string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// newQueryCache creates a query cache holding at most maxSize entries for ttl each
+func newQueryCache(ttl time.Duration, maxSize int) *queryCache {
+	return &queryCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// queryCacheKey hashes the parameters that identify a query result
+func queryCacheKey(dbName, collectionName, query string, limit int, ranking rankOptions, fields []string, filter vectordb.MetadataFilter) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%t\x00%s\x00%g\x00%s\x00%s",
+		dbName, collectionName, query, limit, ranking.rerank, ranking.dedupeBy, ranking.recencyWeight, strings.Join(fields, ","), filter.MilvusExpr())))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the cached value for key if present and not expired
+func (c *queryCache) get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.hits++
+	return entry.value, true
+}
+
+// put stores a value for key, evicting the least recently used entry when full
+func (c *queryCache) put(dbName, key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, exists := c.entries[key]; exists {
+		entry := elem.Value.(*cacheEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		dbName:    dbName,
+		value:     value,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+
+	for c.order.Len() > c.maxSize {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// invalidate drops every cached result for a database
+func (c *queryCache) invalidate(dbName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*cacheEntry).dbName == dbName {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+// stats returns cache hit/miss counters and the current size
+func (c *queryCache) stats() map[string]interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return map[string]interface{}{
+		"hits":    c.hits,
+		"misses":  c.misses,
+		"entries": c.order.Len(),
+	}
+}
+
+// removeElement removes an entry; the caller must hold the mutex
+func (c *queryCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// clientLimiter caps the tool calls each client has in flight, so one client
+// opening many parallel calls cannot monopolize the server. A call beyond a
+// client's cap waits up to waitTimeout for one of its calls to finish, then
+// fails with ErrTooManyRequests; a zero waitTimeout fails at once.
+type clientLimiter struct {
+	mutex       sync.Mutex
+	maxInFlight int
+	waitTimeout time.Duration
+	header      string
+	clients     map[string]*clientSlots
+}
+
+// clientSlots is one client's semaphore. users counts the calls holding or
+// waiting for a slot, so idle clients can be forgotten.
+type clientSlots struct {
+	slots chan struct{}
+	users int
+}
+
+// newClientLimiter creates a limiter configured by cfg
+func newClientLimiter(cfg config.PerClientConcurrencyConfig) *clientLimiter {
+	return &clientLimiter{
+		maxInFlight: cfg.MaxInFlight,
+		waitTimeout: cfg.WaitTimeout,
+		header:      cfg.ClientHeader,
+		clients:     make(map[string]*clientSlots),
+	}
+}
+
+// clientKey identifies the client making r: the configured client header
+// when the request carries it, otherwise the remote IP address
+func (l *clientLimiter) clientKey(r *http.Request) string {
+	if l.header != "" {
+		if client := r.Header.Get(l.header); client != "" {
+			return "header:" + client
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// acquire takes a slot for client, waiting up to the wait timeout or until
+// ctx is done. The caller must call release after a nil error.
+func (l *clientLimiter) acquire(ctx context.Context, client string) error {
+	l.mutex.Lock()
+	entry, exists := l.clients[client]
+	if !exists {
+		entry = &clientSlots{slots: make(chan struct{}, l.maxInFlight)}
+		l.clients[client] = entry
+	}
+	entry.users++
+	l.mutex.Unlock()
+
+	select {
+	case entry.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.waitTimeout > 0 {
+		timer := time.NewTimer(l.waitTimeout)
+		defer timer.Stop()
+
+		select {
+		case entry.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			l.forget(client, entry)
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	l.forget(client, entry)
+	return fmt.Errorf("%w: client has %d tool calls in flight", ErrTooManyRequests, l.maxInFlight)
+}
+
+// release frees a slot taken by acquire
+func (l *clientLimiter) release(client string) {
+	l.mutex.Lock()
+	entry := l.clients[client]
+	l.mutex.Unlock()
+
+	<-entry.slots
+	l.forget(client, entry)
+}
+
+// forget drops a call from client's users, removing the client once it has none
+func (l *clientLimiter) forget(client string, entry *clientSlots) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry.users--
+	if entry.users == 0 {
+		delete(l.clients, client)
+	}
+}
+
+// beginClientCall takes a per-client slot for a tool call made by r, returning
+// the function that releases it. Without a per-client cap it does nothing.
+func (s *Server) beginClientCall(r *http.Request) (func(), error) {
+	if s.clientLimiter == nil {
+		return func() {}, nil
+	}
+
+	client := s.clientLimiter.clientKey(r)
+	if err := s.clientLimiter.acquire(r.Context(), client); err != nil {
+		return nil, err
+	}
+	return func() { s.clientLimiter.release(client) }, nil
+}
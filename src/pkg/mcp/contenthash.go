@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// dedupeContent applies mcp.content_dedup to documents bound for db. Every
+// document is stamped with its content hash when hashes are stored. When
+// duplicates are dropped, a document is dropped if an earlier document of the
+// batch or a live stored document has the same hash; under merge_urls its URL
+// is added to the stored document's duplicate_urls. It returns the documents
+// to write and how many were dropped.
+func (s *Server) dedupeContent(ctx context.Context, db vectordb.VectorDatabase, docs []vectordb.Document) ([]vectordb.Document, int, error) {
+	dedup := s.config.MCP.ContentDedup
+	if !dedup.StoreHash && !dedup.Enabled() {
+		return docs, 0, nil
+	}
+
+	kept := make([]vectordb.Document, 0, len(docs))
+	batch := make(map[string]int, len(docs)) // hash to index in kept
+	for _, doc := range docs {
+		doc = vectordb.WithContentHash(doc)
+		if !dedup.Enabled() {
+			kept = append(kept, doc)
+			continue
+		}
+
+		hash := doc.Metadata[vectordb.MetadataContentHash].(string)
+		if i, seen := batch[hash]; seen {
+			if dedup.Mode == config.ContentDedupMergeURLs {
+				kept[i], _ = vectordb.WithDuplicateURL(kept[i], doc.URL)
+			}
+			continue
+		}
+
+		stored, found, err := findByContentHash(ctx, db, doc)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !found {
+			batch[hash] = len(kept)
+			kept = append(kept, doc)
+			continue
+		}
+
+		if dedup.Mode == config.ContentDedupMergeURLs {
+			if merged, changed := vectordb.WithDuplicateURL(stored, doc.URL); changed {
+				if err := db.UpdateDocument(ctx, merged); err != nil {
+					return nil, 0, fmt.Errorf("failed to merge duplicate URL into document '%s': %w", stored.ID, err)
+				}
+			}
+		}
+		s.logger.Debug("Dropped duplicate document",
+			zap.String("collection", db.CollectionName()),
+			zap.String("url", doc.URL),
+			zap.String("duplicate_of", stored.ID))
+	}
+	return kept, len(docs) - len(kept), nil
+}
+
+// findByContentHash looks up a live document in db with the same content hash
+// as doc, through a pre-filtered search for its text or vector. A collection
+// that does not exist yet holds no duplicates.
+func findByContentHash(ctx context.Context, db vectordb.VectorDatabase, doc vectordb.Document) (vectordb.Document, bool, error) {
+	filter := vectordb.MetadataFilter{vectordb.MetadataContentHash: doc.Metadata[vectordb.MetadataContentHash]}
+	searchCtx := vectordb.WithFilterStrategy(vectordb.WithMetadataFilter(ctx, filter), vectordb.FilterStrategyPre)
+	if len(doc.Vector) > 0 {
+		searchCtx = vectordb.WithQueryVector(searchCtx, doc.Vector)
+	}
+
+	results, err := db.Search(searchCtx, doc.Text, 1, "")
+	if errors.Is(err, vectordb.ErrCollectionNotFound) {
+		return vectordb.Document{}, false, nil
+	}
+	if err != nil {
+		return vectordb.Document{}, false, fmt.Errorf("failed to look up duplicates: %w", err)
+	}
+	for _, result := range results {
+		if !vectordb.IsTombstoned(result.Document) {
+			return result.Document, true, nil
+		}
+	}
+	return vectordb.Document{}, false, nil
+}
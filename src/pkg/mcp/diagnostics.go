@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// embedTexts embeds texts with the configured provider after preprocessing
+// them as documents and queries are, normalizing the vectors when the
+// embedding profile stores them normalized
+func (s *Server) embedTexts(ctx context.Context, texts []string) ([][]float64, error) {
+	if s.embedder == nil {
+		return nil, invalidArgumentf("no embedding provider is configured; set mcp.embedding.provider")
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("embedding"))
+	defer cancel()
+
+	embeddings, err := s.embedder.Embed(embedCtx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("failed to embed text: got %d embeddings for %d texts", len(embeddings), len(texts))
+	}
+
+	if s.config.MCP.Embedding.RequiresNormalization() {
+		for i, embedding := range embeddings {
+			embeddings[i] = vectordb.NormalizeVector(embedding)
+		}
+	}
+	return embeddings, nil
+}
+
+// handleEmbedText handles the embed_text tool, returning the vector the
+// configured provider produces for a text without writing anything
+func (s *Server) handleEmbedText(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	text, ok := args["text"].(string)
+	if !ok || text == "" {
+		return nil, invalidArgumentf("text is required and must be a non-empty string")
+	}
+
+	embedText := s.preprocessQuery(text)
+	embeddings, err := s.embedTexts(ctx, []string{embedText})
+	if err != nil {
+		return nil, err
+	}
+
+	s.toolLogger(ctx).Info("Embedded text for diagnostics",
+		zap.Int("length", len(text)),
+		zap.Int("dimension", len(embeddings[0])))
+
+	response := map[string]interface{}{
+		"text":       text,
+		"provider":   s.config.MCP.Embedding.Provider,
+		"model":      s.config.MCP.Embedding.Model,
+		"dimension":  len(embeddings[0]),
+		"normalized": s.config.MCP.Embedding.RequiresNormalization(),
+		"embedding":  embeddings[0],
+	}
+	if embedText != text {
+		response["embedded_text"] = embedText
+	}
+	return response, nil
+}
+
+// handleCompareTexts handles the compare_texts tool, embedding two texts and
+// scoring them under the metric of a collection, the metric argument, or the
+// configured metric, in that order
+func (s *Server) handleCompareTexts(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	textA, ok := args["text_a"].(string)
+	if !ok || textA == "" {
+		return nil, invalidArgumentf("text_a is required and must be a non-empty string")
+	}
+	textB, ok := args["text_b"].(string)
+	if !ok || textB == "" {
+		return nil, invalidArgumentf("text_b is required and must be a non-empty string")
+	}
+
+	metric, metricSource := s.config.MCP.Embedding.EffectiveMetric(), "config"
+	if m, ok := args["metric"].(string); ok && m != "" {
+		switch strings.ToLower(m) {
+		case config.MetricCosine, config.MetricIP, config.MetricL2:
+			metric, metricSource = strings.ToLower(m), "argument"
+		default:
+			return nil, invalidArgumentf("metric must be one of cosine, ip, or l2")
+		}
+	}
+
+	if dbName, ok := args["db_name"].(string); ok && dbName != "" {
+		db, err := s.getDatabaseByName(dbName)
+		if err != nil {
+			return nil, err
+		}
+		collectionName, _ := args["collection_name"].(string)
+		db, collectionName = s.resolveCollection(dbName, db, collectionName)
+		if collectionName == "" {
+			collectionName = db.CollectionName()
+		}
+
+		infoCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("get_collection_info"))
+		info, err := db.GetCollectionInfo(infoCtx, collectionName)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection info: %w", err)
+		}
+		if collectionMetric, ok := vectordb.CollectionMetric(info); ok {
+			metric, metricSource = collectionMetric, "collection"
+		}
+	}
+
+	embeddings, err := s.embedTexts(ctx, []string{s.preprocessQuery(textA), s.preprocessQuery(textB)})
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := vectordb.Similarity(metric, embeddings[0], embeddings[1])
+	if err != nil {
+		return nil, err
+	}
+
+	s.toolLogger(ctx).Info("Compared texts for diagnostics",
+		zap.String("metric", metric),
+		zap.Float64("score", score))
+
+	return map[string]interface{}{
+		"metric":           metric,
+		"metric_source":    metricSource,
+		"score":            score,
+		"higher_is_closer": metric != config.MetricL2,
+		"dimension":        len(embeddings[0]),
+	}, nil
+}
@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+)
+
+// uncachedEmbedKey marks an embed call that must reach the provider
+type uncachedEmbedKey struct{}
+
+// withoutEmbeddingCache returns a context whose embed calls skip the
+// embedding cache, for warmups and probes that check the provider itself
+func withoutEmbeddingCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedEmbedKey{}, true)
+}
+
+// embedFlight is one in-progress provider request for a text, shared by every
+// caller embedding the same text meanwhile
+type embedFlight struct {
+	done   chan struct{}
+	vector []float64
+	err    error
+}
+
+// embedCacheEntry is a single cached embedding
+type embedCacheEntry struct {
+	key       string
+	vector    []float64
+	expiresAt time.Time
+}
+
+// coalescingEmbedder sends each distinct text to the wrapped embedder once
+// while a request for it is in flight, and with mcp.embedding.cache enabled
+// answers recently embedded texts from an LRU cache. Texts are keyed by model
+// and a hash of the text.
+type coalescingEmbedder struct {
+	next  vectordb.Embedder
+	model string
+	cache config.EmbeddingCacheConfig
+
+	mutex     sync.Mutex
+	inflight  map[string]*embedFlight
+	entries   map[string]*list.Element
+	order     *list.List
+	hits      uint64
+	misses    uint64
+	coalesced uint64
+}
+
+// newCoalescingEmbedder wraps next for the configured model and cache
+func newCoalescingEmbedder(next vectordb.Embedder, cfg config.EmbeddingConfig) *coalescingEmbedder {
+	return &coalescingEmbedder{
+		next:     next,
+		model:    cfg.Model,
+		cache:    cfg.Cache,
+		inflight: make(map[string]*embedFlight),
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// embedCacheKey hashes the model and text an embedding is for
+func embedCacheKey(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Embed returns one embedding per text, in input order. Cached texts are
+// answered without a request, texts already in flight wait for that request,
+// and the rest are embedded in a single call whose outcome, including a
+// failure or cancellation, is shared with every caller waiting on it.
+func (c *coalescingEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	useCache := c.cache.Enabled && ctx.Value(uncachedEmbedKey{}) == nil
+
+	embeddings := make([][]float64, len(texts))
+	waits := make(map[int]*embedFlight)
+	var leadKeys []string
+	var leadTexts []string
+
+	c.mutex.Lock()
+	for i, text := range texts {
+		key := embedCacheKey(c.model, text)
+		if useCache {
+			if vector, ok := c.cached(key); ok {
+				embeddings[i] = vector
+				continue
+			}
+		}
+		if flight, ok := c.inflight[key]; ok {
+			c.coalesced++
+			waits[i] = flight
+			continue
+		}
+		flight := &embedFlight{done: make(chan struct{})}
+		c.inflight[key] = flight
+		waits[i] = flight
+		leadKeys = append(leadKeys, key)
+		leadTexts = append(leadTexts, text)
+	}
+	c.mutex.Unlock()
+
+	if len(leadTexts) > 0 {
+		c.lead(ctx, leadKeys, leadTexts)
+	}
+
+	for i, flight := range waits {
+		select {
+		case <-flight.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if flight.err != nil {
+			return nil, flight.err
+		}
+		embeddings[i] = append([]float64(nil), flight.vector...)
+	}
+	return embeddings, nil
+}
+
+// lead embeds texts with the wrapped embedder and completes their flights
+func (c *coalescingEmbedder) lead(ctx context.Context, keys, texts []string) {
+	vectors, err := c.next.Embed(ctx, texts)
+	if err == nil && len(vectors) != len(texts) {
+		err = fmt.Errorf("got %d embeddings for %d texts", len(vectors), len(texts))
+	}
+
+	c.mutex.Lock()
+	flights := make([]*embedFlight, len(keys))
+	for j, key := range keys {
+		flights[j] = c.inflight[key]
+		delete(c.inflight, key)
+		if err != nil {
+			flights[j].err = err
+			continue
+		}
+		flights[j].vector = vectors[j]
+		if c.cache.Enabled {
+			c.store(key, vectors[j])
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, flight := range flights {
+		close(flight.done)
+	}
+}
+
+// cached returns a copy of the unexpired embedding for key; the caller must
+// hold the mutex
+func (c *coalescingEmbedder) cached(key string) ([]float64, bool) {
+	elem, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*embedCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.hits++
+	return append([]float64(nil), entry.vector...), true
+}
+
+// store caches the embedding for key, evicting the least recently used entry
+// when full; the caller must hold the mutex
+func (c *coalescingEmbedder) store(key string, vector []float64) {
+	if elem, exists := c.entries[key]; exists {
+		entry := elem.Value.(*embedCacheEntry)
+		entry.vector = vector
+		entry.expiresAt = time.Now().Add(c.cache.TTL)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&embedCacheEntry{
+		key:       key,
+		vector:    vector,
+		expiresAt: time.Now().Add(c.cache.TTL),
+	})
+
+	for c.order.Len() > c.cache.MaxSize {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// stats returns coalescing and cache counters
+func (c *coalescingEmbedder) stats() map[string]interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := map[string]interface{}{
+		"coalesced": c.coalesced,
+	}
+	if c.cache.Enabled {
+		stats["cache"] = map[string]interface{}{
+			"hits":    c.hits,
+			"misses":  c.misses,
+			"entries": c.order.Len(),
+		}
+	}
+	return stats
+}
+
+// removeElement removes a cache entry; the caller must hold the mutex
+func (c *coalescingEmbedder) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*embedCacheEntry).key)
+}
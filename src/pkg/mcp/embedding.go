@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// warmupText is embedded at startup to exercise the embedding provider
+const warmupText = "warmup"
+
+// WarmupEmbedding issues a tiny embed call so connection setup and model
+// loading happen at startup and a bad key or model is reported immediately.
+// It does nothing unless mcp.embedding.warmup is set and a provider is
+// configured. Failures are logged, and returned only when warmup_strict is set.
+func (s *Server) WarmupEmbedding(ctx context.Context) error {
+	if !s.config.MCP.Embedding.Warmup || s.embedder == nil {
+		return nil
+	}
+
+	warmupCtx, cancel := context.WithTimeout(withoutEmbeddingCache(ctx), s.config.GetTimeout("embedding"))
+	defer cancel()
+
+	start := time.Now()
+	embeddings, err := s.embedder.Embed(warmupCtx, []string{warmupText})
+	if err == nil {
+		err = s.checkEmbeddingDimension(embeddings[0])
+	}
+	if err != nil {
+		err = fmt.Errorf("embedding warmup failed: %w", err)
+		if s.config.MCP.Embedding.WarmupStrict {
+			return err
+		}
+		s.logger.Warn("Embedding warmup failed; embedding calls may fail",
+			zap.String("provider", s.config.MCP.Embedding.Provider),
+			zap.String("model", s.config.MCP.Embedding.Model),
+			zap.Error(err))
+		return nil
+	}
+
+	s.logger.Info("Embedding provider warmed up",
+		zap.String("provider", s.config.MCP.Embedding.Provider),
+		zap.String("model", s.config.MCP.Embedding.Model),
+		zap.Int("dimension", len(embeddings[0])),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// checkEmbeddingDimension verifies an embedding matches the configured vector size
+func (s *Server) checkEmbeddingDimension(embedding []float64) error {
+	expected := s.config.MCP.Embedding.VectorSize
+	if expected > 0 && len(embedding) != expected {
+		return fmt.Errorf("model returned %d dimensions but vector_size is %d", len(embedding), expected)
+	}
+	return nil
+}
+
+// checkEmbeddingModel compares the embedding recorded in a collection at
+// setup with the active one. A mismatch fails with ErrEmbeddingModelMismatch,
+// or is only logged when mcp.embedding.on_model_mismatch is warn. Collections
+// with no record, or whose info cannot be read, pass.
+func (s *Server) checkEmbeddingModel(ctx context.Context, db vectordb.VectorDatabase, collectionName string) error {
+	field := s.config.MCP.Embedding.ModelField
+	if field == "" {
+		return nil
+	}
+
+	info, err := db.GetCollectionInfo(ctx, collectionName)
+	if err != nil {
+		return nil
+	}
+	recorded, ok := vectordb.CollectionEmbeddingModel(info, field)
+	active := vectordb.ActiveEmbeddingModel(s.config)
+	if !ok || recorded == active {
+		return nil
+	}
+
+	err = fmt.Errorf("%w: collection '%s' was set up with %s but the active embedding is %s; vectors from different models are not comparable",
+		ErrEmbeddingModelMismatch, collectionName, recorded, active)
+	if s.config.MCP.Embedding.OnModelMismatch == config.ModelMismatchWarn {
+		s.logger.Warn("Collection was set up with a different embedding model",
+			zap.String("collection", collectionName),
+			zap.Error(err))
+		return nil
+	}
+	return err
+}
+
+// checkWriteEmbeddingModel runs checkEmbeddingModel for a database's
+// collection before its first write. Passing databases are remembered so
+// later writes skip the collection info lookup.
+func (s *Server) checkWriteEmbeddingModel(ctx context.Context, db vectordb.VectorDatabase) error {
+	s.modelMutex.Lock()
+	checked := s.modelChecked[db]
+	s.modelMutex.Unlock()
+	if checked {
+		return nil
+	}
+
+	if err := s.checkEmbeddingModel(ctx, db, db.CollectionName()); err != nil {
+		return err
+	}
+
+	s.modelMutex.Lock()
+	s.modelChecked[db] = true
+	s.modelMutex.Unlock()
+	return nil
+}
+
+// probeEmbedding embeds a short text to check that the provider answers and
+// describes what came back: its dimension, whether it matches vector_size,
+// and whether the vector is all zeros
+func (s *Server) probeEmbedding(ctx context.Context) map[string]interface{} {
+	probeCtx, cancel := context.WithTimeout(withoutEmbeddingCache(ctx), s.config.GetTimeout("embedding"))
+	defer cancel()
+
+	start := time.Now()
+	embeddings, err := s.embedder.Embed(probeCtx, []string{warmupText})
+	probe := map[string]interface{}{
+		"latency_ms": time.Since(start).Milliseconds(),
+	}
+	if err == nil && len(embeddings) != 1 {
+		err = fmt.Errorf("got %d embeddings for 1 text", len(embeddings))
+	}
+	if err != nil {
+		probe["reachable"] = false
+		probe["error"] = err.Error()
+		return probe
+	}
+
+	probe["reachable"] = true
+	probe["dimension"] = len(embeddings[0])
+	probe["dimension_matches"] = s.checkEmbeddingDimension(embeddings[0]) == nil
+	probe["all_zeros"] = vectordb.IsZeroVector(embeddings[0])
+	return probe
+}
+
+// handleGetEmbeddingInfo handles the get_embedding_info tool, describing the
+// active embedding provider and, unless check is false, whether it answers
+func (s *Server) handleGetEmbeddingInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	check := true
+	if c, ok := args["check"].(bool); ok {
+		check = c
+	}
+
+	cfg := s.config.MCP.Embedding
+	profile, source := cfg.ResolvedProfile()
+	provider := strings.ToLower(cfg.Provider)
+	if s.embedder == nil {
+		provider = "none"
+	}
+
+	response := map[string]interface{}{
+		"provider":            provider,
+		"model":               cfg.Model,
+		"dimension":           cfg.VectorSize,
+		"supported_providers": []string{vectordb.EmbeddingProviderOpenAI, vectordb.EmbeddingProviderCustomLocal},
+		"profile": map[string]interface{}{
+			"metric":    profile.Metric,
+			"normalize": profile.Normalize,
+			"source":    source,
+		},
+		"config": s.config.Redacted().MCP.Embedding,
+	}
+
+	if s.embedder == nil {
+		response["note"] = "no embedding provider is configured; documents are embedded by the vector database or must carry vectors"
+		return response, nil
+	}
+
+	if check {
+		probe := s.probeEmbedding(ctx)
+		response["check"] = probe
+
+		s.toolLogger(ctx).Info("Checked embedding provider",
+			zap.String("provider", provider),
+			zap.String("model", cfg.Model),
+			zap.Any("reachable", probe["reachable"]))
+	}
+
+	// The state is read after the check so it reflects the check's outcome
+	state := s.embeddingState.stats()
+	state["keyword_search_fallback"] = cfg.Fallback.KeywordSearch
+	state["queue_writes_fallback"] = cfg.Fallback.QueueWrites
+	if s.embeddingQueue != nil {
+		state["queue"] = s.embeddingQueue.stats()
+	}
+	response["state"] = state
+	return response, nil
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+)
+
+var (
+	// ErrToolNotFound is returned when calling a tool that is not registered
+	ErrToolNotFound = errors.New("tool not found")
+
+	// ErrToolDisabled is returned when calling a tool hidden by mcp.enabled_tools or mcp.disabled_tools
+	ErrToolDisabled = errors.New("tool disabled")
+
+	// ErrPromptNotFound is returned when prompts/get names a prompt that does not exist
+	ErrPromptNotFound = errors.New("prompt not found")
+
+	// ErrDatabaseNotFound is returned when a tool names a vector database that is not registered
+	ErrDatabaseNotFound = errors.New("vector database not found")
+
+	// ErrDatabaseExists is returned when creating a vector database under a name already in use
+	ErrDatabaseExists = errors.New("vector database already exists")
+
+	// ErrReadOnly is returned when a mutating tool targets a vector database created read-only
+	ErrReadOnly = errors.New("vector database is read-only")
+
+	// ErrIdempotencyConflict is returned when an idempotency_key is reused for a write with different arguments
+	ErrIdempotencyConflict = errors.New("idempotency key conflict")
+
+	// ErrMigrationInProgress is returned when a tool conflicts with a collection migration that is running or awaiting confirmation
+	ErrMigrationInProgress = errors.New("collection migration in progress")
+
+	// ErrSnapshotExpired is returned when a list or export names a snapshot that expired or never existed
+	ErrSnapshotExpired = errors.New("snapshot not found or expired")
+
+	// ErrEmbeddingUnavailable is returned when a call needs the embedding provider while it is down and no fallback covers the call
+	ErrEmbeddingUnavailable = errors.New("embedding provider unavailable")
+
+	// ErrEmbeddingModelMismatch is returned when a collection was set up with a different embedding provider, model, or dimension than the active one
+	ErrEmbeddingModelMismatch = errors.New("embedding model mismatch")
+
+	// ErrTooManyRequests is returned when a client already has mcp.per_client_concurrency tool calls in flight
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrInvalidArgument matches errors caused by missing or malformed tool arguments
+	ErrInvalidArgument = errors.New("invalid argument")
+)
+
+// argumentError marks an error as caused by the caller's arguments while
+// keeping its original message
+type argumentError struct {
+	err error
+}
+
+func (e *argumentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *argumentError) Unwrap() error {
+	return e.err
+}
+
+// Is reports argument errors as ErrInvalidArgument
+func (e *argumentError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
+// invalidArgumentf formats an error that matches ErrInvalidArgument
+func invalidArgumentf(format string, args ...interface{}) error {
+	return &argumentError{err: fmt.Errorf(format, args...)}
+}
+
+// invalidArgument marks err as matching ErrInvalidArgument
+func invalidArgument(err error) error {
+	return &argumentError{err: err}
+}
+
+// statusForError maps a tool error to the HTTP status code that best describes it
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, ErrToolNotFound),
+		errors.Is(err, ErrPromptNotFound),
+		errors.Is(err, ErrDatabaseNotFound),
+		errors.Is(err, vectordb.ErrCollectionNotFound),
+		errors.Is(err, vectordb.ErrDocumentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrToolDisabled),
+		errors.Is(err, ErrReadOnly):
+		return http.StatusForbidden
+	case errors.Is(err, ErrDatabaseExists),
+		errors.Is(err, vectordb.ErrCollectionExists),
+		errors.Is(err, ErrIdempotencyConflict),
+		errors.Is(err, ErrMigrationInProgress),
+		errors.Is(err, ErrEmbeddingModelMismatch):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidArgument),
+		errors.Is(err, vectordb.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSnapshotExpired):
+		return http.StatusGone
+	case errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err, vectordb.ErrBackendUnavailable),
+		errors.Is(err, vectordb.ErrDatabaseClosed),
+		errors.Is(err, vectordb.ErrCollectionNotReady),
+		errors.Is(err, ErrEmbeddingUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// SetExpander installs the expander used when query is called with
+// expand=true, replacing the one built from mcp.expansion. Passing nil
+// disables expansion so the original query is embedded.
+func (s *Server) SetExpander(expander vectordb.Expander) {
+	s.expanderMutex.Lock()
+	defer s.expanderMutex.Unlock()
+
+	s.expander = expander
+}
+
+// getExpander returns the configured expander, if any
+func (s *Server) getExpander() vectordb.Expander {
+	s.expanderMutex.RLock()
+	defer s.expanderMutex.RUnlock()
+
+	return s.expander
+}
+
+// expandQuery returns the query text to embed for an expand=true call. Without
+// an expander, or when it fails, the original query is returned along with a
+// note explaining why it was not expanded.
+func (s *Server) expandQuery(ctx context.Context, query string) (string, string) {
+	expander := s.getExpander()
+	if expander == nil {
+		return query, "query expansion was requested but no expander is configured; the original query was used"
+	}
+
+	expandCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+	defer cancel()
+
+	expanded, err := expander.Expand(expandCtx, query)
+	if err != nil {
+		s.logger.Warn("Query expansion failed, using the original query",
+			zap.String("query", query),
+			zap.Error(err))
+		return query, "query expansion failed; the original query was used"
+	}
+	if expanded == "" {
+		return query, ""
+	}
+	return expanded, ""
+}
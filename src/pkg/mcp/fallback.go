@@ -0,0 +1,391 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// keywordFallbackNote explains results answered by keyword search
+const keywordFallbackNote = "the embedding provider is unavailable; results are from keyword search on document text"
+
+// embeddingState tracks whether the embedding provider is answering. It is
+// marked down after threshold consecutive failed calls and up again by the
+// next successful one.
+type embeddingState struct {
+	mutex       sync.Mutex
+	threshold   int
+	failures    int
+	down        bool
+	since       time.Time
+	lastError   string
+	lastChecked time.Time
+}
+
+// record notes the outcome of an embed call and reports whether it changed
+// the provider between up and down
+func (e *embeddingState) record(err error) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := time.Now().UTC()
+	e.lastChecked = now
+	if err == nil {
+		e.failures = 0
+		if !e.down {
+			return false
+		}
+		e.down = false
+		e.since = now
+		return true
+	}
+
+	e.failures++
+	e.lastError = err.Error()
+	if e.down || e.failures < e.threshold {
+		return false
+	}
+	e.down = true
+	e.since = now
+	return true
+}
+
+// isDown reports whether the provider is currently marked down
+func (e *embeddingState) isDown() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.down
+}
+
+// stats describes the provider's state for /health and get_embedding_info
+func (e *embeddingState) stats() map[string]interface{} {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	stats := map[string]interface{}{
+		"status":               "up",
+		"consecutive_failures": e.failures,
+	}
+	if e.down {
+		stats["status"] = "down"
+	}
+	if !e.since.IsZero() {
+		stats["since"] = e.since
+	}
+	if !e.lastChecked.IsZero() {
+		stats["last_checked"] = e.lastChecked
+	}
+	if e.lastError != "" {
+		stats["last_error"] = e.lastError
+	}
+	return stats
+}
+
+// trackedEmbedder records the outcome of every embed call in an embeddingState
+type trackedEmbedder struct {
+	next   vectordb.Embedder
+	state  *embeddingState
+	logger *zap.Logger
+}
+
+// Embed embeds texts with the wrapped embedder. A call the caller cancelled
+// says nothing about the provider and is not recorded.
+func (t *trackedEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings, err := t.next.Embed(ctx, texts)
+	if errors.Is(err, context.Canceled) {
+		return embeddings, err
+	}
+
+	if t.state.record(err) {
+		if err != nil {
+			t.logger.Warn("Embedding provider is down; degrading until it recovers", zap.Error(err))
+		} else {
+			t.logger.Info("Embedding provider recovered")
+		}
+	}
+	return embeddings, err
+}
+
+// embeddingDown reports whether the embedding provider is marked down
+func (s *Server) embeddingDown() bool {
+	return s.embeddingState != nil && s.embeddingState.isDown()
+}
+
+// keywordFallback reports whether queries should be answered by keyword search
+func (s *Server) keywordFallback() bool {
+	return s.config.MCP.Embedding.Fallback.KeywordSearch && s.embeddingDown()
+}
+
+// keywordSearch answers a query by keyword search on the text of the
+// documents in db's collection
+func (s *Server) keywordSearch(ctx context.Context, db vectordb.VectorDatabase, collectionName, query string, limit int) ([]vectordb.SearchResult, error) {
+	if collectionName != "" && collectionName != db.CollectionName() {
+		return nil, fmt.Errorf("%w: keyword search covers only the instance's collection '%s', not '%s'",
+			ErrEmbeddingUnavailable, db.CollectionName(), collectionName)
+	}
+	results, err := vectordb.KeywordSearch(ctx, db, query, limit, s.config.MCP.Embedding.Fallback.KeywordScanLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run keyword search: %w", err)
+	}
+	return results, nil
+}
+
+// queuedWrite is a write held back until the embedding provider recovers
+type queuedWrite struct {
+	DBName    string              `json:"db_name"`
+	Documents []vectordb.Document `json:"documents"`
+	AutoSetup bool                `json:"auto_setup,omitempty"`
+	QueuedAt  time.Time           `json:"queued_at"`
+}
+
+// embeddingQueue persists queued writes as JSONL so they survive a restart
+type embeddingQueue struct {
+	mutex   sync.Mutex
+	path    string
+	entries []queuedWrite
+}
+
+// newEmbeddingQueue opens the queue at path, loading writes left by a previous run
+func newEmbeddingQueue(cfg config.EmbeddingFallbackConfig) (*embeddingQueue, error) {
+	queue := &embeddingQueue{path: cfg.QueuePath}
+
+	file, err := os.Open(cfg.QueuePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding queue: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry queuedWrite
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read embedding queue line %d: %w", line, err)
+		}
+		queue.entries = append(queue.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read embedding queue: %w", err)
+	}
+	return queue, nil
+}
+
+// enqueue appends a write to the queue file and keeps it in memory
+func (q *embeddingQueue) enqueue(entry queuedWrite) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	file, err := os.OpenFile(q.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open embedding queue: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return fmt.Errorf("failed to queue write: %w", err)
+	}
+	q.entries = append(q.entries, entry)
+	return nil
+}
+
+// pending returns a copy of the queued writes, oldest first
+func (q *embeddingQueue) pending() []queuedWrite {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return append([]queuedWrite(nil), q.entries...)
+}
+
+// remove drops the oldest n writes and rewrites the queue file without them
+func (q *embeddingQueue) remove(n int) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.entries = q.entries[n:]
+	if len(q.entries) == 0 {
+		if err := os.Remove(q.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear embedding queue: %w", err)
+		}
+		return nil
+	}
+
+	// Write the remainder beside the queue and rename it over, so a crash
+	// leaves either the old queue or the new one
+	tmp := q.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite embedding queue: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	for _, entry := range q.entries {
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to rewrite embedding queue: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to rewrite embedding queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("failed to rewrite embedding queue: %w", err)
+	}
+	return nil
+}
+
+// stats describes the queue for /health and get_embedding_info
+func (q *embeddingQueue) stats() map[string]interface{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	documents := 0
+	for _, entry := range q.entries {
+		documents += len(entry.Documents)
+	}
+	stats := map[string]interface{}{
+		"path":      q.path,
+		"writes":    len(q.entries),
+		"documents": documents,
+	}
+	if len(q.entries) > 0 {
+		stats["oldest"] = q.entries[0].QueuedAt
+	}
+	return stats
+}
+
+// queueWrite holds documents back until the embedding provider recovers, when
+// queueing is enabled, the provider is down, and some document has no vector
+// to write without embedding. It reports whether the write was queued.
+func (s *Server) queueWrite(dbName string, documents []vectordb.Document, autoSetup bool) (bool, error) {
+	if s.embeddingQueue == nil || !s.embeddingDown() {
+		return false, nil
+	}
+
+	needsEmbedding := false
+	for _, doc := range documents {
+		if doc.VectorDimension() == 0 {
+			needsEmbedding = true
+			break
+		}
+	}
+	if !needsEmbedding {
+		return false, nil
+	}
+
+	entry := queuedWrite{DBName: dbName, Documents: documents, AutoSetup: autoSetup, QueuedAt: time.Now().UTC()}
+	if err := s.embeddingQueue.enqueue(entry); err != nil {
+		return false, err
+	}
+
+	s.logger.Info("Queued write until the embedding provider recovers",
+		zap.String("db_name", dbName),
+		zap.Int("count", len(documents)))
+	return true, nil
+}
+
+// queuedResponse is the result of a write held in the embedding queue
+func queuedResponse(count int) map[string]interface{} {
+	return map[string]interface{}{
+		"status":  "queued",
+		"message": fmt.Sprintf("Queued %d documents until the embedding provider recovers", count),
+		"queued":  count,
+	}
+}
+
+// FlushEmbeddingQueue writes the queued writes, oldest first, and returns the
+// number of documents written. It stops at the first write that fails so
+// later writes do not overtake it, except that writes to a database that no
+// longer exists are dropped. It does nothing while the provider is down.
+func (s *Server) FlushEmbeddingQueue(ctx context.Context) (int, error) {
+	if s.embeddingQueue == nil || s.embeddingDown() {
+		return 0, nil
+	}
+
+	written, done := 0, 0
+	var flushErr error
+	for _, entry := range s.embeddingQueue.pending() {
+		db, err := s.getWritableDatabase(entry.DBName)
+		if errors.Is(err, ErrDatabaseNotFound) {
+			s.logger.Warn("Dropped queued write to a missing database",
+				zap.String("db_name", entry.DBName),
+				zap.Int("count", len(entry.Documents)))
+			done++
+			continue
+		}
+		if err != nil {
+			flushErr = err
+			break
+		}
+
+		documents := make([]vectordb.Document, len(entry.Documents))
+		for i, doc := range entry.Documents {
+			documents[i] = s.preprocessDocument(doc)
+		}
+
+		writeCtx, cancel := s.withTimeout(ctx, "write_bulk")
+		stats, err := s.writeRoutedDocuments(writeCtx, entry.DBName, db, documents, entry.AutoSetup)
+		err = timeoutError(writeCtx, err)
+		cancel()
+		if err != nil {
+			flushErr = fmt.Errorf("failed to write queued documents to '%s': %w", entry.DBName, err)
+			break
+		}
+		if len(stats.Errors) > 0 {
+			s.logger.Warn("Dropped queued documents that failed to write",
+				zap.String("db_name", entry.DBName),
+				zap.Strings("errors", stats.Errors))
+		}
+		s.invalidateQueryCache(entry.DBName)
+		written += stats.DocumentsWritten
+		done++
+	}
+
+	if done > 0 {
+		if err := s.embeddingQueue.remove(done); err != nil {
+			flushErr = errors.Join(flushErr, err)
+		}
+		s.logger.Info("Flushed embedding queue",
+			zap.Int("writes", done),
+			zap.Int("documents", written))
+	}
+	return written, flushErr
+}
+
+// RunEmbeddingMonitor rechecks a down embedding provider every
+// recheck_interval and, once it answers, flushes the embedding queue, until
+// ctx is done. It returns immediately unless a fallback is enabled.
+func (s *Server) RunEmbeddingMonitor(ctx context.Context) {
+	fallback := s.config.MCP.Embedding.Fallback
+	if s.embeddingState == nil || (!fallback.KeywordSearch && !fallback.QueueWrites) || fallback.RecheckInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(fallback.RecheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.embeddingDown() {
+				s.probeEmbedding(ctx)
+			}
+			if _, err := s.FlushEmbeddingQueue(ctx); err != nil {
+				s.logger.Warn("Failed to flush embedding queue", zap.Error(err))
+			}
+		}
+	}
+}
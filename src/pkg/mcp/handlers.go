@@ -2,8 +2,14 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
 	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
 	"go.uber.org/zap"
 )
@@ -12,44 +18,131 @@ import (
 func (s *Server) handleCreateVectorDatabase(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
-	dbType, ok := args["db_type"].(string)
-	if !ok {
-		return nil, fmt.Errorf("db_type is required and must be a string")
+	dbType := s.config.MCP.VectorDB.Type
+	if t, ok := args["db_type"]; ok {
+		if dbType, ok = t.(string); !ok {
+			return nil, invalidArgumentf("db_type must be a string")
+		}
+	}
+	if dbType == "" {
+		return nil, invalidArgumentf("db_type is required when mcp.vector_db.type is not set")
+	}
+	if !config.IsSupportedVectorDBType(dbType) {
+		return nil, invalidArgumentf("unsupported vector database type '%s'; supported types are %s",
+			dbType, strings.Join(config.SupportedVectorDBTypes, ", "))
+	}
+
+	if err := validateDBName(dbName); err != nil {
+		return nil, err
 	}
 
 	collectionName := "MaestroDocs"
 	if cn, ok := args["collection_name"].(string); ok {
 		collectionName = cn
+	} else if name, templated, err := s.templatedCollectionName(dbName, dbType, args); err != nil {
+		return nil, err
+	} else if templated {
+		collectionName = name
 	}
 
-	s.dbMutex.Lock()
-	defer s.dbMutex.Unlock()
+	var routeField string
+	if rule, ok := args["route_by"].(string); ok && rule != "" {
+		field, err := parseRouteBy(rule)
+		if err != nil {
+			return nil, err
+		}
+		routeField = field
+	}
 
-	// Check if database already exists
-	if _, exists := s.vectorDBs[dbName]; exists {
-		return nil, fmt.Errorf("vector database '%s' already exists", dbName)
+	readOnly, _ := args["read_only"].(bool)
+
+	if err := vectordb.ValidateCollectionName(dbType, collectionName); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	if dbType == "mock" && !s.config.MockAllowed() {
+		return nil, invalidArgumentf("the mock vector database is disabled; set mcp.vector_db.allow_mock to enable it")
+	}
+
+	// Fail fast on an existing name before dialing the backend
+	s.dbMutex.RLock()
+	_, exists := s.vectorDBs[dbName]
+	s.dbMutex.RUnlock()
+	if exists {
+		return nil, fmt.Errorf("%w: '%s'", ErrDatabaseExists, dbName)
 	}
 
-	// Create vector database
+	// Create vector database outside the lock, since connecting can be slow
+	// and would block every other database operation
 	db, err := vectordb.CreateVectorDatabase(dbType, collectionName, s.config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vector database: %w", err)
 	}
 
+	s.dbMutex.Lock()
+	// A concurrent create of the same name may have won while connecting
+	if _, exists := s.vectorDBs[dbName]; exists {
+		s.dbMutex.Unlock()
+		s.discardDatabase(ctx, dbName, db)
+		return nil, fmt.Errorf("%w: '%s'", ErrDatabaseExists, dbName)
+	}
+	defer s.dbMutex.Unlock()
+
 	s.vectorDBs[dbName] = db
+	if readOnly {
+		s.readOnly[dbName] = true
+	}
+	if routeField != "" {
+		s.routers[dbName] = &collectionRouter{
+			field:     routeField,
+			embedding: "default",
+			routed:    make(map[string]vectordb.VectorDatabase),
+		}
+	}
 
-	s.logger.Info("Created vector database",
+	s.toolLogger(ctx).Info("Created vector database",
 		zap.String("name", dbName),
 		zap.String("type", dbType),
-		zap.String("collection", collectionName))
+		zap.String("collection", collectionName),
+		zap.String("route_by", routeField),
+		zap.Bool("read_only", readOnly))
 
 	return fmt.Sprintf("Successfully created %s vector database '%s' with collection '%s'",
 		dbType, dbName, collectionName), nil
 }
 
+// discardDatabase releases the connection of a database that lost a create
+// race. Failures are logged, since nothing refers to it.
+func (s *Server) discardDatabase(ctx context.Context, dbName string, db vectordb.VectorDatabase) {
+	cleanupCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("cleanup"))
+	defer cancel()
+
+	if err := db.Cleanup(cleanupCtx); err != nil {
+		s.logger.Warn("Failed to clean up discarded vector database",
+			zap.String("name", dbName),
+			zap.Error(err))
+	}
+}
+
+// handleServerInfo handles the server_info tool
+func (s *Server) handleServerInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return s.config.BuildInfo(), nil
+}
+
+// handleGetConfig handles the get_config tool, reporting the effective
+// configuration after defaults, the config file, and the environment are merged
+func (s *Server) handleGetConfig(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return s.config.Redacted().Settings(), nil
+}
+
+// handleHealthCheck handles the health tool, reporting the same payload as /health
+func (s *Server) handleHealthCheck(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return s.healthReport(ctx), nil
+}
+
 // handleListDatabases handles the list_databases tool
 func (s *Server) handleListDatabases(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	s.dbMutex.RLock()
@@ -74,6 +167,7 @@ func (s *Server) handleListDatabases(ctx context.Context, args map[string]interf
 			"type":           db.Type(),
 			"collection":     db.CollectionName(),
 			"document_count": count,
+			"read_only":      s.readOnly[dbName],
 		})
 	}
 
@@ -86,7 +180,7 @@ func (s *Server) handleListDatabases(ctx context.Context, args map[string]interf
 func (s *Server) handleSetupDatabase(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
 	embedding := "default"
@@ -100,14 +194,26 @@ func (s *Server) handleSetupDatabase(ctx context.Context, args map[string]interf
 	}
 
 	// Set up the database with timeout
-	setupCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("setup_database"))
+	setupCtx, cancel := s.withTimeout(ctx, "setup_database")
 	defer cancel()
 
+	// Setting up again with a different embedding would mix vector spaces
+	if err := s.checkEmbeddingModel(setupCtx, db, db.CollectionName()); err != nil {
+		return nil, err
+	}
+
 	if err := db.Setup(setupCtx, embedding); err != nil {
-		return nil, fmt.Errorf("failed to set up vector database: %w", err)
+		return nil, fmt.Errorf("failed to set up vector database: %w", timeoutError(setupCtx, err))
+	}
+
+	// Routed collections are set up with the same embedding
+	if router := s.getRouter(dbName); router != nil {
+		router.mutex.Lock()
+		router.embedding = embedding
+		router.mutex.Unlock()
 	}
 
-	s.logger.Info("Set up vector database",
+	s.toolLogger(ctx).Info("Set up vector database",
 		zap.String("name", dbName),
 		zap.String("embedding", embedding))
 
@@ -115,82 +221,118 @@ func (s *Server) handleSetupDatabase(ctx context.Context, args map[string]interf
 		db.Type(), dbName, embedding), nil
 }
 
-// handleWriteDocument handles the write_document tool
-func (s *Server) handleWriteDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+// handleCreateCollection handles the create_collection tool
+func (s *Server) handleCreateCollection(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
-	}
-
-	url, ok := args["url"].(string)
-	if !ok {
-		return nil, fmt.Errorf("url is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
-	text, ok := args["text"].(string)
-	if !ok {
-		return nil, fmt.Errorf("text is required and must be a string")
+	options, err := parseCollectionOptions(args)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := s.getDatabaseByName(dbName)
+	db, err := s.getWritableDatabase(dbName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build document
-	document := vectordb.Document{
-		URL:      url,
-		Text:     text,
-		Metadata: make(map[string]interface{}),
+	collectionName, ok := args["collection_name"].(string)
+	if !ok {
+		name, templated, err := s.templatedCollectionName(dbName, db.Type(), args)
+		if err != nil {
+			return nil, err
+		}
+		collectionName = name
+		ok = templated
 	}
-
-	// Add metadata if provided
-	if metadata, ok := args["metadata"].(map[string]interface{}); ok {
-		document.Metadata = metadata
+	if !ok || collectionName == "" {
+		return nil, invalidArgumentf("collection_name is required and must be a string")
 	}
 
-	// Add vector if provided
-	if vector, ok := args["vector"].([]interface{}); ok {
-		document.Vector = make([]float64, len(vector))
-		for i, v := range vector {
-			if f, ok := v.(float64); ok {
-				document.Vector[i] = f
-			} else {
-				return nil, fmt.Errorf("invalid vector value at index %d", i)
-			}
-		}
+	if err := vectordb.ValidateCollectionName(db.Type(), collectionName); err != nil {
+		return nil, invalidArgument(err)
 	}
 
-	// Write document with timeout
-	writeCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("write_single"))
+	createCtx, cancel := s.withTimeout(ctx, "setup_database")
 	defer cancel()
 
-	stats, err := db.WriteDocument(writeCtx, document)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write document: %w", err)
+	if err := db.CreateCollection(createCtx, collectionName, options); err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", timeoutError(createCtx, err))
 	}
 
-	s.logger.Info("Wrote document",
+	s.toolLogger(ctx).Info("Created collection",
 		zap.String("db_name", dbName),
-		zap.String("url", url))
+		zap.String("collection", collectionName))
 
-	return map[string]interface{}{
-		"status":      "ok",
-		"message":     "Wrote 1 document",
-		"write_stats": stats,
-	}, nil
+	return fmt.Sprintf("Successfully created collection '%s' in vector database '%s'",
+		collectionName, dbName), nil
 }
 
-// handleQuery handles the query tool
-func (s *Server) handleQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	dbName, ok := args["db_name"].(string)
-	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+// templatedCollectionName names a collection of dbName from
+// mcp.vector_db.collection_template, filling {db_name}, {db_type}, and the
+// string values of the collection_vars argument. It reports false when no
+// template is configured.
+func (s *Server) templatedCollectionName(dbName, dbType string, args map[string]interface{}) (string, bool, error) {
+	template := s.config.MCP.VectorDB.CollectionTemplate
+	if template == "" {
+		return "", false, nil
 	}
 
-	query, ok := args["query"].(string)
+	vars := make(map[string]string)
+	if raw, ok := args["collection_vars"]; ok {
+		given, ok := raw.(map[string]interface{})
+		if !ok {
+			return "", false, invalidArgumentf("collection_vars must be an object of strings")
+		}
+		for key, value := range given {
+			str, ok := value.(string)
+			if !ok {
+				return "", false, invalidArgumentf("collection_vars.%s must be a string", key)
+			}
+			vars[key] = str
+		}
+	}
+	vars["db_name"] = dbName
+	vars["db_type"] = dbType
+
+	name, err := vectordb.RenderCollectionName(dbType, template, vars)
+	if err != nil {
+		return "", false, invalidArgument(err)
+	}
+	return name, true, nil
+}
+
+// parseCollectionOptions reads the optional embedding, vector_size, and
+// metric arguments describing a collection's schema
+func parseCollectionOptions(args map[string]interface{}) (vectordb.CollectionOptions, error) {
+	var options vectordb.CollectionOptions
+	if emb, ok := args["embedding"].(string); ok {
+		options.Embedding = emb
+	}
+	if size, ok := args["vector_size"].(float64); ok {
+		if size <= 0 || size != float64(int(size)) {
+			return options, invalidArgumentf("vector_size must be a positive integer")
+		}
+		options.VectorSize = int(size)
+	}
+	if metric, ok := args["metric"].(string); ok {
+		switch strings.ToLower(metric) {
+		case config.MetricCosine, config.MetricIP, config.MetricL2:
+			options.Metric = strings.ToLower(metric)
+		default:
+			return options, invalidArgumentf("metric must be one of cosine, ip, or l2")
+		}
+	}
+	return options, nil
+}
+
+// handleGetVectorDimension handles the get_vector_dimension tool
+func (s *Server) handleGetVectorDimension(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("query is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
 	db, err := s.getDatabaseByName(dbName)
@@ -198,38 +340,38 @@ func (s *Server) handleQuery(ctx context.Context, args map[string]interface{}) (
 		return nil, err
 	}
 
-	limit := 5
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
-
-	var collectionName string
-	if cn, ok := args["collection_name"].(string); ok {
-		collectionName = cn
+	collectionName, _ := args["collection_name"].(string)
+	db, collectionName = s.resolveCollection(dbName, db, collectionName)
+	if collectionName == "" {
+		collectionName = db.CollectionName()
 	}
 
-	// Query with timeout
-	queryCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+	dimensionCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
 	defer cancel()
 
-	result, err := db.Query(queryCtx, query, limit, collectionName)
+	dimension, source, err := s.vectorDimension(dimensionCtx, db, collectionName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query vector database: %w", err)
+		return nil, fmt.Errorf("failed to get vector dimension: %w", err)
 	}
 
-	s.logger.Info("Executed query",
-		zap.String("db_name", dbName),
-		zap.String("query", query),
-		zap.Int("limit", limit))
-
+	result := map[string]interface{}{
+		"db_name":    dbName,
+		"collection": collectionName,
+		"dimension":  dimension,
+		"source":     source,
+		"metric":     s.config.MCP.Embedding.EffectiveMetric(),
+	}
+	if len(s.config.MCP.Embedding.NamedVectors) > 0 {
+		result["named_vectors"] = s.config.MCP.Embedding.NamedVectors
+	}
 	return result, nil
 }
 
-// handleListDocuments handles the list_documents tool
-func (s *Server) handleListDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+// handleGetCollectionInfo handles the get_collection_info tool
+func (s *Server) handleGetCollectionInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
 	db, err := s.getDatabaseByName(dbName)
@@ -237,127 +379,1856 @@ func (s *Server) handleListDocuments(ctx context.Context, args map[string]interf
 		return nil, err
 	}
 
-	limit := 10
-	if l, ok := args["limit"].(float64); ok {
-		limit = int(l)
-	}
-
-	offset := 0
-	if o, ok := args["offset"].(float64); ok {
-		offset = int(o)
+	collectionName, _ := args["collection_name"].(string)
+	db, collectionName = s.resolveCollection(dbName, db, collectionName)
+	if collectionName == "" {
+		collectionName = db.CollectionName()
 	}
 
-	// List documents with timeout
-	listCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("list_documents"))
+	infoCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("get_collection_info"))
 	defer cancel()
 
-	documents, err := db.ListDocuments(listCtx, limit, offset)
+	info, err := db.GetCollectionInfo(infoCtx, collectionName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list documents: %w", err)
+		return nil, fmt.Errorf("failed to get collection info: %w", err)
+	}
+
+	// The profile explains the metric and normalization chosen at setup, and
+	// whether they came from the config, the model's recommendation, or the default
+	profile, source := s.config.MCP.Embedding.ResolvedProfile()
+
+	// Schema versions are tracked for the instance's active collection
+	var schemaVersion *collectionVersion
+	s.dbMutex.RLock()
+	if collectionName == db.CollectionName() && s.vectorDBs[dbName] == db {
+		version := s.collectionVersionOf(dbName, db)
+		schemaVersion = &version
 	}
+	_, pendingMigration := s.migrations[dbName]
+	s.dbMutex.RUnlock()
 
-	s.logger.Info("Listed documents",
+	s.toolLogger(ctx).Info("Retrieved collection info",
 		zap.String("db_name", dbName),
-		zap.Int("limit", limit),
-		zap.Int("offset", offset),
-		zap.Int("count", len(documents)))
+		zap.String("collection", collectionName))
 
-	return map[string]interface{}{
-		"documents": documents,
-		"count":     len(documents),
-	}, nil
+	response := map[string]interface{}{
+		"db_name":    dbName,
+		"collection": collectionName,
+		"info":       info,
+		"embedding_profile": map[string]interface{}{
+			"model":     s.config.MCP.Embedding.Model,
+			"metric":    profile.Metric,
+			"normalize": profile.Normalize,
+			"source":    source,
+		},
+	}
+	if schemaVersion != nil {
+		response["schema_version"] = schemaVersion
+		response["migration_pending"] = pendingMigration
+	}
+
+	// The embedding recorded at setup, next to the active one it must match
+	if field := s.config.MCP.Embedding.ModelField; field != "" {
+		active := vectordb.ActiveEmbeddingModel(s.config)
+		embeddingModel := map[string]interface{}{"active": active}
+		if recorded, ok := vectordb.CollectionEmbeddingModel(info, field); ok {
+			embeddingModel["recorded"] = recorded
+			embeddingModel["matches"] = recorded == active
+		}
+		response["embedding_model"] = embeddingModel
+	}
+	return response, nil
 }
 
-// handleCountDocuments handles the count_documents tool
-func (s *Server) handleCountDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+// vectorDimension returns a collection's vector dimension and where it came
+// from: the collection's schema, or the configured vector_size when the schema
+// does not declare one. A dimension of 0 means neither does.
+func (s *Server) vectorDimension(ctx context.Context, db vectordb.VectorDatabase, collectionName string) (int, string, error) {
+	info, err := db.GetCollectionInfo(ctx, collectionName)
+	if err != nil {
+		return 0, "", err
+	}
+	if dimension, ok := vectordb.CollectionDimension(info); ok {
+		return dimension, "collection", nil
+	}
+	return s.config.MCP.Embedding.VectorSize, "config", nil
+}
+
+// checkVectorDimensions rejects documents whose vectors do not match the
+// target collection's dimension before anything is written. The collection is
+// only consulted when a document carries a vector.
+func (s *Server) checkVectorDimensions(ctx context.Context, db vectordb.VectorDatabase, docs []vectordb.Document) error {
+	if !slices.ContainsFunc(docs, func(doc vectordb.Document) bool { return doc.VectorDimension() > 0 }) {
+		return nil
+	}
+
+	dimension, _, err := s.vectorDimension(ctx, db, db.CollectionName())
+	if err != nil || dimension == 0 {
+		// Leave the check to the backend when the dimension is unknown
+		return nil
+	}
+
+	for i, doc := range docs {
+		if length := doc.VectorDimension(); length > 0 && length != dimension {
+			return fmt.Errorf("%w: document %d has %d dimensions but collection '%s' expects %d",
+				vectordb.ErrDimensionMismatch, i, length, db.CollectionName(), dimension)
+		}
+	}
+	return nil
+}
+
+// handleWriteDocument handles the write_document tool
+func (s *Server) handleWriteDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
-	db, err := s.getDatabaseByName(dbName)
+	document, err := s.documentFromArgs(args)
 	if err != nil {
 		return nil, err
 	}
 
-	// Count documents with timeout
-	countCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("count_documents"))
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := s.queueWrite(dbName, []vectordb.Document{document}, s.autoSetup(args))
+	if err != nil {
+		return nil, err
+	}
+	if queued {
+		return queuedResponse(1), nil
+	}
+
+	// Write document with timeout
+	writeCtx, cancel := s.withTimeout(ctx, "write_single")
 	defer cancel()
 
-	count, err := db.CountDocuments(countCtx)
+	embedded, embedErrors := s.embedWriteDocuments(writeCtx, []vectordb.Document{document})
+	if len(embedErrors) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmbeddingUnavailable, embedErrors[0])
+	}
+	document = embedded[0]
+
+	target, err := s.routeDocument(writeCtx, dbName, db, document)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count documents: %w", err)
+		return nil, err
+	}
+
+	if err := s.checkVectorDimensions(writeCtx, target, []vectordb.Document{document}); err != nil {
+		return nil, err
+	}
+	if err := s.checkWriteEmbeddingModel(writeCtx, target); err != nil {
+		return nil, err
+	}
+
+	deduped, duplicates, err := s.dedupeContent(writeCtx, target, []vectordb.Document{document})
+	if err != nil {
+		return nil, timeoutError(writeCtx, err)
+	}
+	if duplicates > 0 {
+		s.toolLogger(ctx).Info("Skipped duplicate document",
+			zap.String("db_name", dbName),
+			zap.String("collection", target.CollectionName()),
+			zap.String("url", document.URL))
+
+		return map[string]interface{}{
+			"status":      "ok",
+			"message":     "Skipped document: its text is already stored",
+			"write_stats": vectordb.WriteStats{Duplicates: duplicates},
+		}, nil
+	}
+	document = deduped[0]
+
+	stats, err := s.withAutoSetup(writeCtx, dbName, target, s.autoSetup(args), func() (vectordb.WriteStats, error) {
+		return target.WriteDocument(writeCtx, document)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", timeoutError(writeCtx, err))
 	}
 
-	s.logger.Info("Counted documents",
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Wrote document",
 		zap.String("db_name", dbName),
-		zap.Int("count", count))
+		zap.String("collection", target.CollectionName()),
+		zap.String("url", document.URL),
+		zap.String("processing_time", stats.ProcessingTime))
 
 	return map[string]interface{}{
-		"count": count,
+		"status":      "ok",
+		"message":     "Wrote 1 document",
+		"write_stats": stats,
 	}, nil
 }
 
-// handleDeleteDocument handles the delete_document tool
-func (s *Server) handleDeleteDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+// handleWriteDocuments handles the write_documents tool
+func (s *Server) handleWriteDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	dbName, ok := args["db_name"].(string)
 	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+		return nil, invalidArgumentf("db_name is required and must be a string")
 	}
 
-	documentID, ok := args["document_id"].(string)
-	if !ok {
-		return nil, fmt.Errorf("document_id is required and must be a string")
+	// Validate every document before writing any of them
+	documents, err := s.documentsFromArgs(args)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := s.getDatabaseByName(dbName)
+	db, err := s.getWritableDatabase(dbName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Delete document with timeout
-	deleteCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("delete"))
+	queued, err := s.queueWrite(dbName, documents, s.autoSetup(args))
+	if err != nil {
+		return nil, err
+	}
+	if queued {
+		return queuedResponse(len(documents)), nil
+	}
+
+	writeCtx, cancel := s.withTimeout(ctx, "write_bulk")
 	defer cancel()
 
-	if err := db.DeleteDocument(deleteCtx, documentID); err != nil {
-		return nil, fmt.Errorf("failed to delete document: %w", err)
+	stats, err := s.writeRoutedDocuments(writeCtx, dbName, db, documents, s.autoSetup(args))
+	if err != nil {
+		return nil, fmt.Errorf("failed to write documents: %w", timeoutError(writeCtx, err))
 	}
 
-	s.logger.Info("Deleted document",
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Wrote documents",
 		zap.String("db_name", dbName),
-		zap.String("document_id", documentID))
+		zap.Int("count", stats.DocumentsWritten),
+		zap.Int("duplicates", stats.Duplicates),
+		zap.Int("batches", stats.Batches),
+		zap.Int("errors", len(stats.Errors)),
+		zap.String("processing_time", stats.ProcessingTime))
+
+	status := "ok"
+	message := fmt.Sprintf("Wrote %d documents", stats.DocumentsWritten)
+	if stats.Duplicates > 0 {
+		message += fmt.Sprintf(", skipped %d duplicates", stats.Duplicates)
+	}
+	if len(stats.Errors) > 0 {
+		status = "partial"
+		message += fmt.Sprintf(", %d failed", len(stats.Errors))
+	}
+	return map[string]interface{}{
+		"status":      status,
+		"message":     message,
+		"write_stats": stats,
+	}, nil
+}
 
-	return fmt.Sprintf("Successfully deleted document '%s' from vector database '%s'",
-		documentID, dbName), nil
+// documentsFromArgs builds and validates the documents argument of a batch write
+func (s *Server) documentsFromArgs(args map[string]interface{}) ([]vectordb.Document, error) {
+	raw, ok := args["documents"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, invalidArgumentf("documents is required and must be a non-empty array")
+	}
+
+	documents := make([]vectordb.Document, 0, len(raw))
+	for i, item := range raw {
+		docArgs, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, invalidArgumentf("documents[%d] must be an object", i)
+		}
+		document, err := s.documentFromArgs(docArgs)
+		if err != nil {
+			return nil, invalidArgumentf("documents[%d]: %w", i, err)
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
 }
 
-// handleCleanup handles the cleanup tool
-func (s *Server) handleCleanup(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	dbName, ok := args["db_name"].(string)
-	if !ok {
-		return nil, fmt.Errorf("db_name is required and must be a string")
+// autoSetup reports whether a write should set up a missing collection: the
+// auto_setup argument when given, else mcp.vector_db.auto_setup
+func (s *Server) autoSetup(args map[string]interface{}) bool {
+	if enabled, ok := args["auto_setup"].(bool); ok {
+		return enabled
 	}
+	return s.config.MCP.VectorDB.AutoSetup
+}
 
-	s.dbMutex.Lock()
-	defer s.dbMutex.Unlock()
+// withAutoSetup runs write and, when it fails because the collection was never
+// set up and auto setup is enabled, sets the collection up with the default
+// embedding and retries once
+func (s *Server) withAutoSetup(ctx context.Context, dbName string, db vectordb.VectorDatabase, enabled bool, write func() (vectordb.WriteStats, error)) (vectordb.WriteStats, error) {
+	stats, err := write()
+	if err == nil || !enabled || !errors.Is(err, vectordb.ErrCollectionNotFound) {
+		return stats, err
+	}
 
-	db, exists := s.vectorDBs[dbName]
-	if !exists {
-		return nil, fmt.Errorf("vector database '%s' not found", dbName)
+	if err := db.Setup(ctx, "default"); err != nil {
+		return vectordb.WriteStats{}, fmt.Errorf("failed to set up collection automatically: %w", err)
 	}
 
-	// Cleanup with timeout
-	cleanupCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("cleanup"))
-	defer cancel()
+	s.toolLogger(ctx).Info("Set up collection automatically on first write",
+		zap.String("db_name", dbName),
+		zap.String("collection", db.CollectionName()))
 
-	if err := db.Cleanup(cleanupCtx); err != nil {
-		return nil, fmt.Errorf("failed to cleanup vector database: %w", err)
+	return write()
+}
+
+// documentFromArgs builds and validates a document from write arguments:
+// url, text, and the optional metadata, vector, vectors, and ttl
+func (s *Server) documentFromArgs(args map[string]interface{}) (vectordb.Document, error) {
+	url, ok := args["url"].(string)
+	if !ok {
+		return vectordb.Document{}, invalidArgumentf("url is required and must be a string")
 	}
 
-	delete(s.vectorDBs, dbName)
+	text, ok := args["text"].(string)
+	if !ok {
+		return vectordb.Document{}, invalidArgumentf("text is required and must be a string")
+	}
 
-	s.logger.Info("Cleaned up vector database",
-		zap.String("name", dbName))
+	document := vectordb.Document{
+		URL:      url,
+		Text:     text,
+		Metadata: make(map[string]interface{}),
+	}
 
-	return fmt.Sprintf("Successfully cleaned up and removed vector database '%s'", dbName), nil
+	// Add metadata if provided
+	if metadata, ok := args["metadata"].(map[string]interface{}); ok {
+		document.Metadata = metadata
+	}
+
+	// Add vector if provided
+	var err error
+	if vector, ok := args["vector"].([]interface{}); ok {
+		document.Vector, err = parseVector(vector)
+		if err != nil {
+			return vectordb.Document{}, invalidArgumentf("invalid vector: %w", err)
+		}
+	}
+
+	// Add named vectors if provided
+	if vectors, ok := args["vectors"].(map[string]interface{}); ok {
+		document.Vectors = make(map[string][]float64, len(vectors))
+		for name, raw := range vectors {
+			vector, ok := raw.([]interface{})
+			if !ok {
+				return vectordb.Document{}, invalidArgumentf("vector '%s' must be an array of numbers", name)
+			}
+			if document.Vectors[name], err = parseVector(vector); err != nil {
+				return vectordb.Document{}, invalidArgumentf("invalid vector '%s': %w", name, err)
+			}
+		}
+	}
+
+	if err := s.validateDocument(document); err != nil {
+		return vectordb.Document{}, err
+	}
+
+	// Keep a caller-supplied creation time, e.g. for historical imports
+	if c, ok := args["created_at"].(string); ok && c != "" {
+		createdAt, err := time.Parse(time.RFC3339, c)
+		if err != nil {
+			return vectordb.Document{}, invalidArgumentf("created_at must be an RFC 3339 time such as 2024-01-02T15:04:05Z: %w", err)
+		}
+		document.CreatedAt = createdAt
+	}
+
+	// Stamp an expiry if a TTL was provided
+	if t, ok := args["ttl"].(string); ok && t != "" {
+		ttl, err := time.ParseDuration(t)
+		if err != nil {
+			return vectordb.Document{}, invalidArgumentf("ttl must be a duration such as 24h: %w", err)
+		}
+		if ttl <= 0 {
+			return vectordb.Document{}, invalidArgumentf("ttl must be positive")
+		}
+		document = vectordb.WithTTL(document, ttl, time.Now())
+	}
+
+	return s.preprocessDocument(document), nil
+}
+
+// handleQuery handles the query tool
+func (s *Server) handleQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	query, ok := args["query"].(string)
+	if !ok {
+		return nil, invalidArgumentf("query is required and must be a string")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitNote, err := parseLimit(args, s.config.MCP.Search, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionName string
+	if cn, ok := args["collection_name"].(string); ok {
+		collectionName = cn
+	}
+	db, collectionName = s.resolveCollection(dbName, db, collectionName)
+
+	ranking, err := parseRankOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := parseFields(args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(args)
+	if err != nil {
+		return nil, err
+	}
+
+	consistencyLevel, err := parseConsistencyLevel(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expansion only changes the text that is embedded; results are still
+	// reported and re-ranked against the original query
+	expanded, expandNote := query, ""
+	expand, _ := args["expand"].(bool)
+	if expand {
+		expanded, expandNote = s.expandQuery(ctx, query)
+	}
+
+	var cacheKey string
+	if s.queryCache != nil {
+		resolvedCollection := collectionName
+		if resolvedCollection == "" {
+			resolvedCollection = db.CollectionName()
+		}
+		cacheKey = queryCacheKey(dbName, resolvedCollection, expanded, limit, ranking, fields, filter)
+		// A strong read must see the latest writes, which a cached result may predate
+		if cached, hit := s.queryCache.get(cacheKey); hit && consistencyLevel != config.ConsistencyStrong {
+			s.logger.Debug("Query cache hit",
+				zap.String("db_name", dbName),
+				zap.String("query", query))
+			return cached, nil
+		}
+	}
+
+	// Query with timeout
+	queryCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+	defer cancel()
+	queryCtx = withConsistencyLevel(withFilter(queryCtx, filter), consistencyLevel)
+
+	// With the embedding provider down, answer from the document text instead
+	if s.keywordFallback() {
+		results, err := s.keywordSearch(queryCtx, db, collectionName, expanded, limit)
+		if err != nil {
+			return nil, err
+		}
+		s.toolLogger(ctx).Info("Executed query by keyword search",
+			zap.String("db_name", dbName),
+			zap.String("query", query),
+			zap.Int("limit", limit),
+			zap.Int("results", len(results)))
+		return vectordb.FormatQueryResults(query, projectResults(results, fields)) + "Note: " + keywordFallbackNote + "\n", nil
+	}
+
+	// Ranking needs the scored candidates, and a preprocessed query must not
+	// show up in the answer, so both are answered from a search instead
+	embedQuery := s.preprocessQuery(expanded)
+	var result interface{}
+	if ranking.active() || embedQuery != query {
+		fetch, needed := s.rankCandidates(limit, ranking)
+		results, err := db.Search(withFields(queryCtx, fields, append(needed, vectordb.FieldText)...), embedQuery, fetch, collectionName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query vector database: %w", err)
+		}
+		results, _, err = s.rankResults(queryCtx, query, results, limit, ranking)
+		if err != nil {
+			return nil, err
+		}
+		result = vectordb.FormatQueryResults(query, projectResults(results, fields))
+	} else {
+		result, err = db.Query(withFields(queryCtx, fields), query, limit, collectionName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query vector database: %w", err)
+		}
+	}
+
+	if s.queryCache != nil {
+		s.queryCache.put(dbName, cacheKey, result)
+	}
+
+	if text, ok := result.(string); ok {
+		if expanded != query {
+			text += "Expanded query: '" + expanded + "'\n"
+		}
+		for _, note := range []string{expandNote, limitNote} {
+			if note != "" {
+				text += "Note: " + note + "\n"
+			}
+		}
+		result = text
+	}
+
+	s.toolLogger(ctx).Info("Executed query",
+		zap.String("db_name", dbName),
+		zap.String("query", query),
+		zap.Int("limit", limit),
+		zap.Any("filter", filter),
+		zap.Bool("rerank", ranking.rerank),
+		zap.String("dedupe_by", ranking.dedupeBy),
+		zap.Float64("recency_weight", ranking.recencyWeight),
+		zap.String("expanded_query", expanded))
+
+	return result, nil
+}
+
+// handleSearch handles the search tool
+func (s *Server) handleSearch(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	// A vector searches by similarity to it directly, so the query text is optional
+	var queryVector []float64
+	if raw, ok := args["vector"].([]interface{}); ok {
+		vector, err := parseVector(raw)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+		if len(vector) == 0 || vectordb.IsZeroVector(vector) {
+			return nil, invalidArgumentf("vector must not be empty or all zeros")
+		}
+		queryVector = vector
+	}
+
+	query, ok := args["query"].(string)
+	if !ok && queryVector == nil {
+		return nil, invalidArgumentf("query is required and must be a string unless a vector is given")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitNote, err := parseLimit(args, s.config.MCP.Search, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	var collectionName string
+	if cn, ok := args["collection_name"].(string); ok {
+		collectionName = cn
+	}
+	db, collectionName = s.resolveCollection(dbName, db, collectionName)
+
+	// Search with timeout
+	searchCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+	defer cancel()
+
+	// Search a named vector space instead of the default vector when asked
+	vectorName, _ := args["vector_name"].(string)
+	if vectorName != "" {
+		searchCtx = vectordb.WithVectorName(searchCtx, vectorName)
+	}
+
+	fields, err := parseFields(args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(args)
+	if err != nil {
+		return nil, err
+	}
+	searchCtx = withFilter(searchCtx, filter)
+
+	consistencyLevel, err := parseConsistencyLevel(args)
+	if err != nil {
+		return nil, err
+	}
+	searchCtx = withConsistencyLevel(searchCtx, consistencyLevel)
+
+	filterStrategy, _ := args["filter_strategy"].(string)
+	if err := vectordb.ValidateFilterStrategy(filterStrategy); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if filterStrategy != "" {
+		searchCtx = vectordb.WithFilterStrategy(searchCtx, filterStrategy)
+	}
+	if queryVector != nil {
+		searchCtx = vectordb.WithQueryVector(searchCtx, queryVector)
+	}
+
+	ranking, err := parseRankOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	if ranking.rerank && query == "" {
+		return nil, invalidArgumentf("rerank requires a query")
+	}
+
+	// With the embedding provider down, a text query is answered from the
+	// document text instead
+	fetch, needed := s.rankCandidates(limit, ranking)
+	keyword := queryVector == nil && s.keywordFallback()
+	var results []vectordb.SearchResult
+	if keyword {
+		results, err = s.keywordSearch(searchCtx, db, collectionName, query, fetch)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		results, err = db.Search(withFields(searchCtx, fields, needed...), s.preprocessQuery(query), fetch, collectionName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search vector database: %w", err)
+		}
+	}
+
+	// Rank before the score threshold so min_score applies to the final scores
+	results, reranked, err := s.rankResults(searchCtx, query, results, limit, ranking)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the score threshold after ranking
+	if minScore, ok := args["min_score"].(float64); ok {
+		results = vectordb.FilterByScore(results, minScore)
+	}
+
+	// Vectors are large, so only return them on request; an explicit field
+	// selection decides for itself
+	if len(fields) > 0 {
+		results = projectResults(results, fields)
+	} else if includeVectors, _ := args["include_vectors"].(bool); !includeVectors {
+		results = stripResultVectors(results)
+	}
+
+	s.toolLogger(ctx).Info("Executed search",
+		zap.String("db_name", dbName),
+		zap.String("query", query),
+		zap.Int("limit", limit),
+		zap.String("vector_name", vectorName),
+		zap.Bool("by_vector", queryVector != nil),
+		zap.Any("filter", filter),
+		zap.String("filter_strategy", filterStrategy),
+		zap.String("dedupe_by", ranking.dedupeBy),
+		zap.Float64("recency_weight", ranking.recencyWeight),
+		zap.Int("results", len(results)),
+		zap.Bool("reranked", reranked),
+		zap.Bool("keyword", keyword))
+
+	response := map[string]interface{}{
+		"results":  results,
+		"count":    len(results),
+		"reranked": reranked,
+	}
+	if limitNote != "" {
+		response["note"] = limitNote
+	}
+	if keyword {
+		response["search_mode"] = "keyword"
+		response["fallback_note"] = keywordFallbackNote
+	}
+	return response, nil
+}
+
+// handleListDocuments handles the list_documents tool
+func (s *Server) handleListDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitNote, err := parseLimit(args, s.config.MCP.List, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := parseOffset(args)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := parseFields(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// List documents with timeout
+	listCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("list_documents"))
+	defer cancel()
+
+	// Tombstones are recognized by their metadata, so it is fetched even when
+	// not selected for snapshot pages, which read documents by ID
+	includeDeleted, _ := args["include_deleted"].(bool)
+	var needed []string
+	if includeDeleted {
+		listCtx = vectordb.WithTombstones(listCtx)
+	} else {
+		needed = append(needed, vectordb.FieldMetadata)
+	}
+
+	snap, err := s.resolveSnapshot(listCtx, dbName, db, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []vectordb.Document
+	var hasMore bool
+	missing := 0
+	if snap != nil {
+		documents, missing, err = snap.page(withFields(listCtx, fields, needed...), db, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		hasMore = offset+limit < len(snap.ids)
+	} else {
+		// Fetch one extra document to learn whether another page follows
+		documents, err = db.ListDocuments(withFields(listCtx, fields, needed...), limit+1, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		hasMore = len(documents) > limit
+		if hasMore {
+			documents = documents[:limit]
+		}
+	}
+
+	if len(fields) > 0 {
+		documents = vectordb.ProjectDocuments(documents, fields)
+	} else if includeVectors, _ := args["include_vectors"].(bool); !includeVectors {
+		documents = stripVectors(documents)
+	}
+
+	s.toolLogger(ctx).Info("Listed documents",
+		zap.String("db_name", dbName),
+		zap.Int("limit", limit),
+		zap.Int("offset", offset),
+		zap.Int("count", len(documents)))
+
+	response := map[string]interface{}{
+		"documents": documents,
+		"count":     len(documents),
+		"offset":    offset,
+		"limit":     limit,
+		"has_more":  hasMore,
+	}
+	// The total is known without another backend call when everything fit on the first page
+	if offset == 0 && !hasMore && snap == nil {
+		response["total"] = len(documents)
+	}
+	if snap != nil {
+		response["snapshot_id"] = snap.id
+		response["snapshot_taken_at"] = snap.takenAt.Format(time.RFC3339)
+		response["consistency_level"] = snap.consistencyLevel
+		response["total"] = len(snap.ids)
+		response["missing"] = missing
+	}
+	if limitNote != "" {
+		response["note"] = limitNote
+	}
+	return response, nil
+}
+
+// handleCountDocuments handles the count_documents tool
+func (s *Server) handleCountDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	consistencyLevel, err := parseConsistencyLevel(args)
+	if err != nil {
+		return nil, err
+	}
+
+	approximate, _ := args["approximate"].(bool)
+	if approximate && consistencyLevel != "" {
+		return nil, invalidArgumentf("consistency_level does not apply to approximate counts")
+	}
+
+	// Count documents with timeout
+	countCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("count_documents"))
+	defer cancel()
+
+	if includeDeleted, _ := args["include_deleted"].(bool); includeDeleted {
+		countCtx = vectordb.WithTombstones(countCtx)
+	}
+
+	var count int
+	if approximate {
+		count, err = db.EstimateDocuments(countCtx)
+	} else {
+		count, err = db.CountDocuments(withConsistencyLevel(countCtx, consistencyLevel))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	s.toolLogger(ctx).Info("Counted documents",
+		zap.String("db_name", dbName),
+		zap.Int("count", count),
+		zap.Bool("approximate", approximate))
+
+	return map[string]interface{}{
+		"count":       count,
+		"approximate": approximate,
+	}, nil
+}
+
+// handleDeleteDocument handles the delete_document tool
+func (s *Server) handleDeleteDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	documentID, ok := args["document_id"].(string)
+	if !ok {
+		return nil, invalidArgumentf("document_id is required and must be a string")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete document with timeout
+	deleteCtx, cancel := s.withTimeout(ctx, "delete_single")
+	defer cancel()
+
+	if hard, _ := args["hard"].(bool); s.config.MCP.SoftDelete.Enabled && !hard {
+		doc, err := db.GetDocument(deleteCtx, documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete document: %w", timeoutError(deleteCtx, err))
+		}
+		if err := db.UpdateDocument(deleteCtx, vectordb.Tombstone(doc, time.Now())); err != nil {
+			return nil, fmt.Errorf("failed to delete document: %w", timeoutError(deleteCtx, err))
+		}
+
+		s.invalidateQueryCache(dbName)
+
+		s.toolLogger(ctx).Info("Soft-deleted document",
+			zap.String("db_name", dbName),
+			zap.String("document_id", documentID))
+
+		return fmt.Sprintf("Successfully soft-deleted document '%s' from vector database '%s'; use restore_document to recover it",
+			documentID, dbName), nil
+	}
+
+	if err := db.DeleteDocument(deleteCtx, documentID); err != nil {
+		return nil, fmt.Errorf("failed to delete document: %w", timeoutError(deleteCtx, err))
+	}
+
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Deleted document",
+		zap.String("db_name", dbName),
+		zap.String("document_id", documentID))
+
+	return fmt.Sprintf("Successfully deleted document '%s' from vector database '%s'",
+		documentID, dbName), nil
+}
+
+// handleDeleteDocuments handles the delete_documents tool. Every ID is
+// attempted, and the response reports which were deleted and which failed
+// rather than failing the call on the first missing document.
+func (s *Server) handleDeleteDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	rawIDs, ok := args["document_ids"].([]interface{})
+	if !ok || len(rawIDs) == 0 {
+		return nil, invalidArgumentf("document_ids is required and must be a non-empty array of strings")
+	}
+	documentIDs := make([]string, 0, len(rawIDs))
+	for i, raw := range rawIDs {
+		id, ok := raw.(string)
+		if !ok || id == "" {
+			return nil, invalidArgumentf("document_ids[%d] must be a non-empty string", i)
+		}
+		documentIDs = append(documentIDs, id)
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteCtx, cancel := s.withTimeout(ctx, "delete_bulk")
+	defer cancel()
+
+	hard, _ := args["hard"].(bool)
+	soft := s.config.MCP.SoftDelete.Enabled && !hard
+
+	var report vectordb.DeleteReport
+	if soft {
+		report, err = softDeleteDocuments(deleteCtx, db, documentIDs)
+	} else {
+		report, err = db.DeleteDocuments(deleteCtx, documentIDs)
+	}
+	if len(report.Deleted) > 0 {
+		s.invalidateQueryCache(dbName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete documents after deleting %d of %d: %w",
+			len(report.Deleted), len(documentIDs), timeoutError(deleteCtx, err))
+	}
+
+	s.toolLogger(ctx).Info("Deleted documents",
+		zap.String("db_name", dbName),
+		zap.Int("count", len(report.Deleted)),
+		zap.Int("failed", len(report.Failed)),
+		zap.Bool("soft", soft))
+
+	status := "ok"
+	if len(report.Failed) > 0 {
+		status = "partial"
+	}
+	failed := report.Failed
+	if failed == nil {
+		failed = []vectordb.DeleteFailure{}
+	}
+	return map[string]interface{}{
+		"status":       status,
+		"message":      fmt.Sprintf("Deleted %d of %d documents", len(report.Deleted), len(documentIDs)),
+		"deleted":      report.Deleted,
+		"failed":       failed,
+		"soft_deleted": soft,
+	}, nil
+}
+
+// softDeleteDocuments tombstones each document, reporting the ones that could
+// not be found or updated. Only ctx ending stops the batch early.
+func softDeleteDocuments(ctx context.Context, db vectordb.VectorDatabase, documentIDs []string) (vectordb.DeleteReport, error) {
+	report := vectordb.DeleteReport{Deleted: []string{}}
+	now := time.Now()
+	for _, id := range documentIDs {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		doc, err := db.GetDocument(ctx, id)
+		if err == nil {
+			err = db.UpdateDocument(ctx, vectordb.Tombstone(doc, now))
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, err
+			}
+			report.Failed = append(report.Failed, vectordb.DeleteFailure{ID: id, Error: err.Error()})
+			continue
+		}
+		report.Deleted = append(report.Deleted, id)
+	}
+	return report, nil
+}
+
+// handleDeleteByFilter handles the delete_by_filter tool
+func (s *Server) handleDeleteByFilter(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	raw, ok := args["filter"].(map[string]interface{})
+	if !ok {
+		return nil, invalidArgumentf("filter is required and must be an object")
+	}
+
+	filter, err := vectordb.ParseMetadataFilter(raw)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete with timeout
+	deleteCtx, cancel := s.withTimeout(ctx, "delete_bulk")
+	defer cancel()
+
+	deleted, err := db.DeleteByFilter(deleteCtx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete documents by filter: %w", timeoutError(deleteCtx, err))
+	}
+
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Deleted documents by filter",
+		zap.String("db_name", dbName),
+		zap.Any("filter", raw),
+		zap.Int("deleted", deleted))
+
+	return map[string]interface{}{
+		"db_name": dbName,
+		"deleted": deleted,
+	}, nil
+}
+
+// handleTruncateCollection handles the truncate_collection tool
+func (s *Server) handleTruncateCollection(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionName, _ := args["collection_name"].(string)
+	db, collectionName = s.resolveCollection(dbName, db, collectionName)
+	if collectionName == "" {
+		collectionName = db.CollectionName()
+	}
+
+	// Truncate with timeout
+	deleteCtx, cancel := s.withTimeout(ctx, "delete_bulk")
+	defer cancel()
+
+	deleted, err := db.TruncateCollection(deleteCtx, collectionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to truncate collection: %w", timeoutError(deleteCtx, err))
+	}
+
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Truncated collection",
+		zap.String("db_name", dbName),
+		zap.String("collection", collectionName),
+		zap.Int("deleted", deleted))
+
+	return map[string]interface{}{
+		"db_name":    dbName,
+		"collection": collectionName,
+		"deleted":    deleted,
+	}, nil
+}
+
+// handleRestoreDocument handles the restore_document tool
+func (s *Server) handleRestoreDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	documentID, ok := args["document_id"].(string)
+	if !ok {
+		return nil, invalidArgumentf("document_id is required and must be a string")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore document with timeout
+	restoreCtx, cancel := s.withTimeout(ctx, "delete_single")
+	defer cancel()
+
+	doc, err := db.GetDocument(restoreCtx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore document: %w", timeoutError(restoreCtx, err))
+	}
+	if !vectordb.IsTombstoned(doc) {
+		return nil, invalidArgumentf("document '%s' is not deleted", documentID)
+	}
+
+	if err := db.UpdateDocument(restoreCtx, vectordb.Restore(doc)); err != nil {
+		return nil, fmt.Errorf("failed to restore document: %w", timeoutError(restoreCtx, err))
+	}
+
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Restored document",
+		zap.String("db_name", dbName),
+		zap.String("document_id", documentID))
+
+	return fmt.Sprintf("Successfully restored document '%s' in vector database '%s'",
+		documentID, dbName), nil
+}
+
+// handlePurge handles the purge tool
+func (s *Server) handlePurge(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	olderThan := s.config.MCP.SoftDelete.PurgeAfter
+	if o, ok := args["older_than"].(string); ok && o != "" {
+		olderThan, err = time.ParseDuration(o)
+		if err != nil {
+			return nil, invalidArgumentf("older_than must be a duration such as 72h: %w", err)
+		}
+	}
+	if olderThan < 0 {
+		return nil, invalidArgumentf("older_than must not be negative")
+	}
+
+	// Purge tombstones with timeout
+	purgeCtx, cancel := s.withTimeout(ctx, "delete_bulk")
+	defer cancel()
+	purgeCtx = vectordb.WithTombstones(purgeCtx)
+
+	cutoff := time.Now().Add(-olderThan)
+	var expired []string
+	for offset := 0; ; offset += defaultTransferBatchSize {
+		docs, err := db.ListDocuments(purgeCtx, defaultTransferBatchSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan documents: %w", timeoutError(purgeCtx, err))
+		}
+
+		for _, doc := range docs {
+			if !vectordb.IsTombstoned(doc) {
+				continue
+			}
+			if deletedAt, ok := vectordb.DeletedAt(doc); !ok || !deletedAt.After(cutoff) {
+				expired = append(expired, doc.ID)
+			}
+		}
+
+		if len(docs) < defaultTransferBatchSize {
+			break
+		}
+	}
+
+	var report vectordb.DeleteReport
+	if len(expired) > 0 {
+		report, err = db.DeleteDocuments(purgeCtx, expired)
+		if len(report.Deleted) > 0 {
+			s.invalidateQueryCache(dbName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge documents after purging %d of %d: %w",
+				len(report.Deleted), len(expired), timeoutError(purgeCtx, err))
+		}
+	}
+
+	s.toolLogger(ctx).Info("Purged deleted documents",
+		zap.String("db_name", dbName),
+		zap.Duration("older_than", olderThan),
+		zap.Int("count", len(report.Deleted)),
+		zap.Int("failed", len(report.Failed)))
+
+	result := map[string]interface{}{
+		"db_name": dbName,
+		"purged":  len(report.Deleted),
+	}
+	if len(report.Failed) > 0 {
+		result["failed"] = report.Failed
+	}
+	return result, nil
+}
+
+// handleMigrateDocuments handles the migrate_documents tool
+func (s *Server) handleMigrateDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	sourceName, ok := args["source_db"].(string)
+	if !ok {
+		return nil, invalidArgumentf("source_db is required and must be a string")
+	}
+
+	targetName, ok := args["target_db"].(string)
+	if !ok {
+		return nil, invalidArgumentf("target_db is required and must be a string")
+	}
+
+	if sourceName == targetName {
+		return nil, invalidArgumentf("source_db and target_db must be different")
+	}
+
+	source, err := s.getDatabaseByName(sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.getWritableDatabase(targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := 100
+	if b, ok := args["batch_size"].(float64); ok {
+		batchSize = int(b)
+	}
+	if batchSize <= 0 {
+		return nil, invalidArgumentf("batch_size must be positive")
+	}
+
+	offset, err := parseOffset(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dropped vectors are re-embedded the way writes are, so that needs write embedding
+	reembed, _ := args["reembed"].(bool)
+	if reembed && !s.embedsWrites() {
+		return nil, invalidArgumentf("reembed requires mcp.embedding.writes to be enabled")
+	}
+
+	// Migrate with timeout
+	migrateCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("migrate"))
+	defer cancel()
+
+	// Source vectors are checked against the target collection's own dimension
+	dimension, _, err := s.vectorDimension(migrateCtx, target, target.CollectionName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target collection dimension: %w", err)
+	}
+
+	migrated, duplicates := 0, 0
+	var migrateErr error
+	for {
+		docs, err := source.ListDocuments(migrateCtx, batchSize, offset)
+		if err != nil {
+			migrateErr = fmt.Errorf("failed to read from source: %w", err)
+			break
+		}
+		if len(docs) == 0 {
+			break
+		}
+
+		batch := make([]vectordb.Document, len(docs))
+		for i, doc := range docs {
+			if length := doc.VectorDimension(); length > 0 && dimension > 0 && length != dimension {
+				if !reembed {
+					migrateErr = invalidArgumentf("%w: document '%s' has a %d-dimensional vector but the target expects %d; set reembed to drop source vectors and re-embed the text",
+						vectordb.ErrDimensionMismatch, doc.ID, length, dimension)
+					break
+				}
+				doc.Vector = nil
+				doc.Vector32 = nil
+			}
+			batch[i] = doc
+		}
+		if migrateErr != nil {
+			break
+		}
+
+		if batch, err = s.reembedDocuments(migrateCtx, batch, dimension); err != nil {
+			migrateErr = err
+			break
+		}
+
+		stats, err := s.writeRoutedDocuments(migrateCtx, targetName, target, batch, false)
+		if err != nil {
+			migrateErr = fmt.Errorf("failed to write to target: %w", err)
+			break
+		}
+
+		migrated += stats.DocumentsWritten
+		duplicates += stats.Duplicates
+		offset += len(docs)
+
+		s.toolLogger(ctx).Info("Migrated document batch",
+			zap.String("source_db", sourceName),
+			zap.String("target_db", targetName),
+			zap.Int("batch", len(batch)),
+			zap.Int("migrated", migrated),
+			zap.Int("next_offset", offset))
+
+		if len(docs) < batchSize {
+			break
+		}
+	}
+
+	if migrated > 0 {
+		s.invalidateQueryCache(targetName)
+	}
+
+	report := map[string]interface{}{
+		"source_db":   sourceName,
+		"target_db":   targetName,
+		"migrated":    migrated,
+		"duplicates":  duplicates,
+		"next_offset": offset,
+		"completed":   migrateErr == nil,
+	}
+
+	// Partial progress is reported so the migration can be resumed from next_offset
+	if migrateErr != nil {
+		s.logger.Warn("Migration stopped before completion",
+			zap.String("source_db", sourceName),
+			zap.String("target_db", targetName),
+			zap.Int("next_offset", offset),
+			zap.Error(migrateErr))
+		report["error"] = migrateErr.Error()
+	}
+
+	return report, nil
+}
+
+// handleExportDocuments handles the export_documents tool
+func (s *Server) handleExportDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := defaultTransferBatchSize
+	if b, ok := args["batch_size"].(float64); ok {
+		batchSize = int(b)
+	}
+	if batchSize <= 0 {
+		return nil, invalidArgumentf("batch_size must be positive")
+	}
+
+	includeVectors, _ := args["include_vectors"].(bool)
+
+	// Export documents with timeout
+	exportCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("export"))
+	defer cancel()
+	if includeDeleted, _ := args["include_deleted"].(bool); includeDeleted {
+		exportCtx = vectordb.WithTombstones(exportCtx)
+	}
+
+	var snap *listSnapshot
+	if useSnapshot, _ := args["snapshot"].(bool); useSnapshot {
+		level, err := s.snapshotConsistencyLevel(args)
+		if err != nil {
+			return nil, err
+		}
+		if snap, err = s.takeSnapshot(exportCtx, dbName, db, level); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf strings.Builder
+	count, err := exportDocuments(exportCtx, db, snap, &buf, batchSize, includeVectors, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export documents: %w", err)
+	}
+
+	s.toolLogger(ctx).Info("Exported documents",
+		zap.String("db_name", dbName),
+		zap.Int("count", count))
+
+	response := map[string]interface{}{
+		"db_name": dbName,
+		"count":   count,
+		"data":    buf.String(),
+	}
+	if snap != nil {
+		response["snapshot_taken_at"] = snap.takenAt.Format(time.RFC3339)
+		response["consistency_level"] = snap.consistencyLevel
+	}
+	return response, nil
+}
+
+// handleImportDocuments handles the import_documents tool
+func (s *Server) handleImportDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	data, ok := args["data"].(string)
+	if !ok {
+		return nil, invalidArgumentf("data is required and must be a string")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := defaultTransferBatchSize
+	if b, ok := args["batch_size"].(float64); ok {
+		batchSize = int(b)
+	}
+	if batchSize <= 0 {
+		return nil, invalidArgumentf("batch_size must be positive")
+	}
+
+	format := "jsonl"
+	if f, ok := args["format"].(string); ok && f != "" {
+		format = strings.ToLower(f)
+	}
+
+	var records []importRecord
+	var rowErrors []importError
+	switch format {
+	case "jsonl":
+		records, rowErrors, err = s.parseJSONLDocuments(data)
+	case "csv":
+		mapping, mappingErr := parseCSVColumnMapping(args)
+		if mappingErr != nil {
+			return nil, mappingErr
+		}
+		records, rowErrors, err = s.parseCSVDocuments(data, mapping)
+	default:
+		return nil, invalidArgumentf("unsupported import format '%s': must be jsonl or csv", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Import documents with timeout
+	importCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("import"))
+	defer cancel()
+
+	if err := s.checkWriteEmbeddingModel(importCtx, db); err != nil {
+		return nil, err
+	}
+
+	// Vectors, imported or embedded, are checked against the collection's
+	// dimension; an unknown dimension leaves the check to the backend
+	dimension, _, err := s.vectorDimension(importCtx, db, db.CollectionName())
+	if err != nil {
+		dimension = 0
+	}
+
+	imported, duplicates := 0, 0
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		batch := make([]importRecord, 0, end-start)
+		for _, record := range records[start:end] {
+			batch = append(batch, importRecord{row: record.row, doc: s.preprocessDocument(record.doc)})
+		}
+
+		// Rows that fail to embed or have the wrong dimension are reported on their own
+		batch, failed := s.checkImportRecords(importCtx, db, dimension, batch)
+		rowErrors = append(rowErrors, failed...)
+		docs := importDocuments(batch)
+
+		// A failed batch is reported against its rows and the import continues
+		var stats vectordb.WriteStats
+		var err error
+		if len(docs) > 0 {
+			stats, err = s.writeCollectionDocuments(importCtx, db, docs)
+		}
+		if err != nil {
+			for _, record := range batch {
+				rowErrors = append(rowErrors, importError{Row: record.row, Error: fmt.Sprintf("write failed: %v", err)})
+			}
+			continue
+		}
+		imported += stats.DocumentsWritten
+		duplicates += stats.Duplicates
+	}
+
+	if imported > 0 {
+		s.invalidateQueryCache(dbName)
+	}
+
+	s.toolLogger(ctx).Info("Imported documents",
+		zap.String("db_name", dbName),
+		zap.String("format", format),
+		zap.Int("count", imported),
+		zap.Int("duplicates", duplicates),
+		zap.Int("errors", len(rowErrors)))
+
+	return map[string]interface{}{
+		"db_name":    dbName,
+		"imported":   imported,
+		"duplicates": duplicates,
+		"failed":     len(rowErrors),
+		"errors":     rowErrors,
+	}, nil
+}
+
+// handleGetAuditLog handles the get_audit_log tool
+func (s *Server) handleGetAuditLog(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	if s.auditLog == nil {
+		return nil, fmt.Errorf("audit logging is not enabled; set mcp.audit.enabled to true")
+	}
+
+	filter := AuditFilter{Limit: 50}
+	filter.Tool, _ = args["tool"].(string)
+	filter.DBName, _ = args["db_name"].(string)
+	filter.RequestID, _ = args["request_id"].(string)
+	if l, ok := args["limit"].(float64); ok {
+		filter.Limit = int(l)
+	}
+	if since, ok := args["since"].(string); ok && since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, invalidArgumentf("since must be an RFC 3339 timestamp: %w", err)
+		}
+		filter.Since = parsed
+	}
+
+	entries := s.auditLog.query(filter)
+
+	return map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+	}, nil
+}
+
+// handleCleanup handles the cleanup tool
+func (s *Server) handleCleanup(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	db, exists := s.vectorDBs[dbName]
+	if !exists {
+		return nil, fmt.Errorf("%w: '%s'", ErrDatabaseNotFound, dbName)
+	}
+	if s.readOnly[dbName] {
+		return nil, fmt.Errorf("%w: '%s' was created with read_only and cannot be cleaned up", ErrReadOnly, dbName)
+	}
+	if s.migrating[dbName] {
+		return nil, fmt.Errorf("%w: '%s' is copying documents to a new collection version", ErrMigrationInProgress, dbName)
+	}
+
+	// Cleanup with timeout
+	cleanupCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("cleanup"))
+	defer cancel()
+
+	if err := db.Cleanup(cleanupCtx); err != nil {
+		return nil, fmt.Errorf("failed to cleanup vector database: %w", err)
+	}
+
+	if router, exists := s.routers[dbName]; exists {
+		router.mutex.Lock()
+		for collection, routed := range router.routed {
+			if err := routed.Cleanup(cleanupCtx); err != nil {
+				s.logger.Warn("Failed to cleanup routed collection",
+					zap.String("db_name", dbName),
+					zap.String("collection", collection),
+					zap.Error(err))
+			}
+		}
+		router.mutex.Unlock()
+		delete(s.routers, dbName)
+	}
+
+	// A version kept for rollback goes with the instance
+	if migration, exists := s.migrations[dbName]; exists {
+		if err := migration.previous.Cleanup(cleanupCtx); err != nil {
+			s.logger.Warn("Failed to cleanup previous collection version",
+				zap.String("db_name", dbName),
+				zap.String("collection", migration.previousVersion.Collection),
+				zap.Error(err))
+		}
+		delete(s.migrations, dbName)
+	}
+	delete(s.versions, dbName)
+
+	delete(s.vectorDBs, dbName)
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Cleaned up vector database",
+		zap.String("name", dbName))
+
+	return fmt.Sprintf("Successfully cleaned up and removed vector database '%s'", dbName), nil
+}
+
+// validateDocument rejects documents that would produce useless embeddings or
+// junk index entries: empty text or URL unless allowed by mcp.write_validation,
+// and explicitly provided vectors that are empty or all zeros
+func (s *Server) validateDocument(doc vectordb.Document) error {
+	rules := s.config.MCP.WriteValidation
+	if strings.TrimSpace(doc.Text) == "" && !rules.AllowEmptyText {
+		return invalidArgumentf("text must not be empty (set mcp.write_validation.allow_empty_text to store metadata-only records)")
+	}
+	if strings.TrimSpace(doc.URL) == "" && !rules.AllowEmptyURL {
+		return invalidArgumentf("url must not be empty (set mcp.write_validation.allow_empty_url to allow it)")
+	}
+
+	if doc.Vector != nil {
+		if err := validateVector("vector", doc.Vector); err != nil {
+			return err
+		}
+	}
+	for name, vector := range doc.Vectors {
+		if err := validateVector(fmt.Sprintf("vector '%s'", name), vector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateVector rejects an explicitly provided vector that is empty or all zeros
+func validateVector(label string, vector []float64) error {
+	if len(vector) == 0 {
+		return invalidArgumentf("%s must not be empty", label)
+	}
+	if vectordb.IsZeroVector(vector) {
+		return invalidArgumentf("%s must not be all zeros", label)
+	}
+	return nil
+}
+
+// parseLimit reads the limit argument, defaulting to the configured default
+// limit or else fallback, and clamps it to the configured maximum. The note
+// explains a clamped limit and is empty otherwise.
+func parseLimit(args map[string]interface{}, limits config.LimitConfig, fallback int) (int, string, error) {
+	limit := defaultLimit(limits, fallback)
+	if l, ok := args["limit"].(float64); ok {
+		if l < 0 {
+			return 0, "", invalidArgumentf("limit must not be negative, got %v", l)
+		}
+		limit = int(l)
+	}
+
+	if limits.MaxLimit > 0 && limit > limits.MaxLimit {
+		return limits.MaxLimit, fmt.Sprintf("limit %d exceeds the maximum of %d; returned at most %d results",
+			limit, limits.MaxLimit, limits.MaxLimit), nil
+	}
+	return limit, "", nil
+}
+
+// parseOffset reads the optional offset argument, rejecting negative values
+func parseOffset(args map[string]interface{}) (int, error) {
+	o, ok := args["offset"].(float64)
+	if !ok {
+		return 0, nil
+	}
+	if o < 0 {
+		return 0, invalidArgumentf("offset must not be negative, got %v", o)
+	}
+	return int(o), nil
+}
+
+// defaultLimit returns the configured default limit, or fallback when none is set
+func defaultLimit(limits config.LimitConfig, fallback int) int {
+	if limits.DefaultLimit > 0 {
+		return limits.DefaultLimit
+	}
+	return fallback
+}
+
+// parseFields reads the optional fields argument selecting the document fields to return
+func parseFields(args map[string]interface{}) ([]string, error) {
+	raw, ok := args["fields"].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	fields := make([]string, 0, len(raw))
+	for i, item := range raw {
+		field, ok := item.(string)
+		if !ok {
+			return nil, invalidArgumentf("fields[%d] must be a string", i)
+		}
+		fields = append(fields, field)
+	}
+	if err := vectordb.ValidateFields(fields); err != nil {
+		return nil, invalidArgument(err)
+	}
+	return fields, nil
+}
+
+// handleValidateFilter handles the validate_filter tool. It parses a filter
+// and, for backends whose metadata has a declared schema, checks its fields
+// and value types against it, without calling the backend.
+func (s *Server) handleValidateFilter(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	raw, ok := args["filter"].(map[string]interface{})
+	if !ok {
+		return nil, invalidArgumentf("filter is required and must be an object")
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Milvus and the mock store metadata as schemaless JSON, so any field
+	// can be filtered on and only the filter's syntax can be checked. Weaviate
+	// can only filter on fields declared in the metadata schema.
+	schemaChecked := db.Type() == "weaviate"
+
+	issues := []vectordb.FilterIssue{}
+	filter, err := vectordb.ParseMetadataFilter(raw)
+	if err != nil {
+		issues = append(issues, vectordb.FilterIssue{Problem: err.Error()})
+	} else if schemaChecked {
+		issues = append(issues, vectordb.CheckFilterSchema(filter, s.config.MCP.VectorDB.Weaviate.MetadataSchema)...)
+	}
+
+	return map[string]interface{}{
+		"db_name":        dbName,
+		"valid":          len(issues) == 0,
+		"issues":         issues,
+		"schema_checked": schemaChecked,
+	}, nil
+}
+
+// parseFilter reads the optional filter argument restricting a search to
+// documents whose metadata matches it
+func parseFilter(args map[string]interface{}) (vectordb.MetadataFilter, error) {
+	raw, exists := args["filter"]
+	if !exists {
+		return nil, nil
+	}
+
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, invalidArgumentf("filter must be an object")
+	}
+	return vectordb.ParseMetadataFilter(object)
+}
+
+// parseDedupeBy reads the optional dedupe_by argument naming the field that
+// groups results, either url or metadata.<field>
+func parseDedupeBy(args map[string]interface{}) (string, error) {
+	key, _ := args["dedupe_by"].(string)
+	if key == "" {
+		return "", nil
+	}
+	if err := vectordb.ValidateDedupeKey(key); err != nil {
+		return "", invalidArgument(err)
+	}
+	return key, nil
+}
+
+// parseConsistencyLevel reads the optional consistency_level argument
+func parseConsistencyLevel(args map[string]interface{}) (string, error) {
+	level, _ := args["consistency_level"].(string)
+	if level == "" {
+		return "", nil
+	}
+	if err := config.ValidateConsistencyLevel(level); err != nil {
+		return "", invalidArgument(err)
+	}
+	return strings.ToLower(level), nil
+}
+
+// withConsistencyLevel pushes a read consistency level down to the backend
+func withConsistencyLevel(ctx context.Context, level string) context.Context {
+	if level == "" {
+		return ctx
+	}
+	return vectordb.WithConsistencyLevel(ctx, level)
+}
+
+// withFilter pushes a metadata filter down to the backend
+func withFilter(ctx context.Context, filter vectordb.MetadataFilter) context.Context {
+	if filter == nil {
+		return ctx
+	}
+	return vectordb.WithMetadataFilter(ctx, filter)
+}
+
+// withFields pushes a field selection down to the backend, adding any fields
+// the handler needs before it applies the selection itself
+func withFields(ctx context.Context, fields []string, needed ...string) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	selection := append([]string{}, fields...)
+	for _, field := range needed {
+		if !slices.Contains(selection, field) {
+			selection = append(selection, field)
+		}
+	}
+	return vectordb.WithFields(ctx, selection)
+}
+
+// projectResults returns copies of search results keeping only the selected document fields
+func projectResults(results []vectordb.SearchResult, fields []string) []vectordb.SearchResult {
+	if len(fields) == 0 {
+		return results
+	}
+
+	projected := make([]vectordb.SearchResult, len(results))
+	for i, result := range results {
+		result.Document = vectordb.ProjectDocument(result.Document, fields)
+		projected[i] = result
+	}
+	return projected
+}
+
+// parseVector converts a JSON array argument into a vector. JSON numbers
+// decode as float64, but in-process callers may pass float32 or integers.
+func parseVector(values []interface{}) ([]float64, error) {
+	vector := make([]float64, len(values))
+	for i, v := range values {
+		switch n := v.(type) {
+		case float64:
+			vector[i] = n
+		case float32:
+			vector[i] = float64(n)
+		case int:
+			vector[i] = float64(n)
+		case json.Number:
+			f, err := n.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid vector value at index %d: %w", i, err)
+			}
+			vector[i] = f
+		default:
+			return nil, fmt.Errorf("invalid vector value at index %d", i)
+		}
+	}
+	return vector, nil
+}
+
+// stripVectors returns copies of docs without their embedding vectors
+func stripVectors(docs []vectordb.Document) []vectordb.Document {
+	stripped := make([]vectordb.Document, len(docs))
+	for i, doc := range docs {
+		doc.Vector = nil
+		doc.Vector32 = nil
+		doc.Vectors = nil
+		stripped[i] = doc
+	}
+	return stripped
+}
+
+// stripResultVectors returns copies of search results without their embedding vectors
+func stripResultVectors(results []vectordb.SearchResult) []vectordb.SearchResult {
+	stripped := make([]vectordb.SearchResult, len(results))
+	for i, result := range results {
+		result.Document.Vector = nil
+		result.Document.Vector32 = nil
+		result.Document.Vectors = nil
+		stripped[i] = result
+	}
+	return stripped
 }
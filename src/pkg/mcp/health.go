@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// healthReport builds the status payload shared by the HTTP /health endpoint
+// and the health tool. Every registered database is probed for reachability;
+// the service reports degraded when any probe fails.
+func (s *Server) healthReport(ctx context.Context) map[string]interface{} {
+	s.dbMutex.RLock()
+	dbs := make(map[string]vectordb.VectorDatabase, len(s.vectorDBs))
+	for name, db := range s.vectorDBs {
+		dbs[name] = db
+	}
+	s.dbMutex.RUnlock()
+
+	databases := s.probeDatabases(ctx, dbs)
+
+	status := "healthy"
+	for _, probe := range databases {
+		if !probe["reachable"].(bool) {
+			status = "degraded"
+		}
+	}
+
+	// A down embedding provider degrades the service even with a fallback,
+	// since writes are queued and queries lose semantic ranking
+	if s.embeddingDown() {
+		status = "degraded"
+	}
+
+	// Collect wrapper stats after probing so they include the probes' outcome
+	breakers := make(map[string]interface{})
+	concurrency := make(map[string]interface{})
+	for name, db := range dbs {
+		for layer := db; layer != nil; layer = vectordb.Unwrap(layer) {
+			switch wrapper := layer.(type) {
+			case *vectordb.CircuitBreakerDatabase:
+				breakers[name] = wrapper.Stats()
+			case *vectordb.ConcurrencyLimitedDatabase:
+				concurrency[name] = wrapper.Stats()
+			}
+		}
+	}
+
+	response := map[string]interface{}{
+		"status":           status,
+		"timestamp":        time.Now().UTC(),
+		"vector_databases": len(dbs),
+		"databases":        databases,
+	}
+
+	if s.queryCache != nil {
+		response["query_cache"] = s.queryCache.stats()
+	}
+
+	if s.ttlSweep != nil {
+		response["ttl_sweep"] = s.ttlSweep.stats()
+	}
+
+	if s.embeddingState != nil {
+		embedding := s.embeddingState.stats()
+		if s.embeddingQueue != nil {
+			embedding["queue"] = s.embeddingQueue.stats()
+		}
+		for key, value := range s.coalescer.stats() {
+			embedding[key] = value
+		}
+		response["embedding"] = embedding
+	}
+
+	if len(breakers) > 0 {
+		response["circuit_breakers"] = breakers
+	}
+
+	if len(concurrency) > 0 {
+		response["concurrency"] = concurrency
+	}
+
+	return response
+}
+
+// probeDatabases checks in parallel that each database's backend answers a
+// lightweight request within the health timeout
+func (s *Server) probeDatabases(ctx context.Context, dbs map[string]vectordb.VectorDatabase) map[string]map[string]interface{} {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]map[string]interface{}, len(dbs))
+	)
+
+	for name, db := range dbs {
+		wg.Add(1)
+		go func(name string, db vectordb.VectorDatabase) {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("health"))
+			defer cancel()
+
+			start := time.Now()
+			_, err := db.ListCollections(probeCtx)
+			probe := map[string]interface{}{
+				"type":       db.Type(),
+				"collection": db.CollectionName(),
+				"reachable":  err == nil,
+				"latency":    time.Since(start).String(),
+			}
+			if err != nil {
+				probe["error"] = err.Error()
+			}
+
+			mu.Lock()
+			results[name] = probe
+			mu.Unlock()
+		}(name, db)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// WaitForBackends probes the databases declared under mcp.databases every
+// interval until all of them are reachable, so traffic is not served before
+// the backends can answer it. It returns an error naming the databases still
+// unreachable once timeout passes or ctx is done.
+func (s *Server) WaitForBackends(ctx context.Context, timeout, interval time.Duration) error {
+	s.dbMutex.RLock()
+	pending := make(map[string]vectordb.VectorDatabase, len(s.config.MCP.Databases))
+	for _, spec := range s.config.MCP.Databases {
+		if db, exists := s.vectorDBs[spec.Name]; exists {
+			pending[spec.Name] = db
+		}
+	}
+	s.dbMutex.RUnlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for attempt := 1; ; attempt++ {
+		for name, probe := range s.probeDatabases(waitCtx, pending) {
+			if probe["reachable"].(bool) {
+				delete(pending, name)
+				continue
+			}
+			s.logger.Info("Waiting for vector database",
+				zap.String("name", name),
+				zap.Int("attempt", attempt),
+				zap.Any("error", probe["error"]))
+		}
+		if len(pending) == 0 {
+			s.logger.Info("Vector databases are reachable",
+				zap.Int("attempts", attempt),
+				zap.Duration("duration", time.Since(start)))
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			names := make([]string, 0, len(pending))
+			for name := range pending {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("vector databases not reachable after %s: %s", time.Since(start).Round(time.Millisecond), strings.Join(names, ", "))
+		case <-ticker.C:
+		}
+	}
+}
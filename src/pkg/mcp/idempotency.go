@@ -0,0 +1,208 @@
+package mcp
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// idempotencyStore remembers the responses of writes made with an
+// idempotency_key for a window, so a retried write returns the original
+// response instead of writing the documents again
+type idempotencyStore struct {
+	mutex    sync.Mutex
+	window   time.Duration
+	maxKeys  int
+	entries  map[string]*list.Element
+	order    *list.List // completed writes, oldest first
+	inFlight map[string]*idempotencyClaim
+}
+
+// idempotencyEntry is the remembered response of a completed write
+type idempotencyEntry struct {
+	key         string
+	fingerprint string
+	response    map[string]interface{}
+	expiresAt   time.Time
+}
+
+// idempotencyClaim reserves a key while its first write is in flight;
+// done is closed when the write completes or fails
+type idempotencyClaim struct {
+	fingerprint string
+	done        chan struct{}
+}
+
+// newIdempotencyStore creates a store remembering at most maxKeys writes for window each
+func newIdempotencyStore(window time.Duration, maxKeys int) *idempotencyStore {
+	return &idempotencyStore{
+		window:   window,
+		maxKeys:  maxKeys,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		inFlight: make(map[string]*idempotencyClaim),
+	}
+}
+
+// claim returns the remembered response for key, or reserves key for the
+// caller, who must then call complete or release. A retry that arrives while
+// the first write is still in flight waits for its outcome. Reusing a key for
+// different arguments is a conflict.
+func (st *idempotencyStore) claim(ctx context.Context, key, fingerprint string) (map[string]interface{}, bool, error) {
+	for {
+		st.mutex.Lock()
+		st.expire(time.Now())
+
+		if elem, exists := st.entries[key]; exists {
+			entry := elem.Value.(*idempotencyEntry)
+			st.mutex.Unlock()
+			if entry.fingerprint != fingerprint {
+				return nil, false, fmt.Errorf("%w: the key was already used for a write with different arguments", ErrIdempotencyConflict)
+			}
+			return entry.response, false, nil
+		}
+
+		claim, pending := st.inFlight[key]
+		if !pending {
+			st.inFlight[key] = &idempotencyClaim{fingerprint: fingerprint, done: make(chan struct{})}
+			st.mutex.Unlock()
+			return nil, true, nil
+		}
+		st.mutex.Unlock()
+
+		if claim.fingerprint != fingerprint {
+			return nil, false, fmt.Errorf("%w: the key is in use by a write with different arguments", ErrIdempotencyConflict)
+		}
+		select {
+		case <-claim.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+// complete remembers the response of a claimed key's write
+func (st *idempotencyStore) complete(key, fingerprint string, response map[string]interface{}) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.finish(key)
+	st.entries[key] = st.order.PushBack(&idempotencyEntry{
+		key:         key,
+		fingerprint: fingerprint,
+		response:    response,
+		expiresAt:   time.Now().Add(st.window),
+	})
+	for st.maxKeys > 0 && st.order.Len() > st.maxKeys {
+		st.remove(st.order.Front())
+	}
+}
+
+// release frees a claimed key whose write failed, so a retry writes again
+func (st *idempotencyStore) release(key string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.finish(key)
+}
+
+// finish ends a key's in-flight claim and wakes any waiting retries. The
+// caller must hold the mutex.
+func (st *idempotencyStore) finish(key string) {
+	if claim, pending := st.inFlight[key]; pending {
+		delete(st.inFlight, key)
+		close(claim.done)
+	}
+}
+
+// expire drops entries whose window has passed. Every entry has the same
+// window, so the oldest expire first. The caller must hold the mutex.
+func (st *idempotencyStore) expire(now time.Time) {
+	for elem := st.order.Front(); elem != nil; elem = st.order.Front() {
+		if now.Before(elem.Value.(*idempotencyEntry).expiresAt) {
+			return
+		}
+		st.remove(elem)
+	}
+}
+
+// remove drops an entry. The caller must hold the mutex.
+func (st *idempotencyStore) remove(elem *list.Element) {
+	st.order.Remove(elem)
+	delete(st.entries, elem.Value.(*idempotencyEntry).key)
+}
+
+// idempotencyFingerprint hashes a write's arguments, other than its key, so a
+// reused key can be told apart from a genuine retry
+func idempotencyFingerprint(args map[string]interface{}) (string, error) {
+	payload := maps.Clone(args)
+	delete(payload, "idempotency_key")
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint write arguments: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotentWrite wraps a write tool's handler so calls carrying an
+// idempotency_key run at most once per key within mcp.idempotency.window.
+// Responses report deduplicated: false for the write that ran and true for
+// retries answered from memory. Failed writes are not remembered.
+func (s *Server) idempotentWrite(tool string, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		idempotencyKey, _ := args["idempotency_key"].(string)
+		if idempotencyKey == "" {
+			return handler(ctx, args)
+		}
+		if s.idempotency == nil {
+			return nil, invalidArgumentf("idempotency_key requires a positive mcp.idempotency.window")
+		}
+
+		fingerprint, err := idempotencyFingerprint(args)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+
+		// Keys are scoped to the tool and the database they write to
+		dbName, _ := args["db_name"].(string)
+		key := tool + "\x00" + dbName + "\x00" + idempotencyKey
+
+		remembered, claimed, err := s.idempotency.claim(ctx, key, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			s.toolLogger(ctx).Info("Deduplicated retried write",
+				zap.String("tool", tool),
+				zap.String("db_name", dbName),
+				zap.String("idempotency_key", idempotencyKey))
+			return withDeduplicated(remembered, true), nil
+		}
+
+		result, err := handler(ctx, args)
+		response, ok := result.(map[string]interface{})
+		if err != nil || !ok {
+			s.idempotency.release(key)
+			return result, err
+		}
+		s.idempotency.complete(key, fingerprint, response)
+		return withDeduplicated(response, false), nil
+	}
+}
+
+// withDeduplicated returns a copy of a write response flagged as a fresh write or a deduplicated retry
+func withDeduplicated(response map[string]interface{}, deduplicated bool) map[string]interface{} {
+	flagged := maps.Clone(response)
+	flagged["deduplicated"] = deduplicated
+	return flagged
+}
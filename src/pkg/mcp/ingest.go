@@ -0,0 +1,307 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// defaultIngestFlushInterval bounds how long a partial micro-batch waits for
+// more documents before it is written
+const defaultIngestFlushInterval = time.Second
+
+// ingestLine is one line read from an ingest stream: a document, or the
+// reason the line was rejected
+type ingestLine struct {
+	row int
+	doc vectordb.Document
+	err error
+}
+
+// ingestAck acknowledges a written micro-batch. Acknowledged documents are
+// durable in the backend; rows listed in errors were not written.
+type ingestAck struct {
+	Batch          int           `json:"batch"`
+	Written        int           `json:"written"`
+	Duplicates     int           `json:"duplicates,omitempty"`
+	Queued         int           `json:"queued,omitempty"`
+	TotalWritten   int           `json:"total_written"`
+	FirstRow       int           `json:"first_row,omitempty"`
+	LastRow        int           `json:"last_row,omitempty"`
+	ProcessingTime string        `json:"processing_time,omitempty"`
+	Errors         []importError `json:"errors,omitempty"`
+}
+
+// ingestSummary ends an ingest stream
+type ingestSummary struct {
+	Done    bool `json:"done"`
+	Batches int  `json:"batches"`
+	Written int  `json:"written"`
+	Failed  int  `json:"failed"`
+}
+
+// handleIngest accepts a chunked stream of JSONL documents and writes them in
+// micro-batches as they arrive. A batch is written once it holds batch_size
+// documents or flush_interval passes, and an acknowledgement line with its
+// write stats is streamed back after each one. A summary line ends the response.
+func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	dbName := query.Get("db_name")
+	if dbName == "" {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("db_name is required"))
+		return
+	}
+
+	batchSize := defaultTransferBatchSize
+	if b := query.Get("batch_size"); b != "" {
+		parsed, err := strconv.Atoi(b)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("batch_size must be a positive integer"))
+			return
+		}
+		batchSize = parsed
+	}
+
+	flushInterval := defaultIngestFlushInterval
+	if f := query.Get("flush_interval"); f != "" {
+		parsed, err := time.ParseDuration(f)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("flush_interval must be a positive duration such as 500ms"))
+			return
+		}
+		flushInterval = parsed
+	}
+
+	// Streaming is another way to call write_documents, so it follows that tool's filter
+	if !s.config.ToolEnabled("write_documents") {
+		s.writeError(w, http.StatusForbidden, fmt.Errorf("%w: write_documents is not enabled on this server", ErrToolDisabled))
+		return
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer s.inFlight.Done()
+
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	w.Header().Set("X-Request-ID", requestID)
+
+	// A stream holds one of its client's slots for as long as it is open
+	endClientCall, err := s.beginClientCall(r)
+	if err != nil {
+		s.logger.Warn("Rejected ingest stream over the per-client limit",
+			zap.String("db_name", dbName),
+			zap.String("request_id", requestID),
+			zap.Error(err))
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+	defer endClientCall()
+
+	// Acknowledgements are written while the body is still being read, which
+	// HTTP/1.x only allows with full duplex enabled
+	controller := http.NewResponseController(w)
+	_ = controller.EnableFullDuplex()
+
+	ctx, cancel := context.WithCancel(WithRequestID(r.Context(), requestID))
+	defer cancel()
+	lines := s.readIngestLines(ctx, r)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	emit := func(v interface{}) {
+		if err := encoder.Encode(v); err != nil {
+			s.logger.Warn("Failed to send ingest acknowledgement", zap.String("db_name", dbName), zap.Error(err))
+			return
+		}
+		_ = controller.Flush()
+	}
+
+	var summary ingestSummary
+	var batch []ingestLine
+	var rowErrors []importError
+	flush := func() {
+		if len(batch) == 0 && len(rowErrors) == 0 {
+			return
+		}
+		summary.Batches++
+		ack := s.writeIngestBatch(ctx, dbName, db, batch, rowErrors)
+		ack.Batch = summary.Batches
+		summary.Written += ack.Written
+		summary.Failed += len(ack.Errors)
+		ack.TotalWritten = summary.Written
+		emit(ack)
+		batch, rowErrors = nil, nil
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				summary.Done = true
+				emit(summary)
+				s.logger.Info("Ingested document stream",
+					zap.String("db_name", dbName),
+					zap.Int("batches", summary.Batches),
+					zap.Int("written", summary.Written),
+					zap.Int("failed", summary.Failed))
+				return
+			}
+			if line.err != nil {
+				rowErrors = append(rowErrors, importError{Row: line.row, Error: line.err.Error()})
+				continue
+			}
+			batch = append(batch, line)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			// The client went away; whatever was acknowledged is already written
+			s.logger.Warn("Ingest stream ended before completion",
+				zap.String("db_name", dbName),
+				zap.Int("written", summary.Written),
+				zap.Error(ctx.Err()))
+			return
+		}
+	}
+}
+
+// readIngestLines decodes the request body one JSON document per line in the
+// background. Invalid lines are sent with their error; a read failure is sent
+// as a final error line. The channel is closed at the end of the body.
+func (s *Server) readIngestLines(ctx context.Context, r *http.Request) <-chan ingestLine {
+	lines := make(chan ingestLine)
+	go func() {
+		defer close(lines)
+		send := func(line ingestLine) bool {
+			select {
+			case lines <- line:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		row := 0
+		for scanner.Scan() {
+			row++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+
+			line := ingestLine{row: row}
+			if err := json.Unmarshal([]byte(text), &line.doc); err != nil {
+				line.err = fmt.Errorf("invalid JSON: %v", err)
+			} else if err := s.validateDocument(line.doc); err != nil {
+				line.err = err
+			} else {
+				line.doc = s.preprocessDocument(line.doc)
+			}
+			if !send(line) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(ingestLine{row: row + 1, err: fmt.Errorf("failed to read stream: %v", err)})
+		}
+	}()
+	return lines
+}
+
+// writeIngestBatch writes one micro-batch as a write_documents call, through
+// the tool middleware so it is audited and bounded like one, and reports it
+// as an acknowledgement. The batch is held in the embedding queue when
+// write_documents would queue it. Otherwise rows that fail to embed or have
+// the wrong dimension are reported on their own, the rest are routed and
+// written like any write_documents batch, and a failed write is reported
+// against each of them.
+func (s *Server) writeIngestBatch(ctx context.Context, dbName string, db vectordb.VectorDatabase, batch []ingestLine, rowErrors []importError) ingestAck {
+	ack := ingestAck{Errors: rowErrors}
+	if len(batch) == 0 {
+		return ack
+	}
+	ack.FirstRow = batch[0].row
+	ack.LastRow = batch[len(batch)-1].row
+
+	records := make([]importRecord, len(batch))
+	for i, line := range batch {
+		records[i] = importRecord{row: line.row, doc: line.doc}
+	}
+
+	write := s.wrapHandler(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		queued, err := s.queueWrite(dbName, importDocuments(records), false)
+		if err != nil {
+			return nil, err
+		}
+		if queued {
+			ack.Queued = len(records)
+			records = nil
+			return queuedResponse(ack.Queued), nil
+		}
+
+		// An unknown dimension leaves the check to the write
+		dimension, _, err := s.vectorDimension(ctx, db, db.CollectionName())
+		if err != nil {
+			dimension = 0
+		}
+		var failed []importError
+		records, failed = s.checkImportRecords(ctx, db, dimension, records)
+		ack.Errors = append(ack.Errors, failed...)
+		if len(records) == 0 {
+			return vectordb.WriteStats{}, nil
+		}
+
+		stats, err := s.writeRoutedDocuments(ctx, dbName, db, importDocuments(records), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write documents: %w", err)
+		}
+		ack.Written = stats.DocumentsWritten
+		ack.Duplicates = stats.Duplicates
+		ack.ProcessingTime = stats.ProcessingTime
+		return stats, nil
+	})
+
+	if _, err := write(withToolName(ctx, "write_documents"), map[string]interface{}{"db_name": dbName}); err != nil {
+		for _, record := range records {
+			ack.Errors = append(ack.Errors, importError{Row: record.row, Error: fmt.Sprintf("write failed: %v", err)})
+		}
+		return ack
+	}
+
+	if ack.Written > 0 {
+		s.invalidateQueryCache(dbName)
+	}
+	return ack
+}
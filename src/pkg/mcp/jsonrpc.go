@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// serverName identifies the server in the initialize handshake
+const serverName = "maestro-mcp"
+
+// supportedProtocolVersions lists the MCP protocol versions the server
+// speaks, newest first. initialize answers with the client's version when it
+// is listed and with the newest otherwise.
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC 2.0 error codes
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCInternalError  = -32603
+)
+
+// jsonRPCRequest is a JSON-RPC 2.0 request, or a notification when ID is absent
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// jsonRPCResponse answers a JSON-RPC request with a result or an error
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+// jsonRPCError is the error member of a JSON-RPC response
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *jsonRPCError) Error() string {
+	return e.Message
+}
+
+// rpcErrorf formats a JSON-RPC error
+func rpcErrorf(code int, format string, args ...interface{}) *jsonRPCError {
+	return &jsonRPCError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Capabilities returns the MCP capabilities the server offers under its
+// config: tools when any tool is enabled, resources when mcp.resources is
+// enabled, and prompts when any prompt is available. The streaming export
+// and ingest endpoints are reported as an experimental capability.
+func (s *Server) Capabilities() map[string]interface{} {
+	capabilities := map[string]interface{}{
+		"experimental": map[string]interface{}{
+			"streaming": map[string]interface{}{
+				"export": "/mcp/export",
+				"ingest": "/mcp/ingest",
+			},
+		},
+	}
+
+	s.toolsMutex.RLock()
+	for name := range s.Tools {
+		if s.config.ToolEnabled(name) {
+			capabilities["tools"] = map[string]interface{}{"listChanged": false}
+			break
+		}
+	}
+	s.toolsMutex.RUnlock()
+
+	if s.config.MCP.Resources.Enabled {
+		capabilities["resources"] = map[string]interface{}{"subscribe": false, "listChanged": false}
+	}
+
+	if len(s.prompts()) > 0 {
+		capabilities["prompts"] = map[string]interface{}{"listChanged": false}
+	}
+
+	return capabilities
+}
+
+// initializeResult answers the initialize handshake, agreeing on a protocol
+// version and reporting the server's capabilities
+func (s *Server) initializeResult(params json.RawMessage) (interface{}, error) {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"clientInfo"`
+	}
+	if err := decodeParams(params, &request); err != nil {
+		return nil, err
+	}
+
+	version := supportedProtocolVersions[0]
+	if slices.Contains(supportedProtocolVersions, request.ProtocolVersion) {
+		version = request.ProtocolVersion
+	}
+
+	s.logger.Info("Initialized MCP session",
+		zap.String("client", request.ClientInfo.Name),
+		zap.String("client_version", request.ClientInfo.Version),
+		zap.String("requested_protocol_version", request.ProtocolVersion),
+		zap.String("protocol_version", version))
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    s.Capabilities(),
+		"serverInfo": map[string]interface{}{
+			"name":    serverName,
+			"version": s.config.BuildInfo().Version,
+		},
+	}, nil
+}
+
+// decodeParams decodes a request's params, which may be absent
+func decodeParams(params json.RawMessage, into interface{}) error {
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(params, into); err != nil {
+		return rpcErrorf(jsonRPCInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+// dispatchRPC runs a JSON-RPC method. Methods of capabilities the server does
+// not offer are not found.
+func (s *Server) dispatchRPC(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	capabilities := s.Capabilities()
+	offers := func(capability string) bool {
+		_, ok := capabilities[capability]
+		return ok
+	}
+
+	switch {
+	case method == "initialize":
+		return s.initializeResult(params)
+	case method == "ping":
+		return map[string]interface{}{}, nil
+	case method == "tools/list" && offers("tools"):
+		return map[string]interface{}{"tools": s.enabledTools()}, nil
+	case method == "tools/call" && offers("tools"):
+		var request struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := decodeParams(params, &request); err != nil {
+			return nil, err
+		}
+		return s.callToolRPC(ctx, request.Name, request.Arguments)
+	case method == "resources/list" && offers("resources"):
+		resources, err := s.ListResources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"resources": resources}, nil
+	case method == "resources/read" && offers("resources"):
+		var request struct {
+			URI string `json:"uri"`
+		}
+		if err := decodeParams(params, &request); err != nil {
+			return nil, err
+		}
+		contents, err := s.ReadResource(ctx, request.URI)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"contents": []ResourceContents{contents}}, nil
+	case method == "prompts/list" && offers("prompts"):
+		return map[string]interface{}{"prompts": s.ListPrompts()}, nil
+	case method == "prompts/get" && offers("prompts"):
+		var request struct {
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments"`
+		}
+		if err := decodeParams(params, &request); err != nil {
+			return nil, err
+		}
+		return s.GetPrompt(ctx, request.Name, request.Arguments)
+	default:
+		return nil, rpcErrorf(jsonRPCMethodNotFound, "method not found: %s", method)
+	}
+}
+
+// callToolRPC runs a tool for tools/call. Tool failures are reported in the
+// result with isError set, as MCP expects, so the model can see them; an
+// unknown or disabled tool is an invalid params error.
+func (s *Server) callToolRPC(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	result, err := s.CallTool(ctx, name, args)
+	if errors.Is(err, ErrToolNotFound) || errors.Is(err, ErrToolDisabled) {
+		return nil, rpcErrorf(jsonRPCInvalidParams, "%v", err)
+	}
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	text, ok := result.(string)
+	if !ok {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tool result: %w", err)
+		}
+		text = string(encoded)
+	}
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": false,
+	}, nil
+}
+
+// toolErrorResult reports a failed tools/call in its result
+func toolErrorResult(err error) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
+
+// rpcError converts a method's error into a JSON-RPC error: argument errors
+// are invalid params, and anything else is an internal error
+func rpcError(err error) *jsonRPCError {
+	var rpcErr *jsonRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	if statusForError(err) < http.StatusInternalServerError {
+		return &jsonRPCError{Code: jsonRPCInvalidParams, Message: err.Error()}
+	}
+	return &jsonRPCError{Code: jsonRPCInternalError, Message: err.Error()}
+}
+
+// handleJSONRPC serves MCP over JSON-RPC 2.0 on a single endpoint. A
+// notification, which has no id, is acknowledged with 202 and no body.
+func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request jsonRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.writeRPC(w, jsonRPCResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: rpcErrorf(jsonRPCParseError, "parse error: %v", err)})
+		return
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		s.writeRPC(w, jsonRPCResponse{JSONRPC: "2.0", ID: request.ID, Error: rpcErrorf(jsonRPCInvalidRequest, "invalid request: jsonrpc must be \"2.0\" and method is required")})
+		return
+	}
+
+	if len(request.ID) == 0 {
+		s.logger.Debug("Received MCP notification", zap.String("method", request.Method))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer s.inFlight.Done()
+
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	w.Header().Set("X-Request-ID", requestID)
+
+	if request.Method == "tools/call" {
+		endClientCall, err := s.beginClientCall(r)
+		if err != nil {
+			s.logger.Warn("Rejected tool call over the per-client limit",
+				zap.String("request_id", requestID),
+				zap.Error(err))
+			s.writeRPC(w, jsonRPCResponse{JSONRPC: "2.0", ID: request.ID, Result: toolErrorResult(err)})
+			return
+		}
+		defer endClientCall()
+	}
+
+	response := jsonRPCResponse{JSONRPC: "2.0", ID: request.ID}
+	result, err := s.dispatchRPC(WithRequestID(r.Context(), requestID), request.Method, request.Params)
+	if err != nil {
+		response.Error = rpcError(err)
+	} else {
+		response.Result = result
+	}
+	s.writeRPC(w, response)
+}
+
+// writeRPC writes a JSON-RPC response. Errors travel in the body, so the
+// HTTP status is always 200.
+func (s *Server) writeRPC(w http.ResponseWriter, response jsonRPCResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to encode JSON-RPC response", zap.Error(err))
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// toolLevelCore writes info entries at a tool's configured level, so a chatty
+// tool can log its routine messages at debug while another stays at info.
+// Entries at other levels keep theirs.
+type toolLevelCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+// remap returns the level an entry at level is written at
+func (c toolLevelCore) remap(level zapcore.Level) zapcore.Level {
+	if level == zapcore.InfoLevel {
+		return c.level
+	}
+	return level
+}
+
+func (c toolLevelCore) Enabled(level zapcore.Level) bool {
+	return c.Core.Enabled(c.remap(level))
+}
+
+func (c toolLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return toolLevelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c toolLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	entry.Level = c.remap(entry.Level)
+	return c.Core.Check(entry, checked)
+}
+
+// newToolLoggers returns a logger for each tool under logging.tool_levels.
+// Levels are checked by config validation, so an unparsable one is skipped.
+func newToolLoggers(logger *zap.Logger, levels map[string]string) map[string]*zap.Logger {
+	loggers := make(map[string]*zap.Logger, len(levels))
+	for tool, name := range levels {
+		level, err := zapcore.ParseLevel(strings.ToLower(name))
+		if err != nil {
+			continue
+		}
+		loggers[tool] = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return toolLevelCore{Core: core, level: level}
+		}))
+	}
+	return loggers
+}
+
+// toolLogger returns the logger for the tool executing in ctx, which writes
+// the tool's info messages at its level under logging.tool_levels
+func (s *Server) toolLogger(ctx context.Context) *zap.Logger {
+	if len(s.toolLoggers) == 0 || ctx == nil {
+		return s.logger
+	}
+	if logger, exists := s.toolLoggers[ToolNameFromContext(ctx)]; exists {
+		return logger
+	}
+	return s.logger
+}
+
+// warnUnknownToolLevels logs tool names in logging.tool_levels that match no
+// registered tool, which usually means a typo
+func (s *Server) warnUnknownToolLevels() {
+	for name := range s.config.Logging.ToolLevels {
+		if _, exists := s.GetTool(name); !exists {
+			s.logger.Warn("Log level override names an unknown tool", zap.String("name", name))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ToolHandler executes a tool with the given arguments
+type ToolHandler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// Middleware wraps a ToolHandler to add cross-cutting behavior such as logging,
+// timeouts, or authorization. Middlewares see the tool name via ToolNameFromContext.
+type Middleware func(next ToolHandler) ToolHandler
+
+// toolNameKey is the context key holding the name of the tool being executed
+type toolNameKey struct{}
+
+// withToolName returns a context carrying the executing tool's name
+func withToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+// ToolNameFromContext returns the name of the tool being executed, if any
+func ToolNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey{}).(string)
+	return name
+}
+
+// requestIDKey is the context key holding the ID of the request being served
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID generates a random request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// RegisterMiddleware appends a middleware to the chain wrapping every tool call.
+// Middlewares run in registration order, the first registered being outermost.
+func (s *Server) RegisterMiddleware(middleware Middleware) {
+	s.middlewareMutex.Lock()
+	defer s.middlewareMutex.Unlock()
+
+	s.middlewares = append(s.middlewares, middleware)
+}
+
+// wrapHandler applies the registered middleware chain to a tool handler
+func (s *Server) wrapHandler(handler ToolHandler) ToolHandler {
+	s.middlewareMutex.RLock()
+	defer s.middlewareMutex.RUnlock()
+
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](handler)
+	}
+	return handler
+}
+
+// loggingMiddleware logs tool failures with the tool name
+func (s *Server) loggingMiddleware(next ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		start := time.Now()
+
+		result, err := next(ctx, args)
+		if err != nil {
+			s.logger.Error("Tool execution failed",
+				zap.String("tool", ToolNameFromContext(ctx)),
+				zap.String("request_id", RequestIDFromContext(ctx)),
+				zap.Duration("duration", time.Since(start)),
+				zap.Error(err))
+			return nil, err
+		}
+
+		s.logger.Debug("Tool executed",
+			zap.String("tool", ToolNameFromContext(ctx)),
+			zap.Duration("duration", time.Since(start)))
+
+		return result, nil
+	}
+}
+
+// timeoutMiddleware bounds every tool call by the tool_call timeout, or by
+// the tool's own timeout category for bulk tools that need a longer budget
+func (s *Server) timeoutMiddleware(next ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		category := "tool_call"
+		if tool, exists := s.GetTool(ToolNameFromContext(ctx)); exists && tool.Timeout != "" {
+			category = tool.Timeout
+		}
+
+		ctx, cancel := s.withTimeout(ctx, category)
+		defer cancel()
+
+		result, err := next(ctx, args)
+		return result, timeoutError(ctx, err)
+	}
+}
+
+// warnRenamedTimeouts logs timeout categories set under a name replaced when
+// single and bulk operations were split
+func (s *Server) warnRenamedTimeouts() {
+	for old, replacements := range config.RenamedTimeouts {
+		if _, set := s.config.MCP.Timeouts[old]; set {
+			s.logger.Warn("Timeout category is deprecated; set its replacements instead",
+				zap.String("timeout", "mcp.timeouts."+old),
+				zap.Strings("replacements", replacements))
+		}
+	}
+}
+
+// deadlineError names the timeout category whose deadline ran out
+type deadlineError struct {
+	category string
+	timeout  time.Duration
+}
+
+func (e *deadlineError) Error() string {
+	return fmt.Sprintf("the %s timeout of %s ran out; raise mcp.timeouts.%s for larger operations",
+		e.category, e.timeout, e.category)
+}
+
+// withTimeout bounds ctx by the timeout of an operation category, recording
+// the category as the cause when the deadline passes
+func (s *Server) withTimeout(ctx context.Context, category string) (context.Context, context.CancelFunc) {
+	timeout := s.config.GetTimeout(category)
+	return context.WithTimeoutCause(ctx, timeout, &deadlineError{category: category, timeout: timeout})
+}
+
+// timeoutError adds the timeout that ran out to a deadline error from an
+// operation bounded by withTimeout. The cause comes from whichever deadline
+// passed first, so an outer timeout is not blamed on an inner category.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	var named *deadlineError
+	if errors.As(err, &named) {
+		return err
+	}
+	if cause := context.Cause(ctx); errors.As(cause, &named) {
+		return fmt.Errorf("%w (%w)", err, named)
+	}
+	return err
+}
+
+// recoveryMiddleware turns a panicking tool handler into an error so a single
+// misbehaving tool cannot take down the request goroutine
+func (s *Server) recoveryMiddleware(next ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (result interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.logger.Error("Tool handler panicked",
+					zap.String("tool", ToolNameFromContext(ctx)),
+					zap.String("request_id", RequestIDFromContext(ctx)),
+					zap.Any("panic", recovered),
+					zap.ByteString("stack", debug.Stack()))
+
+				result = nil
+				err = fmt.Errorf("tool '%s' failed with an internal error", ToolNameFromContext(ctx))
+			}
+		}()
+
+		return next(ctx, args)
+	}
+}
@@ -0,0 +1,337 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// versionSuffix matches the version suffix migrate_collection appends to a
+// collection name, e.g. _v2
+var versionSuffix = regexp.MustCompile(`_v[0-9]+$`)
+
+// collectionVersion records the schema version of a database instance's
+// active collection. Instances that were never migrated are at version 1.
+type collectionVersion struct {
+	Version    int                        `json:"version"`
+	Collection string                     `json:"collection"`
+	Options    vectordb.CollectionOptions `json:"options"`
+	MigratedAt time.Time                  `json:"migrated_at,omitzero"`
+}
+
+// pendingMigration keeps the previous version of a migrated instance until
+// the migration is confirmed or rolled back
+type pendingMigration struct {
+	previous        vectordb.VectorDatabase
+	previousVersion collectionVersion
+}
+
+// collectionVersionOf returns the recorded schema version of an instance. The
+// caller must hold dbMutex.
+func (s *Server) collectionVersionOf(dbName string, db vectordb.VectorDatabase) collectionVersion {
+	if version, exists := s.versions[dbName]; exists {
+		return version
+	}
+	return collectionVersion{
+		Version:    1,
+		Collection: db.CollectionName(),
+		Options:    vectordb.CollectionOptions{}.WithDefaults(s.config),
+	}
+}
+
+// versionedCollectionName names the collection holding a version of a base collection
+func versionedCollectionName(collectionName string, version int) string {
+	return versionSuffix.ReplaceAllString(collectionName, "") + "_v" + strconv.Itoa(version)
+}
+
+// handleMigrateCollection handles the migrate_collection tool. It creates a
+// collection for the next schema version, copies the active collection's
+// documents into it, and repoints the instance at it. Writes to the instance
+// are rejected while documents are copied. The previous version is kept until
+// confirm_migration drops it or rollback_migration switches back to it.
+func (s *Server) handleMigrateCollection(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	options, err := parseCollectionOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := 100
+	if b, ok := args["batch_size"].(float64); ok {
+		batchSize = int(b)
+	}
+	if batchSize <= 0 {
+		return nil, invalidArgumentf("batch_size must be positive")
+	}
+
+	reembed, _ := args["reembed"].(bool)
+	if reembed && !s.embedsWrites() {
+		return nil, invalidArgumentf("reembed requires mcp.embedding.writes to be enabled")
+	}
+
+	source, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if s.getRouter(dbName) != nil {
+		return nil, invalidArgumentf("'%s' routes writes to several collections and cannot be migrated", dbName)
+	}
+
+	s.dbMutex.Lock()
+	if _, pending := s.migrations[dbName]; pending || s.migrating[dbName] {
+		s.dbMutex.Unlock()
+		return nil, fmt.Errorf("%w: '%s' has a migration that has not been confirmed or rolled back", ErrMigrationInProgress, dbName)
+	}
+	s.migrating[dbName] = true
+	current := s.collectionVersionOf(dbName, source)
+	s.dbMutex.Unlock()
+
+	defer func() {
+		s.dbMutex.Lock()
+		delete(s.migrating, dbName)
+		s.dbMutex.Unlock()
+	}()
+
+	// Unset options carry over from the current version
+	if options.Embedding == "" {
+		options.Embedding = current.Options.Embedding
+	}
+	if options.VectorSize == 0 {
+		options.VectorSize = current.Options.VectorSize
+	}
+	if options.Metric == "" {
+		options.Metric = current.Options.Metric
+	}
+
+	next := collectionVersion{
+		Version:    current.Version + 1,
+		Collection: versionedCollectionName(source.CollectionName(), current.Version+1),
+		Options:    options,
+	}
+
+	migrateCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("migrate"))
+	defer cancel()
+
+	target, err := vectordb.CreateVectorDatabase(source.Type(), next.Collection, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection version %d: %w", next.Version, err)
+	}
+	if err := target.CreateCollection(migrateCtx, next.Collection, next.Options); err != nil {
+		return nil, fmt.Errorf("failed to create collection version %d: %w", next.Version, err)
+	}
+
+	copied, reembedded, err := s.copyCollection(migrateCtx, source, target, next.Options.VectorSize, batchSize, reembed)
+	if err != nil {
+		s.dropCollectionVersion(migrateCtx, dbName, target)
+		return nil, fmt.Errorf("migration to '%s' failed after copying %d documents; the instance still uses '%s': %w",
+			next.Collection, copied, source.CollectionName(), err)
+	}
+
+	count, err := target.CountDocuments(migrateCtx)
+	if err != nil {
+		s.dropCollectionVersion(migrateCtx, dbName, target)
+		return nil, fmt.Errorf("failed to verify collection '%s': %w", next.Collection, err)
+	}
+
+	// Repoint the instance in one step, keeping the previous version for rollback
+	next.MigratedAt = time.Now().UTC()
+	s.dbMutex.Lock()
+	s.vectorDBs[dbName] = target
+	s.versions[dbName] = next
+	s.migrations[dbName] = &pendingMigration{previous: source, previousVersion: current}
+	s.dbMutex.Unlock()
+	s.invalidateQueryCache(dbName)
+
+	s.toolLogger(ctx).Info("Migrated collection",
+		zap.String("db_name", dbName),
+		zap.String("previous_collection", current.Collection),
+		zap.String("collection", next.Collection),
+		zap.Int("version", next.Version),
+		zap.Int("copied", copied),
+		zap.Int("reembedded", reembedded))
+
+	return map[string]interface{}{
+		"db_name":             dbName,
+		"previous_collection": current.Collection,
+		"previous_version":    current.Version,
+		"collection":          next.Collection,
+		"version":             next.Version,
+		"options":             next.Options,
+		"copied":              copied,
+		"reembedded":          reembedded,
+		"document_count":      count,
+		"status":              "pending_confirmation",
+	}, nil
+}
+
+// copyCollection copies every document of source into target in batches.
+// Vectors whose length differs from dimension are dropped and the text
+// re-embedded when reembed is set, and fail the copy otherwise. A document
+// that cannot be re-embedded fails the copy. Named vectors are kept, so one
+// whose length differs from its configured space fails the copy either way.
+// Batches are written through writeCollectionDocuments like any other write.
+// Soft-deleted documents are copied too, so they can still be restored.
+func (s *Server) copyCollection(ctx context.Context, source, target vectordb.VectorDatabase, dimension, batchSize int, reembed bool) (int, int, error) {
+	copied, reembedded := 0, 0
+	readCtx := vectordb.WithTombstones(ctx)
+	for offset := 0; ; {
+		docs, err := source.ListDocuments(readCtx, batchSize, offset)
+		if err != nil {
+			return copied, reembedded, fmt.Errorf("failed to read documents: %w", err)
+		}
+		if len(docs) == 0 {
+			return copied, reembedded, nil
+		}
+
+		dropped := 0
+		for i, doc := range docs {
+			for name, vector := range doc.Vectors {
+				if named, exists := s.config.MCP.Embedding.NamedVectors[name]; exists && len(vector) != named {
+					return copied, reembedded, invalidArgumentf("%w: document '%s' vector '%s' has %d dimensions but its space expects %d; named vectors are not re-embedded",
+						vectordb.ErrDimensionMismatch, doc.ID, name, len(vector), named)
+				}
+			}
+			if length := doc.VectorDimension(); length > 0 && dimension > 0 && length != dimension {
+				if !reembed {
+					return copied, reembedded, invalidArgumentf("%w: document '%s' has a %d-dimensional vector but the new version expects %d; set reembed to drop stored vectors and re-embed the text",
+						vectordb.ErrDimensionMismatch, doc.ID, length, dimension)
+				}
+				docs[i].Vector = nil
+				docs[i].Vector32 = nil
+				dropped++
+			}
+		}
+
+		batch, err := s.reembedDocuments(ctx, docs, dimension)
+		if err != nil {
+			return copied, reembedded, err
+		}
+
+		stats, err := s.writeCollectionDocuments(ctx, target, batch)
+		if err != nil {
+			return copied, reembedded, fmt.Errorf("failed to write documents: %w", err)
+		}
+		copied += stats.DocumentsWritten
+		reembedded += dropped
+		offset += len(docs)
+
+		if len(docs) < batchSize {
+			return copied, reembedded, nil
+		}
+	}
+}
+
+// dropCollectionVersion deletes a collection version that is no longer
+// needed and releases its connection. Failures are logged, since the
+// instance no longer depends on it.
+func (s *Server) dropCollectionVersion(ctx context.Context, dbName string, db vectordb.VectorDatabase) {
+	if err := db.DeleteCollection(ctx, db.CollectionName()); err != nil {
+		s.logger.Warn("Failed to delete collection version",
+			zap.String("db_name", dbName),
+			zap.String("collection", db.CollectionName()),
+			zap.Error(err))
+	}
+	if err := db.Cleanup(ctx); err != nil {
+		s.logger.Warn("Failed to clean up collection version",
+			zap.String("db_name", dbName),
+			zap.String("collection", db.CollectionName()),
+			zap.Error(err))
+	}
+}
+
+// takePendingMigration removes and returns an instance's pending migration
+func (s *Server) takePendingMigration(dbName string) (*pendingMigration, error) {
+	s.dbMutex.Lock()
+	defer s.dbMutex.Unlock()
+
+	if _, exists := s.vectorDBs[dbName]; !exists {
+		return nil, fmt.Errorf("%w: '%s'", ErrDatabaseNotFound, dbName)
+	}
+	migration, pending := s.migrations[dbName]
+	if !pending {
+		return nil, invalidArgumentf("'%s' has no migration awaiting confirmation", dbName)
+	}
+	delete(s.migrations, dbName)
+	return migration, nil
+}
+
+// handleConfirmMigration handles the confirm_migration tool, dropping the
+// collection version an instance was migrated from
+func (s *Server) handleConfirmMigration(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	migration, err := s.takePendingMigration(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("migrate"))
+	defer cancel()
+	s.dropCollectionVersion(confirmCtx, dbName, migration.previous)
+
+	s.dbMutex.RLock()
+	current := s.versions[dbName]
+	s.dbMutex.RUnlock()
+
+	s.toolLogger(ctx).Info("Confirmed collection migration",
+		zap.String("db_name", dbName),
+		zap.String("collection", current.Collection),
+		zap.String("dropped_collection", migration.previousVersion.Collection))
+
+	return map[string]interface{}{
+		"db_name":            dbName,
+		"collection":         current.Collection,
+		"version":            current.Version,
+		"dropped_collection": migration.previousVersion.Collection,
+	}, nil
+}
+
+// handleRollbackMigration handles the rollback_migration tool, repointing an
+// instance at the collection version it was migrated from and dropping the
+// new one. Documents written since the migration are lost.
+func (s *Server) handleRollbackMigration(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	migration, err := s.takePendingMigration(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dbMutex.Lock()
+	migrated := s.vectorDBs[dbName]
+	s.vectorDBs[dbName] = migration.previous
+	s.versions[dbName] = migration.previousVersion
+	s.dbMutex.Unlock()
+	s.invalidateQueryCache(dbName)
+
+	rollbackCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("migrate"))
+	defer cancel()
+	s.dropCollectionVersion(rollbackCtx, dbName, migrated)
+
+	s.toolLogger(ctx).Info("Rolled back collection migration",
+		zap.String("db_name", dbName),
+		zap.String("collection", migration.previousVersion.Collection),
+		zap.String("dropped_collection", migrated.CollectionName()))
+
+	return map[string]interface{}{
+		"db_name":            dbName,
+		"collection":         migration.previousVersion.Collection,
+		"version":            migration.previousVersion.Version,
+		"dropped_collection": migrated.CollectionName(),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+)
+
+// SetTextPreprocessor installs the preprocessor applied to document and query
+// text before embedding, replacing the one built from mcp.preprocess. Passing
+// nil disables preprocessing so text is embedded as written.
+func (s *Server) SetTextPreprocessor(preprocessor vectordb.TextPreprocessor) {
+	s.preprocessorMutex.Lock()
+	defer s.preprocessorMutex.Unlock()
+
+	s.preprocessor = preprocessor
+}
+
+// getTextPreprocessor returns the configured preprocessor, if any
+func (s *Server) getTextPreprocessor() vectordb.TextPreprocessor {
+	s.preprocessorMutex.RLock()
+	defer s.preprocessorMutex.RUnlock()
+
+	return s.preprocessor
+}
+
+// preprocessQuery returns the query text the backend embeds
+func (s *Server) preprocessQuery(query string) string {
+	if p := s.getTextPreprocessor(); p != nil {
+		return p.Preprocess(query)
+	}
+	return query
+}
+
+// preprocessDocument records the text the backend embeds for doc, keeping
+// the original text for results
+func (s *Server) preprocessDocument(doc vectordb.Document) vectordb.Document {
+	return vectordb.PreprocessDocument(s.getTextPreprocessor(), doc)
+}
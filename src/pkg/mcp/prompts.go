@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"go.uber.org/zap"
+)
+
+// defaultPromptLimit is how many documents a prompt retrieves when neither
+// the prompt nor its limit argument sets a number
+const defaultPromptLimit = 5
+
+// builtinPrompts are available unless a configured prompt of the same name replaces them
+var builtinPrompts = []config.PromptConfig{
+	{
+		Name:        "answer_with_documents",
+		Description: "Answer a question using documents retrieved from a vector database",
+		Arguments: []config.PromptArgumentConfig{
+			{Name: "db_name", Description: "Vector database instance to retrieve documents from", Required: true},
+			{Name: "query", Description: "The question to answer", Required: true},
+			{Name: "limit", Description: "Maximum number of documents to retrieve"},
+		},
+		Template: "Answer the question below using only the documents retrieved from {db_name}. " +
+			"If they do not contain the answer, say so.\n\nQuestion: {query}\n\nDocuments:\n{documents}",
+	},
+}
+
+// Prompt describes an MCP prompt in prompts/list
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments"`
+}
+
+// PromptArgument describes an argument of an MCP prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// PromptMessage is a message of a rendered prompt
+type PromptMessage struct {
+	Role    string            `json:"role"`
+	Content map[string]string `json:"content"`
+}
+
+// PromptResult is a prompt rendered by prompts/get
+type PromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// prompts returns the available built-in and configured prompts by name; a
+// configured prompt replaces a built-in one of the same name. Prompts that
+// retrieve documents are unavailable while the query tool is disabled.
+func (s *Server) prompts() map[string]config.PromptConfig {
+	prompts := make(map[string]config.PromptConfig, len(builtinPrompts)+len(s.config.MCP.Prompts))
+	for _, prompt := range builtinPrompts {
+		prompts[prompt.Name] = prompt
+	}
+	for _, prompt := range s.config.MCP.Prompts {
+		prompts[prompt.Name] = prompt
+	}
+
+	if !s.config.ToolEnabled("query") {
+		for name, prompt := range prompts {
+			if slices.Contains(config.PromptPlaceholders(prompt.Template), config.PromptDocumentsPlaceholder) {
+				delete(prompts, name)
+			}
+		}
+	}
+	return prompts
+}
+
+// ListPrompts lists the available prompts sorted by name
+func (s *Server) ListPrompts() []Prompt {
+	prompts := s.prompts()
+	list := make([]Prompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		arguments := make([]PromptArgument, len(prompt.Arguments))
+		for i, argument := range prompt.Arguments {
+			arguments[i] = PromptArgument{Name: argument.Name, Description: argument.Description, Required: argument.Required}
+		}
+		list = append(list, Prompt{Name: prompt.Name, Description: prompt.Description, Arguments: arguments})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// GetPrompt renders a prompt with the given arguments. A template using
+// {documents} runs the query tool, through the middleware chain, to fill it.
+func (s *Server) GetPrompt(ctx context.Context, name string, args map[string]string) (PromptResult, error) {
+	prompt, exists := s.prompts()[name]
+	if !exists {
+		return PromptResult{}, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+
+	declared := make([]string, 0, len(prompt.Arguments))
+	for _, argument := range prompt.Arguments {
+		declared = append(declared, argument.Name)
+		if argument.Required && args[argument.Name] == "" {
+			return PromptResult{}, invalidArgumentf("argument '%s' is required", argument.Name)
+		}
+	}
+	values := make(map[string]string, len(args)+1)
+	for key, value := range args {
+		if !slices.Contains(declared, key) {
+			return PromptResult{}, invalidArgumentf("prompt '%s' has no argument '%s'", name, key)
+		}
+		values[key] = value
+	}
+
+	if slices.Contains(config.PromptPlaceholders(prompt.Template), config.PromptDocumentsPlaceholder) {
+		documents, err := s.promptDocuments(ctx, prompt, args)
+		if err != nil {
+			return PromptResult{}, err
+		}
+		values[config.PromptDocumentsPlaceholder] = documents
+	}
+
+	return PromptResult{
+		Description: prompt.Description,
+		Messages: []PromptMessage{{
+			Role:    "user",
+			Content: map[string]string{"type": "text", "text": config.RenderPrompt(prompt.Template, values)},
+		}},
+	}, nil
+}
+
+// promptDocuments runs the query tool for a prompt's db_name and query
+// arguments and returns its answer as text
+func (s *Server) promptDocuments(ctx context.Context, prompt config.PromptConfig, args map[string]string) (string, error) {
+	limit := prompt.Limit
+	if limit == 0 {
+		limit = defaultPromptLimit
+	}
+	if l, ok := args["limit"]; ok && l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			return "", invalidArgumentf("limit must be a positive integer")
+		}
+		limit = parsed
+	}
+
+	result, err := s.CallTool(ctx, "query", map[string]interface{}{
+		"db_name": args["db_name"],
+		"query":   args["query"],
+		"limit":   float64(limit),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve documents for prompt '%s': %w", prompt.Name, err)
+	}
+
+	if text, ok := result.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode documents for prompt '%s': %w", prompt.Name, err)
+	}
+	return string(encoded), nil
+}
+
+// handlePromptsList serves prompts/list
+func (s *Server) handlePromptsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"prompts": s.ListPrompts()}); err != nil {
+		s.logger.Error("Failed to encode prompts list response", zap.Error(err))
+	}
+}
+
+// handlePromptsGet serves prompts/get for the name and arguments in the request body
+func (s *Server) handlePromptsGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer s.inFlight.Done()
+
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	w.Header().Set("X-Request-ID", requestID)
+
+	result, err := s.GetPrompt(WithRequestID(r.Context(), requestID), request.Name, request.Arguments)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidArgument) && !errors.Is(err, ErrPromptNotFound) {
+			s.logger.Warn("Failed to get prompt", zap.String("name", request.Name), zap.Error(err))
+		}
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+
+	s.logger.Info("Rendered prompt", zap.String("name", request.Name))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		s.logger.Error("Failed to encode prompt response", zap.Error(err))
+	}
+}
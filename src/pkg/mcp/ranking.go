@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+)
+
+// defaultRecencyHalfLife applies when mcp.recency.half_life is unset
+const defaultRecencyHalfLife = 30 * 24 * time.Hour
+
+// rankOptions selects the steps query and search apply to the retrieved
+// candidates before returning them
+type rankOptions struct {
+	rerank        bool
+	dedupeBy      string
+	recencyWeight float64
+}
+
+// parseRankOptions reads the rerank, dedupe_by, and recency_weight arguments
+func parseRankOptions(args map[string]interface{}) (rankOptions, error) {
+	var options rankOptions
+	options.rerank, _ = args["rerank"].(bool)
+
+	dedupeBy, err := parseDedupeBy(args)
+	if err != nil {
+		return rankOptions{}, err
+	}
+	options.dedupeBy = dedupeBy
+
+	if weight, ok := args["recency_weight"].(float64); ok {
+		if err := vectordb.ValidateRecencyWeight(weight); err != nil {
+			return rankOptions{}, invalidArgument(err)
+		}
+		options.recencyWeight = weight
+	}
+	return options, nil
+}
+
+// active reports whether any step needs the scored candidates
+func (o rankOptions) active() bool {
+	return o.rerank || o.dedupeBy != "" || o.recencyWeight > 0
+}
+
+// rankCandidates returns how many candidates to fetch so the ranking steps
+// still fill limit, and the document fields they read
+func (s *Server) rankCandidates(limit int, options rankOptions) (int, []string) {
+	fetch := limit
+	var needed []string
+	if options.rerank {
+		fetch = s.rerankCandidates(limit)
+		needed = append(needed, vectordb.FieldText)
+	}
+	if options.recencyWeight > 0 {
+		fetch = max(fetch, limit*vectordb.RecencyOverfetch)
+		needed = append(needed, vectordb.FieldCreatedAt)
+	}
+	if options.dedupeBy != "" {
+		fetch = max(fetch, limit*vectordb.DedupeOverfetch)
+		needed = append(needed, vectordb.DedupeField(options.dedupeBy))
+	}
+	return fetch, needed
+}
+
+// rankResults re-ranks, boosts recent documents, and deduplicates the
+// candidates in that order, then keeps the top limit. The boolean result
+// reports whether a reranker was applied.
+func (s *Server) rankResults(ctx context.Context, query string, results []vectordb.SearchResult, limit int, options rankOptions) ([]vectordb.SearchResult, bool, error) {
+	// Later steps reorder or collapse results, so keep every candidate until the end
+	keep := limit
+	if options.dedupeBy != "" || options.recencyWeight > 0 {
+		keep = len(results)
+	}
+
+	reranked := false
+	if options.rerank {
+		var err error
+		results, reranked, err = s.rerankResults(ctx, query, results, keep)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if options.recencyWeight > 0 {
+		halfLife := s.config.MCP.Recency.HalfLife
+		if halfLife <= 0 {
+			halfLife = defaultRecencyHalfLife
+		}
+		results = vectordb.BoostRecency(results, options.recencyWeight, halfLife, time.Now())
+	}
+
+	if options.dedupeBy != "" {
+		return vectordb.DedupeResults(results, options.dedupeBy, limit), reranked, nil
+	}
+	return results[:min(limit, len(results))], reranked, nil
+}
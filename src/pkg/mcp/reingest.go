@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// handleReingest handles the reingest tool, replacing every document of an
+// instance's collection with the given documents. With shadow, the documents
+// are loaded into a new collection version and the instance is repointed at
+// it in one step once the load is complete, so queries see either the old
+// documents or the new ones, never a partial load. Without shadow, the live
+// collection is emptied and reloaded in place.
+func (s *Server) handleReingest(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	dbName, ok := args["db_name"].(string)
+	if !ok {
+		return nil, invalidArgumentf("db_name is required and must be a string")
+	}
+
+	documents, err := s.documentsFromArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := defaultTransferBatchSize
+	if b, ok := args["batch_size"].(float64); ok {
+		batchSize = int(b)
+	}
+	if batchSize <= 0 {
+		return nil, invalidArgumentf("batch_size must be positive")
+	}
+
+	shadow := s.config.MCP.Reingest.Shadow
+	if sh, ok := args["shadow"].(bool); ok {
+		shadow = sh
+	}
+	keepPrevious, _ := args["keep_previous"].(bool)
+	if keepPrevious && !shadow {
+		return nil, invalidArgumentf("keep_previous requires shadow")
+	}
+
+	db, err := s.getWritableDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if s.getRouter(dbName) != nil {
+		return nil, invalidArgumentf("'%s' routes writes to several collections and cannot be reingested", dbName)
+	}
+
+	reingestCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("reingest"))
+	defer cancel()
+
+	if shadow {
+		return s.reingestShadow(reingestCtx, dbName, db, documents, batchSize, keepPrevious)
+	}
+	return s.reingestInPlace(reingestCtx, dbName, db, documents, batchSize)
+}
+
+// writeInBatches writes documents to db batchSize at a time through
+// writeCollectionDocuments, returning how many were written. A document that
+// could not be embedded or written fails the load.
+func (s *Server) writeInBatches(ctx context.Context, db vectordb.VectorDatabase, documents []vectordb.Document, batchSize int) (int, error) {
+	if err := s.checkVectorDimensions(ctx, db, documents); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for start := 0; start < len(documents); start += batchSize {
+		end := min(start+batchSize, len(documents))
+		stats, err := s.writeCollectionDocuments(ctx, db, documents[start:end])
+		written += stats.DocumentsWritten
+		if err != nil {
+			return written, fmt.Errorf("failed to write documents: %w", err)
+		}
+		if len(stats.Errors) > 0 {
+			return written, fmt.Errorf("failed to write %d documents: %s", len(stats.Errors), strings.Join(stats.Errors, "; "))
+		}
+	}
+	return written, nil
+}
+
+// reingestInPlace empties the live collection and writes the documents into
+// it. Queries made during the load see a partial collection.
+func (s *Server) reingestInPlace(ctx context.Context, dbName string, db vectordb.VectorDatabase, documents []vectordb.Document, batchSize int) (interface{}, error) {
+	removed, err := db.TruncateCollection(ctx, db.CollectionName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to empty collection '%s': %w", db.CollectionName(), err)
+	}
+	s.invalidateQueryCache(dbName)
+
+	written, err := s.writeInBatches(ctx, db, documents, batchSize)
+	s.invalidateQueryCache(dbName)
+	if err != nil {
+		return nil, fmt.Errorf("reingest of '%s' failed after writing %d of %d documents; the collection is partially loaded: %w",
+			db.CollectionName(), written, len(documents), err)
+	}
+
+	s.toolLogger(ctx).Info("Reingested collection in place",
+		zap.String("db_name", dbName),
+		zap.String("collection", db.CollectionName()),
+		zap.Int("removed", removed),
+		zap.Int("written", written))
+
+	return map[string]interface{}{
+		"db_name":    dbName,
+		"collection": db.CollectionName(),
+		"shadow":     false,
+		"removed":    removed,
+		"written":    written,
+	}, nil
+}
+
+// reingestShadow loads the documents into the next collection version and
+// repoints the instance at it. Writes to the instance are rejected during the
+// load, since they would not carry over. The previous version is dropped, or
+// kept for confirm_migration and rollback_migration with keepPrevious.
+func (s *Server) reingestShadow(ctx context.Context, dbName string, source vectordb.VectorDatabase, documents []vectordb.Document, batchSize int, keepPrevious bool) (interface{}, error) {
+	s.dbMutex.Lock()
+	if _, pending := s.migrations[dbName]; pending || s.migrating[dbName] {
+		s.dbMutex.Unlock()
+		return nil, fmt.Errorf("%w: '%s' has a migration that has not been confirmed or rolled back", ErrMigrationInProgress, dbName)
+	}
+	s.migrating[dbName] = true
+	current := s.collectionVersionOf(dbName, source)
+	s.dbMutex.Unlock()
+
+	defer func() {
+		s.dbMutex.Lock()
+		delete(s.migrating, dbName)
+		s.dbMutex.Unlock()
+	}()
+
+	next := collectionVersion{
+		Version:    current.Version + 1,
+		Collection: versionedCollectionName(source.CollectionName(), current.Version+1),
+		Options:    current.Options,
+	}
+
+	target, err := vectordb.CreateVectorDatabase(source.Type(), next.Collection, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow collection: %w", err)
+	}
+	if err := target.CreateCollection(ctx, next.Collection, next.Options); err != nil {
+		return nil, fmt.Errorf("failed to create shadow collection: %w", err)
+	}
+
+	written, err := s.writeInBatches(ctx, target, documents, batchSize)
+	if err != nil {
+		s.dropCollectionVersion(ctx, dbName, target)
+		return nil, fmt.Errorf("reingest into '%s' failed after writing %d of %d documents; the instance still serves '%s': %w",
+			next.Collection, written, len(documents), source.CollectionName(), err)
+	}
+
+	count, err := target.CountDocuments(ctx)
+	if err != nil {
+		s.dropCollectionVersion(ctx, dbName, target)
+		return nil, fmt.Errorf("failed to verify shadow collection '%s': %w", next.Collection, err)
+	}
+
+	// Repoint the instance in one step, so queries switch from the old documents to the new
+	next.MigratedAt = time.Now().UTC()
+	s.dbMutex.Lock()
+	s.vectorDBs[dbName] = target
+	s.versions[dbName] = next
+	if keepPrevious {
+		s.migrations[dbName] = &pendingMigration{previous: source, previousVersion: current}
+	}
+	s.dbMutex.Unlock()
+	s.invalidateQueryCache(dbName)
+
+	if !keepPrevious {
+		s.dropCollectionVersion(ctx, dbName, source)
+	}
+
+	s.toolLogger(ctx).Info("Reingested collection through a shadow collection",
+		zap.String("db_name", dbName),
+		zap.String("previous_collection", current.Collection),
+		zap.String("collection", next.Collection),
+		zap.Int("version", next.Version),
+		zap.Int("written", written),
+		zap.Bool("kept_previous", keepPrevious))
+
+	response := map[string]interface{}{
+		"db_name":             dbName,
+		"shadow":              true,
+		"previous_collection": current.Collection,
+		"collection":          next.Collection,
+		"version":             next.Version,
+		"written":             written,
+		"document_count":      count,
+	}
+	if keepPrevious {
+		response["status"] = "pending_confirmation"
+	}
+	return response, nil
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// SetReranker installs the reranker used when query or search is called with
+// rerank=true, replacing the one built from mcp.rerank. Passing nil disables
+// re-ranking so raw vector scores are returned.
+func (s *Server) SetReranker(reranker vectordb.Reranker) {
+	s.rerankerMutex.Lock()
+	defer s.rerankerMutex.Unlock()
+
+	s.reranker = reranker
+}
+
+// getReranker returns the configured reranker, if any
+func (s *Server) getReranker() vectordb.Reranker {
+	s.rerankerMutex.RLock()
+	defer s.rerankerMutex.RUnlock()
+
+	return s.reranker
+}
+
+// rerankCandidates returns how many results to fetch so the reranker has a
+// wider pool than the requested limit to choose from
+func (s *Server) rerankCandidates(limit int) int {
+	if s.getReranker() == nil {
+		return limit
+	}
+	return max(limit, s.config.MCP.Rerank.Candidates)
+}
+
+// rerankResults re-scores results with the configured reranker and keeps the
+// top limit. Without a reranker the vector scores are kept as they are. The
+// boolean result reports whether re-ranking was applied.
+func (s *Server) rerankResults(ctx context.Context, query string, results []vectordb.SearchResult, limit int) ([]vectordb.SearchResult, bool, error) {
+	reranker := s.getReranker()
+	if reranker == nil {
+		s.logger.Debug("No reranker configured, using vector scores", zap.String("query", query))
+		return results[:min(limit, len(results))], false, nil
+	}
+
+	reranked, err := reranker.Rerank(ctx, query, results)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to rerank results: %w", err)
+	}
+	return reranked[:min(limit, len(reranked))], true, nil
+}
@@ -0,0 +1,262 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// resourceScheme is the URI scheme of the MCP resources the server exposes:
+// vectordb://<db_name>/<collection> for a collection and
+// vectordb://<db_name>/<collection>/<doc_id> for one of its documents
+const resourceScheme = "vectordb"
+
+// resourceMimeType is the content type of every resource, which is read as JSON
+const resourceMimeType = "application/json"
+
+// Resource describes an MCP resource in resources/list
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+// ResourceContents is the content of a resource returned by resources/read
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// resourceURI builds the URI of a collection or, with a document ID, of a document
+func resourceURI(dbName, collectionName, documentID string) string {
+	uri := resourceScheme + "://" + url.PathEscape(dbName) + "/" + url.PathEscape(collectionName)
+	if documentID != "" {
+		uri += "/" + url.PathEscape(documentID)
+	}
+	return uri
+}
+
+// parseResourceURI splits a resource URI into the database instance,
+// collection, and document ID it names. The document ID is empty for a
+// collection.
+func parseResourceURI(uri string) (string, string, string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", "", "", invalidArgumentf("invalid resource uri '%s': %w", uri, err)
+	}
+	if parsed.Scheme != resourceScheme {
+		return "", "", "", invalidArgumentf("resource uri '%s' must use the %s:// scheme", uri, resourceScheme)
+	}
+
+	segments := strings.Split(strings.TrimPrefix(parsed.EscapedPath(), "/"), "/")
+	if parsed.Host == "" || len(segments) > 2 || segments[0] == "" {
+		return "", "", "", invalidArgumentf("resource uri '%s' must name %s://db_name/collection or %s://db_name/collection/doc_id",
+			uri, resourceScheme, resourceScheme)
+	}
+
+	var parts []string
+	for _, segment := range segments {
+		part, err := url.PathUnescape(segment)
+		if err != nil || part == "" {
+			return "", "", "", invalidArgumentf("resource uri '%s' has an invalid path segment '%s'", uri, segment)
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 1 {
+		return parsed.Host, parts[0], "", nil
+	}
+	return parsed.Host, parts[0], parts[1], nil
+}
+
+// servedCollections returns the collections an instance serves documents
+// from: its own collection and, for a routed instance, each routed collection
+func (s *Server) servedCollections(dbName string, db vectordb.VectorDatabase) []string {
+	collections := []string{db.CollectionName()}
+	if router := s.getRouter(dbName); router != nil {
+		router.mutex.Lock()
+		for name := range router.routed {
+			collections = append(collections, name)
+		}
+		router.mutex.Unlock()
+		sort.Strings(collections[1:])
+	}
+	return collections
+}
+
+// ListResources lists every collection served by a registered database
+// instance as a resource and, when mcp.resources.list_documents is set, up to
+// max_documents of each collection's documents
+func (s *Server) ListResources(ctx context.Context) ([]Resource, error) {
+	s.dbMutex.RLock()
+	names := make([]string, 0, len(s.vectorDBs))
+	dbs := make(map[string]vectordb.VectorDatabase, len(s.vectorDBs))
+	for name, db := range s.vectorDBs {
+		names = append(names, name)
+		dbs[name] = db
+	}
+	s.dbMutex.RUnlock()
+	sort.Strings(names)
+
+	cfg := s.config.MCP.Resources
+	resources := make([]Resource, 0, len(names))
+	for _, dbName := range names {
+		for _, collectionName := range s.servedCollections(dbName, dbs[dbName]) {
+			resources = append(resources, Resource{
+				URI:         resourceURI(dbName, collectionName, ""),
+				Name:        dbName + "/" + collectionName,
+				Description: fmt.Sprintf("Collection '%s' of the %s database '%s'", collectionName, dbs[dbName].Type(), dbName),
+				MimeType:    resourceMimeType,
+			})
+			if !cfg.ListDocuments || cfg.MaxDocuments <= 0 {
+				continue
+			}
+
+			db, _ := s.resolveCollection(dbName, dbs[dbName], collectionName)
+			listCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("list_documents"))
+			docs, err := db.ListDocuments(listCtx, cfg.MaxDocuments, 0)
+			cancel()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list documents of '%s/%s': %w", dbName, collectionName, err)
+			}
+			for _, doc := range docs {
+				resources = append(resources, Resource{
+					URI:         resourceURI(dbName, collectionName, doc.ID),
+					Name:        doc.URL,
+					Description: fmt.Sprintf("Document '%s' in '%s/%s'", doc.ID, dbName, collectionName),
+					MimeType:    resourceMimeType,
+				})
+			}
+		}
+	}
+	return resources, nil
+}
+
+// ReadResource reads a resource by URI. A collection reads as its collection
+// info and a document as the stored document without its vectors.
+func (s *Server) ReadResource(ctx context.Context, uri string) (ResourceContents, error) {
+	dbName, collectionName, documentID, err := parseResourceURI(uri)
+	if err != nil {
+		return ResourceContents{}, err
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return ResourceContents{}, err
+	}
+
+	var content interface{}
+	if documentID == "" {
+		infoCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("get_collection_info"))
+		defer cancel()
+
+		db, resolved := s.resolveCollection(dbName, db, collectionName)
+		if resolved == "" {
+			resolved = db.CollectionName()
+		}
+		info, err := db.GetCollectionInfo(infoCtx, resolved)
+		if err != nil {
+			return ResourceContents{}, fmt.Errorf("failed to get collection info: %w", err)
+		}
+		content = info
+	} else {
+		// Documents are read through the database serving the collection, so
+		// only collections the instance serves have document resources
+		db, resolved := s.resolveCollection(dbName, db, collectionName)
+		if resolved != "" && resolved != db.CollectionName() {
+			return ResourceContents{}, fmt.Errorf("%w: '%s' does not serve collection '%s'", vectordb.ErrCollectionNotFound, dbName, collectionName)
+		}
+
+		getCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("query"))
+		defer cancel()
+
+		doc, err := db.GetDocument(getCtx, documentID)
+		if err == nil && vectordb.IsTombstoned(doc) {
+			err = fmt.Errorf("%w: %s", vectordb.ErrDocumentNotFound, documentID)
+		}
+		if err != nil {
+			return ResourceContents{}, fmt.Errorf("failed to read document: %w", err)
+		}
+		content = stripVectors([]vectordb.Document{doc})[0]
+	}
+
+	text, err := json.Marshal(content)
+	if err != nil {
+		return ResourceContents{}, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return ResourceContents{URI: uri, MimeType: resourceMimeType, Text: string(text)}, nil
+}
+
+// handleResourcesList serves resources/list
+func (s *Server) handleResourcesList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.config.MCP.Resources.Enabled {
+		http.Error(w, "Resources are disabled", http.StatusNotFound)
+		return
+	}
+
+	resources, err := s.ListResources(r.Context())
+	if err != nil {
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"resources": resources}); err != nil {
+		s.logger.Error("Failed to encode resources list response", zap.Error(err))
+	}
+}
+
+// handleResourcesRead serves resources/read for the uri in the request body
+func (s *Server) handleResourcesRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.config.MCP.Resources.Enabled {
+		http.Error(w, "Resources are disabled", http.StatusNotFound)
+		return
+	}
+
+	var request struct {
+		URI string `json:"uri"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer s.inFlight.Done()
+
+	contents, err := s.ReadResource(r.Context(), request.URI)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidArgument) {
+			s.logger.Warn("Failed to read resource", zap.String("uri", request.URI), zap.Error(err))
+		}
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+
+	s.logger.Info("Read resource", zap.String("uri", request.URI))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"contents": []ResourceContents{contents}}); err != nil {
+		s.logger.Error("Failed to encode resource read response", zap.Error(err))
+	}
+}
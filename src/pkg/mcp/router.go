@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// routeByPrefix marks a route_by rule as naming a document metadata field
+const routeByPrefix = "metadata."
+
+// collectionRouter sends a database instance's writes to the collection named
+// by a metadata field of each document, creating routed collections on demand
+type collectionRouter struct {
+	field     string
+	embedding string
+	mutex     sync.Mutex
+	routed    map[string]vectordb.VectorDatabase // by collection name
+}
+
+// parseRouteBy validates a route_by rule such as metadata.type and returns the
+// metadata field it names
+func parseRouteBy(rule string) (string, error) {
+	field, ok := strings.CutPrefix(rule, routeByPrefix)
+	if !ok || field == "" {
+		return "", invalidArgumentf("route_by must name a metadata field, e.g. metadata.type")
+	}
+	return field, nil
+}
+
+// getRouter returns the router for a database instance, or nil when its
+// writes are not routed
+func (s *Server) getRouter(dbName string) *collectionRouter {
+	s.dbMutex.RLock()
+	defer s.dbMutex.RUnlock()
+	return s.routers[dbName]
+}
+
+// routeDocument returns the database a document should be written to. Without
+// a router, or when the document lacks the routing field, that is the instance
+// itself; otherwise it is the routed collection, set up on first use.
+func (s *Server) routeDocument(ctx context.Context, dbName string, db vectordb.VectorDatabase, doc vectordb.Document) (vectordb.VectorDatabase, error) {
+	router := s.getRouter(dbName)
+	if router == nil {
+		return db, nil
+	}
+
+	raw, exists := doc.Metadata[router.field]
+	if !exists || raw == "" {
+		return db, nil
+	}
+	collection, ok := raw.(string)
+	if !ok {
+		return nil, invalidArgumentf("metadata field '%s' routes documents to a collection and must be a string", router.field)
+	}
+	if collection == db.CollectionName() {
+		return db, nil
+	}
+	if err := vectordb.ValidateCollectionName(db.Type(), collection); err != nil {
+		return nil, invalidArgumentf("cannot route to collection named by metadata field '%s': %w", router.field, err)
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if routed, exists := router.routed[collection]; exists {
+		return routed, nil
+	}
+
+	routed, err := vectordb.CreateVectorDatabase(db.Type(), collection, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create routed collection '%s': %w", collection, err)
+	}
+	if err := routed.Setup(ctx, router.embedding); err != nil {
+		return nil, fmt.Errorf("failed to set up routed collection '%s': %w", collection, err)
+	}
+	router.routed[collection] = routed
+
+	s.toolLogger(ctx).Info("Created routed collection",
+		zap.String("db_name", dbName),
+		zap.String("collection", collection),
+		zap.String("field", router.field))
+
+	return routed, nil
+}
+
+// resolveCollection returns the database serving collectionName for an
+// instance: a routed collection is served by its own database, anything else
+// by the instance itself
+func (s *Server) resolveCollection(dbName string, db vectordb.VectorDatabase, collectionName string) (vectordb.VectorDatabase, string) {
+	router := s.getRouter(dbName)
+	if router == nil || collectionName == "" {
+		return db, collectionName
+	}
+
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	if routed, exists := router.routed[collectionName]; exists {
+		return routed, ""
+	}
+	return db, collectionName
+}
+
+// writeRoutedDocuments writes a batch, grouping documents by the collection
+// they route to so each collection receives a single batch write. Documents
+// that could not be embedded under mcp.embedding.writes are reported in the
+// stats' errors and the rest are written.
+func (s *Server) writeRoutedDocuments(ctx context.Context, dbName string, db vectordb.VectorDatabase, docs []vectordb.Document, autoSetup bool) (vectordb.WriteStats, error) {
+	docs, embedErrors := s.embedWriteDocuments(ctx, docs)
+	var stats vectordb.WriteStats
+	var err error
+	if len(docs) > 0 {
+		stats, err = s.writeRoutedBatch(ctx, dbName, db, docs, autoSetup)
+	}
+	stats.Errors = append(embedErrors, stats.Errors...)
+	return stats, err
+}
+
+// writeCollectionDocuments writes a batch to a single collection the way
+// every write path does: documents without a vector are embedded under
+// mcp.embedding.writes, vectors are checked against the collection's
+// dimension and embedding model, and documents whose content is already
+// stored are dropped. Documents that could not be embedded are reported in
+// the stats' errors and the rest are written.
+func (s *Server) writeCollectionDocuments(ctx context.Context, db vectordb.VectorDatabase, docs []vectordb.Document) (vectordb.WriteStats, error) {
+	docs, embedErrors := s.embedWriteDocuments(ctx, docs)
+	var stats vectordb.WriteStats
+	var err error
+	if len(docs) > 0 {
+		stats, err = s.writeEmbeddedDocuments(ctx, db, docs, db.WriteDocuments)
+	}
+	stats.Errors = append(embedErrors, stats.Errors...)
+	return stats, err
+}
+
+// writeEmbeddedDocuments checks the vectors of embedded documents bound for
+// db, drops duplicate content, and writes the rest with write
+func (s *Server) writeEmbeddedDocuments(ctx context.Context, db vectordb.VectorDatabase, docs []vectordb.Document, write func(context.Context, []vectordb.Document) (vectordb.WriteStats, error)) (vectordb.WriteStats, error) {
+	if err := s.checkVectorDimensions(ctx, db, docs); err != nil {
+		return vectordb.WriteStats{}, err
+	}
+	if err := s.checkWriteEmbeddingModel(ctx, db); err != nil {
+		return vectordb.WriteStats{}, err
+	}
+	docs, duplicates, err := s.dedupeContent(ctx, db, docs)
+	if err != nil {
+		return vectordb.WriteStats{}, err
+	}
+	if len(docs) == 0 {
+		return vectordb.WriteStats{Duplicates: duplicates}, nil
+	}
+	stats, err := write(ctx, docs)
+	stats.Duplicates = duplicates
+	return stats, err
+}
+
+// writeRoutedBatch writes the documents of writeRoutedDocuments
+func (s *Server) writeRoutedBatch(ctx context.Context, dbName string, db vectordb.VectorDatabase, docs []vectordb.Document, autoSetup bool) (vectordb.WriteStats, error) {
+	if s.getRouter(dbName) == nil {
+		return s.writeEmbeddedDocuments(ctx, db, docs, func(ctx context.Context, docs []vectordb.Document) (vectordb.WriteStats, error) {
+			return s.withAutoSetup(ctx, dbName, db, autoSetup, func() (vectordb.WriteStats, error) {
+				return db.WriteDocuments(ctx, docs)
+			})
+		})
+	}
+
+	start := time.Now()
+	var targets []vectordb.VectorDatabase
+	groups := make(map[vectordb.VectorDatabase][]vectordb.Document)
+	for _, doc := range docs {
+		target, err := s.routeDocument(ctx, dbName, db, doc)
+		if err != nil {
+			return vectordb.WriteStats{}, err
+		}
+		if _, exists := groups[target]; !exists {
+			targets = append(targets, target)
+		}
+		groups[target] = append(groups[target], doc)
+	}
+
+	for _, target := range targets {
+		if err := s.checkVectorDimensions(ctx, target, groups[target]); err != nil {
+			return vectordb.WriteStats{}, err
+		}
+		if err := s.checkWriteEmbeddingModel(ctx, target); err != nil {
+			return vectordb.WriteStats{}, err
+		}
+	}
+
+	var total vectordb.WriteStats
+	for _, target := range targets {
+		docs, duplicates, err := s.dedupeContent(ctx, target, groups[target])
+		if err != nil {
+			return total, fmt.Errorf("collection '%s': %w", target.CollectionName(), err)
+		}
+		total.Duplicates += duplicates
+		if len(docs) == 0 {
+			continue
+		}
+		stats, err := s.withAutoSetup(ctx, dbName, target, autoSetup, func() (vectordb.WriteStats, error) {
+			return target.WriteDocuments(ctx, docs)
+		})
+		total.DocumentsWritten += stats.DocumentsWritten
+		total.Batches += stats.Batches
+		total.Errors = append(total.Errors, stats.Errors...)
+		if err != nil {
+			return total, fmt.Errorf("collection '%s': %w", target.CollectionName(), err)
+		}
+	}
+	total.ProcessingTime = time.Since(start).String()
+	return total, nil
+}
@@ -3,23 +3,62 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sync"
-	"time"
 
 	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
 	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
 	"go.uber.org/zap"
 )
 
+// dbNamePattern restricts database instance names to URL- and log-friendly characters
+var dbNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
 // Server represents the MCP server implementation
 type Server struct {
-	config    *config.Config
-	logger    *zap.Logger
-	vectorDBs map[string]vectordb.VectorDatabase
-	dbMutex   sync.RWMutex
-	Tools     map[string]Tool
+	config        *config.Config
+	logger        *zap.Logger
+	toolLoggers   map[string]*zap.Logger // by tool, for logging.tool_levels
+	vectorDBs     map[string]vectordb.VectorDatabase
+	routers       map[string]*collectionRouter // guarded by dbMutex
+	readOnly      map[string]bool              // guarded by dbMutex
+	versions      map[string]collectionVersion // guarded by dbMutex
+	migrations    map[string]*pendingMigration // guarded by dbMutex
+	migrating     map[string]bool              // guarded by dbMutex
+	dbMutex       sync.RWMutex
+	queryCache    *queryCache
+	idempotency   *idempotencyStore
+	snapshots     *snapshotStore
+	auditLog      *auditLog
+	ttlSweep      *ttlSweepState
+	reranker      vectordb.Reranker // guarded by rerankerMutex
+	embedder      vectordb.Embedder
+	rerankerMutex sync.RWMutex
+
+	embeddingState *embeddingState                  // nil without an embedding provider
+	embeddingQueue *embeddingQueue                  // nil unless writes are queued while it is down
+	coalescer      *coalescingEmbedder              // nil without an embedding provider
+	modelChecked   map[vectordb.VectorDatabase]bool // guarded by modelMutex
+	modelMutex     sync.Mutex
+
+	preprocessor      vectordb.TextPreprocessor // guarded by preprocessorMutex
+	preprocessorMutex sync.RWMutex
+	expander          vectordb.Expander // guarded by expanderMutex
+	expanderMutex     sync.RWMutex
+	Tools             map[string]Tool // guarded by toolsMutex
+	toolsMutex        sync.RWMutex
+
+	middlewares     []Middleware
+	middlewareMutex sync.RWMutex
+
+	clientLimiter *clientLimiter // nil without a per-client cap
+
+	inFlight   sync.WaitGroup
+	drainMutex sync.Mutex
+	draining   bool
 }
 
 // Tool represents an MCP tool
@@ -27,167 +66,1091 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
-	Handler     func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+	Handler     ToolHandler            `json:"-"`
+	Mutating    bool                   `json:"-"` // changes stored data; calls are audited
+	Timeout     string                 `json:"-"` // timeout category bounding the call; tool_call when empty
 }
 
 // NewServer creates a new MCP server
 func NewServer(cfg *config.Config, logger *zap.Logger) (*Server, error) {
 	server := &Server{
-		config:    cfg,
-		logger:    logger,
-		vectorDBs: make(map[string]vectordb.VectorDatabase),
-		Tools:     make(map[string]Tool),
+		config:       cfg,
+		logger:       logger,
+		vectorDBs:    make(map[string]vectordb.VectorDatabase),
+		routers:      make(map[string]*collectionRouter),
+		readOnly:     make(map[string]bool),
+		versions:     make(map[string]collectionVersion),
+		migrations:   make(map[string]*pendingMigration),
+		migrating:    make(map[string]bool),
+		modelChecked: make(map[vectordb.VectorDatabase]bool),
+		Tools:        make(map[string]Tool),
+		snapshots:    newSnapshotStore(cfg.MCP.Snapshot),
+		toolLoggers:  newToolLoggers(logger, cfg.Logging.ToolLevels),
+	}
+
+	if cfg.MCP.QueryCache.Enabled {
+		server.queryCache = newQueryCache(cfg.MCP.QueryCache.TTL, cfg.MCP.QueryCache.MaxSize)
+	}
+
+	if cfg.MCP.Idempotency.Window > 0 {
+		server.idempotency = newIdempotencyStore(cfg.MCP.Idempotency.Window, cfg.MCP.Idempotency.MaxKeys)
+	}
+
+	if cfg.MCP.PerClientConcurrency.MaxInFlight > 0 {
+		server.clientLimiter = newClientLimiter(cfg.MCP.PerClientConcurrency)
+	}
+
+	if cfg.MCP.TTLSweep.Enabled {
+		server.ttlSweep = &ttlSweepState{interval: cfg.MCP.TTLSweep.Interval}
+	}
+
+	if cfg.MCP.Rerank.URL != "" {
+		server.reranker = vectordb.NewHTTPReranker(cfg.MCP.Rerank)
 	}
 
+	embedder, err := vectordb.NewEmbedder(cfg.MCP.Embedding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedder: %w", err)
+	}
+	if embedder != nil {
+		server.embeddingState = &embeddingState{threshold: max(cfg.MCP.Embedding.Fallback.FailureThreshold, 1)}
+		server.coalescer = newCoalescingEmbedder(&trackedEmbedder{next: embedder, state: server.embeddingState, logger: logger}, cfg.MCP.Embedding)
+		server.embedder = server.coalescer
+	}
+
+	if cfg.MCP.Embedding.Fallback.QueueWrites {
+		queue, err := newEmbeddingQueue(cfg.MCP.Embedding.Fallback)
+		if err != nil {
+			return nil, err
+		}
+		server.embeddingQueue = queue
+	}
+
+	preprocessor, err := vectordb.NewPreprocessor(cfg.MCP.Preprocess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create text preprocessor: %w", err)
+	}
+	server.preprocessor = preprocessor
+
+	if expander := vectordb.NewSynonymExpander(cfg.MCP.Expansion); expander != nil {
+		server.expander = expander
+	}
+
+	// The audit middleware is outermost so it also records calls that panic
+	if cfg.MCP.Audit.Enabled {
+		auditLog, err := newAuditLog(cfg.MCP.Audit)
+		if err != nil {
+			return nil, err
+		}
+		server.auditLog = auditLog
+		server.RegisterMiddleware(server.auditMiddleware)
+	}
+
+	// Built-in middlewares wrap every tool call
+	server.RegisterMiddleware(server.recoveryMiddleware)
+	server.RegisterMiddleware(server.loggingMiddleware)
+	server.RegisterMiddleware(server.timeoutMiddleware)
+
 	// Register tools
 	server.registerTools()
+	server.warnUnknownToolFilters()
+	server.warnUnknownToolLevels()
+	server.warnRenamedTimeouts()
+
+	// Register databases declared in config
+	if err := server.registerConfiguredDatabases(); err != nil {
+		return nil, err
+	}
 
 	return server, nil
 }
 
+// registerConfiguredDatabases constructs and registers the databases listed under mcp.databases
+func (s *Server) registerConfiguredDatabases() error {
+	for _, spec := range s.config.MCP.Databases {
+		collectionName := spec.Collection
+		if collectionName == "" {
+			collectionName = "MaestroDocs"
+		}
+
+		if err := validateDBName(spec.Name); err != nil {
+			return fmt.Errorf("configured database '%s': %w", spec.Name, err)
+		}
+
+		if err := vectordb.ValidateCollectionName(spec.Type, collectionName); err != nil {
+			return fmt.Errorf("configured database '%s': %w", spec.Name, err)
+		}
+
+		db, err := vectordb.CreateVectorDatabase(spec.Type, collectionName, s.config)
+		if err != nil {
+			return fmt.Errorf("failed to create configured database '%s': %w", spec.Name, err)
+		}
+
+		s.dbMutex.Lock()
+		s.vectorDBs[spec.Name] = db
+		if spec.ReadOnly {
+			s.readOnly[spec.Name] = true
+		}
+		s.dbMutex.Unlock()
+
+		s.logger.Info("Registered configured vector database",
+			zap.String("name", spec.Name),
+			zap.String("type", spec.Type),
+			zap.String("collection", collectionName),
+			zap.Bool("read_only", spec.ReadOnly))
+	}
+
+	return nil
+}
+
 // Handler returns the HTTP handler for the MCP server
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check endpoint
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/version", s.handleVersion)
 
 	// MCP endpoints
+	mux.HandleFunc("/mcp", s.handleJSONRPC)
 	mux.HandleFunc("/mcp/tools/list", s.handleToolsList)
 	mux.HandleFunc("/mcp/tools/call", s.handleToolCall)
+	mux.HandleFunc("/mcp/export", s.handleExport)
+	mux.HandleFunc("/mcp/ingest", s.handleIngest)
+	mux.HandleFunc("/mcp/resources/list", s.handleResourcesList)
+	mux.HandleFunc("/mcp/resources/read", s.handleResourcesRead)
+	mux.HandleFunc("/mcp/prompts/list", s.handlePromptsList)
+	mux.HandleFunc("/mcp/prompts/get", s.handlePromptsGet)
 
 	return mux
 }
 
-// registerTools registers all available MCP tools
-func (s *Server) registerTools() {
-	// Database management tools
+// registerTools registers all available MCP tools
+func (s *Server) registerTools() {
+	// Database management tools
+	s.registerTool(Tool{
+		Name:        "create_vector_database",
+		Description: "Create a new vector database instance",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Unique name for the vector database instance",
+				},
+				"db_type": map[string]interface{}{
+					"type":        "string",
+					"description": "Type of vector database to create, defaulting to mcp.vector_db.type; mock requires mcp.vector_db.allow_mock outside development",
+					"enum":        config.SupportedVectorDBTypes,
+					"default":     s.config.MCP.VectorDB.Type,
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the collection to use; defaults to mcp.vector_db.collection_template when set, else MaestroDocs",
+					"default":     "MaestroDocs",
+				},
+				"collection_vars": map[string]interface{}{
+					"type":                 "object",
+					"description":          "Values for the variables of mcp.vector_db.collection_template, used when collection_name is omitted",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+				"route_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Route writes to the collection named by a metadata field, e.g. metadata.type; routed collections are created on demand",
+				},
+				"read_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Reject writes, deletes, and cleanup on this instance while allowing queries and lists",
+					"default":     false,
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler:  s.handleCreateVectorDatabase,
+		Mutating: true,
+	})
+
+	s.registerTool(Tool{
+		Name:        "server_info",
+		Description: "Get the server version, git commit, build time, and Go version",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleServerInfo,
+	})
+
+	s.registerTool(Tool{
+		Name:        "get_config",
+		Description: "Get the effective server configuration, merged from defaults, the config file, and the environment, with secrets redacted",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetConfig,
+	})
+
+	s.registerTool(Tool{
+		Name:        "health",
+		Description: "Check service health and whether each registered vector database is reachable",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleHealthCheck,
+	})
+
+	s.registerTool(Tool{
+		Name:        "list_databases",
+		Description: "List all available vector database instances",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleListDatabases,
+	})
+
+	s.registerTool(Tool{
+		Name:        "setup_database",
+		Description: "Set up a vector database and create collections",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance to set up",
+				},
+				"embedding": map[string]interface{}{
+					"type":        "string",
+					"description": "Embedding model to use for the collection",
+					"default":     "default",
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler:  s.handleSetupDatabase,
+		Mutating: true,
+		Timeout:  "setup_database",
+	})
+
+	s.registerTool(Tool{
+		Name:        "get_vector_dimension",
+		Description: "Get the vector dimension a collection expects, to check an embedding model before bulk loading",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Collection to describe; defaults to the instance's collection",
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler: s.handleGetVectorDimension,
+	})
+
+	s.registerTool(Tool{
+		Name:        "embed_text",
+		Description: "Embed a text with the configured embedding provider and return the vector, without writing anything, to diagnose retrieval quality",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "Text to embed; it is preprocessed as queries are",
+				},
+			},
+			"required": []string{"text"},
+		},
+		Handler: s.handleEmbedText,
+	})
+
+	s.registerTool(Tool{
+		Name:        "compare_texts",
+		Description: "Embed two texts and score their similarity under a collection's metric, to diagnose why documents do or don't match a query",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"text_a": map[string]interface{}{
+					"type":        "string",
+					"description": "First text to compare",
+				},
+				"text_b": map[string]interface{}{
+					"type":        "string",
+					"description": "Second text to compare",
+				},
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Vector database instance whose collection metric is used",
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Collection whose metric is used; defaults to the instance's collection",
+				},
+				"metric": map[string]interface{}{
+					"type":        "string",
+					"description": "Metric to use when no db_name is given; defaults to mcp.embedding.metric",
+					"enum":        []string{"cosine", "ip", "l2"},
+				},
+			},
+			"required": []string{"text_a", "text_b"},
+		},
+		Handler: s.handleCompareTexts,
+	})
+
+	s.registerTool(Tool{
+		Name:        "get_embedding_info",
+		Description: "Describe the active embedding provider, model, and dimension, with the redacted embedding config and a reachability check",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"check": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Embed a short text to check that the provider answers with vectors of the configured dimension",
+					"default":     true,
+				},
+			},
+		},
+		Handler: s.handleGetEmbeddingInfo,
+	})
+
+	s.registerTool(Tool{
+		Name:        "get_collection_info",
+		Description: "Get a collection's schema and document count, and the embedding profile (metric and normalization) new collections are set up with",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Collection to describe; defaults to the instance's collection",
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler: s.handleGetCollectionInfo,
+	})
+
+	// A collection name template makes collection_name optional
+	createCollectionRequired := []string{"db_name", "collection_name"}
+	if s.config.MCP.VectorDB.CollectionTemplate != "" {
+		createCollectionRequired = []string{"db_name"}
+	}
+
+	s.registerTool(Tool{
+		Name:        "create_collection",
+		Description: "Create an additional collection in an existing vector database instance",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the collection to create; required unless mcp.vector_db.collection_template is set",
+				},
+				"collection_vars": map[string]interface{}{
+					"type":                 "object",
+					"description":          "Values for the variables of mcp.vector_db.collection_template, used when collection_name is omitted",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+				"embedding": map[string]interface{}{
+					"type":        "string",
+					"description": "Embedding model to use for the collection",
+					"default":     "default",
+				},
+				"vector_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Vector dimension; defaults to mcp.embedding.vector_size",
+				},
+				"metric": map[string]interface{}{
+					"type":        "string",
+					"description": "Similarity metric; defaults to mcp.embedding.metric",
+					"enum":        []string{"cosine", "ip", "l2"},
+				},
+			},
+			"required": createCollectionRequired,
+		},
+		Handler:  s.handleCreateCollection,
+		Mutating: true,
+		Timeout:  "setup_database",
+	})
+
+	// Document operations
+	s.registerTool(Tool{
+		Name:        "write_document",
+		Description: "Write a single document to a vector database",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"url": map[string]interface{}{
+					"type":        "string",
+					"description": "URL of the document",
+				},
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "Text content of the document",
+				},
+				"metadata": map[string]interface{}{
+					"type":        "object",
+					"description": "Additional metadata for the document",
+					"default":     map[string]interface{}{},
+				},
+				"vector": map[string]interface{}{
+					"type":        "array",
+					"description": "Pre-computed vector embedding (optional)",
+					"items": map[string]interface{}{
+						"type": "number",
+					},
+				},
+				"vectors": map[string]interface{}{
+					"type":        "object",
+					"description": "Pre-computed embeddings keyed by named vector space, e.g. title and body (optional)",
+					"additionalProperties": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "number"},
+					},
+				},
+				"ttl": map[string]interface{}{
+					"type":        "string",
+					"description": "Time to live, e.g. 24h; the document is deleted by the TTL sweeper once it expires",
+				},
+				"created_at": map[string]interface{}{
+					"type":        "string",
+					"description": "RFC 3339 creation time to record instead of now, e.g. when importing historical data",
+				},
+				"auto_setup": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Set up the collection if it does not exist yet; defaults to mcp.vector_db.auto_setup",
+				},
+				"idempotency_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Client-chosen key making retries safe: a repeat within mcp.idempotency.window returns the first response with deduplicated=true instead of writing again",
+				},
+			},
+			"required": []string{"db_name", "url", "text"},
+		},
+		Handler:  s.idempotentWrite("write_document", s.handleWriteDocument),
+		Mutating: true,
+		Timeout:  "write_single",
+	})
+
+	s.registerTool(Tool{
+		Name:        "write_documents",
+		Description: "Write a batch of documents to a vector database",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"auto_setup": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Set up the collection if it does not exist yet; defaults to mcp.vector_db.auto_setup",
+				},
+				"idempotency_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Client-chosen key making batch retries safe: a repeat within mcp.idempotency.window returns the first response with deduplicated=true instead of writing again",
+				},
+				"documents": map[string]interface{}{
+					"type":        "array",
+					"description": "Documents to write; each takes the same fields as write_document",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"url":      map[string]interface{}{"type": "string"},
+							"text":     map[string]interface{}{"type": "string"},
+							"metadata": map[string]interface{}{"type": "object"},
+							"vector": map[string]interface{}{
+								"type":  "array",
+								"items": map[string]interface{}{"type": "number"},
+							},
+							"vectors":    map[string]interface{}{"type": "object"},
+							"ttl":        map[string]interface{}{"type": "string"},
+							"created_at": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"url", "text"},
+					},
+				},
+			},
+			"required": []string{"db_name", "documents"},
+		},
+		Handler:  s.idempotentWrite("write_documents", s.handleWriteDocuments),
+		Mutating: true,
+		Timeout:  "write_bulk",
+	})
+
+	s.registerTool(Tool{
+		Name:        "query",
+		Description: "Query a vector database using natural language",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "The query string to search for",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of results to consider; clamped to mcp.search.max_limit",
+					"default":     defaultLimit(s.config.MCP.Search, 5),
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional collection name to search in, including collections created by route_by",
+				},
+				"rerank": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Re-score the top candidates with the configured reranker; raw vector scores are used when none is configured",
+					"default":     false,
+				},
+				"dedupe_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Collapse results to the best-scoring hit per url or metadata field, e.g. metadata.parent_id",
+				},
+				"recency_weight": map[string]interface{}{
+					"type":        "number",
+					"description": "Blend similarity with document age so newer documents rank higher; 0 (default) ignores age, 1 ranks by age alone",
+					"minimum":     0,
+					"maximum":     1,
+				},
+				"expand": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Augment the query with synonyms or a paraphrase from the configured expander before embedding; the expanded query is reported in the response",
+					"default":     false,
+				},
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Only match documents whose metadata equals every given value, e.g. {\"source\": \"wiki\"}; test arrays with {\"tags\": {\"contains\": \"ml\"}}, contains_any, or contains_all",
+				},
+				"fields": map[string]interface{}{
+					"type":        "array",
+					"description": "Document fields to return (id, url, text, metadata, vector, vectors, created_at, updated_at); all fields when omitted",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"consistency_level": map[string]interface{}{
+					"type":        "string",
+					"description": "Read consistency: strong sees every acknowledged write, eventually is fastest; defaults to mcp.vector_db.milvus.consistency_level",
+					"enum":        []string{"strong", "bounded", "session", "eventually"},
+				},
+			},
+			"required": []string{"db_name", "query"},
+		},
+		Handler: s.handleQuery,
+	})
+
+	s.registerTool(Tool{
+		Name:        "search",
+		Description: "Perform a vector similarity search returning scored documents",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "The query string to search for; required unless vector is given",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of results to return; clamped to mcp.search.max_limit",
+					"default":     defaultLimit(s.config.MCP.Search, 5),
+				},
+				"collection_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional collection name to search in, including collections created by route_by",
+				},
+				"min_score": map[string]interface{}{
+					"type":        "number",
+					"description": "Drop results scoring below this threshold; fewer than limit results may be returned",
+				},
+				"include_vectors": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include stored embedding vectors in the results",
+					"default":     false,
+				},
+				"rerank": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Re-score the top candidates with the configured reranker; raw vector scores are used when none is configured",
+					"default":     false,
+				},
+				"dedupe_by": map[string]interface{}{
+					"type":        "string",
+					"description": "Collapse results to the best-scoring hit per url or metadata field, e.g. metadata.parent_id",
+				},
+				"recency_weight": map[string]interface{}{
+					"type":        "number",
+					"description": "Blend similarity with document age so newer documents rank higher; 0 (default) ignores age, 1 ranks by age alone",
+					"minimum":     0,
+					"maximum":     1,
+				},
+				"vector_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Named vector space to search instead of the default vector",
+				},
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Only match documents whose metadata equals every given value, e.g. {\"source\": \"wiki\"}; test arrays with {\"tags\": {\"contains\": \"ml\"}}, contains_any, or contains_all",
+				},
+				"filter_strategy": map[string]interface{}{
+					"type":        "string",
+					"description": "Apply the filter before the vector search (pre, full recall) or to its results (post, faster but may return fewer than limit); defaults to the backend's recommendation",
+					"enum":        []string{"pre", "post"},
+				},
+				"vector": map[string]interface{}{
+					"type":        "array",
+					"description": "Search by similarity to this vector instead of the query text",
+					"items": map[string]interface{}{
+						"type": "number",
+					},
+				},
+				"fields": map[string]interface{}{
+					"type":        "array",
+					"description": "Document fields to return (id, url, text, metadata, vector, vectors, created_at, updated_at); all fields when omitted",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"consistency_level": map[string]interface{}{
+					"type":        "string",
+					"description": "Read consistency: strong sees every acknowledged write, eventually is fastest; defaults to mcp.vector_db.milvus.consistency_level",
+					"enum":        []string{"strong", "bounded", "session", "eventually"},
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler: s.handleSearch,
+	})
+
+	s.registerTool(Tool{
+		Name:        "validate_filter",
+		Description: "Check a metadata filter without running it: its syntax and, on Weaviate, its fields and value types against mcp.vector_db.weaviate.metadata_schema",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance the filter is for",
+				},
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Filter to check, in the form query and search accept",
+				},
+			},
+			"required": []string{"db_name", "filter"},
+		},
+		Handler: s.handleValidateFilter,
+	})
+
+	s.registerTool(Tool{
+		Name:        "batch_query",
+		Description: "Run several similarity searches in one call, returning result sets aligned to the queries",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"queries": map[string]interface{}{
+					"type":        "array",
+					"description": "Queries to run, at most 50; repeated query texts share one embedding",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"query": map[string]interface{}{
+								"type":        "string",
+								"description": "The query string to search for",
+							},
+							"limit": map[string]interface{}{
+								"type":        "integer",
+								"description": "Maximum number of results for this query; clamped to mcp.search.max_limit",
+								"default":     defaultLimit(s.config.MCP.Search, 5),
+							},
+							"filter": map[string]interface{}{
+								"type":        "object",
+								"description": "Only match documents whose metadata equals every given value or satisfies an array operator (contains, contains_any, contains_all)",
+							},
+						},
+						"required": []string{"query"},
+					},
+				},
+			},
+			"required": []string{"db_name", "queries"},
+		},
+		Handler: s.handleBatchQuery,
+	})
+
+	s.registerTool(Tool{
+		Name:        "list_documents",
+		Description: "List documents from a vector database",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of documents to return; clamped to mcp.list.max_limit",
+					"default":     defaultLimit(s.config.MCP.List, 10),
+				},
+				"offset": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of documents to skip",
+					"default":     0,
+				},
+				"include_vectors": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include stored embedding vectors in the results",
+					"default":     false,
+				},
+				"include_deleted": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include soft-deleted documents",
+					"default":     false,
+				},
+				"fields": map[string]interface{}{
+					"type":        "array",
+					"description": "Document fields to return (id, url, text, metadata, vector, vectors, created_at, updated_at); all fields when omitted",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"snapshot": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Pin the collection's document IDs so later pages, requested with the returned snapshot_id, neither skip nor repeat documents while it is written",
+					"default":     false,
+				},
+				"snapshot_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Snapshot returned by an earlier page of the same scan",
+				},
+				"consistency_level": map[string]interface{}{
+					"type":        "string",
+					"description": "Read consistency for taking the snapshot; defaults to mcp.snapshot.consistency_level",
+					"enum":        []string{"strong", "bounded", "session", "eventually"},
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler: s.handleListDocuments,
+	})
+
+	s.registerTool(Tool{
+		Name:        "count_documents",
+		Description: "Get the current count of documents in a collection",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"consistency_level": map[string]interface{}{
+					"type":        "string",
+					"description": "Read consistency: strong sees every acknowledged write, eventually is fastest; defaults to mcp.vector_db.milvus.consistency_level",
+					"enum":        []string{"strong", "bounded", "session", "eventually"},
+				},
+				"approximate": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return a fast estimate from backend statistics instead of an exact count; the estimate may include recently deleted and soft-deleted documents",
+					"default":     false,
+				},
+				"include_deleted": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Count soft-deleted documents too",
+					"default":     false,
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler: s.handleCountDocuments,
+	})
+
+	s.registerTool(Tool{
+		Name:        "delete_document",
+		Description: "Delete a single document from a vector database",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"document_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Document ID to delete",
+				},
+				"hard": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Permanently delete even when soft delete is enabled",
+					"default":     false,
+				},
+			},
+			"required": []string{"db_name", "document_id"},
+		},
+		Handler:  s.handleDeleteDocument,
+		Mutating: true,
+		Timeout:  "delete_single",
+	})
+
+	s.registerTool(Tool{
+		Name:        "delete_documents",
+		Description: "Delete many documents by ID, attempting every ID and reporting which were deleted and which failed",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Document IDs to delete",
+					"items":       map[string]interface{}{"type": "string"},
+					"minItems":    1,
+				},
+				"hard": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Permanently delete even when soft delete is enabled",
+					"default":     false,
+				},
+			},
+			"required": []string{"db_name", "document_ids"},
+		},
+		Handler:  s.handleDeleteDocuments,
+		Mutating: true,
+		Timeout:  "delete_bulk",
+	})
+
+	s.registerTool(Tool{
+		Name:        "delete_by_filter",
+		Description: "Permanently delete every document whose metadata matches a filter",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"filter": map[string]interface{}{
+					"type":          "object",
+					"description":   "Metadata values that matching documents must equal, e.g. {\"category\": \"news\"}, or array operators such as {\"tags\": {\"contains_any\": [\"a\", \"b\"]}}",
+					"minProperties": 1,
+				},
+			},
+			"required": []string{"db_name", "filter"},
+		},
+		Handler:  s.handleDeleteByFilter,
+		Mutating: true,
+		Timeout:  "delete_bulk",
+	})
+
 	s.registerTool(Tool{
-		Name:        "create_vector_database",
-		Description: "Create a new vector database instance",
+		Name:        "truncate_collection",
+		Description: "Permanently delete every document in a collection while keeping the collection, its schema, and its index",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"db_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Unique name for the vector database instance",
-				},
-				"db_type": map[string]interface{}{
-					"type":        "string",
-					"description": "Type of vector database to create",
-					"enum":        []string{"weaviate", "milvus"},
+					"description": "Name of the vector database instance",
 				},
 				"collection_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the collection to use",
-					"default":     "MaestroDocs",
+					"description": "Collection to truncate; defaults to the instance's collection",
 				},
 			},
-			"required": []string{"db_name", "db_type"},
+			"required": []string{"db_name"},
 		},
-		Handler: s.handleCreateVectorDatabase,
+		Handler:  s.handleTruncateCollection,
+		Mutating: true,
+		Timeout:  "delete_bulk",
 	})
 
 	s.registerTool(Tool{
-		Name:        "list_databases",
-		Description: "List all available vector database instances",
+		Name:        "restore_document",
+		Description: "Restore a soft-deleted document",
 		InputSchema: map[string]interface{}{
-			"type":       "object",
-			"properties": map[string]interface{}{},
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database instance",
+				},
+				"document_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Document ID to restore",
+				},
+			},
+			"required": []string{"db_name", "document_id"},
 		},
-		Handler: s.handleListDatabases,
+		Handler:  s.handleRestoreDocument,
+		Mutating: true,
+		Timeout:  "delete_single",
 	})
 
 	s.registerTool(Tool{
-		Name:        "setup_database",
-		Description: "Set up a vector database and create collections",
+		Name:        "purge",
+		Description: "Permanently delete soft-deleted documents older than a given age",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"db_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the vector database instance to set up",
+					"description": "Name of the vector database instance",
 				},
-				"embedding": map[string]interface{}{
+				"older_than": map[string]interface{}{
 					"type":        "string",
-					"description": "Embedding model to use for the collection",
-					"default":     "default",
+					"description": "Minimum time since deletion, e.g. 72h (defaults to mcp.soft_delete.purge_after)",
 				},
 			},
 			"required": []string{"db_name"},
 		},
-		Handler: s.handleSetupDatabase,
+		Handler:  s.handlePurge,
+		Mutating: true,
+		Timeout:  "delete_bulk",
 	})
 
-	// Document operations
 	s.registerTool(Tool{
-		Name:        "write_document",
-		Description: "Write a single document to a vector database",
+		Name:        "migrate_documents",
+		Description: "Copy documents from one vector database to another in batches",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"db_name": map[string]interface{}{
+				"source_db": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the vector database instance",
+					"description": "Name of the vector database to copy documents from",
 				},
-				"url": map[string]interface{}{
+				"target_db": map[string]interface{}{
 					"type":        "string",
-					"description": "URL of the document",
+					"description": "Name of the vector database to copy documents into",
 				},
-				"text": map[string]interface{}{
-					"type":        "string",
-					"description": "Text content of the document",
+				"batch_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of documents to copy per batch",
+					"default":     100,
 				},
-				"metadata": map[string]interface{}{
-					"type":        "object",
-					"description": "Additional metadata for the document",
-					"default":     map[string]interface{}{},
+				"offset": map[string]interface{}{
+					"type":        "integer",
+					"description": "Source offset to resume from (use next_offset from a previous run)",
+					"default":     0,
 				},
-				"vector": map[string]interface{}{
-					"type":        "array",
-					"description": "Pre-computed vector embedding (optional)",
-					"items": map[string]interface{}{
-						"type": "number",
-					},
+				"reembed": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Allow migrating vectors whose dimension differs from the target by dropping them and re-embedding the text; requires mcp.embedding.writes",
+					"default":     false,
 				},
 			},
-			"required": []string{"db_name", "url", "text"},
+			"required": []string{"source_db", "target_db"},
 		},
-		Handler: s.handleWriteDocument,
+		Handler:  s.handleMigrateDocuments,
+		Mutating: true,
+		Timeout:  "migrate",
 	})
 
 	s.registerTool(Tool{
-		Name:        "query",
-		Description: "Query a vector database using natural language",
+		Name:        "migrate_collection",
+		Description: "Move a vector database instance to a new collection version with a changed schema, copying its documents and repointing the instance once the copy succeeds",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"db_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the vector database instance",
+					"description": "Name of the vector database instance to migrate",
 				},
-				"query": map[string]interface{}{
+				"embedding": map[string]interface{}{
 					"type":        "string",
-					"description": "The query string to search for",
+					"description": "Embedding model of the new version; defaults to the current version's",
 				},
-				"limit": map[string]interface{}{
+				"vector_size": map[string]interface{}{
 					"type":        "integer",
-					"description": "Maximum number of results to consider",
-					"default":     5,
+					"description": "Vector dimension of the new version; defaults to the current version's",
 				},
-				"collection_name": map[string]interface{}{
+				"metric": map[string]interface{}{
 					"type":        "string",
-					"description": "Optional collection name to search in",
+					"description": "Similarity metric of the new version; defaults to the current version's",
+					"enum":        []string{"cosine", "ip", "l2"},
+				},
+				"batch_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of documents to copy per batch",
+					"default":     100,
+				},
+				"reembed": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Drop stored vectors whose dimension differs from the new version and re-embed the text; requires mcp.embedding.writes",
+					"default":     false,
 				},
 			},
-			"required": []string{"db_name", "query"},
+			"required": []string{"db_name"},
 		},
-		Handler: s.handleQuery,
+		Handler:  s.handleMigrateCollection,
+		Mutating: true,
+		Timeout:  "migrate",
 	})
 
 	s.registerTool(Tool{
-		Name:        "list_documents",
-		Description: "List documents from a vector database",
+		Name:        "confirm_migration",
+		Description: "Confirm a collection migration, deleting the collection version the instance was migrated from",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the migrated vector database instance",
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler:  s.handleConfirmMigration,
+		Mutating: true,
+	})
+
+	s.registerTool(Tool{
+		Name:        "rollback_migration",
+		Description: "Undo a collection migration, repointing the instance at its previous collection version and deleting the new one; documents written since the migration are lost",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the migrated vector database instance",
+				},
+			},
+			"required": []string{"db_name"},
+		},
+		Handler:  s.handleRollbackMigration,
+		Mutating: true,
+	})
+
+	s.registerTool(Tool{
+		Name:        "reingest",
+		Description: "Replace every document in a vector database's collection, loading a shadow collection and swapping it in once complete so queries never see a partial load",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -195,56 +1158,163 @@ func (s *Server) registerTools() {
 					"type":        "string",
 					"description": "Name of the vector database instance",
 				},
-				"limit": map[string]interface{}{
+				"documents": map[string]interface{}{
+					"type":        "array",
+					"description": "The collection's new documents; each takes the same fields as write_document",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"url":      map[string]interface{}{"type": "string"},
+							"text":     map[string]interface{}{"type": "string"},
+							"metadata": map[string]interface{}{"type": "object"},
+							"vector": map[string]interface{}{
+								"type":  "array",
+								"items": map[string]interface{}{"type": "number"},
+							},
+							"vectors":    map[string]interface{}{"type": "object"},
+							"ttl":        map[string]interface{}{"type": "string"},
+							"created_at": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"url", "text"},
+					},
+				},
+				"shadow": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Load a shadow collection and swap it in once complete instead of emptying and reloading the live one; defaults to mcp.reingest.shadow",
+				},
+				"keep_previous": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the replaced collection until confirm_migration deletes it or rollback_migration switches back",
+					"default":     false,
+				},
+				"batch_size": map[string]interface{}{
 					"type":        "integer",
-					"description": "Maximum number of documents to return",
-					"default":     10,
+					"description": "Number of documents to write per batch",
+					"default":     100,
 				},
-				"offset": map[string]interface{}{
+			},
+			"required": []string{"db_name", "documents"},
+		},
+		Handler:  s.handleReingest,
+		Mutating: true,
+		Timeout:  "reingest",
+	})
+
+	s.registerTool(Tool{
+		Name:        "export_documents",
+		Description: "Export all documents in a vector database as JSONL, one document per line",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"db_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the vector database to export",
+				},
+				"batch_size": map[string]interface{}{
 					"type":        "integer",
-					"description": "Number of documents to skip",
-					"default":     0,
+					"description": "Number of documents to read per page",
+					"default":     100,
+				},
+				"include_vectors": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include stored embedding vectors in the export",
+					"default":     false,
+				},
+				"include_deleted": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include soft-deleted documents",
+					"default":     false,
+				},
+				"snapshot": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Export the documents present when the export starts, unaffected by concurrent writes",
+					"default":     false,
+				},
+				"consistency_level": map[string]interface{}{
+					"type":        "string",
+					"description": "Read consistency for taking the snapshot; defaults to mcp.snapshot.consistency_level",
+					"enum":        []string{"strong", "bounded", "session", "eventually"},
 				},
 			},
 			"required": []string{"db_name"},
 		},
-		Handler: s.handleListDocuments,
+		Handler: s.handleExportDocuments,
+		Timeout: "export",
 	})
 
 	s.registerTool(Tool{
-		Name:        "count_documents",
-		Description: "Get the current count of documents in a collection",
+		Name:        "import_documents",
+		Description: "Import documents from a JSONL or CSV payload, reporting per-row errors",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"db_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the vector database instance",
+					"description": "Name of the vector database to import into",
+				},
+				"data": map[string]interface{}{
+					"type":        "string",
+					"description": "JSONL payload with one document per line, or CSV with a header row",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Payload format",
+					"enum":        []string{"jsonl", "csv"},
+					"default":     "jsonl",
+				},
+				"columns": map[string]interface{}{
+					"type":        "object",
+					"description": "CSV column mapping: url and text column names, an optional RFC 3339 created_at column, and metadata as a list of columns (defaults to all other columns)",
+					"properties": map[string]interface{}{
+						"url":        map[string]interface{}{"type": "string", "default": "url"},
+						"text":       map[string]interface{}{"type": "string", "default": "text"},
+						"created_at": map[string]interface{}{"type": "string"},
+						"metadata":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+					},
+				},
+				"batch_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of documents to write per batch",
+					"default":     100,
 				},
 			},
-			"required": []string{"db_name"},
+			"required": []string{"db_name", "data"},
 		},
-		Handler: s.handleCountDocuments,
+		Handler:  s.handleImportDocuments,
+		Mutating: true,
+		Timeout:  "import",
 	})
 
 	s.registerTool(Tool{
-		Name:        "delete_document",
-		Description: "Delete a single document from a vector database",
+		Name:        "get_audit_log",
+		Description: "Query recent audited mutating operations, newest first",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
+				"tool": map[string]interface{}{
+					"type":        "string",
+					"description": "Only return entries for this tool",
+				},
 				"db_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the vector database instance",
+					"description": "Only return entries for this vector database",
 				},
-				"document_id": map[string]interface{}{
+				"request_id": map[string]interface{}{
 					"type":        "string",
-					"description": "Document ID to delete",
+					"description": "Only return entries for this request ID",
+				},
+				"since": map[string]interface{}{
+					"type":        "string",
+					"description": "Only return entries at or after this RFC 3339 timestamp",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of entries to return",
+					"default":     50,
 				},
 			},
-			"required": []string{"db_name", "document_id"},
 		},
-		Handler: s.handleDeleteDocument,
+		Handler: s.handleGetAuditLog,
 	})
 
 	s.registerTool(Tool{
@@ -260,14 +1330,76 @@ func (s *Server) registerTools() {
 			},
 			"required": []string{"db_name"},
 		},
-		Handler: s.handleCleanup,
+		Handler:  s.handleCleanup,
+		Mutating: true,
 	})
 }
 
-// registerTool registers a tool with the server
+// registerTool registers a built-in tool with the server
 func (s *Server) registerTool(tool Tool) {
+	if err := s.RegisterTool(tool); err != nil {
+		s.logger.Error("Failed to register tool", zap.String("name", tool.Name), zap.Error(err))
+	}
+}
+
+// RegisterTool adds a tool to the server at runtime. It is safe to call
+// concurrently with tool dispatch and fails if the name is already taken.
+func (s *Server) RegisterTool(tool Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("tool '%s' has no handler", tool.Name)
+	}
+
+	s.toolsMutex.Lock()
+	defer s.toolsMutex.Unlock()
+
+	if _, exists := s.Tools[tool.Name]; exists {
+		return fmt.Errorf("tool '%s' is already registered", tool.Name)
+	}
+
 	s.Tools[tool.Name] = tool
 	s.logger.Debug("Registered tool", zap.String("name", tool.Name))
+
+	return nil
+}
+
+// warnUnknownToolFilters logs tool names in mcp.enabled_tools or
+// mcp.disabled_tools that match no built-in tool, which usually means a typo
+func (s *Server) warnUnknownToolFilters() {
+	for _, list := range [][]string{s.config.MCP.EnabledTools, s.config.MCP.DisabledTools} {
+		for _, name := range list {
+			if _, exists := s.GetTool(name); !exists {
+				s.logger.Warn("Tool filter names an unknown tool", zap.String("name", name))
+			}
+		}
+	}
+}
+
+// GetTool returns a registered tool by name. Prefer it over reading Tools
+// directly, which is not safe while tools are being registered concurrently.
+func (s *Server) GetTool(name string) (Tool, bool) {
+	s.toolsMutex.RLock()
+	defer s.toolsMutex.RUnlock()
+
+	tool, exists := s.Tools[name]
+	return tool, exists
+}
+
+// UnregisterTool removes a tool from the server, reporting whether it was registered
+func (s *Server) UnregisterTool(name string) bool {
+	s.toolsMutex.Lock()
+	defer s.toolsMutex.Unlock()
+
+	if _, exists := s.Tools[name]; !exists {
+		return false
+	}
+
+	delete(s.Tools, name)
+	s.logger.Debug("Unregistered tool", zap.String("name", name))
+
+	return true
 }
 
 // handleHealth handles health check requests
@@ -277,15 +1409,7 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.dbMutex.RLock()
-	dbCount := len(s.vectorDBs)
-	s.dbMutex.RUnlock()
-
-	response := map[string]interface{}{
-		"status":           "healthy",
-		"timestamp":        time.Now().UTC(),
-		"vector_databases": dbCount,
-	}
+	response := s.healthReport(r.Context())
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -293,24 +1417,47 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleToolsList handles tool listing requests
-func (s *Server) handleToolsList(w http.ResponseWriter, r *http.Request) {
+// handleVersion reports the running build
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.config.BuildInfo()); err != nil {
+		s.logger.Error("Failed to encode version response", zap.Error(err))
+	}
+}
+
+// enabledTools describes the tools exposed under the config's tool filters
+func (s *Server) enabledTools() []map[string]interface{} {
+	s.toolsMutex.RLock()
+	defer s.toolsMutex.RUnlock()
+
 	tools := make([]map[string]interface{}, 0, len(s.Tools))
 	for _, tool := range s.Tools {
+		if !s.config.ToolEnabled(tool.Name) {
+			continue
+		}
 		tools = append(tools, map[string]interface{}{
 			"name":        tool.Name,
 			"description": tool.Description,
 			"inputSchema": tool.InputSchema,
 		})
 	}
+	return tools
+}
+
+// handleToolsList handles tool listing requests
+func (s *Server) handleToolsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
 	response := map[string]interface{}{
-		"tools": tools,
+		"tools": s.enabledTools(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -336,30 +1483,36 @@ func (s *Server) handleToolCall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tool, exists := s.Tools[request.Name]
-	if !exists {
-		http.Error(w, fmt.Sprintf("Tool '%s' not found", request.Name), http.StatusNotFound)
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
 		return
 	}
+	defer s.inFlight.Done()
 
-	// Execute tool with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), s.config.GetTimeout("tool_call"))
-	defer cancel()
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	w.Header().Set("X-Request-ID", requestID)
 
-	result, err := tool.Handler(ctx, request.Arguments)
+	endClientCall, err := s.beginClientCall(r)
 	if err != nil {
-		s.logger.Error("Tool execution failed",
+		s.logger.Warn("Rejected tool call over the per-client limit",
 			zap.String("tool", request.Name),
+			zap.String("request_id", requestID),
 			zap.Error(err))
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+	defer endClientCall()
 
-		response := map[string]interface{}{
-			"error": err.Error(),
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
-			s.logger.Error("Failed to encode error response", zap.Error(encodeErr))
-		}
+	result, err := s.CallTool(WithRequestID(r.Context(), requestID), request.Name, request.Arguments)
+	if errors.Is(err, ErrToolNotFound) {
+		http.Error(w, fmt.Sprintf("Tool '%s' not found", request.Name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.writeError(w, statusForError(err), err)
 		return
 	}
 
@@ -373,6 +1526,72 @@ func (s *Server) handleToolCall(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// writeError writes a JSON error response with the given status code
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
+		s.logger.Error("Failed to encode error response", zap.Error(encodeErr))
+	}
+}
+
+// beginCall registers an in-flight tool call, or reports false when the server is draining
+func (s *Server) beginCall() bool {
+	s.drainMutex.Lock()
+	defer s.drainMutex.Unlock()
+
+	if s.draining {
+		return false
+	}
+	s.inFlight.Add(1)
+	return true
+}
+
+// Drain stops accepting new tool calls and waits for in-flight calls to finish,
+// returning the context error if they do not complete before ctx is done
+func (s *Server) Drain(ctx context.Context) error {
+	s.drainMutex.Lock()
+	s.draining = true
+	s.drainMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close releases resources held by the server, such as the audit log file
+func (s *Server) Close() error {
+	if s.auditLog != nil {
+		return s.auditLog.close()
+	}
+	return nil
+}
+
+// CallTool executes a registered tool through the middleware chain
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	tool, exists := s.GetTool(name)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	if !s.config.ToolEnabled(name) {
+		return nil, fmt.Errorf("%w: %s is not enabled on this server", ErrToolDisabled, name)
+	}
+
+	return s.wrapHandler(tool.Handler)(withToolName(ctx, name), args)
+}
+
 // getDatabaseByName returns a vector database by name
 func (s *Server) getDatabaseByName(dbName string) (vectordb.VectorDatabase, error) {
 	s.dbMutex.RLock()
@@ -380,8 +1599,56 @@ func (s *Server) getDatabaseByName(dbName string) (vectordb.VectorDatabase, erro
 
 	db, exists := s.vectorDBs[dbName]
 	if !exists {
-		return nil, fmt.Errorf("vector database '%s' not found. Please create it first", dbName)
+		return nil, fmt.Errorf("%w: '%s'. Please create it first", ErrDatabaseNotFound, dbName)
 	}
 
 	return db, nil
 }
+
+// getWritableDatabase returns a vector database by name for a tool that
+// modifies it, failing with ErrReadOnly when the instance is read-only
+func (s *Server) getWritableDatabase(dbName string) (vectordb.VectorDatabase, error) {
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		return nil, err
+	}
+	if s.isReadOnly(dbName) {
+		return nil, fmt.Errorf("%w: '%s' was created with read_only and accepts queries only", ErrReadOnly, dbName)
+	}
+	if s.isMigrating(dbName) {
+		return nil, fmt.Errorf("%w: '%s' is copying documents to a new collection version and accepts queries only until it finishes", ErrMigrationInProgress, dbName)
+	}
+	return db, nil
+}
+
+// isMigrating reports whether migrate_collection is copying a vector database's documents
+func (s *Server) isMigrating(dbName string) bool {
+	s.dbMutex.RLock()
+	defer s.dbMutex.RUnlock()
+	return s.migrating[dbName]
+}
+
+// isReadOnly reports whether a vector database was created read-only
+func (s *Server) isReadOnly(dbName string) bool {
+	s.dbMutex.RLock()
+	defer s.dbMutex.RUnlock()
+	return s.readOnly[dbName]
+}
+
+// invalidateQueryCache drops cached query results for a database after it is modified
+func (s *Server) invalidateQueryCache(dbName string) {
+	if s.queryCache != nil {
+		s.queryCache.invalidate(dbName)
+	}
+}
+
+// validateDBName checks that a database instance name is usable as a registry key
+func validateDBName(dbName string) error {
+	if dbName == "" {
+		return invalidArgumentf("db_name must not be empty")
+	}
+	if !dbNamePattern.MatchString(dbName) {
+		return invalidArgumentf("invalid db_name '%s': names must start with a letter or digit and contain only letters, digits, underscores, and hyphens", dbName)
+	}
+	return nil
+}
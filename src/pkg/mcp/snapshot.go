@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// Built-in snapshot limits, used when mcp.snapshot leaves them unset
+const (
+	defaultSnapshotTTL          = 10 * time.Minute
+	defaultMaxSnapshots         = 100
+	defaultSnapshotMaxDocuments = 100000
+)
+
+// listSnapshot pins the IDs of a collection's documents at one point in time,
+// so a paginated scan pages through a stable list: documents written after the
+// snapshot do not appear, and deleting documents does not shift later pages.
+// Documents are read when their page is fetched, so a document updated since
+// the snapshot is returned as it is now, and one deleted since is skipped.
+type listSnapshot struct {
+	id               string
+	dbName           string
+	collection       string
+	ids              []string
+	consistencyLevel string
+	takenAt          time.Time
+	expiresAt        time.Time
+}
+
+// page fetches the documents of the snapshot's IDs from offset on, returning
+// them with the number of IDs that no longer exist. Documents soft-deleted
+// since the snapshot count as missing unless the context includes them.
+func (snap *listSnapshot) page(ctx context.Context, db vectordb.VectorDatabase, limit, offset int) ([]vectordb.Document, int, error) {
+	if offset >= len(snap.ids) {
+		return []vectordb.Document{}, 0, nil
+	}
+	end := min(offset+limit, len(snap.ids))
+
+	docs := make([]vectordb.Document, 0, end-offset)
+	missing := 0
+	for _, id := range snap.ids[offset:end] {
+		doc, err := db.GetDocument(ctx, id)
+		if errors.Is(err, vectordb.ErrDocumentNotFound) {
+			missing++
+			continue
+		}
+		if err != nil {
+			return nil, missing, err
+		}
+		if vectordb.IsTombstoned(doc) && !vectordb.TombstonesFromContext(ctx) {
+			missing++
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, missing, nil
+}
+
+// snapshotStore holds the snapshots of scans in progress. A snapshot expires
+// ttl after it was last used.
+type snapshotStore struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	max       int
+	snapshots map[string]*listSnapshot
+}
+
+// newSnapshotStore creates a store for the configured snapshot limits
+func newSnapshotStore(cfg config.SnapshotConfig) *snapshotStore {
+	st := &snapshotStore{
+		ttl:       cfg.TTL,
+		max:       cfg.MaxSnapshots,
+		snapshots: make(map[string]*listSnapshot),
+	}
+	if st.ttl == 0 {
+		st.ttl = defaultSnapshotTTL
+	}
+	if st.max == 0 {
+		st.max = defaultMaxSnapshots
+	}
+	return st
+}
+
+// add stores a snapshot, dropping the oldest ones beyond the store's capacity
+func (st *snapshotStore) add(snap *listSnapshot) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	now := time.Now()
+	st.expire(now)
+	snap.expiresAt = now.Add(st.ttl)
+	st.snapshots[snap.id] = snap
+
+	for len(st.snapshots) > st.max {
+		var oldest *listSnapshot
+		for _, candidate := range st.snapshots {
+			if oldest == nil || candidate.takenAt.Before(oldest.takenAt) {
+				oldest = candidate
+			}
+		}
+		delete(st.snapshots, oldest.id)
+	}
+}
+
+// get returns a snapshot that has not expired and extends its lifetime
+func (st *snapshotStore) get(id string) (*listSnapshot, bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	now := time.Now()
+	st.expire(now)
+	snap, exists := st.snapshots[id]
+	if exists {
+		snap.expiresAt = now.Add(st.ttl)
+	}
+	return snap, exists
+}
+
+// expire drops snapshots whose lifetime has passed. The caller must hold the mutex.
+func (st *snapshotStore) expire(now time.Time) {
+	for id, snap := range st.snapshots {
+		if !now.Before(snap.expiresAt) {
+			delete(st.snapshots, id)
+		}
+	}
+}
+
+// snapshotConsistencyLevel returns the consistency_level argument, or the
+// configured snapshot consistency level when it is not given
+func (s *Server) snapshotConsistencyLevel(args map[string]interface{}) (string, error) {
+	if level, err := parseConsistencyLevel(args); level != "" || err != nil {
+		return level, err
+	}
+	if level := s.config.MCP.Snapshot.ConsistencyLevel; level != "" {
+		return strings.ToLower(level), nil
+	}
+	return config.ConsistencyStrong, nil
+}
+
+// takeSnapshot reads the IDs of every document in an instance's collection in
+// a single read at the given consistency level and pins them as a snapshot
+func (s *Server) takeSnapshot(ctx context.Context, dbName string, db vectordb.VectorDatabase, level string) (*listSnapshot, error) {
+	maxDocuments := s.config.MCP.Snapshot.MaxDocuments
+	if maxDocuments == 0 {
+		maxDocuments = defaultSnapshotMaxDocuments
+	}
+
+	readCtx := vectordb.WithConsistencyLevel(vectordb.WithFields(ctx, []string{vectordb.FieldID}), level)
+	docs, err := db.ListDocuments(readCtx, maxDocuments+1, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take snapshot: %w", err)
+	}
+	if len(docs) > maxDocuments {
+		return nil, invalidArgumentf("'%s' holds more than %d documents, the most a snapshot pins; raise mcp.snapshot.max_documents or page without a snapshot",
+			dbName, maxDocuments)
+	}
+
+	snap := &listSnapshot{
+		id:               NewRequestID(),
+		dbName:           dbName,
+		collection:       db.CollectionName(),
+		ids:              make([]string, len(docs)),
+		consistencyLevel: level,
+		takenAt:          time.Now().UTC(),
+	}
+	for i, doc := range docs {
+		snap.ids[i] = doc.ID
+	}
+
+	s.toolLogger(ctx).Info("Took document snapshot",
+		zap.String("db_name", dbName),
+		zap.String("snapshot_id", snap.id),
+		zap.String("consistency_level", level),
+		zap.Int("documents", len(snap.ids)))
+
+	return snap, nil
+}
+
+// resolveSnapshot returns the snapshot a list call pages through: the one
+// named by snapshot_id, a new one when snapshot is set, or nil for a plain
+// offset scan. A snapshot only serves the instance and collection it was
+// taken of.
+func (s *Server) resolveSnapshot(ctx context.Context, dbName string, db vectordb.VectorDatabase, args map[string]interface{}) (*listSnapshot, error) {
+	if id, ok := args["snapshot_id"].(string); ok && id != "" {
+		snap, exists := s.snapshots.get(id)
+		if !exists || snap.dbName != dbName || snap.collection != db.CollectionName() {
+			return nil, fmt.Errorf("%w: '%s'; start a new scan with snapshot set", ErrSnapshotExpired, id)
+		}
+		return snap, nil
+	}
+
+	if take, _ := args["snapshot"].(bool); !take {
+		return nil, nil
+	}
+
+	level, err := s.snapshotConsistencyLevel(args)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := s.takeSnapshot(ctx, dbName, db, level)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshots.add(snap)
+	return snap, nil
+}
@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// defaultTransferBatchSize is the page size used when exporting or importing documents
+const defaultTransferBatchSize = 100
+
+// exportDocuments pages through a database and writes each document to w as a
+// JSON line, so only one page is held in memory at a time. With a snapshot, it
+// pages through the snapshot's documents instead. Soft-deleted documents are
+// skipped unless the context includes them. It returns the number of
+// documents written.
+func exportDocuments(ctx context.Context, db vectordb.VectorDatabase, snap *listSnapshot, w io.Writer, batchSize int, includeVectors bool, flush func()) (int, error) {
+	encoder := json.NewEncoder(w)
+	exported := 0
+
+	for offset := 0; ; offset += batchSize {
+		var docs []vectordb.Document
+		var err error
+		var last bool
+		if snap != nil {
+			docs, _, err = snap.page(ctx, db, batchSize, offset)
+			last = offset+batchSize >= len(snap.ids)
+		} else {
+			docs, err = db.ListDocuments(ctx, batchSize, offset)
+			last = len(docs) < batchSize
+		}
+		if err != nil {
+			return exported, fmt.Errorf("failed to list documents at offset %d: %w", offset, err)
+		}
+
+		for _, doc := range docs {
+			if !includeVectors {
+				doc.Vector = nil
+				doc.Vector32 = nil
+				doc.Vectors = nil
+			}
+			if err := encoder.Encode(doc); err != nil {
+				return exported, fmt.Errorf("failed to encode document '%s': %w", doc.ID, err)
+			}
+			exported++
+		}
+
+		if flush != nil {
+			flush()
+		}
+
+		if last {
+			return exported, nil
+		}
+	}
+}
+
+// importRecord is a parsed document together with its source row number
+type importRecord struct {
+	row int
+	doc vectordb.Document
+}
+
+// importError describes a row that could not be imported
+type importError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// importDocuments returns the documents of records
+func importDocuments(records []importRecord) []vectordb.Document {
+	docs := make([]vectordb.Document, len(records))
+	for i, record := range records {
+		docs[i] = record.doc
+	}
+	return docs
+}
+
+// checkImportRecords embeds the records of an import or ingest batch that
+// carry no vector and checks each vector against the collection's dimension,
+// so a row that fails either is reported on its own. It returns the records
+// still to write and an error for each row that failed. A dimension of zero
+// leaves the check to the write.
+func (s *Server) checkImportRecords(ctx context.Context, db vectordb.VectorDatabase, dimension int, records []importRecord) ([]importRecord, []importError) {
+	docs, embedErrors := s.embedMissingVectors(ctx, importDocuments(records))
+
+	kept := make([]importRecord, 0, len(records))
+	var rowErrors []importError
+	for i, doc := range docs {
+		row := records[i].row
+		if err, failed := embedErrors[i]; failed {
+			rowErrors = append(rowErrors, importError{Row: row, Error: fmt.Sprintf("failed to embed: %v", err)})
+			continue
+		}
+		if length := doc.VectorDimension(); length > 0 && dimension > 0 && length != dimension {
+			rowErrors = append(rowErrors, importError{Row: row, Error: fmt.Sprintf("%v: vector has %d dimensions but collection '%s' expects %d",
+				vectordb.ErrDimensionMismatch, length, db.CollectionName(), dimension)})
+			continue
+		}
+		kept = append(kept, importRecord{row: row, doc: doc})
+	}
+	return kept, rowErrors
+}
+
+// csvColumnMapping maps CSV columns onto document fields. When Metadata is
+// empty, every unmapped column becomes a metadata field.
+type csvColumnMapping struct {
+	URL       string
+	Text      string
+	CreatedAt string
+	Metadata  []string
+}
+
+// parseCSVColumnMapping reads the columns argument of import_documents
+func parseCSVColumnMapping(args map[string]interface{}) (csvColumnMapping, error) {
+	mapping := csvColumnMapping{URL: "url", Text: "text"}
+
+	columns, ok := args["columns"].(map[string]interface{})
+	if !ok {
+		return mapping, nil
+	}
+
+	if url, ok := columns["url"].(string); ok {
+		mapping.URL = url
+	}
+	if text, ok := columns["text"].(string); ok {
+		mapping.Text = text
+	}
+	if createdAt, ok := columns["created_at"].(string); ok {
+		mapping.CreatedAt = createdAt
+	}
+	if metadata, ok := columns["metadata"].([]interface{}); ok {
+		for _, column := range metadata {
+			name, ok := column.(string)
+			if !ok {
+				return mapping, invalidArgumentf("columns.metadata must be a list of column names")
+			}
+			mapping.Metadata = append(mapping.Metadata, name)
+		}
+	}
+
+	return mapping, nil
+}
+
+// parseJSONLDocuments decodes one document per non-empty line, collecting
+// invalid lines, and documents validateDocument rejects, as row errors instead
+// of failing the whole payload
+func (s *Server) parseJSONLDocuments(data string) ([]importRecord, []importError, error) {
+	var records []importRecord
+	var rowErrors []importError
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var doc vectordb.Document
+		if err := json.Unmarshal([]byte(text), &doc); err != nil {
+			rowErrors = append(rowErrors, importError{Row: line, Error: fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		if err := s.validateDocument(doc); err != nil {
+			rowErrors = append(rowErrors, importError{Row: line, Error: err.Error()})
+			continue
+		}
+		records = append(records, importRecord{row: line, doc: doc})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read JSONL data: %w", err)
+	}
+
+	return records, rowErrors, nil
+}
+
+// parseCSVDocuments decodes a CSV payload with a header row using the given
+// column mapping, validating each document as parseJSONLDocuments does. Row
+// numbers count the header as row 1.
+func (s *Server) parseCSVDocuments(data string, mapping csvColumnMapping) ([]importRecord, []importError, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, invalidArgumentf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.TrimSpace(column)] = i
+	}
+
+	urlIndex, ok := index[mapping.URL]
+	if !ok {
+		return nil, nil, invalidArgumentf("CSV header has no url column '%s'", mapping.URL)
+	}
+	textIndex, ok := index[mapping.Text]
+	if !ok {
+		return nil, nil, invalidArgumentf("CSV header has no text column '%s'", mapping.Text)
+	}
+
+	createdAtIndex := -1
+	if mapping.CreatedAt != "" {
+		if createdAtIndex, ok = index[mapping.CreatedAt]; !ok {
+			return nil, nil, invalidArgumentf("CSV header has no created_at column '%s'", mapping.CreatedAt)
+		}
+	}
+
+	metadataColumns := mapping.Metadata
+	if len(metadataColumns) == 0 {
+		for _, column := range header {
+			column = strings.TrimSpace(column)
+			if column != mapping.URL && column != mapping.Text && column != mapping.CreatedAt {
+				metadataColumns = append(metadataColumns, column)
+			}
+		}
+	}
+	for _, column := range metadataColumns {
+		if _, ok := index[column]; !ok {
+			return nil, nil, invalidArgumentf("CSV header has no metadata column '%s'", column)
+		}
+	}
+
+	var records []importRecord
+	var rowErrors []importError
+
+	for row := 2; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, importError{Row: row, Error: err.Error()})
+			continue
+		}
+
+		field := func(i int) string {
+			if i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+
+		doc := vectordb.Document{
+			URL:      field(urlIndex),
+			Text:     field(textIndex),
+			Metadata: make(map[string]interface{}, len(metadataColumns)),
+		}
+		for _, column := range metadataColumns {
+			doc.Metadata[column] = field(index[column])
+		}
+		if createdAtIndex >= 0 && field(createdAtIndex) != "" {
+			createdAt, err := time.Parse(time.RFC3339, field(createdAtIndex))
+			if err != nil {
+				rowErrors = append(rowErrors, importError{Row: row, Error: fmt.Sprintf("invalid created_at: %v", err)})
+				continue
+			}
+			doc.CreatedAt = createdAt
+		}
+
+		if err := s.validateDocument(doc); err != nil {
+			rowErrors = append(rowErrors, importError{Row: row, Error: err.Error()})
+			continue
+		}
+		records = append(records, importRecord{row: row, doc: doc})
+	}
+
+	return records, rowErrors, nil
+}
+
+// handleExport streams a database's documents as JSONL over HTTP
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	dbName := query.Get("db_name")
+	if dbName == "" {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("db_name is required"))
+		return
+	}
+
+	batchSize := defaultTransferBatchSize
+	if b := query.Get("batch_size"); b != "" {
+		parsed, err := strconv.Atoi(b)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("batch_size must be a positive integer"))
+			return
+		}
+		batchSize = parsed
+	}
+	includeVectors, _ := strconv.ParseBool(query.Get("include_vectors"))
+	includeDeleted, _ := strconv.ParseBool(query.Get("include_deleted"))
+	useSnapshot, _ := strconv.ParseBool(query.Get("snapshot"))
+	level, err := s.snapshotConsistencyLevel(map[string]interface{}{"consistency_level": query.Get("consistency_level")})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	db, err := s.getDatabaseByName(dbName)
+	if err != nil {
+		s.writeError(w, statusForError(err), err)
+		return
+	}
+
+	if !s.beginCall() {
+		s.writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server is shutting down"))
+		return
+	}
+	defer s.inFlight.Done()
+
+	exportCtx, cancel := context.WithTimeout(r.Context(), s.config.GetTimeout("export"))
+	defer cancel()
+	if includeDeleted {
+		exportCtx = vectordb.WithTombstones(exportCtx)
+	}
+
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+
+	// The snapshot is taken before the response starts, so a failure can still be reported
+	var snap *listSnapshot
+	if useSnapshot {
+		if snap, err = s.takeSnapshot(exportCtx, dbName, db, level); err != nil {
+			s.writeError(w, statusForError(err), err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	count, err := exportDocuments(exportCtx, db, snap, w, batchSize, includeVectors, flush)
+	if err != nil {
+		// Headers are already sent, so the failure can only be logged
+		s.logger.Error("Failed to stream export",
+			zap.String("db_name", dbName),
+			zap.Int("exported", count),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Streamed document export",
+		zap.String("db_name", dbName),
+		zap.Int("count", count))
+}
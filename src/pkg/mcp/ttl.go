@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// ttlSweepState tracks the background sweeper that deletes expired documents
+type ttlSweepState struct {
+	mutex       sync.Mutex
+	interval    time.Duration
+	lastSweep   time.Time
+	nextSweep   time.Time
+	lastDeleted int
+}
+
+// stats returns the sweeper schedule and the result of the last sweep
+func (t *ttlSweepState) stats() map[string]interface{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := map[string]interface{}{
+		"interval":     t.interval.String(),
+		"last_deleted": t.lastDeleted,
+	}
+	if !t.lastSweep.IsZero() {
+		stats["last_sweep"] = t.lastSweep.UTC()
+	}
+	if !t.nextSweep.IsZero() {
+		stats["next_sweep"] = t.nextSweep.UTC()
+	}
+	return stats
+}
+
+// RunTTLSweeper periodically deletes expired documents from every registered
+// database until ctx is done. It returns immediately when the sweep is disabled.
+func (s *Server) RunTTLSweeper(ctx context.Context) {
+	if s.ttlSweep == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.ttlSweep.interval)
+	defer ticker.Stop()
+
+	s.scheduleSweep(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.SweepExpired(ctx); err != nil {
+				s.logger.Warn("TTL sweep finished with errors", zap.Error(err))
+			}
+			s.scheduleSweep(now)
+		}
+	}
+}
+
+// scheduleSweep records when the next sweep will run
+func (s *Server) scheduleSweep(from time.Time) {
+	s.ttlSweep.mutex.Lock()
+	defer s.ttlSweep.mutex.Unlock()
+
+	s.ttlSweep.nextSweep = from.Add(s.ttlSweep.interval)
+}
+
+// sweepTarget is a collection swept for expired documents, served by db on
+// behalf of the database instance dbName
+type sweepTarget struct {
+	dbName string
+	db     vectordb.VectorDatabase
+}
+
+// sweepTargets returns every collection the server writes to: the collection
+// of each writable database instance and the collections its writes are
+// routed to
+func (s *Server) sweepTargets() []sweepTarget {
+	s.dbMutex.RLock()
+	var targets []sweepTarget
+	routers := make(map[string]*collectionRouter)
+	for name, db := range s.vectorDBs {
+		// Read-only databases are never modified, even to drop expired documents
+		if s.readOnly[name] {
+			continue
+		}
+		targets = append(targets, sweepTarget{dbName: name, db: db})
+		if router := s.routers[name]; router != nil {
+			routers[name] = router
+		}
+	}
+	s.dbMutex.RUnlock()
+
+	for name, router := range routers {
+		router.mutex.Lock()
+		for _, routed := range router.routed {
+			targets = append(targets, sweepTarget{dbName: name, db: routed})
+		}
+		router.mutex.Unlock()
+	}
+	return targets
+}
+
+// SweepExpired deletes documents whose TTL has passed from every collection
+// the server writes to, continuing past failures, and returns the number deleted
+func (s *Server) SweepExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	total := 0
+	var errs []error
+	for _, target := range s.sweepTargets() {
+		sweepCtx, cancel := s.withTimeout(ctx, "delete_bulk")
+		deleted, err := target.db.DeleteExpired(sweepCtx, now)
+		err = timeoutError(sweepCtx, err)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s collection '%s': %w", target.dbName, target.db.CollectionName(), err))
+			continue
+		}
+
+		if deleted > 0 {
+			s.invalidateQueryCache(target.dbName)
+			s.logger.Info("Deleted expired documents",
+				zap.String("db_name", target.dbName),
+				zap.String("collection", target.db.CollectionName()),
+				zap.Int("count", deleted))
+		}
+		total += deleted
+	}
+
+	if s.ttlSweep != nil {
+		s.ttlSweep.mutex.Lock()
+		s.ttlSweep.lastSweep = now
+		s.ttlSweep.lastDeleted = total
+		s.ttlSweep.mutex.Unlock()
+	}
+
+	return total, errors.Join(errs...)
+}
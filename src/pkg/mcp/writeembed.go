@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"go.uber.org/zap"
+)
+
+// embedWriteDocuments embeds the documents of a write that carry no vector,
+// under mcp.embedding.writes, in sub-batches that are each retried on their
+// own so one flaky call fails only its documents. It returns the documents
+// ready to write, in input order, and an error naming the URL of each document
+// whose sub-batch never succeeded. Without write embedding it returns docs as is.
+func (s *Server) embedWriteDocuments(ctx context.Context, docs []vectordb.Document) ([]vectordb.Document, []string) {
+	embedded, failed := s.embedMissingVectors(ctx, docs)
+	if len(failed) == 0 {
+		return embedded, nil
+	}
+
+	kept := make([]vectordb.Document, 0, len(embedded)-len(failed))
+	var failures []string
+	for i, doc := range embedded {
+		if err, ok := failed[i]; ok {
+			failures = append(failures, fmt.Sprintf("%s: failed to embed: %v", doc.URL, err))
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	return kept, failures
+}
+
+// reembedDocuments embeds the documents of a migration batch whose vectors were
+// dropped, failing the batch if any of them could not be embedded or came
+// back with a length other than the target's dimension
+func (s *Server) reembedDocuments(ctx context.Context, docs []vectordb.Document, dimension int) ([]vectordb.Document, error) {
+	embedded, failed := s.embedMissingVectors(ctx, docs)
+	for i, doc := range embedded {
+		if err, ok := failed[i]; ok {
+			return nil, fmt.Errorf("%w: failed to re-embed document '%s': %v", ErrEmbeddingUnavailable, doc.ID, err)
+		}
+		if length := doc.VectorDimension(); length > 0 && dimension > 0 && length != dimension {
+			return nil, fmt.Errorf("%w: document '%s' was re-embedded with %d dimensions but the target expects %d; check the embedding model",
+				vectordb.ErrDimensionMismatch, doc.ID, length, dimension)
+		}
+	}
+	return embedded, nil
+}
+
+// embedsWrites reports whether documents written without a vector are
+// embedded by the server under mcp.embedding.writes
+func (s *Server) embedsWrites() bool {
+	return s.config.MCP.Embedding.Writes.Enabled && s.embedder != nil
+}
+
+// embedMissingVectors embeds the documents that carry no vector as
+// embedWriteDocuments does, returning every document in input order and the
+// embedding error of each one whose sub-batch never succeeded, by index
+func (s *Server) embedMissingVectors(ctx context.Context, docs []vectordb.Document) ([]vectordb.Document, map[int]error) {
+	if !s.embedsWrites() {
+		return docs, nil
+	}
+	cfg := s.config.MCP.Embedding.Writes
+
+	pending := make([]int, 0, len(docs))
+	for i, doc := range docs {
+		if doc.VectorDimension() == 0 {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return docs, nil
+	}
+
+	embedded := append([]vectordb.Document(nil), docs...)
+	failed := make(map[int]error)
+	for start := 0; start < len(pending); start += cfg.BatchSize {
+		batch := pending[start:min(start+cfg.BatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for j, i := range batch {
+			texts[j] = docs[i].TextForEmbedding()
+		}
+
+		vectors, err := s.embedWithRetry(ctx, texts)
+		if err != nil {
+			s.logger.Warn("Failed to embed documents for write",
+				zap.Int("count", len(batch)),
+				zap.Error(err))
+			for _, i := range batch {
+				failed[i] = err
+			}
+			continue
+		}
+		for j, i := range batch {
+			embedded[i].Vector = vectors[j]
+		}
+	}
+	return embedded, failed
+}
+
+// embedWithRetry embeds texts, retrying a failed call up to
+// mcp.embedding.writes.max_retries times with doubling backoff. Each attempt
+// is bounded by the embedding timeout.
+func (s *Server) embedWithRetry(ctx context.Context, texts []string) ([][]float64, error) {
+	cfg := s.config.MCP.Embedding.Writes
+	backoff := cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		vectors, err := s.embedOnce(ctx, texts)
+		if err == nil || attempt == cfg.MaxRetries || ctx.Err() != nil {
+			return vectors, err
+		}
+
+		s.logger.Debug("Retrying document embedding",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// embedOnce makes a single embed call bounded by the embedding timeout
+func (s *Server) embedOnce(ctx context.Context, texts []string) ([][]float64, error) {
+	embedCtx, cancel := context.WithTimeout(ctx, s.config.GetTimeout("embedding"))
+	defer cancel()
+
+	vectors, err := s.embedder.Embed(embedCtx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
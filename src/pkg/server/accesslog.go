@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"go.uber.org/zap"
+)
+
+// accessLogSkipPaths are not logged to keep probes from flooding the access log
+var accessLogSkipPaths = map[string]bool{
+	"/health": true,
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush forwards to the underlying writer so streamed exports keep flushing
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// AccessLog wraps next so each request is logged with its method, path, status
+// code, duration, and request ID. Requests without an X-Request-ID header are
+// assigned one, which the tool call handler then reuses.
+func AccessLog(next http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accessLogSkipPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = mcp.NewRequestID()
+			r.Header.Set("X-Request-ID", requestID)
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+
+		logger.Info("HTTP request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", recorder.status),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("request_id", requestID))
+	})
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// compressSkipPaths stream NDJSON as it is produced, so they are never
+// buffered or compressed
+var compressSkipPaths = map[string]bool{
+	"/mcp/export": true,
+	"/mcp/ingest": true,
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+		// gzip;q=0 explicitly refuses it
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipWriter buffers a response until it reaches minSize bytes, then
+// compresses it. Shorter responses are written uncompressed when the handler
+// finishes, and a Flush before the threshold gives up on compression so
+// streamed output is not held back.
+type gzipWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buffer  []byte
+	gzip    *gzip.Writer
+	// decided is set once the response is committed, compressed or not
+	decided bool
+}
+
+// WriteHeader holds the status until the encoding is decided
+func (w *gzipWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write buffers p until the threshold is reached
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.gzip != nil {
+			return w.gzip.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buffer = append(w.buffer, p...)
+	if len(w.buffer) >= w.minSize {
+		if err := w.commit(w.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush commits the response, uncompressed if still undecided, and flushes it
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		if err := w.commit(false); err != nil {
+			return
+		}
+	}
+	if w.gzip != nil {
+		_ = w.gzip.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// compressible reports whether the handler left the response for this
+// middleware to encode
+func (w *gzipWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	return !strings.HasPrefix(header.Get("Content-Type"), "text/event-stream")
+}
+
+// commit writes the held status and buffered body, gzipped when compress is set
+func (w *gzipWriter) commit(compress bool) error {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(w.status)
+		w.gzip = gzip.NewWriter(w.ResponseWriter)
+		_, err := w.gzip.Write(w.buffer)
+		w.buffer = nil
+		return err
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buffer)
+	w.buffer = nil
+	return err
+}
+
+// close finishes the response once the handler returns
+func (w *gzipWriter) close() error {
+	if !w.decided {
+		return w.commit(false)
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+// Compress wraps next so responses of at least minSize bytes are gzipped for
+// clients whose Accept-Encoding allows it. The streaming export and ingest
+// endpoints and server-sent events are passed through untouched.
+func Compress(next http.Handler, minSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if compressSkipPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &gzipWriter{ResponseWriter: w, minSize: minSize}
+		defer func() { _ = writer.close() }()
+		next.ServeHTTP(writer, r)
+	})
+}
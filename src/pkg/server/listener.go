@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// limitListener closes connections accepted beyond a cap on concurrently open
+// connections. Unlike a blocking limit, excess clients are turned away at once
+// instead of queueing in the kernel backlog.
+type limitListener struct {
+	net.Listener
+	max      int64
+	open     atomic.Int64
+	rejected atomic.Int64
+	logger   *zap.Logger
+}
+
+// LimitListener wraps l so at most max connections are open at once
+func LimitListener(l net.Listener, max int, logger *zap.Logger) net.Listener {
+	return &limitListener{Listener: l, max: int64(max), logger: logger}
+}
+
+// Accept returns the next connection within the cap, closing any excess ones
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.open.Add(1) > l.max {
+			l.open.Add(-1)
+			rejected := l.rejected.Add(1)
+			l.logger.Warn("Rejected connection over max_connections",
+				zap.String("remote_addr", conn.RemoteAddr().String()),
+				zap.Int64("max_connections", l.max),
+				zap.Int64("rejected_total", rejected))
+			conn.Close()
+			continue
+		}
+
+		return &limitedConn{Conn: conn, release: func() { l.open.Add(-1) }}, nil
+	}
+}
+
+// limitedConn releases its slot in the listener when closed
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close closes the connection and frees its slot once
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -27,14 +28,30 @@ func New(cfg *config.Config, logger *zap.Logger) (*Server, error) {
 		return nil, fmt.Errorf("failed to create MCP server: %w", err)
 	}
 
+	// Surface bad embedding configuration at startup rather than on first write
+	if err := mcpServer.WarmupEmbedding(context.Background()); err != nil {
+		return nil, err
+	}
+
+	handler := mcpServer.Handler()
+	if cfg.Server.Compression.Enabled {
+		handler = Compress(handler, cfg.Server.Compression.MinSize)
+	}
+	if cfg.Server.AccessLog {
+		handler = AccessLog(handler, logger)
+	}
+
 	// Create HTTP server
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      mcpServer.Handler(),
+		Handler:      handler,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	if cfg.Server.DisableKeepAlives {
+		httpServer.SetKeepAlivesEnabled(false)
+	}
 
 	return &Server{
 		config:     cfg,
@@ -46,17 +63,39 @@ func New(cfg *config.Config, logger *zap.Logger) (*Server, error) {
 
 // Start starts the server
 func (s *Server) Start(ctx context.Context) error {
+	// Hold off listening until the configured backends answer, so a fresh
+	// deploy does not take traffic it can only fail
+	if wait := s.config.Server.WaitForBackends; wait.Enabled {
+		if err := s.mcpServer.WaitForBackends(ctx, wait.Timeout, wait.Interval); err != nil {
+			return fmt.Errorf("startup gate failed: %w", err)
+		}
+	}
+
 	s.logger.Info("Starting MCP server",
 		zap.String("address", s.httpServer.Addr))
 
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+	if s.config.Server.MaxConnections > 0 {
+		listener = LimitListener(listener, s.config.Server.MaxConnections, s.logger)
+	}
+
 	// Start HTTP server in a goroutine
 	serverErr := make(chan error, 1)
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 			serverErr <- err
 		}
 	}()
 
+	// Delete expired documents in the background until shutdown
+	go s.mcpServer.RunTTLSweeper(ctx)
+
+	// Recheck a down embedding provider and flush queued writes once it recovers
+	go s.mcpServer.RunEmbeddingMonitor(ctx)
+
 	// Wait for context cancellation or server error
 	select {
 	case <-ctx.Done():
@@ -66,12 +105,21 @@ func (s *Server) Start(ctx context.Context) error {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		// Let in-flight tool calls finish before closing connections
+		if err := s.mcpServer.Drain(shutdownCtx); err != nil {
+			s.logger.Warn("Timed out waiting for in-flight tool calls", zap.Error(err))
+		}
+
 		// Shutdown HTTP server
 		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 			s.logger.Error("Server shutdown error", zap.Error(err))
 			return fmt.Errorf("server shutdown error: %w", err)
 		}
 
+		if err := s.mcpServer.Close(); err != nil {
+			s.logger.Error("Failed to close MCP server", zap.Error(err))
+		}
+
 		s.logger.Info("Server shutdown complete")
 		return nil
 
@@ -86,5 +134,13 @@ func (s *Server) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	return s.httpServer.Shutdown(ctx)
+	if err := s.mcpServer.Drain(ctx); err != nil {
+		s.logger.Warn("Timed out waiting for in-flight tool calls", zap.Error(err))
+	}
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return s.mcpServer.Close()
 }
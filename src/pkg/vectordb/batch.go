@@ -0,0 +1,47 @@
+package vectordb
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// estimateDocumentSize approximates the encoded size of a document in bytes
+func estimateDocumentSize(doc Document) int {
+	// Vectors travel as float32 whatever precision they were written with
+	size := len(doc.ID) + len(doc.URL) + len(doc.Text) + 4*doc.VectorDimension()
+	if len(doc.Metadata) > 0 {
+		if encoded, err := json.Marshal(doc.Metadata); err == nil {
+			size += len(encoded)
+		}
+	}
+	return size
+}
+
+// insertBatchSize returns how many documents fit in one insert of at most
+// maxBytes, based on the average document size. A non-positive limit means
+// everything is sent at once.
+func insertBatchSize(docs []Document, maxBytes int) int {
+	if maxBytes <= 0 || len(docs) == 0 {
+		return max(len(docs), 1)
+	}
+
+	total := 0
+	for _, doc := range docs {
+		total += estimateDocumentSize(doc)
+	}
+	average := max(total/len(docs), 1)
+
+	return max(min(maxBytes/average, len(docs)), 1)
+}
+
+// isMessageTooLarge reports whether err is a backend rejection for exceeding
+// the message size limit, such as gRPC's ResourceExhausted error
+func isMessageTooLarge(err error) bool {
+	if errors.Is(err, ErrMessageTooLarge) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "message larger than max") ||
+		strings.Contains(message, "resourceexhausted")
+}
@@ -0,0 +1,352 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState string
+
+// Circuit breaker states
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerDatabase wraps a VectorDatabase with a circuit breaker. After
+// failure_threshold consecutive backend failures the circuit opens and calls fail
+// fast with ErrBackendUnavailable. Once the reset timeout elapses a single probe
+// call is let through; success closes the circuit, failure reopens it with the
+// reset timeout doubled up to max_reset_timeout.
+type CircuitBreakerDatabase struct {
+	VectorDatabase
+
+	mutex            sync.Mutex
+	failureThreshold int
+	baseReset        time.Duration
+	maxReset         time.Duration
+	state            CircuitState
+	failures         int
+	resetTimeout     time.Duration
+	openedAt         time.Time
+	probing          bool
+}
+
+// NewCircuitBreakerDatabase wraps db with a circuit breaker configured by cfg
+func NewCircuitBreakerDatabase(db VectorDatabase, cfg config.CircuitBreakerConfig) *CircuitBreakerDatabase {
+	maxReset := cfg.MaxResetTimeout
+	if maxReset < cfg.ResetTimeout {
+		maxReset = cfg.ResetTimeout
+	}
+
+	return &CircuitBreakerDatabase{
+		VectorDatabase:   db,
+		failureThreshold: cfg.FailureThreshold,
+		baseReset:        cfg.ResetTimeout,
+		maxReset:         maxReset,
+		state:            CircuitClosed,
+		resetTimeout:     cfg.ResetTimeout,
+	}
+}
+
+// Unwrap returns the wrapped database
+func (c *CircuitBreakerDatabase) Unwrap() VectorDatabase {
+	return c.VectorDatabase
+}
+
+// State returns the current circuit state
+func (c *CircuitBreakerDatabase) State() CircuitState {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= c.resetTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Stats returns the breaker state for health reporting
+func (c *CircuitBreakerDatabase) Stats() map[string]interface{} {
+	state := c.State()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := map[string]interface{}{
+		"state":                state,
+		"consecutive_failures": c.failures,
+	}
+	if state != CircuitClosed {
+		stats["reset_timeout"] = c.resetTimeout.String()
+	}
+	return stats
+}
+
+// allow reports whether a call may reach the backend, marking it as the
+// half-open probe when the reset timeout has elapsed
+func (c *CircuitBreakerDatabase) allow() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch c.state {
+	case CircuitClosed:
+		return nil
+	case CircuitOpen:
+		if time.Since(c.openedAt) < c.resetTimeout {
+			retryIn := c.resetTimeout - time.Since(c.openedAt)
+			return fmt.Errorf("%w: %s circuit is open, retry in %s",
+				ErrBackendUnavailable, c.Type(), retryIn.Round(time.Millisecond))
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+		return nil
+	default:
+		if c.probing {
+			return fmt.Errorf("%w: %s circuit is half-open and a recovery probe is in flight",
+				ErrBackendUnavailable, c.Type())
+		}
+		c.probing = true
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a backend call
+func (c *CircuitBreakerDatabase) record(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// A caller giving up says nothing about the backend's health
+	if errors.Is(err, context.Canceled) {
+		if c.state == CircuitHalfOpen {
+			c.probing = false
+		}
+		return
+	}
+
+	// The backend answered; the request itself was at fault
+	if errors.Is(err, ErrInvalidRequest) {
+		err = nil
+	}
+
+	if err == nil {
+		c.state = CircuitClosed
+		c.failures = 0
+		c.resetTimeout = c.baseReset
+		c.probing = false
+		return
+	}
+
+	c.failures++
+	switch c.state {
+	case CircuitHalfOpen:
+		c.resetTimeout *= 2
+		if c.resetTimeout > c.maxReset {
+			c.resetTimeout = c.maxReset
+		}
+		c.open()
+	case CircuitClosed:
+		if c.failures >= c.failureThreshold {
+			c.open()
+		}
+	}
+}
+
+// open trips the circuit; the caller must hold the mutex
+func (c *CircuitBreakerDatabase) open() {
+	c.state = CircuitOpen
+	c.openedAt = time.Now()
+	c.probing = false
+}
+
+// guard runs fn through the breaker
+func (c *CircuitBreakerDatabase) guard(fn func() error) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := fn()
+	c.record(err)
+	return err
+}
+
+// Setup initializes the database through the breaker
+func (c *CircuitBreakerDatabase) Setup(ctx context.Context, embedding string) error {
+	return c.guard(func() error {
+		return c.VectorDatabase.Setup(ctx, embedding)
+	})
+}
+
+// WriteDocument writes a single document through the breaker
+func (c *CircuitBreakerDatabase) WriteDocument(ctx context.Context, doc Document) (WriteStats, error) {
+	var stats WriteStats
+	err := c.guard(func() (err error) {
+		stats, err = c.VectorDatabase.WriteDocument(ctx, doc)
+		return err
+	})
+	return stats, err
+}
+
+// WriteDocuments writes multiple documents through the breaker
+func (c *CircuitBreakerDatabase) WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error) {
+	var stats WriteStats
+	err := c.guard(func() (err error) {
+		stats, err = c.VectorDatabase.WriteDocuments(ctx, docs)
+		return err
+	})
+	return stats, err
+}
+
+// Query performs a natural language query through the breaker
+func (c *CircuitBreakerDatabase) Query(ctx context.Context, query string, limit int, collectionName string) (interface{}, error) {
+	var result interface{}
+	err := c.guard(func() (err error) {
+		result, err = c.VectorDatabase.Query(ctx, query, limit, collectionName)
+		return err
+	})
+	return result, err
+}
+
+// Search performs a vector similarity search through the breaker
+func (c *CircuitBreakerDatabase) Search(ctx context.Context, query string, limit int, collectionName string) ([]SearchResult, error) {
+	var results []SearchResult
+	err := c.guard(func() (err error) {
+		results, err = c.VectorDatabase.Search(ctx, query, limit, collectionName)
+		return err
+	})
+	return results, err
+}
+
+// ListDocuments lists documents through the breaker
+func (c *CircuitBreakerDatabase) ListDocuments(ctx context.Context, limit, offset int) ([]Document, error) {
+	var docs []Document
+	err := c.guard(func() (err error) {
+		docs, err = c.VectorDatabase.ListDocuments(ctx, limit, offset)
+		return err
+	})
+	return docs, err
+}
+
+// GetDocument fetches a document through the breaker
+func (c *CircuitBreakerDatabase) GetDocument(ctx context.Context, documentID string) (Document, error) {
+	var doc Document
+	err := c.guard(func() (err error) {
+		doc, err = c.VectorDatabase.GetDocument(ctx, documentID)
+		return err
+	})
+	return doc, err
+}
+
+// UpdateDocument updates a document through the breaker
+func (c *CircuitBreakerDatabase) UpdateDocument(ctx context.Context, doc Document) error {
+	return c.guard(func() error {
+		return c.VectorDatabase.UpdateDocument(ctx, doc)
+	})
+}
+
+// EstimateDocuments estimates the document count through the breaker
+func (c *CircuitBreakerDatabase) EstimateDocuments(ctx context.Context) (int, error) {
+	var count int
+	err := c.guard(func() (err error) {
+		count, err = c.VectorDatabase.EstimateDocuments(ctx)
+		return err
+	})
+	return count, err
+}
+
+// CountDocuments counts documents through the breaker
+func (c *CircuitBreakerDatabase) CountDocuments(ctx context.Context) (int, error) {
+	var count int
+	err := c.guard(func() (err error) {
+		count, err = c.VectorDatabase.CountDocuments(ctx)
+		return err
+	})
+	return count, err
+}
+
+// DeleteDocument deletes a document through the breaker
+func (c *CircuitBreakerDatabase) DeleteDocument(ctx context.Context, documentID string) error {
+	return c.guard(func() error {
+		return c.VectorDatabase.DeleteDocument(ctx, documentID)
+	})
+}
+
+// DeleteDocuments deletes multiple documents through the breaker. Documents
+// that fail on their own are reported, not counted as backend failures.
+func (c *CircuitBreakerDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error) {
+	var report DeleteReport
+	err := c.guard(func() (err error) {
+		report, err = c.VectorDatabase.DeleteDocuments(ctx, documentIDs)
+		return err
+	})
+	return report, err
+}
+
+// DeleteExpired deletes expired documents through the breaker
+func (c *CircuitBreakerDatabase) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	var deleted int
+	err := c.guard(func() (err error) {
+		deleted, err = c.VectorDatabase.DeleteExpired(ctx, before)
+		return err
+	})
+	return deleted, err
+}
+
+// DeleteByFilter deletes documents matching a metadata filter through the breaker
+func (c *CircuitBreakerDatabase) DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error) {
+	var deleted int
+	err := c.guard(func() (err error) {
+		deleted, err = c.VectorDatabase.DeleteByFilter(ctx, filter)
+		return err
+	})
+	return deleted, err
+}
+
+// TruncateCollection empties a collection through the breaker
+func (c *CircuitBreakerDatabase) TruncateCollection(ctx context.Context, collectionName string) (int, error) {
+	var deleted int
+	err := c.guard(func() (err error) {
+		deleted, err = c.VectorDatabase.TruncateCollection(ctx, collectionName)
+		return err
+	})
+	return deleted, err
+}
+
+// ListCollections lists collections through the breaker
+func (c *CircuitBreakerDatabase) ListCollections(ctx context.Context) ([]string, error) {
+	var collections []string
+	err := c.guard(func() (err error) {
+		collections, err = c.VectorDatabase.ListCollections(ctx)
+		return err
+	})
+	return collections, err
+}
+
+// GetCollectionInfo returns collection information through the breaker
+func (c *CircuitBreakerDatabase) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	err := c.guard(func() (err error) {
+		info, err = c.VectorDatabase.GetCollectionInfo(ctx, collectionName)
+		return err
+	})
+	return info, err
+}
+
+// CreateCollection creates a collection through the breaker
+func (c *CircuitBreakerDatabase) CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error {
+	return c.guard(func() error {
+		return c.VectorDatabase.CreateCollection(ctx, collectionName, options)
+	})
+}
+
+// DeleteCollection deletes a collection through the breaker
+func (c *CircuitBreakerDatabase) DeleteCollection(ctx context.Context, collectionName string) error {
+	return c.guard(func() error {
+		return c.VectorDatabase.DeleteCollection(ctx, collectionName)
+	})
+}
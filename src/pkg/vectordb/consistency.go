@@ -0,0 +1,56 @@
+package vectordb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// consistencyLevelKey is the context key holding the read consistency level
+type consistencyLevelKey struct{}
+
+// WithConsistencyLevel returns a context whose reads use the given consistency
+// level, one of the config.Consistency* levels
+func WithConsistencyLevel(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, consistencyLevelKey{}, strings.ToLower(level))
+}
+
+// ConsistencyLevelFromContext returns the read consistency level, or an empty
+// string to leave the client default
+func ConsistencyLevelFromContext(ctx context.Context) string {
+	level, _ := ctx.Value(consistencyLevelKey{}).(string)
+	return level
+}
+
+// MilvusConsistencyLevel maps a consistency level to the Milvus name
+func MilvusConsistencyLevel(level string) string {
+	switch level {
+	case config.ConsistencyStrong:
+		return "Strong"
+	case config.ConsistencyBounded:
+		return "Bounded"
+	case config.ConsistencySession:
+		return "Session"
+	case config.ConsistencyEventually:
+		return "Eventually"
+	default:
+		return ""
+	}
+}
+
+// WeaviateConsistencyLevel maps a consistency level to the Weaviate replica
+// consistency level. Weaviate has no session or bounded guarantee, so both
+// read from a quorum of replicas.
+func WeaviateConsistencyLevel(level string) string {
+	switch level {
+	case config.ConsistencyStrong:
+		return "ALL"
+	case config.ConsistencyBounded, config.ConsistencySession:
+		return "QUORUM"
+	case config.ConsistencyEventually:
+		return "ONE"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,55 @@
+package vectordb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Metadata keys used by content deduplication
+const (
+	// MetadataContentHash holds the SHA-256 of a document's text
+	MetadataContentHash = "content_hash"
+	// MetadataDuplicateURLs lists the URLs of duplicates merged into a document
+	MetadataDuplicateURLs = "duplicate_urls"
+)
+
+// ContentHash returns the hex SHA-256 of text
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithContentHash returns a copy of doc with the hash of its text recorded in
+// its metadata
+func WithContentHash(doc Document) Document {
+	doc.Metadata = copyMetadata(doc.Metadata)
+	doc.Metadata[MetadataContentHash] = ContentHash(doc.Text)
+	return doc
+}
+
+// WithDuplicateURL returns a copy of doc listing url among its duplicate
+// URLs, and false when url is already its URL or listed
+func WithDuplicateURL(doc Document, url string) (Document, bool) {
+	if url == "" || url == doc.URL {
+		return doc, false
+	}
+
+	var urls []interface{}
+	switch listed := doc.Metadata[MetadataDuplicateURLs].(type) {
+	case []interface{}:
+		urls = listed
+	case []string:
+		for _, u := range listed {
+			urls = append(urls, u)
+		}
+	}
+	for _, listed := range urls {
+		if listed == url {
+			return doc, false
+		}
+	}
+
+	doc.Metadata = copyMetadata(doc.Metadata)
+	doc.Metadata[MetadataDuplicateURLs] = append(append([]interface{}{}, urls...), url)
+	return doc, true
+}
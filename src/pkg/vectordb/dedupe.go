@@ -0,0 +1,83 @@
+package vectordb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DedupeOverfetch multiplies the limit of a deduplicated search so that
+// enough distinct groups usually remain after collapsing
+const DedupeOverfetch = 4
+
+// dedupeMetadataPrefix selects a metadata field as the dedupe key
+const dedupeMetadataPrefix = "metadata."
+
+// ValidateDedupeKey checks a dedupe_by value, which is either url or
+// metadata.<field>
+func ValidateDedupeKey(key string) error {
+	if key == FieldURL {
+		return nil
+	}
+	if field, ok := strings.CutPrefix(key, dedupeMetadataPrefix); ok && field != "" {
+		return nil
+	}
+	return invalidRequestf("dedupe_by must be url or a metadata field such as metadata.parent_id, got '%s'", key)
+}
+
+// DedupeField returns the document field a dedupe key reads
+func DedupeField(key string) string {
+	if key == FieldURL {
+		return FieldURL
+	}
+	return FieldMetadata
+}
+
+// DedupeResults collapses results to the best-scoring hit per group of equal
+// key values and keeps at most limit groups. Results must be ordered best
+// first. Each kept result's GroupSize counts the candidates in its group; a
+// result with no value for the key is a group of its own.
+func DedupeResults(results []SearchResult, key string, limit int) []SearchResult {
+	deduped := make([]SearchResult, 0, min(limit, len(results)))
+	groups := make(map[string]int, len(results))
+	for _, result := range results {
+		value, ok := dedupeValue(result.Document, key)
+		if ok {
+			if i, seen := groups[value]; seen {
+				if i >= 0 {
+					deduped[i].GroupSize++
+				}
+				continue
+			}
+		}
+		if len(deduped) == limit {
+			// Keep counting members of kept groups, but start no new ones
+			if ok {
+				groups[value] = -1
+			}
+			continue
+		}
+		if ok {
+			groups[value] = len(deduped)
+		}
+		result.GroupSize = 1
+		deduped = append(deduped, result)
+	}
+	return deduped
+}
+
+// dedupeValue returns the grouping value of doc for key
+func dedupeValue(doc Document, key string) (string, bool) {
+	if key == FieldURL {
+		return doc.URL, doc.URL != ""
+	}
+
+	value, exists := doc.Metadata[strings.TrimPrefix(key, dedupeMetadataPrefix)]
+	if !exists || value == nil {
+		return "", false
+	}
+	// Numbers group by value whatever their type, but 1 and "1" stay distinct
+	if isNumber(value) {
+		return fmt.Sprintf("number:%v", toFloat(value)), true
+	}
+	return fmt.Sprintf("%T:%v", value, value), true
+}
@@ -0,0 +1,123 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Supported embedding providers
+const (
+	EmbeddingProviderOpenAI      = "openai"
+	EmbeddingProviderCustomLocal = "custom_local"
+)
+
+// defaultOpenAIEmbeddingURL is the embeddings endpoint used for the openai provider
+const defaultOpenAIEmbeddingURL = "https://api.openai.com/v1/embeddings"
+
+// Embedder turns texts into embedding vectors
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// HTTPEmbedder calls an OpenAI-compatible embeddings endpoint. It keeps a single
+// HTTP client so connections are reused across calls.
+type HTTPEmbedder struct {
+	url    string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewEmbedder creates an embedder for the configured provider. It returns nil
+// when no provider is configured.
+func NewEmbedder(cfg config.EmbeddingConfig) (Embedder, error) {
+	embedder := &HTTPEmbedder{
+		url:    cfg.URL,
+		apiKey: cfg.APIKey,
+		model:  cfg.Model,
+		client: &http.Client{},
+	}
+
+	switch strings.ToLower(cfg.Provider) {
+	case "", "none":
+		return nil, nil
+	case EmbeddingProviderOpenAI:
+		if embedder.url == "" {
+			embedder.url = defaultOpenAIEmbeddingURL
+		}
+	case EmbeddingProviderCustomLocal:
+		if embedder.url == "" {
+			return nil, fmt.Errorf("embedding url is required for the %s provider", EmbeddingProviderCustomLocal)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", cfg.Provider)
+	}
+
+	return embedder, nil
+}
+
+// embeddingRequest is the body posted to the embeddings endpoint
+type embeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the body returned by the embeddings endpoint
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns one embedding per text, in input order
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(embeddingRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embedding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding endpoint returned status %d", resp.StatusCode)
+	}
+
+	var decoded embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for _, item := range decoded.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding endpoint returned out of range index %d", item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+	for i, embedding := range embeddings {
+		if len(embedding) == 0 {
+			return nil, fmt.Errorf("embedding endpoint returned no embedding for input %d", i)
+		}
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,79 @@
+package vectordb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned, possibly wrapped, by VectorDatabase implementations
+// and their clients. Match them with errors.Is rather than on message text.
+var (
+	// ErrInvalidRequest is matched by every error the request itself caused
+	// rather than the backend's health, including each sentinel below that
+	// describes a bad name, vector, document, or filter, or a collection or
+	// document that does not exist or already does
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrCollectionNotFound is returned when a collection does not exist
+	ErrCollectionNotFound = invalidRequestf("collection not found")
+
+	// ErrCollectionExists is returned when creating a collection that already exists
+	ErrCollectionExists = invalidRequestf("collection already exists")
+
+	// ErrDocumentNotFound is returned when a document ID does not exist in a collection
+	ErrDocumentNotFound = invalidRequestf("document not found")
+
+	// ErrDatabaseClosed is returned for operations on a database whose connection was closed
+	ErrDatabaseClosed = errors.New("database is closed")
+
+	// ErrDimensionMismatch is returned when a vector's length differs from the configured dimension
+	ErrDimensionMismatch = invalidRequestf("vector dimension mismatch")
+
+	// ErrVectorSizeTooLarge is returned when a collection's dimension exceeds mcp.embedding.max_vector_size
+	ErrVectorSizeTooLarge = invalidRequestf("vector size too large")
+
+	// ErrUnknownVector is returned when a document or search names a vector space that is not configured
+	ErrUnknownVector = invalidRequestf("unknown named vector")
+
+	// ErrDocumentTooLarge is returned when a document exceeds a size or field limit of the backend
+	ErrDocumentTooLarge = invalidRequestf("document exceeds backend limits")
+
+	// ErrMetadataSchema is returned when metadata does not match the declared metadata schema
+	ErrMetadataSchema = invalidRequestf("metadata does not match the schema")
+
+	// ErrInvalidFilter is returned for an empty or malformed metadata filter
+	ErrInvalidFilter = invalidRequestf("invalid filter")
+
+	// ErrCollectionNotReady is returned when a new collection is still not queryable when the readiness wait ends
+	ErrCollectionNotReady = errors.New("collection not ready")
+
+	// ErrBackendUnavailable is returned without contacting the backend while its circuit breaker is open
+	ErrBackendUnavailable = errors.New("backend unavailable")
+
+	// ErrMessageTooLarge is returned by clients when a request exceeds the backend's message size limit
+	ErrMessageTooLarge = errors.New("message too large")
+)
+
+// requestError is an error caused by the request, matching ErrInvalidRequest
+// while keeping its own message
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// Is reports request errors as ErrInvalidRequest
+func (e *requestError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// invalidRequestf formats an error that matches ErrInvalidRequest
+func invalidRequestf(format string, args ...interface{}) error {
+	return &requestError{err: fmt.Errorf(format, args...)}
+}
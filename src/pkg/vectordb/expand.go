@@ -0,0 +1,64 @@
+package vectordb
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Expander augments query text before it is embedded, for example with
+// synonyms or an LLM-generated paraphrase, so short queries retrieve more.
+// Implementations return the full text to embed, including the original query.
+type Expander interface {
+	Expand(ctx context.Context, query string) (string, error)
+}
+
+// SynonymExpander appends the configured synonyms of each query term to the
+// query. Terms match case-insensitively, ignoring surrounding punctuation.
+type SynonymExpander struct {
+	synonyms map[string][]string
+}
+
+// NewSynonymExpander creates an expander from mcp.expansion.synonyms. It
+// returns nil when no synonyms are configured.
+func NewSynonymExpander(cfg config.ExpansionConfig) *SynonymExpander {
+	if len(cfg.Synonyms) == 0 {
+		return nil
+	}
+
+	synonyms := make(map[string][]string, len(cfg.Synonyms))
+	for term, alternatives := range cfg.Synonyms {
+		synonyms[strings.ToLower(term)] = alternatives
+	}
+	return &SynonymExpander{synonyms: synonyms}
+}
+
+// Expand returns the query followed by the synonyms of its terms that it does
+// not already contain. A query without known terms is returned unchanged.
+func (e *SynonymExpander) Expand(ctx context.Context, query string) (string, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	seen := make(map[string]bool, len(terms))
+	for i, term := range terms {
+		terms[i] = strings.TrimFunc(term, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		seen[terms[i]] = true
+	}
+
+	var additions []string
+	for _, term := range terms {
+		for _, synonym := range e.synonyms[term] {
+			if key := strings.ToLower(synonym); !seen[key] {
+				seen[key] = true
+				additions = append(additions, synonym)
+			}
+		}
+	}
+
+	if len(additions) == 0 {
+		return query, nil
+	}
+	return query + " " + strings.Join(additions, " "), nil
+}
@@ -0,0 +1,449 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetadataFilter selects documents whose metadata satisfies every condition.
+// Keys are metadata keys, with dots addressing fields of nested objects such
+// as author.name; values are strings, numbers, or booleans the metadata must
+// equal, or an ArrayCondition on a metadata array.
+type MetadataFilter map[string]interface{}
+
+// Array operators test a metadata array: contains matches arrays holding a
+// value, contains_any arrays holding at least one of several values, and
+// contains_all arrays holding every one of them
+const (
+	FilterContains    = "contains"
+	FilterContainsAny = "contains_any"
+	FilterContainsAll = "contains_all"
+)
+
+// ArrayCondition is a filter condition on a metadata array, written in a
+// filter as {"contains": "a"} or {"contains_any": ["a", "b"]}. Contains is
+// held as contains_any with a single value.
+type ArrayCondition struct {
+	Operator string
+	Values   []interface{}
+}
+
+// ParseMetadataFilter validates a filter argument. The filter must not be
+// empty so a missing filter can never match a whole collection.
+func ParseMetadataFilter(raw map[string]interface{}) (MetadataFilter, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: filter must have at least one condition", ErrInvalidFilter)
+	}
+
+	filter := make(MetadataFilter, len(raw))
+	for key, value := range raw {
+		if key == "" {
+			return nil, fmt.Errorf("%w: filter keys must not be empty", ErrInvalidFilter)
+		}
+		switch v := value.(type) {
+		case string, bool, float64, int, int64:
+			filter[key] = value
+		case map[string]interface{}:
+			condition, err := parseArrayCondition(key, v)
+			if err != nil {
+				return nil, err
+			}
+			filter[key] = condition
+		default:
+			return nil, fmt.Errorf("%w: value for '%s' must be a string, number, boolean, or array operator", ErrInvalidFilter, key)
+		}
+	}
+	return filter, nil
+}
+
+// parseArrayCondition validates an array operator object such as
+// {"contains_any": ["a", "b"]}. Its values must all be strings, all numbers,
+// or all booleans, since Weaviate compares an array property against values
+// of a single type.
+func parseArrayCondition(key string, raw map[string]interface{}) (ArrayCondition, error) {
+	if len(raw) != 1 {
+		return ArrayCondition{}, fmt.Errorf("%w: condition for '%s' must have exactly one of %s, %s, or %s", ErrInvalidFilter, key, FilterContains, FilterContainsAny, FilterContainsAll)
+	}
+
+	var condition ArrayCondition
+	for operator, value := range raw {
+		switch operator {
+		case FilterContains:
+			condition = ArrayCondition{Operator: FilterContainsAny, Values: []interface{}{value}}
+		case FilterContainsAny, FilterContainsAll:
+			values, ok := value.([]interface{})
+			if !ok || len(values) == 0 {
+				return ArrayCondition{}, fmt.Errorf("%w: %s for '%s' must be a non-empty array", ErrInvalidFilter, operator, key)
+			}
+			condition = ArrayCondition{Operator: operator, Values: values}
+		default:
+			return ArrayCondition{}, fmt.Errorf("%w: unknown operator '%s' for '%s'; use %s, %s, or %s", ErrInvalidFilter, operator, key, FilterContains, FilterContainsAny, FilterContainsAll)
+		}
+	}
+
+	kind := ""
+	for _, value := range condition.Values {
+		valueKind := filterValueKind(value)
+		if valueKind == "" {
+			return ArrayCondition{}, fmt.Errorf("%w: values for '%s' must be strings, numbers, or booleans", ErrInvalidFilter, key)
+		}
+		if kind != "" && valueKind != kind {
+			return ArrayCondition{}, fmt.Errorf("%w: values for '%s' must all be of one type", ErrInvalidFilter, key)
+		}
+		kind = valueKind
+	}
+	return condition, nil
+}
+
+// filterValueKind names the kind of a scalar filter value: string, boolean, or
+// number. It returns "" for any other value.
+func filterValueKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int, int64:
+		return "number"
+	}
+	return ""
+}
+
+// metadataFilterKey is the context key holding the metadata filter for a search
+type metadataFilterKey struct{}
+
+// WithMetadataFilter returns a context that restricts Search and Query to
+// documents matching filter
+func WithMetadataFilter(ctx context.Context, filter MetadataFilter) context.Context {
+	return context.WithValue(ctx, metadataFilterKey{}, filter)
+}
+
+// MetadataFilterFromContext returns the metadata filter for a search, or nil for none
+func MetadataFilterFromContext(ctx context.Context) MetadataFilter {
+	filter, _ := ctx.Value(metadataFilterKey{}).(MetadataFilter)
+	return filter
+}
+
+// Filter strategies for a filtered search. Pre-filtering restricts the
+// candidates before the ANN search, so every result matches and recall is
+// preserved, at the cost of a slower search over selective filters.
+// Post-filtering runs the ANN search unfiltered and drops non-matching
+// results; it is fast but returns fewer than limit results when matches are
+// rare among the nearest neighbours.
+const (
+	FilterStrategyPre  = "pre"
+	FilterStrategyPost = "post"
+)
+
+// postFilterOverfetch multiplies the limit of a post-filtered search so that
+// enough candidates usually survive the filter
+const postFilterOverfetch = 4
+
+// ValidateFilterStrategy checks a filter_strategy value; empty selects the
+// backend's recommended strategy
+func ValidateFilterStrategy(strategy string) error {
+	switch strategy {
+	case "", FilterStrategyPre, FilterStrategyPost:
+		return nil
+	default:
+		return fmt.Errorf("%w: filter_strategy must be %s or %s", ErrInvalidFilter, FilterStrategyPre, FilterStrategyPost)
+	}
+}
+
+// filterStrategyKey is the context key holding the filter strategy for a search
+type filterStrategyKey struct{}
+
+// WithFilterStrategy returns a context that selects how Search applies its
+// metadata filter
+func WithFilterStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, filterStrategyKey{}, strategy)
+}
+
+// FilterStrategyFromContext returns the filter strategy for a search, or
+// empty for the backend's recommended strategy
+func FilterStrategyFromContext(ctx context.Context) string {
+	strategy, _ := ctx.Value(filterStrategyKey{}).(string)
+	return strategy
+}
+
+// filteredSearch runs search with the context's metadata filter applied by the
+// context's strategy, falling back to recommended when none is set. With
+// post-filtering the backend searches unfiltered for more candidates and the
+// results are filtered and trimmed to limit here. It returns the strategy used.
+func filteredSearch(ctx context.Context, limit int, recommended string, search func(ctx context.Context, limit int) ([]SearchResult, error)) ([]SearchResult, string, error) {
+	strategy := FilterStrategyFromContext(ctx)
+	if strategy == "" {
+		strategy = recommended
+	}
+
+	filter := MetadataFilterFromContext(ctx)
+	if len(filter) == 0 || strategy != FilterStrategyPost {
+		results, err := search(ctx, limit)
+		return results, strategy, err
+	}
+
+	// The filter needs metadata even when the caller did not select it
+	fields := FieldsFromContext(ctx)
+	if len(fields) > 0 && !containsField(fields, FieldMetadata) {
+		ctx = WithFields(ctx, append(append([]string{}, fields...), FieldMetadata))
+	}
+
+	candidates, err := search(WithMetadataFilter(ctx, nil), limit*postFilterOverfetch)
+	if err != nil {
+		return nil, strategy, err
+	}
+
+	results := make([]SearchResult, 0, limit)
+	for _, result := range candidates {
+		if len(results) == limit {
+			break
+		}
+		if filter.Matches(result.Document) {
+			if len(fields) > 0 {
+				result.Document = ProjectDocument(result.Document, fields)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, strategy, nil
+}
+
+// keys returns the filter's keys sorted so translated expressions are stable
+func (f MetadataFilter) keys() []string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Matches reports whether doc's metadata satisfies every condition
+func (f MetadataFilter) Matches(doc Document) bool {
+	for key, want := range f {
+		got, exists := metadataValue(doc.Metadata, key)
+		if !exists {
+			return false
+		}
+		if condition, ok := want.(ArrayCondition); ok {
+			if !condition.matches(got) {
+				return false
+			}
+		} else if !filterValuesEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether a metadata value is an array satisfying the condition
+func (c ArrayCondition) matches(got interface{}) bool {
+	elements, ok := metadataArray(got)
+	if !ok {
+		return false
+	}
+	for _, want := range c.Values {
+		found := false
+		for _, element := range elements {
+			if filterValuesEqual(element, want) {
+				found = true
+				break
+			}
+		}
+		if found && c.Operator == FilterContainsAny {
+			return true
+		}
+		if !found && c.Operator == FilterContainsAll {
+			return false
+		}
+	}
+	return c.Operator == FilterContainsAll
+}
+
+// metadataArray returns the elements of a metadata array, whether it was
+// decoded from JSON or built in Go
+func metadataArray(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		elements := make([]interface{}, len(v))
+		for i, element := range v {
+			elements[i] = element
+		}
+		return elements, true
+	case []float64:
+		elements := make([]interface{}, len(v))
+		for i, element := range v {
+			elements[i] = element
+		}
+		return elements, true
+	default:
+		return nil, false
+	}
+}
+
+// metadataValue looks up a filter key in metadata. A key naming a top-level
+// field is used as is; otherwise its dots descend into nested objects.
+func metadataValue(metadata map[string]interface{}, key string) (interface{}, bool) {
+	if value, exists := metadata[key]; exists {
+		return value, true
+	}
+
+	parts := strings.Split(key, ".")
+	var value interface{} = metadata
+	for _, part := range parts {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// MilvusExpr translates the filter into a Milvus boolean expression over the
+// JSON metadata field. Array conditions use json_contains_any and
+// json_contains_all, or json_contains for a single value.
+func (f MetadataFilter) MilvusExpr() string {
+	conditions := make([]string, 0, len(f))
+	for _, key := range f.keys() {
+		path := "metadata"
+		for _, part := range strings.Split(key, ".") {
+			path += "[" + strconv.Quote(part) + "]"
+		}
+		condition, ok := f[key].(ArrayCondition)
+		switch {
+		case !ok:
+			conditions = append(conditions, fmt.Sprintf("%s == %s", path, milvusLiteral(f[key])))
+		case len(condition.Values) == 1:
+			conditions = append(conditions, fmt.Sprintf("json_contains(%s, %s)", path, milvusLiteral(condition.Values[0])))
+		default:
+			literals := make([]string, len(condition.Values))
+			for i, value := range condition.Values {
+				literals[i] = milvusLiteral(value)
+			}
+			conditions = append(conditions, fmt.Sprintf("json_%s(%s, [%s])", condition.Operator, path, strings.Join(literals, ", ")))
+		}
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// WeaviateWhere translates the filter into a Weaviate where filter on metadata
+// properties, or nil for an empty filter. Array conditions use the ContainsAny
+// and ContainsAll operators.
+func (f MetadataFilter) WeaviateWhere() map[string]interface{} {
+	if len(f) == 0 {
+		return nil
+	}
+
+	operands := make([]map[string]interface{}, 0, len(f))
+	for _, key := range f.keys() {
+		operand := map[string]interface{}{
+			"path":     append([]string{"metadata"}, strings.Split(key, ".")...),
+			"operator": "Equal",
+		}
+		if condition, ok := f[key].(ArrayCondition); ok {
+			operand["operator"] = "ContainsAll"
+			if condition.Operator == FilterContainsAny {
+				operand["operator"] = "ContainsAny"
+			}
+			weaviateArrayValue(operand, condition.Values)
+			operands = append(operands, operand)
+			continue
+		}
+		switch value := f[key].(type) {
+		case string:
+			operand["valueText"] = value
+		case bool:
+			operand["valueBoolean"] = value
+		default:
+			operand["valueNumber"] = toFloat(value)
+		}
+		operands = append(operands, operand)
+	}
+
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return map[string]interface{}{
+		"operator": "And",
+		"operands": operands,
+	}
+}
+
+// weaviateArrayValue sets an operand's array value field for values, which
+// ParseMetadataFilter has checked are all of one type
+func weaviateArrayValue(operand map[string]interface{}, values []interface{}) {
+	switch values[0].(type) {
+	case string:
+		texts := make([]string, len(values))
+		for i, value := range values {
+			texts[i] = value.(string)
+		}
+		operand["valueTextArray"] = texts
+	case bool:
+		booleans := make([]bool, len(values))
+		for i, value := range values {
+			booleans[i] = value.(bool)
+		}
+		operand["valueBooleanArray"] = booleans
+	default:
+		numbers := make([]float64, len(values))
+		for i, value := range values {
+			numbers[i] = toFloat(value)
+		}
+		operand["valueNumberArray"] = numbers
+	}
+}
+
+// milvusLiteral renders a filter value as a Milvus expression literal
+func milvusLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return strconv.FormatFloat(toFloat(v), 'g', -1, 64)
+	}
+}
+
+// filterValuesEqual compares metadata values, treating all numeric types alike
+func filterValuesEqual(a, b interface{}) bool {
+	if isNumber(a) && isNumber(b) {
+		return toFloat(a) == toFloat(b)
+	}
+	return a == b
+}
+
+// isNumber reports whether v holds a numeric metadata value
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case float64, float32, int, int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// toFloat converts a numeric metadata value to float64
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return 0
+	}
+}
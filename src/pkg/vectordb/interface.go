@@ -2,7 +2,9 @@ package vectordb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
 )
@@ -24,6 +26,9 @@ type VectorDatabase interface {
 	// WriteDocuments writes multiple documents to the database
 	WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error)
 
+	// Query, Search, ListDocuments, and CountDocuments skip soft-deleted
+	// documents unless the context is marked WithTombstones
+
 	// Query performs a natural language query on the database
 	Query(ctx context.Context, query string, limit int, collectionName string) (interface{}, error)
 
@@ -33,14 +38,41 @@ type VectorDatabase interface {
 	// ListDocuments lists documents from the database
 	ListDocuments(ctx context.Context, limit, offset int) ([]Document, error)
 
+	// GetDocument returns a single document by ID
+	GetDocument(ctx context.Context, documentID string) (Document, error)
+
+	// UpdateDocument replaces a stored document's URL, text, and metadata in
+	// place and stamps its UpdatedAt
+	UpdateDocument(ctx context.Context, doc Document) error
+
 	// CountDocuments returns the count of documents in the database
 	CountDocuments(ctx context.Context) (int, error)
 
+	// EstimateDocuments returns a fast estimate of the count of documents
+	// from backend statistics instead of an exact count. It may include
+	// documents that were recently deleted, soft-deleted, or not yet flushed.
+	EstimateDocuments(ctx context.Context) (int, error)
+
 	// DeleteDocument deletes a document by ID
 	DeleteDocument(ctx context.Context, documentID string) error
 
-	// DeleteDocuments deletes multiple documents by IDs
-	DeleteDocuments(ctx context.Context, documentIDs []string) error
+	// DeleteDocuments attempts to delete every document by ID and reports
+	// which were deleted and which failed, with why. The error is for a
+	// failure of the whole call, such as ctx ending; the report then covers
+	// the IDs attempted before it.
+	DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error)
+
+	// DeleteExpired deletes documents whose TTL expired at or before the given
+	// time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+
+	// DeleteByFilter deletes every document whose metadata matches the filter
+	// and returns how many were removed. The filter must not be empty.
+	DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error)
+
+	// TruncateCollection deletes every document in a collection, keeping the
+	// collection, its schema, and its index, and returns how many were removed
+	TruncateCollection(ctx context.Context, collectionName string) (int, error)
 
 	// ListCollections lists all collections in the database
 	ListCollections(ctx context.Context) ([]string, error)
@@ -48,6 +80,10 @@ type VectorDatabase interface {
 	// GetCollectionInfo returns information about a collection
 	GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error)
 
+	// CreateCollection creates an additional named collection without changing
+	// the instance's default collection
+	CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error
+
 	// DeleteCollection deletes a collection
 	DeleteCollection(ctx context.Context, collectionName string) error
 
@@ -58,33 +94,161 @@ type VectorDatabase interface {
 // Document represents a document in the vector database
 type Document struct {
 	ID       string                 `json:"id,omitempty"`
-	URL      string                 `json:"url"`
-	Text     string                 `json:"text"`
-	Metadata map[string]interface{} `json:"metadata"`
+	URL      string                 `json:"url,omitempty"`
+	Text     string                 `json:"text,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 	Vector   []float64              `json:"vector,omitempty"`
+
+	// EmbeddingText is the preprocessed text embedded in place of Text when
+	// mcp.preprocess is configured. It is stored alongside Text, which keeps
+	// the original so results return it.
+	EmbeddingText string `json:"-"`
+
+	// Vector32 carries the vector at the float32 precision the backends store.
+	// Callers that already hold float32 embeddings set it instead of Vector to
+	// skip a conversion; it takes precedence over Vector when both are set.
+	Vector32 []float32 `json:"-"`
+
+	// Vectors holds additional embeddings keyed by the named vector spaces
+	// configured under mcp.embedding.named_vectors
+	Vectors map[string][]float64 `json:"vectors,omitempty"`
+
+	// CreatedAt and UpdatedAt are stamped on write and update. A CreatedAt
+	// set by the caller is kept, so imports can preserve historical dates.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// TextForEmbedding returns the text the backend embeds: the preprocessed
+// text when there is one, the original text otherwise
+func (d Document) TextForEmbedding() string {
+	if d.EmbeddingText != "" {
+		return d.EmbeddingText
+	}
+	return d.Text
+}
+
+// VectorDimension returns the length of the document's vector, if any
+func (d Document) VectorDimension() int {
+	if len(d.Vector32) > 0 {
+		return len(d.Vector32)
+	}
+	return len(d.Vector)
+}
+
+// Float32Vector returns the vector at backend precision, converting Vector
+// only when no float32 vector was provided
+func (d Document) Float32Vector() []float32 {
+	if len(d.Vector32) > 0 {
+		return d.Vector32
+	}
+	return ToFloat32(d.Vector)
+}
+
+// MarshalJSON encodes a float32-only vector under "vector" so responses look
+// the same whichever precision the document was written with
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	encoded := document(d)
+	if len(encoded.Vector) == 0 && len(encoded.Vector32) > 0 {
+		encoded.Vector = make([]float64, len(encoded.Vector32))
+		for i, x := range encoded.Vector32 {
+			encoded.Vector[i] = float64(x)
+		}
+	}
+	return json.Marshal(encoded)
 }
 
 // SearchResult represents a search result
 type SearchResult struct {
 	Document Document `json:"document"`
 	Score    float64  `json:"score"`
+	// GroupSize counts the candidates collapsed into this result by dedupe_by
+	GroupSize int `json:"group_size,omitempty"`
+}
+
+// CollectionOptions configures a collection created with CreateCollection.
+// Zero values fall back to the configured embedding settings.
+type CollectionOptions struct {
+	Embedding  string `json:"embedding,omitempty"`
+	VectorSize int    `json:"vector_size,omitempty"`
+	Metric     string `json:"metric,omitempty"`
+}
+
+// WithDefaults fills unset options from the configuration
+func (o CollectionOptions) WithDefaults(cfg *config.Config) CollectionOptions {
+	if o.Embedding == "" {
+		o.Embedding = "default"
+	}
+	if o.VectorSize == 0 {
+		o.VectorSize = cfg.MCP.Embedding.VectorSize
+	}
+	if o.Metric == "" {
+		o.Metric = cfg.MCP.Embedding.EffectiveMetric()
+	}
+	return o
+}
+
+// DeleteReport is the outcome of a batch delete
+type DeleteReport struct {
+	Deleted []string        `json:"deleted"`
+	Failed  []DeleteFailure `json:"failed,omitempty"`
+}
+
+// DeleteFailure records why one document of a batch delete was not deleted
+type DeleteFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
 }
 
 // WriteStats represents statistics from a write operation
 type WriteStats struct {
 	DocumentsWritten int      `json:"documents_written"`
 	ProcessingTime   string   `json:"processing_time"`
+	Batches          int      `json:"batches,omitempty"`
 	Errors           []string `json:"errors,omitempty"`
+	// Duplicates counts documents not written because their text was already
+	// stored, under mcp.content_dedup
+	Duplicates int `json:"duplicates,omitempty"`
 }
 
-// CreateVectorDatabase creates a new vector database instance
+// CreateVectorDatabase creates a new vector database instance, wrapped in a
+// circuit breaker when one is enabled and in a concurrency limit when one is set.
+// The limit is outermost so calls it rejects never count as backend failures.
 func CreateVectorDatabase(dbType, collectionName string, cfg *config.Config) (VectorDatabase, error) {
+	var db VectorDatabase
+	var err error
+
 	switch dbType {
 	case "milvus":
-		return NewMilvusDatabase(collectionName, cfg)
+		db, err = NewMilvusDatabase(collectionName, cfg)
 	case "weaviate":
-		return NewWeaviateDatabase(collectionName, cfg)
+		db, err = NewWeaviateDatabase(collectionName, cfg)
+	case "mock":
+		if !cfg.MockAllowed() {
+			return nil, fmt.Errorf("the mock vector database is disabled; set mcp.vector_db.allow_mock to enable it")
+		}
+		db, err = NewMockDatabase(collectionName, cfg)
 	default:
 		return nil, fmt.Errorf("unsupported vector database type: %s", dbType)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MCP.CircuitBreaker.Enabled {
+		db = NewCircuitBreakerDatabase(db, cfg.MCP.CircuitBreaker)
+	}
+	if cfg.MCP.Concurrency.MaxConcurrentOperations > 0 {
+		db = NewConcurrencyLimitedDatabase(db, cfg.MCP.Concurrency)
+	}
+	return db, nil
+}
+
+// Unwrap returns the database wrapped by db, or nil when db is not a wrapper
+func Unwrap(db VectorDatabase) VectorDatabase {
+	if wrapper, ok := db.(interface{ Unwrap() VectorDatabase }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
 }
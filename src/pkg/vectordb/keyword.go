@@ -0,0 +1,93 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// keywordScanPageSize is how many documents KeywordSearch reads per page
+const keywordScanPageSize = 500
+
+// keywordTerms splits text into lowercase terms on anything that is not a letter or digit
+func keywordTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// KeywordSearch answers a query without embedding it by scanning up to
+// scanLimit documents and scoring each by the share of distinct query terms
+// its text contains. Ties are broken by how often the terms occur. The
+// metadata filter carried by ctx applies. It is a fallback for when the
+// embedding provider is down, and is not a substitute for a text index.
+func KeywordSearch(ctx context.Context, db VectorDatabase, query string, limit, scanLimit int) ([]SearchResult, error) {
+	terms := make(map[string]bool)
+	for _, term := range keywordTerms(query) {
+		terms[term] = true
+	}
+	if len(terms) == 0 || limit <= 0 {
+		return []SearchResult{}, nil
+	}
+
+	filter := MetadataFilterFromContext(ctx)
+	type scored struct {
+		result      SearchResult
+		occurrences int
+	}
+	var matches []scored
+	for offset := 0; scanLimit <= 0 || offset < scanLimit; {
+		pageSize := keywordScanPageSize
+		if scanLimit > 0 {
+			pageSize = min(pageSize, scanLimit-offset)
+		}
+		docs, err := db.ListDocuments(ctx, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan documents: %w", err)
+		}
+
+		for _, doc := range docs {
+			if len(filter) > 0 && !filter.Matches(doc) {
+				continue
+			}
+			matched := make(map[string]bool)
+			occurrences := 0
+			for _, term := range keywordTerms(doc.Text) {
+				if terms[term] {
+					matched[term] = true
+					occurrences++
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			matches = append(matches, scored{
+				result:      SearchResult{Document: doc, Score: float64(len(matched)) / float64(len(terms))},
+				occurrences: occurrences,
+			})
+		}
+
+		offset += len(docs)
+		if len(docs) < pageSize {
+			break
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].result.Score != matches[j].result.Score {
+			return matches[i].result.Score > matches[j].result.Score
+		}
+		return matches[i].occurrences > matches[j].occurrences
+	})
+
+	results := make([]SearchResult, 0, min(limit, len(matches)))
+	for _, match := range matches {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, match.result)
+	}
+	return results, nil
+}
@@ -0,0 +1,264 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// ConcurrencyLimitedDatabase wraps a VectorDatabase with a semaphore capping the
+// operations in flight against the backend. A call beyond the cap waits up to
+// wait_timeout for a slot, then fails with ErrBackendUnavailable instead of
+// piling onto a saturated backend. A zero wait_timeout fails fast.
+type ConcurrencyLimitedDatabase struct {
+	VectorDatabase
+
+	slots       chan struct{}
+	waitTimeout time.Duration
+	waiting     atomic.Int64
+	rejected    atomic.Int64
+}
+
+// NewConcurrencyLimitedDatabase wraps db with a semaphore configured by cfg
+func NewConcurrencyLimitedDatabase(db VectorDatabase, cfg config.ConcurrencyConfig) *ConcurrencyLimitedDatabase {
+	return &ConcurrencyLimitedDatabase{
+		VectorDatabase: db,
+		slots:          make(chan struct{}, cfg.MaxConcurrentOperations),
+		waitTimeout:    cfg.WaitTimeout,
+	}
+}
+
+// Unwrap returns the wrapped database
+func (l *ConcurrencyLimitedDatabase) Unwrap() VectorDatabase {
+	return l.VectorDatabase
+}
+
+// Stats returns the semaphore state for health reporting
+func (l *ConcurrencyLimitedDatabase) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"in_flight":                 len(l.slots),
+		"waiting":                   l.waiting.Load(),
+		"max_concurrent_operations": cap(l.slots),
+		"rejected":                  l.rejected.Load(),
+	}
+}
+
+// acquire takes a slot, waiting up to the wait timeout or until ctx is done
+func (l *ConcurrencyLimitedDatabase) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.waitTimeout > 0 {
+		l.waiting.Add(1)
+		defer l.waiting.Add(-1)
+
+		timer := time.NewTimer(l.waitTimeout)
+		defer timer.Stop()
+
+		select {
+		case l.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	l.rejected.Add(1)
+	return fmt.Errorf("%w: %s has %d operations in flight", ErrBackendUnavailable, l.Type(), cap(l.slots))
+}
+
+// release frees a slot taken by acquire
+func (l *ConcurrencyLimitedDatabase) release() {
+	<-l.slots
+}
+
+// limit runs fn while holding a slot
+func (l *ConcurrencyLimitedDatabase) limit(ctx context.Context, fn func() error) error {
+	if err := l.acquire(ctx); err != nil {
+		return err
+	}
+	defer l.release()
+	return fn()
+}
+
+// Setup initializes the database within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) Setup(ctx context.Context, embedding string) error {
+	return l.limit(ctx, func() error {
+		return l.VectorDatabase.Setup(ctx, embedding)
+	})
+}
+
+// WriteDocument writes a single document within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) WriteDocument(ctx context.Context, doc Document) (WriteStats, error) {
+	var stats WriteStats
+	err := l.limit(ctx, func() (err error) {
+		stats, err = l.VectorDatabase.WriteDocument(ctx, doc)
+		return err
+	})
+	return stats, err
+}
+
+// WriteDocuments writes multiple documents within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error) {
+	var stats WriteStats
+	err := l.limit(ctx, func() (err error) {
+		stats, err = l.VectorDatabase.WriteDocuments(ctx, docs)
+		return err
+	})
+	return stats, err
+}
+
+// Query performs a natural language query within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) Query(ctx context.Context, query string, limit int, collectionName string) (interface{}, error) {
+	var result interface{}
+	err := l.limit(ctx, func() (err error) {
+		result, err = l.VectorDatabase.Query(ctx, query, limit, collectionName)
+		return err
+	})
+	return result, err
+}
+
+// Search performs a vector similarity search within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) Search(ctx context.Context, query string, limit int, collectionName string) ([]SearchResult, error) {
+	var results []SearchResult
+	err := l.limit(ctx, func() (err error) {
+		results, err = l.VectorDatabase.Search(ctx, query, limit, collectionName)
+		return err
+	})
+	return results, err
+}
+
+// ListDocuments lists documents within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) ListDocuments(ctx context.Context, limit, offset int) ([]Document, error) {
+	var docs []Document
+	err := l.limit(ctx, func() (err error) {
+		docs, err = l.VectorDatabase.ListDocuments(ctx, limit, offset)
+		return err
+	})
+	return docs, err
+}
+
+// GetDocument fetches a document within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) GetDocument(ctx context.Context, documentID string) (Document, error) {
+	var doc Document
+	err := l.limit(ctx, func() (err error) {
+		doc, err = l.VectorDatabase.GetDocument(ctx, documentID)
+		return err
+	})
+	return doc, err
+}
+
+// UpdateDocument updates a document within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) UpdateDocument(ctx context.Context, doc Document) error {
+	return l.limit(ctx, func() error {
+		return l.VectorDatabase.UpdateDocument(ctx, doc)
+	})
+}
+
+// EstimateDocuments estimates the document count within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) EstimateDocuments(ctx context.Context) (int, error) {
+	var count int
+	err := l.limit(ctx, func() (err error) {
+		count, err = l.VectorDatabase.EstimateDocuments(ctx)
+		return err
+	})
+	return count, err
+}
+
+// CountDocuments counts documents within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) CountDocuments(ctx context.Context) (int, error) {
+	var count int
+	err := l.limit(ctx, func() (err error) {
+		count, err = l.VectorDatabase.CountDocuments(ctx)
+		return err
+	})
+	return count, err
+}
+
+// DeleteDocument deletes a document within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) DeleteDocument(ctx context.Context, documentID string) error {
+	return l.limit(ctx, func() error {
+		return l.VectorDatabase.DeleteDocument(ctx, documentID)
+	})
+}
+
+// DeleteDocuments deletes multiple documents within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error) {
+	var report DeleteReport
+	err := l.limit(ctx, func() (err error) {
+		report, err = l.VectorDatabase.DeleteDocuments(ctx, documentIDs)
+		return err
+	})
+	return report, err
+}
+
+// DeleteExpired deletes expired documents within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	var deleted int
+	err := l.limit(ctx, func() (err error) {
+		deleted, err = l.VectorDatabase.DeleteExpired(ctx, before)
+		return err
+	})
+	return deleted, err
+}
+
+// DeleteByFilter deletes documents matching a metadata filter within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error) {
+	var deleted int
+	err := l.limit(ctx, func() (err error) {
+		deleted, err = l.VectorDatabase.DeleteByFilter(ctx, filter)
+		return err
+	})
+	return deleted, err
+}
+
+// TruncateCollection empties a collection within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) TruncateCollection(ctx context.Context, collectionName string) (int, error) {
+	var deleted int
+	err := l.limit(ctx, func() (err error) {
+		deleted, err = l.VectorDatabase.TruncateCollection(ctx, collectionName)
+		return err
+	})
+	return deleted, err
+}
+
+// ListCollections lists collections within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) ListCollections(ctx context.Context) ([]string, error) {
+	var collections []string
+	err := l.limit(ctx, func() (err error) {
+		collections, err = l.VectorDatabase.ListCollections(ctx)
+		return err
+	})
+	return collections, err
+}
+
+// GetCollectionInfo returns collection information within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	err := l.limit(ctx, func() (err error) {
+		info, err = l.VectorDatabase.GetCollectionInfo(ctx, collectionName)
+		return err
+	})
+	return info, err
+}
+
+// CreateCollection creates a collection within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error {
+	return l.limit(ctx, func() error {
+		return l.VectorDatabase.CreateCollection(ctx, collectionName, options)
+	})
+}
+
+// DeleteCollection deletes a collection within the concurrency limit
+func (l *ConcurrencyLimitedDatabase) DeleteCollection(ctx context.Context, collectionName string) error {
+	return l.limit(ctx, func() error {
+		return l.VectorDatabase.DeleteCollection(ctx, collectionName)
+	})
+}
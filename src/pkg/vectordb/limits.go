@@ -0,0 +1,101 @@
+package vectordb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Limits bounds the documents a backend accepts, so an oversized document is
+// rejected with a precise error before anything is sent. Sizes are in bytes
+// and a zero limit is not checked.
+type Limits struct {
+	Backend           string `json:"backend"`
+	MaxTextBytes      int    `json:"max_text_bytes,omitempty"`
+	MaxURLBytes       int    `json:"max_url_bytes,omitempty"`
+	MaxMetadataBytes  int    `json:"max_metadata_bytes,omitempty"`
+	MaxMetadataFields int    `json:"max_metadata_fields,omitempty"`
+}
+
+// defaultLimits holds each backend's built-in limits. Milvus stores url and
+// text as VARCHAR, capped at 65,535 bytes, and metadata as a JSON field capped
+// at 64 KiB. Weaviate stores text without a cap, but each metadata key becomes
+// a nested property of the class schema and a url is indexed whole.
+var defaultLimits = map[string]Limits{
+	"milvus": {
+		MaxTextBytes:     65535,
+		MaxURLBytes:      65535,
+		MaxMetadataBytes: 65536,
+	},
+	"weaviate": {
+		MaxURLBytes:       2048,
+		MaxMetadataFields: 100,
+	},
+}
+
+// BackendLimits returns the document limits of a backend type with the
+// overrides configured under mcp.vector_db.<backend>.limits applied. The mock
+// backend has no limits of its own.
+func BackendLimits(dbType string, cfg *config.Config) Limits {
+	limits := defaultLimits[dbType]
+	limits.Backend = dbType
+
+	var overrides config.DocumentLimitsConfig
+	switch dbType {
+	case "milvus":
+		overrides = cfg.MCP.VectorDB.Milvus.Limits
+	case "weaviate":
+		overrides = cfg.MCP.VectorDB.Weaviate.Limits
+	}
+
+	limits.MaxTextBytes = overrideLimit(limits.MaxTextBytes, overrides.MaxTextBytes)
+	limits.MaxURLBytes = overrideLimit(limits.MaxURLBytes, overrides.MaxURLBytes)
+	limits.MaxMetadataBytes = overrideLimit(limits.MaxMetadataBytes, overrides.MaxMetadataBytes)
+	limits.MaxMetadataFields = overrideLimit(limits.MaxMetadataFields, overrides.MaxMetadataFields)
+	return limits
+}
+
+// overrideLimit applies a configured override: zero keeps the built-in limit
+// and a negative value removes it
+func overrideLimit(builtin, override int) int {
+	switch {
+	case override > 0:
+		return override
+	case override < 0:
+		return 0
+	default:
+		return builtin
+	}
+}
+
+// Check reports the first limit a document exceeds
+func (l Limits) Check(doc Document) error {
+	if l.MaxURLBytes > 0 && len(doc.URL) > l.MaxURLBytes {
+		return l.exceeded("url is %d bytes", len(doc.URL), l.MaxURLBytes)
+	}
+	if l.MaxTextBytes > 0 && len(doc.Text) > l.MaxTextBytes {
+		return l.exceeded("text is %d bytes", len(doc.Text), l.MaxTextBytes)
+	}
+	if l.MaxTextBytes > 0 && len(doc.EmbeddingText) > l.MaxTextBytes {
+		return l.exceeded("preprocessed text is %d bytes", len(doc.EmbeddingText), l.MaxTextBytes)
+	}
+	if l.MaxMetadataFields > 0 && len(doc.Metadata) > l.MaxMetadataFields {
+		return l.exceeded("metadata has %d fields", len(doc.Metadata), l.MaxMetadataFields)
+	}
+	if l.MaxMetadataBytes > 0 && len(doc.Metadata) > 0 {
+		encoded, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		if len(encoded) > l.MaxMetadataBytes {
+			return l.exceeded("metadata is %d bytes as JSON", len(encoded), l.MaxMetadataBytes)
+		}
+	}
+	return nil
+}
+
+// exceeded formats a limit violation naming the backend and its limit
+func (l Limits) exceeded(format string, size, limit int) error {
+	return fmt.Errorf("%w: "+format+"; %s allows at most %d", ErrDocumentTooLarge, size, l.Backend, limit)
+}
@@ -0,0 +1,174 @@
+package vectordb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Data types a metadata field can be declared with in a metadata schema. A
+// field declared as a map of its own fields is an object.
+const (
+	MetadataTypeText    = "text"
+	MetadataTypeNumber  = "number"
+	MetadataTypeInt     = "int"
+	MetadataTypeBoolean = "boolean"
+	MetadataTypeDate    = "date"
+)
+
+// weaviateNestedProperties translates a metadata schema into the nested
+// property definitions of Weaviate's object data type. Each schema key maps to
+// a data type or, for a nested object, to a schema of its own fields.
+// Properties are sorted by name so the class definition is stable.
+func weaviateNestedProperties(schema map[string]interface{}, path string) ([]map[string]interface{}, error) {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		field := path + name
+		switch declared := schema[name].(type) {
+		case string:
+			switch declared {
+			case MetadataTypeText, MetadataTypeNumber, MetadataTypeInt, MetadataTypeBoolean, MetadataTypeDate:
+			default:
+				return nil, fmt.Errorf("metadata field '%s' has unsupported type '%s'; use text, number, int, boolean, date, or a map of nested fields", field, declared)
+			}
+			properties = append(properties, map[string]interface{}{
+				"name":     name,
+				"dataType": []string{declared},
+			})
+		case map[string]interface{}:
+			if len(declared) == 0 {
+				return nil, fmt.Errorf("metadata field '%s' is an object with no fields", field)
+			}
+			nested, err := weaviateNestedProperties(declared, field+".")
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, map[string]interface{}{
+				"name":             name,
+				"dataType":         []string{"object"},
+				"nestedProperties": nested,
+			})
+		default:
+			return nil, fmt.Errorf("metadata field '%s' must be declared with a type name or a map of nested fields", field)
+		}
+	}
+	return properties, nil
+}
+
+// checkMetadataSchema verifies that the metadata fields declared in schema
+// hold values of their declared types. Fields the schema does not declare are
+// not checked, and a declared field may be absent.
+func checkMetadataSchema(schema, metadata map[string]interface{}, path string) error {
+	for name, declared := range schema {
+		value, exists := metadata[name]
+		if !exists || value == nil {
+			continue
+		}
+
+		field := path + name
+		if nested, ok := declared.(map[string]interface{}); ok {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%w: '%s' must be an object", ErrMetadataSchema, field)
+			}
+			if err := checkMetadataSchema(nested, object, field+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !metadataValueHasType(value, declared.(string)) {
+			return fmt.Errorf("%w: '%s' must be of type %s, got %T", ErrMetadataSchema, field, declared, value)
+		}
+	}
+	return nil
+}
+
+// metadataValueHasType reports whether a metadata value can be stored as the declared type
+func metadataValueHasType(value interface{}, dataType string) bool {
+	switch dataType {
+	case MetadataTypeText:
+		_, ok := value.(string)
+		return ok
+	case MetadataTypeNumber:
+		return isNumber(value)
+	case MetadataTypeInt:
+		return isNumber(value) && toFloat(value) == float64(int64(toFloat(value)))
+	case MetadataTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case MetadataTypeDate:
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339, v)
+			return err == nil
+		}
+	}
+	return false
+}
+
+// FilterIssue is a problem found checking a filter condition against a metadata schema
+type FilterIssue struct {
+	Field   string `json:"field,omitempty"`
+	Problem string `json:"problem"`
+}
+
+// CheckFilterSchema checks each condition of a filter against a metadata
+// schema: its field must be declared, as a field rather than an object, and
+// the values compared with it must be of the declared type. Issues are in
+// field order.
+func CheckFilterSchema(filter MetadataFilter, schema map[string]interface{}) []FilterIssue {
+	var issues []FilterIssue
+	for _, key := range filter.keys() {
+		declared, ok := schemaField(schema, key)
+		if !ok {
+			issues = append(issues, FilterIssue{Field: key, Problem: "unknown field: not declared in the metadata schema"})
+			continue
+		}
+		dataType, ok := declared.(string)
+		if !ok {
+			issues = append(issues, FilterIssue{Field: key, Problem: fmt.Sprintf("'%s' is an object; filter on one of its fields, e.g. %s.<field>", key, key)})
+			continue
+		}
+
+		values := []interface{}{filter[key]}
+		if condition, ok := filter[key].(ArrayCondition); ok {
+			values = condition.Values
+		}
+		for _, value := range values {
+			if !metadataValueHasType(value, dataType) {
+				issues = append(issues, FilterIssue{Field: key, Problem: fmt.Sprintf("type mismatch: field is declared as %s, got %s %v", dataType, filterValueKind(value), value)})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// schemaField looks up the declaration of a dotted field path such as
+// author.name in a metadata schema
+func schemaField(schema map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		declared, ok := schema[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return declared, true
+		}
+		if schema, ok = declared.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
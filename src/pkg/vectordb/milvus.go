@@ -2,7 +2,13 @@ package vectordb
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
@@ -14,6 +20,7 @@ type MilvusDatabase struct {
 	config         *config.Config
 	logger         *zap.Logger
 	collectionName string
+	tlsConfig      *tls.Config
 	client         MilvusClient
 }
 
@@ -25,29 +32,96 @@ type MilvusClient interface {
 	Search(ctx context.Context, collectionName string, query string, limit int) ([]SearchResult, error)
 	Query(ctx context.Context, collectionName string, query string, limit int) (interface{}, error)
 	ListDocuments(ctx context.Context, collectionName string, limit, offset int) ([]Document, error)
+	GetDocument(ctx context.Context, collectionName string, documentID string) (Document, error)
+	UpdateDocument(ctx context.Context, collectionName string, doc Document) error
 	CountDocuments(ctx context.Context, collectionName string) (int, error)
+	// EstimateDocuments returns the row count from the collection statistics
+	EstimateDocuments(ctx context.Context, collectionName string) (int, error)
 	DeleteDocument(ctx context.Context, collectionName string, documentID string) error
-	DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) error
+	DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) (DeleteReport, error)
+	DeleteExpired(ctx context.Context, collectionName string, before time.Time) (int, error)
+	DeleteByFilter(ctx context.Context, collectionName string, filter MetadataFilter) (int, error)
+	Truncate(ctx context.Context, collectionName string) (int, error)
 	ListCollections(ctx context.Context) ([]string, error)
 	GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error)
+	// CollectionState reports the index build progress and load state of a collection
+	CollectionState(ctx context.Context, collectionName string) (MilvusCollectionState, error)
 	DeleteCollection(ctx context.Context, collectionName string) error
 	Close() error
 }
 
+// MilvusCollectionState is the index build progress and load state of a
+// Milvus collection
+type MilvusCollectionState struct {
+	IndexedRows int  `json:"indexed_rows"`
+	TotalRows   int  `json:"total_rows"`
+	Loaded      bool `json:"loaded"`
+}
+
+// Ready reports whether the collection can be queried: every row is indexed
+// and the collection is loaded
+func (s MilvusCollectionState) Ready() bool {
+	return s.Loaded && s.IndexedRows >= s.TotalRows
+}
+
 // NewMilvusDatabase creates a new Milvus database instance
 func NewMilvusDatabase(collectionName string, cfg *config.Config) (*MilvusDatabase, error) {
+	return NewMilvusDatabaseWithClient(collectionName, cfg, NewMockMilvusClient()) // Use mock for now
+}
+
+// NewMilvusDatabaseWithClient creates a Milvus database instance backed by the given client
+func NewMilvusDatabaseWithClient(collectionName string, cfg *config.Config, client MilvusClient) (*MilvusDatabase, error) {
 	logger, _ := zap.NewProduction()
 
+	tlsConfig, err := newMilvusTLSConfig(cfg.MCP.VectorDB.Milvus.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Milvus TLS configuration: %w", err)
+	}
+
 	db := &MilvusDatabase{
 		config:         cfg,
 		logger:         logger,
 		collectionName: collectionName,
-		client:         NewMockMilvusClient(), // Use mock for now
+		tlsConfig:      tlsConfig,
+		client:         client,
 	}
 
 	return db, nil
 }
 
+// newMilvusTLSConfig builds the TLS settings for the Milvus gRPC connection, or nil when TLS is disabled
+func newMilvusTLSConfig(cfg config.MilvusTLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // #nosec G402 -- explicit operator opt-in
+	}
+
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// TLSConfig returns the TLS settings used for the Milvus connection, or nil when TLS is disabled
+func (m *MilvusDatabase) TLSConfig() *tls.Config {
+	return m.tlsConfig
+}
+
 // Type returns the database type
 func (m *MilvusDatabase) Type() string {
 	return "milvus"
@@ -60,51 +134,189 @@ func (m *MilvusDatabase) CollectionName() string {
 
 // Setup initializes the database and creates collections
 func (m *MilvusDatabase) Setup(ctx context.Context, embedding string) error {
-	if err := m.client.Connect(ctx); err != nil {
-		return fmt.Errorf("failed to connect to Milvus: %w", err)
+	if err := ValidateCollectionName(m.Type(), m.collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(m.config, CollectionOptions{Embedding: embedding}); err != nil {
+		return err
 	}
 
-	// Create collection schema
-	schema := map[string]interface{}{
-		"name": m.collectionName,
-		"fields": []map[string]interface{}{
-			{
-				"name":    "id",
-				"type":    "string",
-				"primary": true,
-			},
-			{
-				"name": "url",
-				"type": "string",
-			},
-			{
-				"name": "text",
-				"type": "string",
-			},
-			{
-				"name": "metadata",
-				"type": "json",
-			},
-			{
-				"name":      "vector",
-				"type":      "float_vector",
-				"dimension": m.config.MCP.Embedding.VectorSize,
-			},
-		},
-		"embedding": embedding,
+	if err := m.client.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Milvus: %w", err)
 	}
 
+	schema := m.collectionSchema(m.collectionName, CollectionOptions{Embedding: embedding})
 	if err := m.client.CreateCollection(ctx, m.collectionName, schema); err != nil {
 		return fmt.Errorf("failed to create collection: %w", err)
 	}
+	if err := m.waitUntilReady(ctx, m.collectionName); err != nil {
+		return err
+	}
 
 	m.logger.Info("Set up Milvus collection",
 		zap.String("collection", m.collectionName),
-		zap.String("embedding", embedding))
+		zap.String("embedding", embedding),
+		zap.Any("index", schema["index"]))
 
 	return nil
 }
 
+// CreateCollection creates an additional collection with the document schema
+func (m *MilvusDatabase) CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error {
+	if err := ValidateCollectionName(m.Type(), collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(m.config, options); err != nil {
+		return err
+	}
+
+	if err := m.client.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Milvus: %w", err)
+	}
+
+	collections, err := m.client.ListCollections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collections in Milvus: %w", err)
+	}
+	if slices.Contains(collections, collectionName) {
+		return fmt.Errorf("%w: '%s'", ErrCollectionExists, collectionName)
+	}
+
+	options = options.WithDefaults(m.config)
+	if err := m.client.CreateCollection(ctx, collectionName, m.collectionSchema(collectionName, options)); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	if err := m.waitUntilReady(ctx, collectionName); err != nil {
+		return err
+	}
+
+	m.logger.Info("Created Milvus collection",
+		zap.String("collection", collectionName),
+		zap.String("embedding", options.Embedding),
+		zap.Int("dimension", options.VectorSize))
+
+	return nil
+}
+
+// waitUntilReady polls a new collection until its index is built and it is
+// loaded, so queries issued right after setup do not fail, for at most
+// mcp.vector_db.milvus.readiness.timeout or until ctx's deadline, whichever
+// comes first. It fails with ErrCollectionNotReady when the collection is
+// still not ready then.
+func (m *MilvusDatabase) waitUntilReady(ctx context.Context, collectionName string) error {
+	readiness := m.config.MCP.VectorDB.Milvus.Readiness
+	if readiness.Timeout <= 0 {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, readiness.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var state MilvusCollectionState
+	for {
+		current, err := m.client.CollectionState(waitCtx, collectionName)
+		if err != nil && waitCtx.Err() == nil {
+			return fmt.Errorf("failed to check readiness of collection '%s': %w", collectionName, err)
+		}
+		if err == nil {
+			state = current
+			if state.Ready() {
+				m.logger.Debug("Milvus collection ready",
+					zap.String("collection", collectionName),
+					zap.Duration("waited", time.Since(start)))
+				return nil
+			}
+		}
+
+		timer := time.NewTimer(readiness.Interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%w: collection '%s' after %s: %d of %d rows indexed, loaded: %t",
+				ErrCollectionNotReady, collectionName, time.Since(start).Round(time.Millisecond),
+				state.IndexedRows, state.TotalRows, state.Loaded)
+		case <-timer.C:
+		}
+	}
+}
+
+// collectionSchema returns the Milvus schema for a document collection
+func (m *MilvusDatabase) collectionSchema(collectionName string, options CollectionOptions) map[string]interface{} {
+	options = options.WithDefaults(m.config)
+
+	fields := []map[string]interface{}{
+		{
+			"name":    "id",
+			"type":    "string",
+			"primary": true,
+		},
+		{
+			"name": "url",
+			"type": "string",
+		},
+		{
+			"name": "text",
+			"type": "string",
+		},
+		{
+			"name": "metadata",
+			"type": "json",
+		},
+		{
+			"name":      "vector",
+			"type":      "float_vector",
+			"dimension": options.VectorSize,
+		},
+	}
+
+	// Each named vector space gets its own vector field
+	fields = append(fields, namedVectorFields(m.config)...)
+
+	// Timestamps are scalar Unix-second fields so they can be filtered on
+	fields = append(fields,
+		map[string]interface{}{"name": FieldCreatedAt, "type": "int64"},
+		map[string]interface{}{"name": FieldUpdatedAt, "type": "int64"})
+
+	// The preprocessed text that was embedded, when it differs from text
+	fields = append(fields, map[string]interface{}{"name": "embedding_text", "type": "string"})
+
+	schema := map[string]interface{}{
+		"name":        collectionName,
+		"fields":      fields,
+		"embedding":   options.Embedding,
+		"metric_type": options.Metric,
+		"index":       m.vectorIndex(collectionName, options.Metric),
+	}
+	recordEmbeddingModel(schema, m.config)
+	return schema
+}
+
+// vectorIndex returns the index built on the vector field, with the
+// parameters configured for the collection under mcp.vector_db.milvus.index
+func (m *MilvusDatabase) vectorIndex(collectionName, metric string) map[string]interface{} {
+	index := m.config.MCP.VectorDB.Milvus.Index.ForCollection(collectionName)
+
+	params := map[string]interface{}{}
+	switch index.Type {
+	case config.MilvusIndexIVFFlat, config.MilvusIndexIVFSQ8:
+		params["nlist"] = index.NList
+	case config.MilvusIndexHNSW:
+		params["M"] = index.M
+		params["efConstruction"] = index.EfConstruction
+	}
+
+	return map[string]interface{}{
+		"field_name":  "vector",
+		"index_type":  index.Type,
+		"metric_type": strings.ToUpper(metric),
+		"params":      params,
+	}
+}
+
 // WriteDocument writes a single document to the database
 func (m *MilvusDatabase) WriteDocument(ctx context.Context, doc Document) (WriteStats, error) {
 	start := time.Now()
@@ -118,12 +330,44 @@ func (m *MilvusDatabase) WriteDocument(ctx context.Context, doc Document) (Write
 	return stats, nil
 }
 
-// WriteDocuments writes multiple documents to the database
+// WriteDocuments writes multiple documents to the database, splitting them into
+// sub-batches that stay under the configured insert size limit
 func (m *MilvusDatabase) WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error) {
 	start := time.Now()
 
-	if err := m.client.Insert(ctx, m.collectionName, docs); err != nil {
-		return WriteStats{}, fmt.Errorf("failed to insert documents: %w", err)
+	docs, err := prepareDocuments(m.config, BackendLimits(m.Type(), m.config), docs)
+	if err != nil {
+		return WriteStats{}, err
+	}
+	batchSize := insertBatchSize(docs, m.config.MCP.VectorDB.Milvus.MaxInsertBytes)
+
+	written := 0
+	batches := 0
+	for written < len(docs) {
+		end := written + batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+
+		err := m.client.Insert(ctx, m.collectionName, docs[written:end])
+		if err != nil && isMessageTooLarge(err) && end-written > 1 {
+			// The size estimate was too optimistic; retry with smaller batches
+			batchSize = (end - written) / 2
+			m.logger.Warn("Milvus insert too large, retrying with a smaller batch",
+				zap.String("collection", m.collectionName),
+				zap.Int("batch_size", batchSize))
+			continue
+		}
+		if err != nil {
+			return WriteStats{
+				DocumentsWritten: written,
+				ProcessingTime:   time.Since(start).String(),
+				Batches:          batches,
+			}, fmt.Errorf("failed to insert documents after %d written: %w", written, err)
+		}
+
+		written = end
+		batches++
 	}
 
 	processingTime := time.Since(start)
@@ -131,11 +375,13 @@ func (m *MilvusDatabase) WriteDocuments(ctx context.Context, docs []Document) (W
 	m.logger.Info("Wrote documents to Milvus",
 		zap.String("collection", m.collectionName),
 		zap.Int("count", len(docs)),
+		zap.Int("batches", batches),
 		zap.Duration("processing_time", processingTime))
 
 	return WriteStats{
 		DocumentsWritten: len(docs),
 		ProcessingTime:   processingTime.String(),
+		Batches:          batches,
 	}, nil
 }
 
@@ -145,6 +391,7 @@ func (m *MilvusDatabase) Query(ctx context.Context, query string, limit int, col
 		collectionName = m.collectionName
 	}
 
+	ctx, level := m.readConsistency(ctx)
 	result, err := m.client.Query(ctx, collectionName, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query Milvus: %w", err)
@@ -153,6 +400,7 @@ func (m *MilvusDatabase) Query(ctx context.Context, query string, limit int, col
 	m.logger.Info("Executed query on Milvus",
 		zap.String("collection", collectionName),
 		zap.String("query", query),
+		zap.String("consistency_level", level),
 		zap.Int("limit", limit))
 
 	return result, nil
@@ -164,7 +412,19 @@ func (m *MilvusDatabase) Search(ctx context.Context, query string, limit int, co
 		collectionName = m.collectionName
 	}
 
-	results, err := m.client.Search(ctx, collectionName, query, limit)
+	vectorName := VectorNameFromContext(ctx)
+	if err := validateVectorName(m.config, vectorName); err != nil {
+		return nil, err
+	}
+	queryVector := QueryVectorFromContext(ctx)
+	if err := validateQueryVector(m.config, vectorName, queryVector); err != nil {
+		return nil, err
+	}
+
+	ctx, level := m.readConsistency(ctx)
+	results, strategy, err := filteredSearch(ctx, limit, FilterStrategyPre, func(ctx context.Context, limit int) ([]SearchResult, error) {
+		return m.client.Search(ctx, collectionName, query, limit)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search Milvus: %w", err)
 	}
@@ -172,6 +432,13 @@ func (m *MilvusDatabase) Search(ctx context.Context, query string, limit int, co
 	m.logger.Info("Executed search on Milvus",
 		zap.String("collection", collectionName),
 		zap.String("query", query),
+		zap.String("consistency_level", level),
+		zap.String("vector_name", vectorName),
+		zap.Strings("output_fields", MilvusOutputFields(m.config, FieldsFromContext(ctx))),
+		zap.String("expr", MetadataFilterFromContext(ctx).MilvusExpr()),
+		zap.String("filter_strategy", strategy),
+		zap.Bool("by_vector", queryVector != nil),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
 		zap.Int("limit", limit),
 		zap.Int("results", len(results)))
 
@@ -180,6 +447,7 @@ func (m *MilvusDatabase) Search(ctx context.Context, query string, limit int, co
 
 // ListDocuments lists documents from the database
 func (m *MilvusDatabase) ListDocuments(ctx context.Context, limit, offset int) ([]Document, error) {
+	ctx, level := m.readConsistency(ctx)
 	documents, err := m.client.ListDocuments(ctx, m.collectionName, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list documents from Milvus: %w", err)
@@ -187,6 +455,9 @@ func (m *MilvusDatabase) ListDocuments(ctx context.Context, limit, offset int) (
 
 	m.logger.Info("Listed documents from Milvus",
 		zap.String("collection", m.collectionName),
+		zap.Strings("output_fields", MilvusOutputFields(m.config, FieldsFromContext(ctx))),
+		zap.String("consistency_level", level),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.Int("count", len(documents)))
@@ -194,14 +465,76 @@ func (m *MilvusDatabase) ListDocuments(ctx context.Context, limit, offset int) (
 	return documents, nil
 }
 
+// ReadConsistencyLevel returns the consistency level of a read: the level the
+// call asks for, else mcp.vector_db.milvus.consistency_level, else an empty
+// string for the client default
+func (m *MilvusDatabase) ReadConsistencyLevel(ctx context.Context) string {
+	if level := ConsistencyLevelFromContext(ctx); level != "" {
+		return level
+	}
+	return strings.ToLower(m.config.MCP.VectorDB.Milvus.ConsistencyLevel)
+}
+
+// readConsistency resolves the consistency level of a read and passes it on
+// to the client, returning it by its Milvus name for logging
+func (m *MilvusDatabase) readConsistency(ctx context.Context) (context.Context, string) {
+	level := m.ReadConsistencyLevel(ctx)
+	if level == "" {
+		return ctx, ""
+	}
+	return WithConsistencyLevel(ctx, level), MilvusConsistencyLevel(level)
+}
+
+// GetDocument returns a single document by ID
+func (m *MilvusDatabase) GetDocument(ctx context.Context, documentID string) (Document, error) {
+	doc, err := m.client.GetDocument(ctx, m.collectionName, documentID)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to get document from Milvus: %w", err)
+	}
+
+	return doc, nil
+}
+
+// UpdateDocument replaces a stored document's URL, text, and metadata in place
+func (m *MilvusDatabase) UpdateDocument(ctx context.Context, doc Document) error {
+	doc = touchDocument(doc)
+	if err := m.client.UpdateDocument(ctx, m.collectionName, doc); err != nil {
+		return fmt.Errorf("failed to update document in Milvus: %w", err)
+	}
+
+	m.logger.Info("Updated document in Milvus",
+		zap.String("collection", m.collectionName),
+		zap.String("document_id", doc.ID))
+
+	return nil
+}
+
 // CountDocuments returns the count of documents in the database
 func (m *MilvusDatabase) CountDocuments(ctx context.Context) (int, error) {
+	ctx, level := m.readConsistency(ctx)
 	count, err := m.client.CountDocuments(ctx, m.collectionName)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count documents in Milvus: %w", err)
 	}
 
 	m.logger.Info("Counted documents in Milvus",
+		zap.String("collection", m.collectionName),
+		zap.String("consistency_level", level),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
+		zap.Int("count", count))
+
+	return count, nil
+}
+
+// EstimateDocuments returns the row count from the Milvus collection
+// statistics, which is fast but counts deleted rows until they are compacted
+func (m *MilvusDatabase) EstimateDocuments(ctx context.Context) (int, error) {
+	count, err := m.client.EstimateDocuments(ctx, m.collectionName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get collection statistics from Milvus: %w", err)
+	}
+
+	m.logger.Info("Estimated documents in Milvus",
 		zap.String("collection", m.collectionName),
 		zap.Int("count", count))
 
@@ -221,17 +554,74 @@ func (m *MilvusDatabase) DeleteDocument(ctx context.Context, documentID string)
 	return nil
 }
 
-// DeleteDocuments deletes multiple documents by IDs
-func (m *MilvusDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) error {
-	if err := m.client.DeleteDocuments(ctx, m.collectionName, documentIDs); err != nil {
-		return fmt.Errorf("failed to delete documents from Milvus: %w", err)
+// DeleteDocuments deletes multiple documents by IDs, reporting per-document failures
+func (m *MilvusDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error) {
+	report, err := m.client.DeleteDocuments(ctx, m.collectionName, documentIDs)
+	if err != nil {
+		return report, fmt.Errorf("failed to delete documents from Milvus: %w", err)
 	}
 
 	m.logger.Info("Deleted documents from Milvus",
 		zap.String("collection", m.collectionName),
-		zap.Int("count", len(documentIDs)))
+		zap.Int("count", len(report.Deleted)),
+		zap.Int("failed", len(report.Failed)))
 
-	return nil
+	return report, nil
+}
+
+// DeleteExpired deletes documents whose TTL expired at or before the given time,
+// using the boolean expression `expires_at <= before`
+func (m *MilvusDatabase) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := m.client.DeleteExpired(ctx, m.collectionName, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired documents from Milvus: %w", err)
+	}
+
+	m.logger.Info("Deleted expired documents from Milvus",
+		zap.String("collection", m.collectionName),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// DeleteByFilter deletes documents matching a metadata filter with a single
+// delete using the filter's boolean expression
+func (m *MilvusDatabase) DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error) {
+	if len(filter) == 0 {
+		return 0, fmt.Errorf("%w: refusing to delete without a filter", ErrInvalidFilter)
+	}
+
+	deleted, err := m.client.DeleteByFilter(ctx, m.collectionName, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete documents by filter from Milvus: %w", err)
+	}
+
+	m.logger.Info("Deleted documents by filter from Milvus",
+		zap.String("collection", m.collectionName),
+		zap.String("expr", filter.MilvusExpr()),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// TruncateCollection deletes every entity with a match-all expression, so the
+// collection keeps its schema and index
+func (m *MilvusDatabase) TruncateCollection(ctx context.Context, collectionName string) (int, error) {
+	if collectionName == "" {
+		collectionName = m.collectionName
+	}
+
+	deleted, err := m.client.Truncate(ctx, collectionName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to truncate Milvus collection: %w", err)
+	}
+
+	m.logger.Info("Truncated Milvus collection",
+		zap.String("collection", collectionName),
+		zap.String("expr", `id != ""`),
+		zap.Int("count", deleted))
+
+	return deleted, nil
 }
 
 // ListCollections lists all collections in the database
@@ -2,315 +2,266 @@ package vectordb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
 	"go.uber.org/zap"
 )
 
-// MockMilvusClient implements MilvusClient for testing
-type MockMilvusClient struct {
+// mockStore is the in-memory backend shared by the mock Milvus and Weaviate clients
+type mockStore struct {
+	backend     string
 	collections map[string]map[string]interface{}
+	createdAt   map[string]time.Time
 	documents   map[string][]Document
+	closed      bool
 	mutex       sync.RWMutex
 	logger      *zap.Logger
-}
+	nextID      atomic.Uint64
 
-// NewMockMilvusClient creates a new mock Milvus client
-func NewMockMilvusClient() *MockMilvusClient {
-	logger, _ := zap.NewProduction()
-	return &MockMilvusClient{
-		collections: make(map[string]map[string]interface{}),
-		documents:   make(map[string][]Document),
-		logger:      logger,
-	}
-}
+	// IDGen generates the ID of each inserted document that has none. It
+	// defaults to a counter yielding doc_1, doc_2, ...; tests may replace it
+	// before use to control IDs.
+	IDGen func() string
 
-// Connect simulates connecting to Milvus
-func (m *MockMilvusClient) Connect(ctx context.Context) error {
-	m.logger.Info("Mock Milvus client connected")
-	return nil
-}
+	// Clock returns the current time used for collection timestamps; tests may
+	// replace it before use
+	Clock func() time.Time
 
-// CreateCollection simulates creating a collection
-func (m *MockMilvusClient) CreateCollection(ctx context.Context, name string, schema map[string]interface{}) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	// Delay is an artificial latency added to every operation. Like a real
+	// backend call, the wait ends early when the context is done.
+	Delay time.Duration
 
-	m.collections[name] = schema
-	m.documents[name] = make([]Document, 0)
+	// Latency adds per-operation latency on top of Delay, keyed by method
+	// name such as "Search" or "Insert"
+	Latency map[string]time.Duration
 
-	m.logger.Info("Mock Milvus collection created", zap.String("name", name))
-	return nil
+	// Fault, when set, is consulted after the latency of every operation and
+	// fails the call with the error it returns
+	Fault FaultFunc
+	calls atomic.Int64
 }
 
-// Insert simulates inserting documents
-func (m *MockMilvusClient) Insert(ctx context.Context, collectionName string, documents []Document) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+// FaultFunc decides whether a mock operation fails. It receives the method
+// name and the store-wide 1-based call number and returns nil to let the call
+// proceed.
+type FaultFunc func(op string, call int) error
 
-	if _, exists := m.documents[collectionName]; !exists {
-		return fmt.Errorf("collection '%s' does not exist", collectionName)
-	}
-
-	// Add IDs to documents if not present
-	for i := range documents {
-		if documents[i].ID == "" {
-			documents[i].ID = fmt.Sprintf("doc_%d_%d", time.Now().UnixNano(), i)
+// FailEveryNth returns a fault that fails every nth call with err
+func FailEveryNth(n int, err error) FaultFunc {
+	return func(op string, call int) error {
+		if n > 0 && call%n == 0 {
+			return err
 		}
+		return nil
 	}
-
-	m.documents[collectionName] = append(m.documents[collectionName], documents...)
-
-	m.logger.Info("Mock Milvus documents inserted",
-		zap.String("collection", collectionName),
-		zap.Int("count", len(documents)))
-
-	return nil
 }
 
-// Search simulates vector search
-func (m *MockMilvusClient) Search(ctx context.Context, collectionName string, query string, limit int) ([]SearchResult, error) {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+// FailOperation returns a fault that fails every call of the named operation with err
+func FailOperation(name string, err error) FaultFunc {
+	return func(op string, call int) error {
+		if op == name {
+			return err
+		}
+		return nil
 	}
+}
 
-	results := make([]SearchResult, 0, limit)
-	for i, doc := range docs {
-		if i >= limit {
-			break
-		}
-		results = append(results, SearchResult{
-			Document: doc,
-			Score:    0.9 - float64(i)*0.1, // Mock decreasing scores
-		})
+// newMockStore creates an empty in-memory backend that logs as the named backend
+func newMockStore(backend string) *mockStore {
+	logger, _ := zap.NewProduction()
+	m := &mockStore{
+		backend:     backend,
+		collections: make(map[string]map[string]interface{}),
+		createdAt:   make(map[string]time.Time),
+		documents:   make(map[string][]Document),
+		logger:      logger,
+		Clock:       time.Now,
 	}
+	m.IDGen = m.sequentialID
+	return m
+}
 
-	m.logger.Info("Mock Milvus search executed",
-		zap.String("collection", collectionName),
-		zap.String("query", query),
-		zap.Int("limit", limit),
-		zap.Int("results", len(results)))
+// sequentialID returns the next ID from a per-store counter, so IDs are unique
+// and stable across runs
+func (m *mockStore) sequentialID() string {
+	return fmt.Sprintf("doc_%d", m.nextID.Add(1))
+}
 
-	return results, nil
+// MockMilvusClient implements MilvusClient for testing
+type MockMilvusClient struct {
+	*mockStore
 }
 
-// Query simulates natural language query
-func (m *MockMilvusClient) Query(ctx context.Context, collectionName string, query string, limit int) (interface{}, error) {
-	results, err := m.Search(ctx, collectionName, query, limit)
-	if err != nil {
-		return nil, err
-	}
+// NewMockMilvusClient creates a new mock Milvus client
+func NewMockMilvusClient() *MockMilvusClient {
+	return &MockMilvusClient{mockStore: newMockStore("Milvus")}
+}
 
-	// Convert to natural language response
-	response := fmt.Sprintf("Found %d relevant documents for query '%s':\n", len(results), query)
-	for i, result := range results {
-		response += fmt.Sprintf("%d. %s (Score: %.2f)\n", i+1, result.Document.Text[:min(100, len(result.Document.Text))], result.Score)
+// CollectionState simulates the Milvus index build progress and load state.
+// The mock indexes and loads synchronously, so a collection is always ready.
+func (m *MockMilvusClient) CollectionState(ctx context.Context, collectionName string) (MilvusCollectionState, error) {
+	if err := m.begin(ctx, "CollectionState"); err != nil {
+		return MilvusCollectionState{}, err
 	}
 
-	return response, nil
-}
-
-// ListDocuments simulates listing documents
-func (m *MockMilvusClient) ListDocuments(ctx context.Context, collectionName string, limit, offset int) ([]Document, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return MilvusCollectionState{}, err
 	}
+	return MilvusCollectionState{IndexedRows: len(docs), TotalRows: len(docs), Loaded: true}, nil
+}
 
-	start := offset
-	end := offset + limit
-	if start >= len(docs) {
-		return []Document{}, nil
-	}
-	if end > len(docs) {
-		end = len(docs)
-	}
+// MockWeaviateVersion is the server version the mock Weaviate client reports
+const MockWeaviateVersion = "1.25.0"
 
-	result := docs[start:end]
+// MockWeaviateClient implements WeaviateClient for testing
+type MockWeaviateClient struct {
+	*mockStore
 
-	m.logger.Info("Mock Milvus documents listed",
-		zap.String("collection", collectionName),
-		zap.Int("limit", limit),
-		zap.Int("offset", offset),
-		zap.Int("count", len(result)))
+	// Version is the server version reported by Meta; tests may replace it
+	// before use
+	Version string
+}
 
-	return result, nil
+// NewMockWeaviateClient creates a new mock Weaviate client
+func NewMockWeaviateClient() *MockWeaviateClient {
+	return &MockWeaviateClient{mockStore: newMockStore("Weaviate"), Version: MockWeaviateVersion}
 }
 
-// CountDocuments simulates counting documents
-func (m *MockMilvusClient) CountDocuments(ctx context.Context, collectionName string) (int, error) {
+// Meta simulates the Weaviate meta endpoint, reporting the server version
+func (m *MockWeaviateClient) Meta(ctx context.Context) (map[string]interface{}, error) {
+	if err := m.begin(ctx, "Meta"); err != nil {
+		return nil, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return 0, fmt.Errorf("collection '%s' does not exist", collectionName)
+	if m.closed {
+		return nil, ErrDatabaseClosed
 	}
 
-	count := len(docs)
-
-	m.logger.Info("Mock Milvus documents counted",
-		zap.String("collection", collectionName),
-		zap.Int("count", count))
-
-	return count, nil
+	return map[string]interface{}{
+		"hostname": "http://[::]:8080",
+		"version":  m.Version,
+	}, nil
 }
 
-// DeleteDocument simulates deleting a document
-func (m *MockMilvusClient) DeleteDocument(ctx context.Context, collectionName string, documentID string) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return fmt.Errorf("collection '%s' does not exist", collectionName)
+// begin fails when ctx is already done, waits out the artificial latency of
+// op, and then applies any injected fault, so cancellation, deadlines, and
+// backend errors behave as they would against a real backend
+func (m *mockStore) begin(ctx context.Context, op string) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	call := int(m.calls.Add(1))
 
-	for i, doc := range docs {
-		if doc.ID == documentID {
-			m.documents[collectionName] = append(docs[:i], docs[i+1:]...)
-			m.logger.Info("Mock Milvus document deleted",
-				zap.String("collection", collectionName),
-				zap.String("document_id", documentID))
-			return nil
+	if latency := m.Delay + m.Latency[op]; latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
 		}
 	}
 
-	return fmt.Errorf("document '%s' not found", documentID)
-}
-
-// DeleteDocuments simulates deleting multiple documents
-func (m *MockMilvusClient) DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) error {
-	for _, id := range documentIDs {
-		if err := m.DeleteDocument(ctx, collectionName, id); err != nil {
+	if m.Fault != nil {
+		if err := m.Fault(op, call); err != nil {
+			m.logger.Info(fmt.Sprintf("Mock %s injected fault", m.backend),
+				zap.String("operation", op),
+				zap.Int("call", call),
+				zap.Error(err))
 			return err
 		}
 	}
 	return nil
 }
 
-// ListCollections simulates listing collections
-func (m *MockMilvusClient) ListCollections(ctx context.Context) ([]string, error) {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-
-	collections := make([]string, 0, len(m.collections))
-	for name := range m.collections {
-		collections = append(collections, name)
+// collectionDocuments returns a collection's documents; the caller must hold the mutex
+func (m *mockStore) collectionDocuments(collectionName string) ([]Document, error) {
+	if m.closed {
+		return nil, ErrDatabaseClosed
 	}
 
-	m.logger.Info("Mock Milvus collections listed", zap.Int("count", len(collections)))
-
-	return collections, nil
-}
-
-// GetCollectionInfo simulates getting collection info
-func (m *MockMilvusClient) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-
-	schema, exists := m.collections[collectionName]
+	docs, exists := m.documents[collectionName]
 	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+		return nil, fmt.Errorf("%w: collection '%s' does not exist", ErrCollectionNotFound, collectionName)
 	}
+	return docs, nil
+}
 
-	docs := m.documents[collectionName]
-
-	info := map[string]interface{}{
-		"name":           collectionName,
-		"schema":         schema,
-		"document_count": len(docs),
-		"created_at":     time.Now().Format(time.RFC3339),
+// Connect simulates connecting to the backend
+func (m *mockStore) Connect(ctx context.Context) error {
+	if err := m.begin(ctx, "Connect"); err != nil {
+		return err
 	}
 
-	m.logger.Info("Mock Milvus collection info retrieved", zap.String("collection", collectionName))
-
-	return info, nil
-}
-
-// DeleteCollection simulates deleting a collection
-func (m *MockMilvusClient) DeleteCollection(ctx context.Context, collectionName string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	delete(m.collections, collectionName)
-	delete(m.documents, collectionName)
-
-	m.logger.Info("Mock Milvus collection deleted", zap.String("collection", collectionName))
+	m.closed = false
 
+	m.logger.Info(fmt.Sprintf("Mock %s client connected", m.backend))
 	return nil
 }
 
-// Close simulates closing the client
-func (m *MockMilvusClient) Close() error {
-	m.logger.Info("Mock Milvus client closed")
-	return nil
-}
-
-// MockWeaviateClient implements WeaviateClient for testing
-type MockWeaviateClient struct {
-	collections map[string]map[string]interface{}
-	documents   map[string][]Document
-	mutex       sync.RWMutex
-	logger      *zap.Logger
-}
-
-// NewMockWeaviateClient creates a new mock Weaviate client
-func NewMockWeaviateClient() *MockWeaviateClient {
-	logger, _ := zap.NewProduction()
-	return &MockWeaviateClient{
-		collections: make(map[string]map[string]interface{}),
-		documents:   make(map[string][]Document),
-		logger:      logger,
+// CreateCollection simulates creating a collection
+func (m *mockStore) CreateCollection(ctx context.Context, name string, schema map[string]interface{}) error {
+	if err := m.begin(ctx, "CreateCollection"); err != nil {
+		return err
 	}
-}
-
-// Connect simulates connecting to Weaviate
-func (m *MockWeaviateClient) Connect(ctx context.Context) error {
-	m.logger.Info("Mock Weaviate client connected")
-	return nil
-}
 
-// CreateCollection simulates creating a collection
-func (m *MockWeaviateClient) CreateCollection(ctx context.Context, name string, schema map[string]interface{}) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if m.closed {
+		return ErrDatabaseClosed
+	}
+
 	m.collections[name] = schema
+	m.createdAt[name] = m.Clock()
 	m.documents[name] = make([]Document, 0)
 
-	m.logger.Info("Mock Weaviate collection created", zap.String("name", name))
+	m.logger.Info(fmt.Sprintf("Mock %s collection created", m.backend), zap.String("name", name))
 	return nil
 }
 
 // Insert simulates inserting documents
-func (m *MockWeaviateClient) Insert(ctx context.Context, collectionName string, documents []Document) error {
+func (m *mockStore) Insert(ctx context.Context, collectionName string, documents []Document) error {
+	if err := m.begin(ctx, "Insert"); err != nil {
+		return err
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	if _, exists := m.documents[collectionName]; !exists {
-		return fmt.Errorf("collection '%s' does not exist", collectionName)
+	if _, err := m.collectionDocuments(collectionName); err != nil {
+		return err
 	}
 
 	// Add IDs to documents if not present
 	for i := range documents {
 		if documents[i].ID == "" {
-			documents[i].ID = fmt.Sprintf("doc_%d_%d", time.Now().UnixNano(), i)
+			documents[i].ID = m.IDGen()
 		}
 	}
 
 	m.documents[collectionName] = append(m.documents[collectionName], documents...)
 
-	m.logger.Info("Mock Weaviate documents inserted",
+	m.logger.Info(fmt.Sprintf("Mock %s documents inserted", m.backend),
 		zap.String("collection", collectionName),
 		zap.Int("count", len(documents)))
 
@@ -318,29 +269,67 @@ func (m *MockWeaviateClient) Insert(ctx context.Context, collectionName string,
 }
 
 // Search simulates vector search
-func (m *MockWeaviateClient) Search(ctx context.Context, collectionName string, query string, limit int) ([]SearchResult, error) {
+func (m *mockStore) Search(ctx context.Context, collectionName string, query string, limit int) ([]SearchResult, error) {
+	if err := m.begin(ctx, "Search"); err != nil {
+		return nil, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return nil, err
 	}
 
-	results := make([]SearchResult, 0, limit)
-	for i, doc := range docs {
-		if i >= limit {
-			break
+	// Soft-deleted documents are skipped unless the context includes them, and a
+	// named vector search only sees documents embedded in that space
+	docs = visibleDocuments(ctx, docs)
+	vectorName := VectorNameFromContext(ctx)
+	filter := MetadataFilterFromContext(ctx)
+	queryVector := QueryVectorFromContext(ctx)
+	queryTerms := termFrequencies(query)
+	results := make([]SearchResult, 0, len(docs))
+	for _, doc := range docs {
+		if _, embedded := doc.Vectors[vectorName]; vectorName != "" && !embedded {
+			continue
+		}
+		if filter != nil && !filter.Matches(doc) {
+			continue
+		}
+
+		// A vector search ranks by the stored vectors, skipping documents that
+		// were not embedded in the searched space; otherwise rank by text
+		var score float64
+		if queryVector != nil {
+			docVector := mockDocumentVector(doc, vectorName)
+			if len(docVector) != len(queryVector) {
+				continue
+			}
+			score = vectorCosine(queryVector, docVector)
+		} else {
+			score = cosineSimilarity(queryTerms, termFrequencies(doc.TextForEmbedding()))
 		}
-		results = append(results, SearchResult{
-			Document: doc,
-			Score:    0.9 - float64(i)*0.1, // Mock decreasing scores
-		})
+
+		results = append(results, SearchResult{Document: doc, Score: score})
 	}
 
-	m.logger.Info("Mock Weaviate search executed",
+	// Rank by similarity; ties keep insertion order so results are deterministic
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Document = ProjectDocument(results[i].Document, FieldsFromContext(ctx))
+	}
+
+	m.logger.Info(fmt.Sprintf("Mock %s search executed", m.backend),
 		zap.String("collection", collectionName),
 		zap.String("query", query),
+		zap.String("vector_name", vectorName),
+		zap.Bool("by_vector", queryVector != nil),
 		zap.Int("limit", limit),
 		zap.Int("results", len(results)))
 
@@ -348,30 +337,30 @@ func (m *MockWeaviateClient) Search(ctx context.Context, collectionName string,
 }
 
 // Query simulates natural language query
-func (m *MockWeaviateClient) Query(ctx context.Context, collectionName string, query string, limit int) (interface{}, error) {
+func (m *mockStore) Query(ctx context.Context, collectionName string, query string, limit int) (interface{}, error) {
 	results, err := m.Search(ctx, collectionName, query, limit)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert to natural language response
-	response := fmt.Sprintf("Found %d relevant documents for query '%s':\n", len(results), query)
-	for i, result := range results {
-		response += fmt.Sprintf("%d. %s (Score: %.2f)\n", i+1, result.Document.Text[:min(100, len(result.Document.Text))], result.Score)
-	}
-
-	return response, nil
+	return FormatQueryResults(query, results), nil
 }
 
 // ListDocuments simulates listing documents
-func (m *MockWeaviateClient) ListDocuments(ctx context.Context, collectionName string, limit, offset int) ([]Document, error) {
+func (m *mockStore) ListDocuments(ctx context.Context, collectionName string, limit, offset int) ([]Document, error) {
+	if err := m.begin(ctx, "ListDocuments"); err != nil {
+		return nil, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return nil, err
 	}
+	docs = visibleDocuments(ctx, docs)
 
 	start := offset
 	end := offset + limit
@@ -382,9 +371,9 @@ func (m *MockWeaviateClient) ListDocuments(ctx context.Context, collectionName s
 		end = len(docs)
 	}
 
-	result := docs[start:end]
+	result := ProjectDocuments(docs[start:end], FieldsFromContext(ctx))
 
-	m.logger.Info("Mock Weaviate documents listed",
+	m.logger.Info(fmt.Sprintf("Mock %s documents listed", m.backend),
 		zap.String("collection", collectionName),
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
@@ -393,19 +382,95 @@ func (m *MockWeaviateClient) ListDocuments(ctx context.Context, collectionName s
 	return result, nil
 }
 
+// GetDocument simulates fetching a document by ID
+func (m *mockStore) GetDocument(ctx context.Context, collectionName string, documentID string) (Document, error) {
+	if err := m.begin(ctx, "GetDocument"); err != nil {
+		return Document{}, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return Document{}, err
+	}
+
+	for _, doc := range docs {
+		if doc.ID == documentID {
+			return doc, nil
+		}
+	}
+
+	return Document{}, fmt.Errorf("%w: document '%s' not found", ErrDocumentNotFound, documentID)
+}
+
+// UpdateDocument simulates updating a document's fields in place
+func (m *mockStore) UpdateDocument(ctx context.Context, collectionName string, doc Document) error {
+	if err := m.begin(ctx, "UpdateDocument"); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return err
+	}
+
+	for i := range docs {
+		if docs[i].ID == doc.ID {
+			docs[i].URL = doc.URL
+			docs[i].Text = doc.Text
+			docs[i].EmbeddingText = doc.EmbeddingText
+			docs[i].Metadata = doc.Metadata
+			docs[i].UpdatedAt = doc.UpdatedAt
+			m.logger.Info(fmt.Sprintf("Mock %s document updated", m.backend),
+				zap.String("collection", collectionName),
+				zap.String("document_id", doc.ID))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: document '%s' not found", ErrDocumentNotFound, doc.ID)
+}
+
+// EstimateDocuments simulates reading the document count from collection
+// statistics. The mock keeps no separate statistics, so the estimate is the
+// stored count.
+func (m *mockStore) EstimateDocuments(ctx context.Context, collectionName string) (int, error) {
+	if err := m.begin(ctx, "EstimateDocuments"); err != nil {
+		return 0, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
 // CountDocuments simulates counting documents
-func (m *MockWeaviateClient) CountDocuments(ctx context.Context, collectionName string) (int, error) {
+func (m *mockStore) CountDocuments(ctx context.Context, collectionName string) (int, error) {
+	if err := m.begin(ctx, "CountDocuments"); err != nil {
+		return 0, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return 0, fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return 0, err
 	}
 
-	count := len(docs)
+	count := len(visibleDocuments(ctx, docs))
 
-	m.logger.Info("Mock Weaviate documents counted",
+	m.logger.Info(fmt.Sprintf("Mock %s documents counted", m.backend),
 		zap.String("collection", collectionName),
 		zap.Int("count", count))
 
@@ -413,96 +478,301 @@ func (m *MockWeaviateClient) CountDocuments(ctx context.Context, collectionName
 }
 
 // DeleteDocument simulates deleting a document
-func (m *MockWeaviateClient) DeleteDocument(ctx context.Context, collectionName string, documentID string) error {
+func (m *mockStore) DeleteDocument(ctx context.Context, collectionName string, documentID string) error {
+	if err := m.begin(ctx, "DeleteDocument"); err != nil {
+		return err
+	}
+	return m.deleteDocument(collectionName, documentID)
+}
+
+// DeleteDocuments simulates deleting multiple documents. Every ID is
+// attempted and a missing one is reported as failed; only ctx ending or a
+// missing collection stops the batch early.
+func (m *mockStore) DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) (DeleteReport, error) {
+	report := DeleteReport{Deleted: []string{}}
+	if err := m.begin(ctx, "DeleteDocuments"); err != nil {
+		return report, err
+	}
+	for _, id := range documentIDs {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := m.deleteDocument(collectionName, id); err != nil {
+			if !errors.Is(err, ErrDocumentNotFound) {
+				return report, err
+			}
+			report.Failed = append(report.Failed, DeleteFailure{ID: id, Error: err.Error()})
+			continue
+		}
+		report.Deleted = append(report.Deleted, id)
+	}
+	return report, nil
+}
+
+// deleteDocument removes a single document by ID
+func (m *mockStore) deleteDocument(collectionName string, documentID string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	docs, exists := m.documents[collectionName]
-	if !exists {
-		return fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return err
 	}
 
 	for i, doc := range docs {
 		if doc.ID == documentID {
 			m.documents[collectionName] = append(docs[:i], docs[i+1:]...)
-			m.logger.Info("Mock Weaviate document deleted",
+			m.logger.Info(fmt.Sprintf("Mock %s document deleted", m.backend),
 				zap.String("collection", collectionName),
 				zap.String("document_id", documentID))
 			return nil
 		}
 	}
 
-	return fmt.Errorf("document '%s' not found", documentID)
+	return fmt.Errorf("%w: document '%s' not found", ErrDocumentNotFound, documentID)
 }
 
-// DeleteDocuments simulates deleting multiple documents
-func (m *MockWeaviateClient) DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) error {
-	for _, id := range documentIDs {
-		if err := m.DeleteDocument(ctx, collectionName, id); err != nil {
-			return err
+// DeleteExpired simulates deleting documents whose TTL has passed
+func (m *mockStore) DeleteExpired(ctx context.Context, collectionName string, before time.Time) (int, error) {
+	if err := m.begin(ctx, "DeleteExpired"); err != nil {
+		return 0, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]Document, 0, len(docs))
+	for _, doc := range docs {
+		if !IsExpired(doc, before) {
+			kept = append(kept, doc)
 		}
 	}
-	return nil
+	m.documents[collectionName] = kept
+
+	deleted := len(docs) - len(kept)
+	m.logger.Info(fmt.Sprintf("Mock %s expired documents deleted", m.backend),
+		zap.String("collection", collectionName),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// DeleteByFilter simulates deleting the documents matching a metadata filter
+func (m *mockStore) DeleteByFilter(ctx context.Context, collectionName string, filter MetadataFilter) (int, error) {
+	if err := m.begin(ctx, "DeleteByFilter"); err != nil {
+		return 0, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]Document, 0, len(docs))
+	for _, doc := range docs {
+		if !filter.Matches(doc) {
+			kept = append(kept, doc)
+		}
+	}
+	m.documents[collectionName] = kept
+
+	deleted := len(docs) - len(kept)
+	m.logger.Info(fmt.Sprintf("Mock %s documents deleted by filter", m.backend),
+		zap.String("collection", collectionName),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// Truncate simulates deleting every document while keeping the collection and its schema
+func (m *mockStore) Truncate(ctx context.Context, collectionName string) (int, error) {
+	if err := m.begin(ctx, "Truncate"); err != nil {
+		return 0, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return 0, err
+	}
+	m.documents[collectionName] = make([]Document, 0)
+
+	m.logger.Info(fmt.Sprintf("Mock %s collection truncated", m.backend),
+		zap.String("collection", collectionName),
+		zap.Int("count", len(docs)))
+
+	return len(docs), nil
 }
 
 // ListCollections simulates listing collections
-func (m *MockWeaviateClient) ListCollections(ctx context.Context) ([]string, error) {
+func (m *mockStore) ListCollections(ctx context.Context) ([]string, error) {
+	if err := m.begin(ctx, "ListCollections"); err != nil {
+		return nil, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
+	if m.closed {
+		return nil, ErrDatabaseClosed
+	}
+
 	collections := make([]string, 0, len(m.collections))
 	for name := range m.collections {
 		collections = append(collections, name)
 	}
 
-	m.logger.Info("Mock Weaviate collections listed", zap.Int("count", len(collections)))
+	m.logger.Info(fmt.Sprintf("Mock %s collections listed", m.backend), zap.Int("count", len(collections)))
 
 	return collections, nil
 }
 
 // GetCollectionInfo simulates getting collection info
-func (m *MockWeaviateClient) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
+func (m *mockStore) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
+	if err := m.begin(ctx, "GetCollectionInfo"); err != nil {
+		return nil, err
+	}
+
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	schema, exists := m.collections[collectionName]
-	if !exists {
-		return nil, fmt.Errorf("collection '%s' does not exist", collectionName)
+	docs, err := m.collectionDocuments(collectionName)
+	if err != nil {
+		return nil, err
 	}
 
-	docs := m.documents[collectionName]
-
 	info := map[string]interface{}{
 		"name":           collectionName,
-		"schema":         schema,
+		"schema":         m.collections[collectionName],
 		"document_count": len(docs),
-		"created_at":     time.Now().Format(time.RFC3339),
+		"created_at":     m.createdAt[collectionName].Format(time.RFC3339),
 	}
 
-	m.logger.Info("Mock Weaviate collection info retrieved", zap.String("collection", collectionName))
+	m.logger.Info(fmt.Sprintf("Mock %s collection info retrieved", m.backend), zap.String("collection", collectionName))
 
 	return info, nil
 }
 
 // DeleteCollection simulates deleting a collection
-func (m *MockWeaviateClient) DeleteCollection(ctx context.Context, collectionName string) error {
+func (m *mockStore) DeleteCollection(ctx context.Context, collectionName string) error {
+	if err := m.begin(ctx, "DeleteCollection"); err != nil {
+		return err
+	}
+
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if m.closed {
+		return ErrDatabaseClosed
+	}
+
 	delete(m.collections, collectionName)
+	delete(m.createdAt, collectionName)
 	delete(m.documents, collectionName)
 
-	m.logger.Info("Mock Weaviate collection deleted", zap.String("collection", collectionName))
+	m.logger.Info(fmt.Sprintf("Mock %s collection deleted", m.backend), zap.String("collection", collectionName))
 
 	return nil
 }
 
-// Close simulates closing the client
-func (m *MockWeaviateClient) Close() error {
-	m.logger.Info("Mock Weaviate client closed")
+// Close simulates closing the client; later calls fail with ErrDatabaseClosed until Connect
+func (m *mockStore) Close() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.closed = true
+
+	m.logger.Info(fmt.Sprintf("Mock %s client closed", m.backend))
 	return nil
 }
 
+// stopWords are ignored when scoring mock search results
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "that": true, "the": true, "this": true, "to": true, "with": true,
+}
+
+// visibleDocuments returns the documents a read sees: soft-deleted documents
+// are skipped, as a metadata filter would on the backend, unless the context
+// includes them
+func visibleDocuments(ctx context.Context, docs []Document) []Document {
+	if TombstonesFromContext(ctx) {
+		return docs
+	}
+	return ExcludeTombstones(docs)
+}
+
+// termFrequencies builds a bag-of-words vector of the lowercased words in text
+func termFrequencies(text string) map[string]float64 {
+	terms := make(map[string]float64)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if !stopWords[word] {
+			terms[word]++
+		}
+	}
+	return terms
+}
+
+// cosineSimilarity scores two bag-of-words vectors between 0 and 1
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// mockDocumentVector returns the stored vector searched in the named space, or
+// the default vector when name is empty
+func mockDocumentVector(doc Document, name string) []float64 {
+	if name != "" {
+		return doc.Vectors[name]
+	}
+	if len(doc.Vector) == 0 && len(doc.Vector32) > 0 {
+		vector := make([]float64, len(doc.Vector32))
+		for i, v := range doc.Vector32 {
+			vector[i] = float64(v)
+		}
+		return vector
+	}
+	return doc.Vector
+}
+
+// vectorCosine returns the cosine similarity of two equal-length vectors
+func vectorCosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
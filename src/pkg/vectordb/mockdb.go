@@ -0,0 +1,246 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"go.uber.org/zap"
+)
+
+// MockDatabase implements VectorDatabase entirely in memory on the mock store,
+// so the server can run end to end without an external backend
+type MockDatabase struct {
+	config         *config.Config
+	logger         *zap.Logger
+	collectionName string
+	store          *mockStore
+}
+
+// NewMockDatabase creates an in-memory database instance
+func NewMockDatabase(collectionName string, cfg *config.Config) (*MockDatabase, error) {
+	logger, _ := zap.NewProduction()
+
+	return &MockDatabase{
+		config:         cfg,
+		logger:         logger,
+		collectionName: collectionName,
+		store:          newMockStore("in-memory"),
+	}, nil
+}
+
+// Type returns the database type
+func (m *MockDatabase) Type() string {
+	return "mock"
+}
+
+// CollectionName returns the current collection name
+func (m *MockDatabase) CollectionName() string {
+	return m.collectionName
+}
+
+// Setup initializes the database and creates the collection
+func (m *MockDatabase) Setup(ctx context.Context, embedding string) error {
+	if err := ValidateCollectionName(m.Type(), m.collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(m.config, CollectionOptions{Embedding: embedding}); err != nil {
+		return err
+	}
+
+	if err := m.store.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to mock store: %w", err)
+	}
+
+	schema := map[string]interface{}{
+		"name":        m.collectionName,
+		"embedding":   embedding,
+		"dimension":   m.config.MCP.Embedding.VectorSize,
+		"metric_type": m.config.MCP.Embedding.EffectiveMetric(),
+	}
+	recordEmbeddingModel(schema, m.config)
+	if err := m.store.CreateCollection(ctx, m.collectionName, schema); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	m.logger.Info("Set up mock collection",
+		zap.String("collection", m.collectionName),
+		zap.String("embedding", embedding))
+
+	return nil
+}
+
+// WriteDocument writes a single document to the database
+func (m *MockDatabase) WriteDocument(ctx context.Context, doc Document) (WriteStats, error) {
+	return m.WriteDocuments(ctx, []Document{doc})
+}
+
+// WriteDocuments writes multiple documents to the database in one batch
+func (m *MockDatabase) WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error) {
+	start := time.Now()
+
+	docs, err := prepareDocuments(m.config, BackendLimits(m.Type(), m.config), docs)
+	if err != nil {
+		return WriteStats{}, err
+	}
+
+	if err := m.store.Insert(ctx, m.collectionName, docs); err != nil {
+		return WriteStats{ProcessingTime: time.Since(start).String()},
+			fmt.Errorf("failed to insert documents: %w", err)
+	}
+
+	return WriteStats{
+		DocumentsWritten: len(docs),
+		ProcessingTime:   time.Since(start).String(),
+		Batches:          1,
+	}, nil
+}
+
+// Query performs a natural language query on the database
+func (m *MockDatabase) Query(ctx context.Context, query string, limit int, collectionName string) (interface{}, error) {
+	if collectionName == "" {
+		collectionName = m.collectionName
+	}
+	return m.store.Query(ctx, collectionName, query, limit)
+}
+
+// Search performs a similarity search over the stored text or vectors
+func (m *MockDatabase) Search(ctx context.Context, query string, limit int, collectionName string) ([]SearchResult, error) {
+	if collectionName == "" {
+		collectionName = m.collectionName
+	}
+
+	vectorName := VectorNameFromContext(ctx)
+	if err := validateVectorName(m.config, vectorName); err != nil {
+		return nil, err
+	}
+	if err := validateQueryVector(m.config, vectorName, QueryVectorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	results, _, err := filteredSearch(ctx, limit, FilterStrategyPre, func(ctx context.Context, limit int) ([]SearchResult, error) {
+		return m.store.Search(ctx, collectionName, query, limit)
+	})
+	return results, err
+}
+
+// ListDocuments lists documents from the database
+func (m *MockDatabase) ListDocuments(ctx context.Context, limit, offset int) ([]Document, error) {
+	return m.store.ListDocuments(ctx, m.collectionName, limit, offset)
+}
+
+// GetDocument returns a single document by ID
+func (m *MockDatabase) GetDocument(ctx context.Context, documentID string) (Document, error) {
+	return m.store.GetDocument(ctx, m.collectionName, documentID)
+}
+
+// UpdateDocument replaces a stored document's URL, text, and metadata in place
+func (m *MockDatabase) UpdateDocument(ctx context.Context, doc Document) error {
+	return m.store.UpdateDocument(ctx, m.collectionName, touchDocument(doc))
+}
+
+// CountDocuments returns the count of documents in the database
+func (m *MockDatabase) CountDocuments(ctx context.Context) (int, error) {
+	return m.store.CountDocuments(ctx, m.collectionName)
+}
+
+// EstimateDocuments returns the mock store's collection statistics count
+func (m *MockDatabase) EstimateDocuments(ctx context.Context) (int, error) {
+	return m.store.EstimateDocuments(ctx, m.collectionName)
+}
+
+// DeleteDocument deletes a document by ID
+func (m *MockDatabase) DeleteDocument(ctx context.Context, documentID string) error {
+	return m.store.DeleteDocument(ctx, m.collectionName, documentID)
+}
+
+// DeleteDocuments deletes multiple documents by IDs
+func (m *MockDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error) {
+	return m.store.DeleteDocuments(ctx, m.collectionName, documentIDs)
+}
+
+// DeleteExpired deletes documents whose TTL expired at or before the given time
+func (m *MockDatabase) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	return m.store.DeleteExpired(ctx, m.collectionName, before)
+}
+
+// DeleteByFilter deletes documents matching a metadata filter
+func (m *MockDatabase) DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error) {
+	if len(filter) == 0 {
+		return 0, fmt.Errorf("%w: refusing to delete without a filter", ErrInvalidFilter)
+	}
+	return m.store.DeleteByFilter(ctx, m.collectionName, filter)
+}
+
+// TruncateCollection deletes every document in a collection, keeping the collection
+func (m *MockDatabase) TruncateCollection(ctx context.Context, collectionName string) (int, error) {
+	if collectionName == "" {
+		collectionName = m.collectionName
+	}
+	return m.store.Truncate(ctx, collectionName)
+}
+
+// ListCollections lists all collections in the database
+func (m *MockDatabase) ListCollections(ctx context.Context) ([]string, error) {
+	return m.store.ListCollections(ctx)
+}
+
+// GetCollectionInfo returns information about a collection
+func (m *MockDatabase) GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error) {
+	if collectionName == "" {
+		collectionName = m.collectionName
+	}
+	return m.store.GetCollectionInfo(ctx, collectionName)
+}
+
+// CreateCollection creates an additional collection in the store
+func (m *MockDatabase) CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error {
+	if err := ValidateCollectionName(m.Type(), collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(m.config, options); err != nil {
+		return err
+	}
+
+	if err := m.store.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to mock store: %w", err)
+	}
+
+	collections, err := m.store.ListCollections(ctx)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(collections, collectionName) {
+		return fmt.Errorf("%w: '%s'", ErrCollectionExists, collectionName)
+	}
+
+	options = options.WithDefaults(m.config)
+	schema := map[string]interface{}{
+		"name":        collectionName,
+		"embedding":   options.Embedding,
+		"dimension":   options.VectorSize,
+		"metric_type": options.Metric,
+	}
+	recordEmbeddingModel(schema, m.config)
+	if err := m.store.CreateCollection(ctx, collectionName, schema); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	m.logger.Info("Created mock collection",
+		zap.String("collection", collectionName),
+		zap.String("embedding", options.Embedding))
+
+	return nil
+}
+
+// DeleteCollection deletes a collection
+func (m *MockDatabase) DeleteCollection(ctx context.Context, collectionName string) error {
+	return m.store.DeleteCollection(ctx, collectionName)
+}
+
+// Cleanup closes the in-memory store; stored documents are kept until the process exits
+func (m *MockDatabase) Cleanup(ctx context.Context) error {
+	return m.store.Close()
+}
@@ -0,0 +1,68 @@
+package vectordb
+
+import (
+	"regexp"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// maxCollectionNameLength is the longest collection name accepted by either backend
+const maxCollectionNameLength = 255
+
+var (
+	milvusNamePattern   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	weaviateNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+	// collectionVariablePattern matches a value that may fill a collection name template
+	collectionVariablePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// ValidateCollectionName checks a collection name against the naming rules of the given backend
+func ValidateCollectionName(dbType, name string) error {
+	if name == "" {
+		return invalidRequestf("collection name is required")
+	}
+	if len(name) > maxCollectionNameLength {
+		return invalidRequestf("collection name '%s' exceeds %d characters", name, maxCollectionNameLength)
+	}
+
+	switch dbType {
+	case "milvus", "mock":
+		if !milvusNamePattern.MatchString(name) {
+			return invalidRequestf("invalid Milvus collection name '%s': names must start with a letter or underscore and contain only letters, digits, and underscores", name)
+		}
+	case "weaviate":
+		if name[0] < 'A' || name[0] > 'Z' {
+			return invalidRequestf("invalid Weaviate collection name '%s': Weaviate class names must start with an uppercase letter", name)
+		}
+		if !weaviateNamePattern.MatchString(name) {
+			return invalidRequestf("invalid Weaviate collection name '%s': class names may only contain letters, digits, and underscores", name)
+		}
+	default:
+		return invalidRequestf("unsupported vector database type: %s", dbType)
+	}
+
+	return nil
+}
+
+// RenderCollectionName fills a collection name template such as
+// tenant_{tenant}_docs from vars and checks the result against the naming
+// rules of the given backend. Every variable in the template needs a value of
+// letters, digits, and underscores.
+func RenderCollectionName(dbType, template string, vars map[string]string) (string, error) {
+	for _, variable := range config.PromptPlaceholders(template) {
+		value := vars[variable]
+		if value == "" {
+			return "", invalidRequestf("collection name template '%s' needs a value for {%s}", template, variable)
+		}
+		if !collectionVariablePattern.MatchString(value) {
+			return "", invalidRequestf("invalid value '%s' for {%s}: collection name variables may only contain letters, digits, and underscores", value, variable)
+		}
+	}
+
+	name := config.RenderPrompt(template, vars)
+	if err := ValidateCollectionName(dbType, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,102 @@
+package vectordb
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Built-in preprocessing steps accepted in mcp.preprocess.steps
+const (
+	PreprocessStripHTML           = "strip_html"
+	PreprocessNormalizeWhitespace = "normalize_whitespace"
+	PreprocessLowercase           = "lowercase"
+)
+
+// TextPreprocessor cleans text before it is embedded. It is applied to
+// written documents and to query text alike, so both sides of a similarity
+// search are embedded the same way.
+type TextPreprocessor interface {
+	Preprocess(text string) string
+}
+
+// PreprocessorFunc adapts a plain function to a TextPreprocessor
+type PreprocessorFunc func(text string) string
+
+// Preprocess calls f(text)
+func (f PreprocessorFunc) Preprocess(text string) string {
+	return f(text)
+}
+
+// PreprocessorChain applies its preprocessors in order
+type PreprocessorChain []TextPreprocessor
+
+// Preprocess runs text through every preprocessor of the chain
+func (c PreprocessorChain) Preprocess(text string) string {
+	for _, p := range c {
+		text = p.Preprocess(text)
+	}
+	return text
+}
+
+var (
+	// htmlBlockPattern matches elements whose content is never visible text
+	htmlBlockPattern = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+)
+
+// StripHTML removes HTML tags, comments, and script and style blocks, then
+// unescapes entities. Tags become spaces so adjacent blocks do not run together.
+func StripHTML(text string) string {
+	text = htmlBlockPattern.ReplaceAllString(text, " ")
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	return html.UnescapeString(text)
+}
+
+// NormalizeWhitespace collapses runs of whitespace to single spaces and trims the ends
+func NormalizeWhitespace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// builtinPreprocessors maps the configurable step names to their implementation
+var builtinPreprocessors = map[string]TextPreprocessor{
+	PreprocessStripHTML:           PreprocessorFunc(StripHTML),
+	PreprocessNormalizeWhitespace: PreprocessorFunc(NormalizeWhitespace),
+	PreprocessLowercase:           PreprocessorFunc(strings.ToLower),
+}
+
+// NewPreprocessor builds the chain of steps listed in mcp.preprocess. It
+// returns nil when no steps are configured, leaving text untouched.
+func NewPreprocessor(cfg config.PreprocessConfig) (TextPreprocessor, error) {
+	if len(cfg.Steps) == 0 {
+		return nil, nil
+	}
+
+	chain := make(PreprocessorChain, 0, len(cfg.Steps))
+	for _, step := range cfg.Steps {
+		p, ok := builtinPreprocessors[step]
+		if !ok {
+			return nil, fmt.Errorf("unknown preprocess step '%s': must be %s, %s, or %s",
+				step, PreprocessStripHTML, PreprocessNormalizeWhitespace, PreprocessLowercase)
+		}
+		chain = append(chain, p)
+	}
+	return chain, nil
+}
+
+// PreprocessDocument returns doc with EmbeddingText set to its preprocessed
+// text. Text keeps the original, so results return what was written. A nil
+// preprocessor, or one that leaves the text unchanged, clears EmbeddingText.
+func PreprocessDocument(p TextPreprocessor, doc Document) Document {
+	doc.EmbeddingText = ""
+	if p == nil {
+		return doc
+	}
+	if cleaned := p.Preprocess(doc.Text); cleaned != doc.Text {
+		doc.EmbeddingText = cleaned
+	}
+	return doc
+}
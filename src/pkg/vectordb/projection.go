@@ -0,0 +1,160 @@
+package vectordb
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Document fields that can be selected with the fields argument
+const (
+	FieldID       = "id"
+	FieldURL      = "url"
+	FieldText     = "text"
+	FieldMetadata = "metadata"
+	FieldVector   = "vector"
+	FieldVectors  = "vectors"
+
+	FieldCreatedAt = "created_at"
+	FieldUpdatedAt = "updated_at"
+)
+
+// documentFields lists the selectable document fields in schema order
+var documentFields = []string{FieldID, FieldURL, FieldText, FieldMetadata, FieldVector, FieldVectors, FieldCreatedAt, FieldUpdatedAt}
+
+// ValidateFields checks that every field names a selectable document field
+func ValidateFields(fields []string) error {
+	for _, field := range fields {
+		if !containsField(documentFields, field) {
+			return invalidRequestf("unknown document field '%s'; expected one of %s", field, strings.Join(documentFields, ", "))
+		}
+	}
+	return nil
+}
+
+// containsField reports whether fields includes field
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsKey is the context key holding the document fields to return
+type fieldsKey struct{}
+
+// WithFields returns a context that restricts the document fields returned by
+// Search, Query, and ListDocuments. Clients push the selection down to the
+// backend; an empty selection returns every field.
+func WithFields(ctx context.Context, fields []string) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// FieldsFromContext returns the document fields to return, or nil for all fields
+func FieldsFromContext(ctx context.Context) []string {
+	fields, _ := ctx.Value(fieldsKey{}).([]string)
+	return fields
+}
+
+// ProjectDocument returns a copy of doc keeping only the selected fields. An
+// empty selection keeps every field.
+func ProjectDocument(doc Document, fields []string) Document {
+	if len(fields) == 0 {
+		return doc
+	}
+
+	var projected Document
+	for _, field := range fields {
+		switch field {
+		case FieldID:
+			projected.ID = doc.ID
+		case FieldURL:
+			projected.URL = doc.URL
+		case FieldText:
+			projected.Text = doc.Text
+			projected.EmbeddingText = doc.EmbeddingText
+		case FieldMetadata:
+			projected.Metadata = doc.Metadata
+		case FieldVector:
+			projected.Vector = doc.Vector
+			projected.Vector32 = doc.Vector32
+		case FieldVectors:
+			projected.Vectors = doc.Vectors
+		case FieldCreatedAt:
+			projected.CreatedAt = doc.CreatedAt
+		case FieldUpdatedAt:
+			projected.UpdatedAt = doc.UpdatedAt
+		}
+	}
+	return projected
+}
+
+// ProjectDocuments returns copies of docs keeping only the selected fields
+func ProjectDocuments(docs []Document, fields []string) []Document {
+	if len(fields) == 0 {
+		return docs
+	}
+
+	projected := make([]Document, len(docs))
+	for i, doc := range docs {
+		projected[i] = ProjectDocument(doc, fields)
+	}
+	return projected
+}
+
+// MilvusOutputFields translates a field selection into Milvus output fields.
+// The primary key is always returned, and vectors expands to the configured
+// named vector fields.
+func MilvusOutputFields(cfg *config.Config, fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	output := []string{FieldID}
+	for _, field := range fields {
+		switch field {
+		case FieldID:
+		case FieldVectors:
+			names := make([]string, 0, len(cfg.MCP.Embedding.NamedVectors))
+			for name := range cfg.MCP.Embedding.NamedVectors {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			output = append(output, names...)
+		default:
+			output = append(output, field)
+		}
+	}
+	return output
+}
+
+// WeaviateFieldSelection translates a field selection into a GraphQL selection
+// set; the object ID and vectors live under _additional
+func WeaviateFieldSelection(fields []string) string {
+	if len(fields) == 0 {
+		fields = documentFields
+	}
+
+	var properties, additional []string
+	for _, field := range fields {
+		switch field {
+		case FieldID:
+			additional = append(additional, "id")
+		case FieldVector:
+			additional = append(additional, "vector")
+		case FieldVectors:
+			additional = append(additional, "vectors")
+		default:
+			properties = append(properties, field)
+		}
+	}
+
+	if len(additional) > 0 {
+		properties = append(properties, "_additional { "+strings.Join(additional, " ")+" }")
+	}
+	return strings.Join(properties, " ")
+}
@@ -0,0 +1,45 @@
+package vectordb
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// RecencyOverfetch multiplies the limit of a recency-boosted search so newer
+// documents just outside the top results can move up
+const RecencyOverfetch = 2
+
+// ValidateRecencyWeight checks a recency_weight value, which must lie in [0, 1]
+func ValidateRecencyWeight(weight float64) error {
+	if weight < 0 || weight > 1 || math.IsNaN(weight) {
+		return invalidRequestf("recency_weight must be between 0 and 1, got %v", weight)
+	}
+	return nil
+}
+
+// BoostRecency blends each result's score with the age of its document,
+// (1-weight)*score + weight*0.5^(age/halfLife), and re-sorts the results by
+// the blended score. A document created now counts fully and one halfLife old
+// counts half. Documents without a creation time keep their score, so the
+// boost does nothing for collections written before timestamps were stored.
+func BoostRecency(results []SearchResult, weight float64, halfLife time.Duration, now time.Time) []SearchResult {
+	if weight <= 0 || halfLife <= 0 {
+		return results
+	}
+
+	boosted := make([]SearchResult, len(results))
+	for i, result := range results {
+		if createdAt := result.Document.CreatedAt; !createdAt.IsZero() {
+			age := max(now.Sub(createdAt), 0)
+			freshness := math.Exp2(-float64(age) / float64(halfLife))
+			result.Score = (1-weight)*result.Score + weight*freshness
+		}
+		boosted[i] = result
+	}
+
+	sort.SliceStable(boosted, func(i, j int) bool {
+		return boosted[i].Score > boosted[j].Score
+	})
+	return boosted
+}
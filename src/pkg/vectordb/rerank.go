@@ -0,0 +1,148 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// Reranker re-scores search candidates against the query. Implementations
+// return the results ordered by their new score, highest first.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error)
+}
+
+// HTTPReranker scores candidates with a re-rank endpoint such as a hosted
+// cross-encoder. It posts {"model", "query", "documents"} and expects
+// {"results": [{"index", "relevance_score"}]} in response.
+type HTTPReranker struct {
+	url    string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewHTTPReranker creates a reranker for the configured endpoint
+func NewHTTPReranker(cfg config.RerankConfig) *HTTPReranker {
+	return &HTTPReranker{
+		url:    cfg.URL,
+		apiKey: cfg.APIKey,
+		model:  cfg.Model,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// rerankRequest is the body posted to the re-rank endpoint
+type rerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+// rerankResponse is the body returned by the re-rank endpoint
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank replaces each result's score with the endpoint's relevance score
+func (r *HTTPReranker) Rerank(ctx context.Context, query string, results []SearchResult) ([]SearchResult, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	documents := make([]string, len(results))
+	for i, result := range results {
+		documents[i] = result.Document.Text
+	}
+
+	body, err := json.Marshal(rerankRequest{Model: r.model, Query: query, Documents: documents})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rerank endpoint returned status %d", resp.StatusCode)
+	}
+
+	var decoded rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode rerank response: %w", err)
+	}
+
+	reranked := make([]SearchResult, 0, len(decoded.Results))
+	for _, scored := range decoded.Results {
+		if scored.Index < 0 || scored.Index >= len(results) {
+			return nil, fmt.Errorf("rerank endpoint returned out of range index %d", scored.Index)
+		}
+		result := results[scored.Index]
+		result.Score = scored.RelevanceScore
+		reranked = append(reranked, result)
+	}
+
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked, nil
+}
+
+// querySummaryLength is the number of characters of text shown per query result
+const querySummaryLength = 100
+
+// FormatQueryResults renders search results as the natural language answer
+// returned by Query. Documents are labelled by the start of their text, or by
+// URL or ID when the text was not selected, and dated when last updated.
+func FormatQueryResults(query string, results []SearchResult) string {
+	response := fmt.Sprintf("Found %d relevant documents for query '%s':\n", len(results), query)
+	for i, result := range results {
+		label := truncateRunes(result.Document.Text, querySummaryLength)
+		if label == "" {
+			label = result.Document.URL
+		}
+		if label == "" {
+			label = result.Document.ID
+		}
+		response += fmt.Sprintf("%d. %s (Score: %.2f)", i+1, label, result.Score)
+		if !result.Document.UpdatedAt.IsZero() {
+			response += " [updated " + result.Document.UpdatedAt.Format(time.DateOnly) + "]"
+		}
+		response += "\n"
+	}
+	return response
+}
+
+// truncateRunes returns the first n characters of s, never splitting a
+// multi-byte UTF-8 character
+func truncateRunes(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
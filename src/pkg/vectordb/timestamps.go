@@ -0,0 +1,29 @@
+package vectordb
+
+import (
+	"time"
+)
+
+// documentTime returns the current time at the second precision the backends
+// store timestamps with
+func documentTime() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// stampTimestamps returns a copy of doc written at now. A CreatedAt set by
+// the caller is kept; UpdatedAt always records the write.
+func stampTimestamps(doc Document, now time.Time) Document {
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = now
+	} else {
+		doc.CreatedAt = doc.CreatedAt.UTC()
+	}
+	doc.UpdatedAt = now
+	return doc
+}
+
+// touchDocument returns a copy of doc stamped as updated now
+func touchDocument(doc Document) Document {
+	doc.UpdatedAt = documentTime()
+	return doc
+}
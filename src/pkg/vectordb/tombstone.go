@@ -0,0 +1,83 @@
+package vectordb
+
+import (
+	"context"
+	"time"
+)
+
+// Metadata keys used to tombstone soft-deleted documents
+const (
+	MetadataDeleted   = "deleted"
+	MetadataDeletedAt = "deleted_at"
+)
+
+// IsTombstoned reports whether a document has been soft-deleted
+func IsTombstoned(doc Document) bool {
+	deleted, _ := doc.Metadata[MetadataDeleted].(bool)
+	return deleted
+}
+
+// DeletedAt returns when a tombstoned document was soft-deleted
+func DeletedAt(doc Document) (time.Time, bool) {
+	value, ok := doc.Metadata[MetadataDeletedAt].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	deletedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deletedAt, true
+}
+
+// Tombstone returns a copy of doc marked as deleted at the given time
+func Tombstone(doc Document, at time.Time) Document {
+	doc.Metadata = copyMetadata(doc.Metadata)
+	doc.Metadata[MetadataDeleted] = true
+	doc.Metadata[MetadataDeletedAt] = at.UTC().Format(time.RFC3339)
+	return doc
+}
+
+// Restore returns a copy of doc with its tombstone removed
+func Restore(doc Document) Document {
+	doc.Metadata = copyMetadata(doc.Metadata)
+	delete(doc.Metadata, MetadataDeleted)
+	delete(doc.Metadata, MetadataDeletedAt)
+	return doc
+}
+
+// includeTombstonesKey is the context key for reads that include soft-deleted
+// documents
+type includeTombstonesKey struct{}
+
+// WithTombstones returns a context whose searches, queries, listings, and
+// counts include soft-deleted documents, which they skip by default
+func WithTombstones(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeTombstonesKey{}, true)
+}
+
+// TombstonesFromContext reports whether a read includes soft-deleted documents
+func TombstonesFromContext(ctx context.Context) bool {
+	include, _ := ctx.Value(includeTombstonesKey{}).(bool)
+	return include
+}
+
+// ExcludeTombstones returns the documents that have not been soft-deleted
+func ExcludeTombstones(docs []Document) []Document {
+	live := make([]Document, 0, len(docs))
+	for _, doc := range docs {
+		if !IsTombstoned(doc) {
+			live = append(live, doc)
+		}
+	}
+	return live
+}
+
+// copyMetadata returns a shallow copy of metadata that is safe to modify
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata)+2)
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
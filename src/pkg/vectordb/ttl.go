@@ -0,0 +1,40 @@
+package vectordb
+
+import (
+	"time"
+)
+
+// Metadata keys used to expire documents written with a TTL
+const (
+	MetadataTTL       = "ttl"
+	MetadataExpiresAt = "expires_at"
+)
+
+// WithTTL returns a copy of doc that expires ttl after now. The absolute expiry
+// is stored as Unix seconds so backends can delete with a single range filter.
+func WithTTL(doc Document, ttl time.Duration, now time.Time) Document {
+	doc.Metadata = copyMetadata(doc.Metadata)
+	doc.Metadata[MetadataTTL] = int64(ttl.Seconds())
+	doc.Metadata[MetadataExpiresAt] = now.Add(ttl).Unix()
+	return doc
+}
+
+// ExpiresAt returns when a document written with a TTL expires
+func ExpiresAt(doc Document) (time.Time, bool) {
+	switch value := doc.Metadata[MetadataExpiresAt].(type) {
+	case int64:
+		return time.Unix(value, 0), true
+	case int:
+		return time.Unix(int64(value), 0), true
+	case float64:
+		return time.Unix(int64(value), 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// IsExpired reports whether a document's TTL has passed at the given time
+func IsExpired(doc Document, now time.Time) bool {
+	expiresAt, ok := ExpiresAt(doc)
+	return ok && !expiresAt.After(now)
+}
@@ -0,0 +1,374 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+)
+
+// NormalizeVector returns a copy of v scaled to unit L2 length. A zero vector is returned unchanged.
+func NormalizeVector(v []float64) []float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+
+	normalized := make([]float64, len(v))
+	if sum == 0 {
+		copy(normalized, v)
+		return normalized
+	}
+
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		normalized[i] = x / norm
+	}
+	return normalized
+}
+
+// NormalizeVector32 returns a copy of v scaled to unit L2 length, computed
+// without leaving float32. A zero vector is returned unchanged.
+func NormalizeVector32(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+
+	normalized := make([]float32, len(v))
+	if sum == 0 {
+		copy(normalized, v)
+		return normalized
+	}
+
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		normalized[i] = float32(float64(x) / norm)
+	}
+	return normalized
+}
+
+// ToFloat32 converts a vector to the float32 precision stored by the backends
+func ToFloat32(v []float64) []float32 {
+	if v == nil {
+		return nil
+	}
+
+	converted := make([]float32, len(v))
+	for i, x := range v {
+		converted[i] = float32(x)
+	}
+	return converted
+}
+
+// IsZeroVector reports whether every component of v is zero
+func IsZeroVector(v []float64) bool {
+	for _, x := range v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkVectorSize rejects a collection whose vector dimension, or that of a
+// named vector space, exceeds mcp.embedding.max_vector_size. Unset options
+// fall back to the configured dimension.
+func checkVectorSize(cfg *config.Config, options CollectionOptions) error {
+	limit := cfg.MCP.Embedding.MaxVectorSize
+	if limit <= 0 {
+		return nil
+	}
+	if size := options.WithDefaults(cfg).VectorSize; size > limit {
+		return fmt.Errorf("%w: %d dimensions exceeds mcp.embedding.max_vector_size of %d; check the embedding model and vector_size",
+			ErrVectorSizeTooLarge, size, limit)
+	}
+	for name, size := range cfg.MCP.Embedding.NamedVectors {
+		if size > limit {
+			return fmt.Errorf("%w: named vector '%s' has %d dimensions, exceeding mcp.embedding.max_vector_size of %d",
+				ErrVectorSizeTooLarge, name, size, limit)
+		}
+	}
+	return nil
+}
+
+// prepareDocuments returns copies of docs ready for insertion, rejecting vectors
+// whose length differs from the configured dimension and normalizing vectors
+// when the configured metric requires it. Named vectors must belong to a
+// configured vector space and match its dimension. Documents exceeding the
+// backend's limits are rejected. Each copy is stamped with the write time, and
+// nil metadata is replaced with an empty map so it is stored as {} rather than null.
+func prepareDocuments(cfg *config.Config, limits Limits, docs []Document) ([]Document, error) {
+	normalize := cfg.MCP.Embedding.RequiresNormalization()
+	dimension := cfg.MCP.Embedding.VectorSize
+
+	now := documentTime()
+	prepared := make([]Document, len(docs))
+	for i, doc := range docs {
+		if err := limits.Check(doc); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		doc = stampTimestamps(doc, now)
+		if doc.Metadata == nil {
+			doc.Metadata = map[string]interface{}{}
+		}
+		if length := doc.VectorDimension(); dimension > 0 && length > 0 && length != dimension {
+			return nil, fmt.Errorf("%w: document %d has %d dimensions, expected %d",
+				ErrDimensionMismatch, i, length, dimension)
+		}
+		if normalize && len(doc.Vector32) > 0 {
+			doc.Vector32 = NormalizeVector32(doc.Vector32)
+		} else if normalize && len(doc.Vector) > 0 {
+			doc.Vector = NormalizeVector(doc.Vector)
+		}
+
+		if len(doc.Vectors) > 0 {
+			vectors := make(map[string][]float64, len(doc.Vectors))
+			for name, vector := range doc.Vectors {
+				named, exists := cfg.MCP.Embedding.NamedVectors[name]
+				if !exists {
+					return nil, fmt.Errorf("%w: document %d has vector '%s'", ErrUnknownVector, i, name)
+				}
+				if len(vector) != named {
+					return nil, fmt.Errorf("%w: document %d vector '%s' has %d dimensions, expected %d",
+						ErrDimensionMismatch, i, name, len(vector), named)
+				}
+				if normalize {
+					vector = NormalizeVector(vector)
+				}
+				vectors[name] = vector
+			}
+			doc.Vectors = vectors
+		}
+
+		prepared[i] = doc
+	}
+	return prepared, nil
+}
+
+// namedVectorFields returns the configured named vector spaces as schema
+// fields, sorted by name so schemas are stable
+func namedVectorFields(cfg *config.Config) []map[string]interface{} {
+	names := make([]string, 0, len(cfg.MCP.Embedding.NamedVectors))
+	for name := range cfg.MCP.Embedding.NamedVectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		fields[i] = map[string]interface{}{
+			"name":      name,
+			"type":      "float_vector",
+			"dimension": cfg.MCP.Embedding.NamedVectors[name],
+		}
+	}
+	return fields
+}
+
+// vectorNameKey is the context key selecting the named vector space to search
+type vectorNameKey struct{}
+
+// WithVectorName returns a context that directs Search to the named vector
+// space instead of the default vector field
+func WithVectorName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, vectorNameKey{}, name)
+}
+
+// VectorNameFromContext returns the named vector space to search, or "" for the default vector
+func VectorNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(vectorNameKey{}).(string)
+	return name
+}
+
+// queryVectorKey is the context key holding a vector to search by
+type queryVectorKey struct{}
+
+// WithQueryVector returns a context that makes Search rank documents by
+// similarity to vector instead of embedding the query text
+func WithQueryVector(ctx context.Context, vector []float64) context.Context {
+	return context.WithValue(ctx, queryVectorKey{}, vector)
+}
+
+// QueryVectorFromContext returns the vector to search by, or nil to search by the query text
+func QueryVectorFromContext(ctx context.Context) []float64 {
+	vector, _ := ctx.Value(queryVectorKey{}).([]float64)
+	return vector
+}
+
+// validateQueryVector checks a query vector against the dimension of the
+// vector space being searched
+func validateQueryVector(cfg *config.Config, vectorName string, vector []float64) error {
+	if len(vector) == 0 {
+		return nil
+	}
+
+	dimension := cfg.MCP.Embedding.VectorSize
+	if vectorName != "" {
+		dimension = cfg.MCP.Embedding.NamedVectors[vectorName]
+	}
+	if dimension > 0 && len(vector) != dimension {
+		return fmt.Errorf("%w: query vector has %d dimensions, expected %d", ErrDimensionMismatch, len(vector), dimension)
+	}
+	return nil
+}
+
+// validateVectorName checks that a named vector space is configured
+func validateVectorName(cfg *config.Config, name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, exists := cfg.MCP.Embedding.NamedVectors[name]; !exists {
+		return fmt.Errorf("%w: '%s' is not configured under mcp.embedding.named_vectors", ErrUnknownVector, name)
+	}
+	return nil
+}
+
+// FilterByScore drops results scoring below minScore, preserving their order
+func FilterByScore(results []SearchResult, minScore float64) []SearchResult {
+	filtered := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Score >= minScore {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// CollectionDimension returns the default vector dimension declared in a
+// collection's schema, as reported by GetCollectionInfo. It reports false when
+// the schema declares none, as for a Weaviate class whose vectorizer decides.
+func CollectionDimension(info map[string]interface{}) (int, bool) {
+	schema, ok := info["schema"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	if dimension, ok := schema["dimension"]; ok && isNumber(dimension) {
+		return int(toFloat(dimension)), toFloat(dimension) > 0
+	}
+
+	fields, _ := schema["fields"].([]map[string]interface{})
+	for _, field := range fields {
+		if field["name"] == FieldVector && isNumber(field["dimension"]) {
+			dimension := int(toFloat(field["dimension"]))
+			return dimension, dimension > 0
+		}
+	}
+	return 0, false
+}
+
+// CollectionMetric returns the similarity metric declared in a collection's
+// schema, as reported by GetCollectionInfo. It reports false when the schema
+// declares none.
+func CollectionMetric(info map[string]interface{}) (string, bool) {
+	schema, ok := info["schema"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	if metric, ok := schema["metric_type"].(string); ok && metric != "" {
+		return strings.ToLower(metric), true
+	}
+
+	// Weaviate declares its distance in the vector index config
+	if indexConfig, ok := schema["vectorIndexConfig"].(map[string]interface{}); ok {
+		switch indexConfig["distance"] {
+		case "cosine":
+			return config.MetricCosine, true
+		case "dot":
+			return config.MetricIP, true
+		case "l2-squared":
+			return config.MetricL2, true
+		}
+	}
+	return "", false
+}
+
+// EmbeddingModel identifies the embedding that produces a collection's vectors.
+// Vectors from different models live in different spaces and must not be mixed.
+type EmbeddingModel struct {
+	Provider  string `json:"provider"`
+	Model     string `json:"model"`
+	Dimension int    `json:"dimension"`
+}
+
+func (e EmbeddingModel) String() string {
+	return fmt.Sprintf("%s/%s (%d dimensions)", e.Provider, e.Model, e.Dimension)
+}
+
+// ActiveEmbeddingModel returns the embedding configured under mcp.embedding
+func ActiveEmbeddingModel(cfg *config.Config) EmbeddingModel {
+	return EmbeddingModel{
+		Provider:  cfg.MCP.Embedding.Provider,
+		Model:     cfg.MCP.Embedding.Model,
+		Dimension: cfg.MCP.Embedding.VectorSize,
+	}
+}
+
+// recordEmbeddingModel stores the active embedding in a collection schema
+// under mcp.embedding.model_field, unless that is empty
+func recordEmbeddingModel(schema map[string]interface{}, cfg *config.Config) {
+	if field := cfg.MCP.Embedding.ModelField; field != "" {
+		model := ActiveEmbeddingModel(cfg)
+		schema[field] = map[string]interface{}{
+			"provider":  model.Provider,
+			"model":     model.Model,
+			"dimension": model.Dimension,
+		}
+	}
+}
+
+// CollectionEmbeddingModel returns the embedding recorded under field in a
+// collection's schema, as reported by GetCollectionInfo. It reports false when
+// none is recorded, as for a collection set up before models were recorded.
+func CollectionEmbeddingModel(info map[string]interface{}, field string) (EmbeddingModel, bool) {
+	schema, ok := info["schema"].(map[string]interface{})
+	if !ok || field == "" {
+		return EmbeddingModel{}, false
+	}
+	recorded, ok := schema[field].(map[string]interface{})
+	if !ok {
+		return EmbeddingModel{}, false
+	}
+
+	model := EmbeddingModel{}
+	model.Provider, _ = recorded["provider"].(string)
+	model.Model, _ = recorded["model"].(string)
+	if dimension := recorded["dimension"]; isNumber(dimension) {
+		model.Dimension = int(toFloat(dimension))
+	}
+	return model, true
+}
+
+// Similarity scores two equal-length vectors under a metric the way the
+// backends rank search results: cosine similarity, inner product, or squared
+// L2 distance. For l2 a lower score is closer; for the others a higher one is.
+func Similarity(metric string, a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("%w: vectors have %d and %d dimensions", ErrDimensionMismatch, len(a), len(b))
+	}
+
+	switch strings.ToLower(metric) {
+	case config.MetricCosine, "":
+		return vectorCosine(a, b), nil
+	case config.MetricIP:
+		var dot float64
+		for i := range a {
+			dot += a[i] * b[i]
+		}
+		return dot, nil
+	case config.MetricL2:
+		var distance float64
+		for i := range a {
+			d := a[i] - b[i]
+			distance += d * d
+		}
+		return distance, nil
+	default:
+		return 0, invalidRequestf("unsupported metric: %s", metric)
+	}
+}
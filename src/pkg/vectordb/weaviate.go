@@ -3,6 +3,10 @@ package vectordb
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
@@ -14,20 +18,38 @@ type WeaviateDatabase struct {
 	config         *config.Config
 	logger         *zap.Logger
 	collectionName string
+	authHeaders    map[string]string
 	client         WeaviateClient
+
+	// metadataProperties are the nested properties declared for metadata by
+	// mcp.vector_db.weaviate.metadata_schema
+	metadataProperties []map[string]interface{}
+
+	// serverVersion is the Weaviate server version, pinned by
+	// mcp.vector_db.weaviate.server_version or detected on first setup
+	serverVersion *weaviateVersion
+	versionMutex  sync.Mutex
 }
 
 // WeaviateClient defines the interface for Weaviate client operations
 type WeaviateClient interface {
 	Connect(ctx context.Context) error
+	Meta(ctx context.Context) (map[string]interface{}, error)
 	CreateCollection(ctx context.Context, name string, schema map[string]interface{}) error
 	Insert(ctx context.Context, collectionName string, documents []Document) error
 	Search(ctx context.Context, collectionName string, query string, limit int) ([]SearchResult, error)
 	Query(ctx context.Context, collectionName string, query string, limit int) (interface{}, error)
 	ListDocuments(ctx context.Context, collectionName string, limit, offset int) ([]Document, error)
+	GetDocument(ctx context.Context, collectionName string, documentID string) (Document, error)
+	UpdateDocument(ctx context.Context, collectionName string, doc Document) error
 	CountDocuments(ctx context.Context, collectionName string) (int, error)
+	// EstimateDocuments returns the object count the nodes endpoint reports for a class
+	EstimateDocuments(ctx context.Context, collectionName string) (int, error)
 	DeleteDocument(ctx context.Context, collectionName string, documentID string) error
-	DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) error
+	DeleteDocuments(ctx context.Context, collectionName string, documentIDs []string) (DeleteReport, error)
+	DeleteExpired(ctx context.Context, collectionName string, before time.Time) (int, error)
+	DeleteByFilter(ctx context.Context, collectionName string, filter MetadataFilter) (int, error)
+	Truncate(ctx context.Context, collectionName string) (int, error)
 	ListCollections(ctx context.Context) ([]string, error)
 	GetCollectionInfo(ctx context.Context, collectionName string) (map[string]interface{}, error)
 	DeleteCollection(ctx context.Context, collectionName string) error
@@ -38,16 +60,48 @@ type WeaviateClient interface {
 func NewWeaviateDatabase(collectionName string, cfg *config.Config) (*WeaviateDatabase, error) {
 	logger, _ := zap.NewProduction()
 
+	// Only send auth headers when credentials are configured
+	authHeaders, err := cfg.MCP.VectorDB.Weaviate.AuthHeaders()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Weaviate auth configuration: %w", err)
+	}
+
+	metadataProperties, err := weaviateNestedProperties(cfg.MCP.VectorDB.Weaviate.MetadataSchema, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Weaviate metadata schema: %w", err)
+	}
+
+	var serverVersion *weaviateVersion
+	if pinned := cfg.MCP.VectorDB.Weaviate.ServerVersion; pinned != "" {
+		version, err := parseWeaviateVersion(pinned)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Weaviate server_version: %w", err)
+		}
+		serverVersion = &version
+	}
+
 	db := &WeaviateDatabase{
 		config:         cfg,
 		logger:         logger,
 		collectionName: collectionName,
+		authHeaders:    authHeaders,
 		client:         NewMockWeaviateClient(), // Use mock for now
+
+		metadataProperties: metadataProperties,
+		serverVersion:      serverVersion,
 	}
 
+	logger.Debug("Configured Weaviate client",
+		zap.String("auth_type", cfg.MCP.VectorDB.Weaviate.ResolvedAuthType()))
+
 	return db, nil
 }
 
+// AuthHeaders returns the authentication headers sent with Weaviate requests
+func (w *WeaviateDatabase) AuthHeaders() map[string]string {
+	return w.authHeaders
+}
+
 // Type returns the database type
 func (w *WeaviateDatabase) Type() string {
 	return "weaviate"
@@ -60,39 +114,242 @@ func (w *WeaviateDatabase) CollectionName() string {
 
 // Setup initializes the database and creates collections
 func (w *WeaviateDatabase) Setup(ctx context.Context, embedding string) error {
+	if err := ValidateCollectionName(w.Type(), w.collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(w.config, CollectionOptions{Embedding: embedding}); err != nil {
+		return err
+	}
+
+	if err := w.client.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Weaviate: %w", err)
+	}
+
+	w.detectServerVersion(ctx)
+	schema := w.collectionSchema(w.collectionName, CollectionOptions{Embedding: embedding})
+	if err := w.client.CreateCollection(ctx, w.collectionName, schema); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	w.logger.Info("Set up Weaviate collection",
+		zap.String("collection", w.collectionName),
+		zap.String("embedding", embedding))
+
+	return nil
+}
+
+// CreateCollection creates an additional class with the document schema
+func (w *WeaviateDatabase) CreateCollection(ctx context.Context, collectionName string, options CollectionOptions) error {
+	if err := ValidateCollectionName(w.Type(), collectionName); err != nil {
+		return err
+	}
+	if err := checkVectorSize(w.config, options); err != nil {
+		return err
+	}
+
 	if err := w.client.Connect(ctx); err != nil {
 		return fmt.Errorf("failed to connect to Weaviate: %w", err)
 	}
 
-	// Create collection schema
+	collections, err := w.client.ListCollections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collections in Weaviate: %w", err)
+	}
+	if slices.Contains(collections, collectionName) {
+		return fmt.Errorf("%w: '%s'", ErrCollectionExists, collectionName)
+	}
+
+	if options.Embedding == "" {
+		options.Embedding = "default"
+	}
+	w.detectServerVersion(ctx)
+	if err := w.client.CreateCollection(ctx, collectionName, w.collectionSchema(collectionName, options)); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	w.logger.Info("Created Weaviate collection",
+		zap.String("collection", collectionName),
+		zap.String("embedding", options.Embedding))
+
+	return nil
+}
+
+// collectionSchema returns the Weaviate class definition for a document
+// collection. The distance is only set when a metric is given, leaving the
+// Weaviate default otherwise; the vector size comes from the vectorizer.
+// Metadata fields declared in the metadata schema become nested properties.
+func (w *WeaviateDatabase) collectionSchema(collectionName string, options CollectionOptions) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"name":     "metadata",
+		"dataType": []string{"object"},
+	}
+	if len(w.metadataProperties) > 0 {
+		metadata["nestedProperties"] = w.metadataProperties
+	}
+
 	schema := map[string]interface{}{
-		"class": w.collectionName,
+		"class": collectionName,
 		"properties": []map[string]interface{}{
+			w.urlProperty(),
 			{
-				"name":     "url",
-				"dataType": []string{"string"},
+				"name":     "text",
+				"dataType": []string{"text"},
 			},
 			{
-				"name":     "text",
+				// The preprocessed text that was embedded, when it differs from text
+				"name":     "embedding_text",
 				"dataType": []string{"text"},
 			},
+			metadata,
 			{
-				"name":     "metadata",
-				"dataType": []string{"object"},
+				"name":     FieldCreatedAt,
+				"dataType": []string{"date"},
+			},
+			{
+				"name":     FieldUpdatedAt,
+				"dataType": []string{"date"},
 			},
 		},
-		"vectorizer": embedding,
+		"vectorizer": options.Embedding,
 	}
 
-	if err := w.client.CreateCollection(ctx, w.collectionName, schema); err != nil {
-		return fmt.Errorf("failed to create collection: %w", err)
+	if options.Metric != "" {
+		schema["vectorIndexConfig"] = map[string]interface{}{
+			"distance": weaviateDistance(options.Metric),
+		}
 	}
 
-	w.logger.Info("Set up Weaviate collection",
-		zap.String("collection", w.collectionName),
-		zap.String("embedding", embedding))
+	// Named vector spaces are declared as Weaviate named vectors
+	if fields := namedVectorFields(w.config); len(fields) > 0 {
+		vectorConfig := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			vectorConfig[field["name"].(string)] = map[string]interface{}{
+				"vectorizer":      map[string]interface{}{"none": map[string]interface{}{}},
+				"vectorIndexType": "hnsw",
+				"dimension":       field["dimension"],
+			}
+		}
+		schema["vectorConfig"] = vectorConfig
+	}
 
-	return nil
+	recordEmbeddingModel(schema, w.config)
+	return schema
+}
+
+// weaviateVersion is a Weaviate server release
+type weaviateVersion struct {
+	major, minor, patch int
+}
+
+// weaviateTextOnlyVersion is the release that deprecated the string data type
+// in favor of text with a tokenization setting
+var weaviateTextOnlyVersion = weaviateVersion{major: 1, minor: 19}
+
+// parseWeaviateVersion parses a release such as 1.24.5, v1.19, or 1.25.0-rc.1
+func parseWeaviateVersion(version string) (weaviateVersion, error) {
+	core, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), "-")
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return weaviateVersion{}, fmt.Errorf("'%s' is not a version such as 1.24.5", version)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return weaviateVersion{}, fmt.Errorf("'%s' is not a version such as 1.24.5", version)
+		}
+		numbers[i] = n
+	}
+	return weaviateVersion{major: numbers[0], minor: numbers[1], patch: numbers[2]}, nil
+}
+
+// atLeast reports whether v is the same release as other or a later one
+func (v weaviateVersion) atLeast(other weaviateVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+	if v.minor != other.minor {
+		return v.minor > other.minor
+	}
+	return v.patch >= other.patch
+}
+
+func (v weaviateVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+}
+
+// detectServerVersion reads the server version from the meta endpoint unless
+// it is pinned or already known. A failed lookup is logged and retried on the
+// next setup; until then the current data types are assumed.
+func (w *WeaviateDatabase) detectServerVersion(ctx context.Context) {
+	w.versionMutex.Lock()
+	defer w.versionMutex.Unlock()
+
+	if w.serverVersion != nil {
+		return
+	}
+
+	meta, err := w.client.Meta(ctx)
+	if err != nil {
+		w.logger.Warn("Failed to detect Weaviate server version; set server_version to pin it",
+			zap.Error(err))
+		return
+	}
+	reported, _ := meta["version"].(string)
+	version, err := parseWeaviateVersion(reported)
+	if err != nil {
+		w.logger.Warn("Weaviate reported an unrecognized server version; set server_version to pin it",
+			zap.String("version", reported))
+		return
+	}
+
+	w.serverVersion = &version
+	w.logger.Info("Detected Weaviate server version", zap.String("version", version.String()))
+}
+
+// ServerVersion returns the pinned or detected Weaviate server version, or an
+// empty string when it is not known yet
+func (w *WeaviateDatabase) ServerVersion() string {
+	w.versionMutex.Lock()
+	defer w.versionMutex.Unlock()
+
+	if w.serverVersion == nil {
+		return ""
+	}
+	return w.serverVersion.String()
+}
+
+// urlProperty declares the url property. Servers before 1.19 take the string
+// data type; later ones deprecate it, so url is declared as text with field
+// tokenization, which likewise indexes the whole url as one token.
+func (w *WeaviateDatabase) urlProperty() map[string]interface{} {
+	w.versionMutex.Lock()
+	defer w.versionMutex.Unlock()
+
+	if w.serverVersion != nil && !w.serverVersion.atLeast(weaviateTextOnlyVersion) {
+		return map[string]interface{}{
+			"name":     "url",
+			"dataType": []string{"string"},
+		}
+	}
+	return map[string]interface{}{
+		"name":         "url",
+		"dataType":     []string{"text"},
+		"tokenization": "field",
+	}
+}
+
+// weaviateDistance maps a configured metric to the Weaviate distance name
+func weaviateDistance(metric string) string {
+	switch strings.ToLower(metric) {
+	case config.MetricIP:
+		return "dot"
+	case config.MetricL2:
+		return "l2-squared"
+	default:
+		return "cosine"
+	}
 }
 
 // WriteDocument writes a single document to the database
@@ -112,6 +369,16 @@ func (w *WeaviateDatabase) WriteDocument(ctx context.Context, doc Document) (Wri
 func (w *WeaviateDatabase) WriteDocuments(ctx context.Context, docs []Document) (WriteStats, error) {
 	start := time.Now()
 
+	docs, err := prepareDocuments(w.config, BackendLimits(w.Type(), w.config), docs)
+	if err != nil {
+		return WriteStats{}, err
+	}
+	for i, doc := range docs {
+		if err := w.checkMetadata(doc); err != nil {
+			return WriteStats{}, fmt.Errorf("document %d: %w", i, err)
+		}
+	}
+
 	if err := w.client.Insert(ctx, w.collectionName, docs); err != nil {
 		return WriteStats{}, fmt.Errorf("failed to insert documents: %w", err)
 	}
@@ -129,6 +396,12 @@ func (w *WeaviateDatabase) WriteDocuments(ctx context.Context, docs []Document)
 	}, nil
 }
 
+// checkMetadata rejects a document whose metadata does not match the declared
+// metadata schema, which Weaviate would refuse to store
+func (w *WeaviateDatabase) checkMetadata(doc Document) error {
+	return checkMetadataSchema(w.config.MCP.VectorDB.Weaviate.MetadataSchema, doc.Metadata, "")
+}
+
 // Query performs a natural language query on the database
 func (w *WeaviateDatabase) Query(ctx context.Context, query string, limit int, collectionName string) (interface{}, error) {
 	if collectionName == "" {
@@ -154,7 +427,18 @@ func (w *WeaviateDatabase) Search(ctx context.Context, query string, limit int,
 		collectionName = w.collectionName
 	}
 
-	results, err := w.client.Search(ctx, collectionName, query, limit)
+	vectorName := VectorNameFromContext(ctx)
+	if err := validateVectorName(w.config, vectorName); err != nil {
+		return nil, err
+	}
+	queryVector := QueryVectorFromContext(ctx)
+	if err := validateQueryVector(w.config, vectorName, queryVector); err != nil {
+		return nil, err
+	}
+
+	results, strategy, err := filteredSearch(ctx, limit, FilterStrategyPre, func(ctx context.Context, limit int) ([]SearchResult, error) {
+		return w.client.Search(ctx, collectionName, query, limit)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search Weaviate: %w", err)
 	}
@@ -162,6 +446,12 @@ func (w *WeaviateDatabase) Search(ctx context.Context, query string, limit int,
 	w.logger.Info("Executed search on Weaviate",
 		zap.String("collection", collectionName),
 		zap.String("query", query),
+		zap.String("vector_name", vectorName),
+		zap.String("selection", WeaviateFieldSelection(FieldsFromContext(ctx))),
+		zap.Any("where", MetadataFilterFromContext(ctx).WeaviateWhere()),
+		zap.String("filter_strategy", strategy),
+		zap.Bool("by_vector", queryVector != nil),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
 		zap.Int("limit", limit),
 		zap.Int("results", len(results)))
 
@@ -177,6 +467,9 @@ func (w *WeaviateDatabase) ListDocuments(ctx context.Context, limit, offset int)
 
 	w.logger.Info("Listed documents from Weaviate",
 		zap.String("collection", w.collectionName),
+		zap.String("selection", WeaviateFieldSelection(FieldsFromContext(ctx))),
+		zap.String("consistency_level", WeaviateConsistencyLevel(ConsistencyLevelFromContext(ctx))),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
 		zap.Int("count", len(documents)))
@@ -184,6 +477,33 @@ func (w *WeaviateDatabase) ListDocuments(ctx context.Context, limit, offset int)
 	return documents, nil
 }
 
+// GetDocument returns a single document by ID
+func (w *WeaviateDatabase) GetDocument(ctx context.Context, documentID string) (Document, error) {
+	doc, err := w.client.GetDocument(ctx, w.collectionName, documentID)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to get document from Weaviate: %w", err)
+	}
+
+	return doc, nil
+}
+
+// UpdateDocument replaces a stored document's URL, text, and metadata in place
+func (w *WeaviateDatabase) UpdateDocument(ctx context.Context, doc Document) error {
+	if err := w.checkMetadata(doc); err != nil {
+		return err
+	}
+	doc = touchDocument(doc)
+	if err := w.client.UpdateDocument(ctx, w.collectionName, doc); err != nil {
+		return fmt.Errorf("failed to update document in Weaviate: %w", err)
+	}
+
+	w.logger.Info("Updated document in Weaviate",
+		zap.String("collection", w.collectionName),
+		zap.String("document_id", doc.ID))
+
+	return nil
+}
+
 // CountDocuments returns the count of documents in the database
 func (w *WeaviateDatabase) CountDocuments(ctx context.Context) (int, error) {
 	count, err := w.client.CountDocuments(ctx, w.collectionName)
@@ -192,6 +512,22 @@ func (w *WeaviateDatabase) CountDocuments(ctx context.Context) (int, error) {
 	}
 
 	w.logger.Info("Counted documents in Weaviate",
+		zap.String("collection", w.collectionName),
+		zap.Bool("include_deleted", TombstonesFromContext(ctx)),
+		zap.Int("count", count))
+
+	return count, nil
+}
+
+// EstimateDocuments returns the object count Weaviate's node statistics
+// report for the class, which is fast but updated asynchronously
+func (w *WeaviateDatabase) EstimateDocuments(ctx context.Context) (int, error) {
+	count, err := w.client.EstimateDocuments(ctx, w.collectionName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get node statistics from Weaviate: %w", err)
+	}
+
+	w.logger.Info("Estimated documents in Weaviate",
 		zap.String("collection", w.collectionName),
 		zap.Int("count", count))
 
@@ -211,17 +547,73 @@ func (w *WeaviateDatabase) DeleteDocument(ctx context.Context, documentID string
 	return nil
 }
 
-// DeleteDocuments deletes multiple documents by IDs
-func (w *WeaviateDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) error {
-	if err := w.client.DeleteDocuments(ctx, w.collectionName, documentIDs); err != nil {
-		return fmt.Errorf("failed to delete documents from Weaviate: %w", err)
+// DeleteDocuments deletes multiple documents by IDs, reporting per-document failures
+func (w *WeaviateDatabase) DeleteDocuments(ctx context.Context, documentIDs []string) (DeleteReport, error) {
+	report, err := w.client.DeleteDocuments(ctx, w.collectionName, documentIDs)
+	if err != nil {
+		return report, fmt.Errorf("failed to delete documents from Weaviate: %w", err)
 	}
 
 	w.logger.Info("Deleted documents from Weaviate",
 		zap.String("collection", w.collectionName),
-		zap.Int("count", len(documentIDs)))
+		zap.Int("count", len(report.Deleted)),
+		zap.Int("failed", len(report.Failed)))
 
-	return nil
+	return report, nil
+}
+
+// DeleteExpired deletes documents whose TTL expired at or before the given time,
+// using a `where` filter with expires_at LessThanEqual before
+func (w *WeaviateDatabase) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := w.client.DeleteExpired(ctx, w.collectionName, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired documents from Weaviate: %w", err)
+	}
+
+	w.logger.Info("Deleted expired documents from Weaviate",
+		zap.String("collection", w.collectionName),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// DeleteByFilter deletes documents matching a metadata filter with a batch
+// delete using the filter as its where clause
+func (w *WeaviateDatabase) DeleteByFilter(ctx context.Context, filter MetadataFilter) (int, error) {
+	if len(filter) == 0 {
+		return 0, fmt.Errorf("%w: refusing to delete without a filter", ErrInvalidFilter)
+	}
+
+	deleted, err := w.client.DeleteByFilter(ctx, w.collectionName, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete documents by filter from Weaviate: %w", err)
+	}
+
+	w.logger.Info("Deleted documents by filter from Weaviate",
+		zap.String("collection", w.collectionName),
+		zap.Any("where", filter.WeaviateWhere()),
+		zap.Int("count", deleted))
+
+	return deleted, nil
+}
+
+// TruncateCollection batch deletes every object in the class, so the class
+// keeps its schema and index
+func (w *WeaviateDatabase) TruncateCollection(ctx context.Context, collectionName string) (int, error) {
+	if collectionName == "" {
+		collectionName = w.collectionName
+	}
+
+	deleted, err := w.client.Truncate(ctx, collectionName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to truncate Weaviate class: %w", err)
+	}
+
+	w.logger.Info("Truncated Weaviate class",
+		zap.String("collection", collectionName),
+		zap.Int("count", deleted))
+
+	return deleted, nil
 }
 
 // ListCollections lists all collections in the database
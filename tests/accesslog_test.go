@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMCPAccessLogRecordsRequests(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := server.AccessLog(newTestServer(t).Handler(), zap.New(core))
+
+	body := `{"name": "nope", "arguments": {}}`
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call", strings.NewReader(body))
+	request.Header.Set("X-Request-ID", "req-1")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	entries := logs.FilterMessage("HTTP request").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, http.MethodPost, fields["method"])
+	assert.Equal(t, "/mcp/tools/call", fields["path"])
+	assert.EqualValues(t, http.StatusNotFound, fields["status"])
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Contains(t, fields, "duration")
+}
+
+func TestMCPAccessLogAssignsRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := server.AccessLog(newTestServer(t).Handler(), zap.New(core))
+
+	body := `{"name": "list_databases", "arguments": {}}`
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	entries := logs.FilterMessage("HTTP request").All()
+	require.Len(t, entries, 1)
+	requestID := entries[0].ContextMap()["request_id"]
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, recorder.Header().Get("X-Request-ID"))
+	assert.EqualValues(t, http.StatusOK, entries[0].ContextMap()["status"])
+}
+
+func TestMCPAccessLogSkipsHealth(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := server.AccessLog(newTestServer(t).Handler(), zap.New(core))
+
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, logs.FilterMessage("HTTP request").All())
+}
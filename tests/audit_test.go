@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditConfig(audit config.AuditConfig) *config.Config {
+	return &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Audit:       audit,
+		},
+	}
+}
+
+func TestMCPAuditLogRecordsMutatingCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	server := newTestServerWithConfig(t, auditConfig(config.AuditConfig{
+		Enabled:    true,
+		Sink:       config.AuditSinkFile,
+		Path:       path,
+		MaxEntries: 100,
+	}))
+	defer server.Close()
+
+	ctx := mcp.WithRequestID(context.Background(), "req-1")
+	_, err := server.CallTool(ctx, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	require.NoError(t, err)
+	_, err = server.CallTool(ctx, "list_databases", map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = server.CallTool(ctx, "delete_document", map[string]interface{}{"db_name": "missing", "document_id": "doc-1"})
+	require.Error(t, err)
+
+	result := callTool(t, server, "get_audit_log", map[string]interface{}{}).(map[string]interface{})
+	require.Equal(t, 2, result["count"], "reads should not be audited")
+
+	entries := result["entries"].([]mcp.AuditEntry)
+	assert.Equal(t, "delete_document", entries[0].Tool)
+	assert.Equal(t, "error", entries[0].Status)
+	assert.Equal(t, []string{"doc-1"}, entries[0].DocumentIDs)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+	assert.Equal(t, "create_vector_database", entries[1].Tool)
+	assert.Equal(t, "success", entries[1].Status)
+	assert.Equal(t, "docs", entries[1].DBName)
+
+	result = callTool(t, server, "get_audit_log", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, result["count"])
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2)
+}
+
+func TestMCPAuditLogDisabled(t *testing.T) {
+	server := newTestServer(t)
+
+	_, err := server.Tools["get_audit_log"].Handler(context.Background(), map[string]interface{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "audit logging is not enabled")
+}
+
+func TestConfigAuditValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Audit = config.AuditConfig{Enabled: true, Sink: "postgres", MaxEntries: 10}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported audit sink")
+
+	cfg.MCP.Audit.Sink = config.AuditSinkFile
+	cfg.MCP.Audit.Path = ""
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMCPWriteWithoutSetupFailsByDefault(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	_, err := server.Tools["write_document"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	assert.ErrorIs(t, err, vectordb.ErrCollectionNotFound)
+}
+
+func TestMCPAutoSetupOnFirstWrite(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	server, err := mcp.NewServer(&config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			VectorDB:    config.VectorDBConfig{AutoSetup: true},
+		},
+	}, zap.New(core))
+	require.NoError(t, err)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+	assert.Equal(t, 1, logs.FilterMessage("Set up collection automatically on first write").Len())
+
+	// Later writes find the collection and do not set it up again
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/2",
+		"text":    "world",
+	})
+	assert.Equal(t, 1, logs.FilterMessage("Set up collection automatically on first write").Len())
+}
+
+func TestMCPAutoSetupPerWriteArgument(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	result := callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name":    "docs",
+		"auto_setup": true,
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/1", "text": "one"},
+			map[string]interface{}{"url": "https://example.com/2", "text": "two"},
+		},
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["write_stats"].(vectordb.WriteStats).DocumentsWritten)
+
+	// The argument also opts out when the config enables it
+	server = newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			VectorDB:    config.VectorDBConfig{AutoSetup: true},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	assert.Equal(t, http.StatusNotFound, postToolCall(server,
+		`{"name": "write_document", "arguments": {"db_name": "docs", "url": "https://example.com/1", "text": "x", "auto_setup": false}}`))
+}
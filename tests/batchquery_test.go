@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPBatchQueryAlignsResults(t *testing.T) {
+	server := newSeededServer(t, "alpha document", "beta document", "gamma document")
+
+	result := callTool(t, server, "batch_query", map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{
+			map[string]interface{}{"query": "gamma", "limit": float64(1)},
+			map[string]interface{}{"query": "document", "limit": float64(2)},
+			map[string]interface{}{"query": "alpha"},
+		},
+	}).(map[string]interface{})
+	assert.Equal(t, 3, result["count"])
+
+	sets := result["results"].([]map[string]interface{})
+	require.Len(t, sets, 3)
+
+	assert.Equal(t, "gamma", sets[0]["query"])
+	assert.Equal(t, 1, sets[0]["count"])
+	first := sets[0]["results"].([]vectordb.SearchResult)
+	assert.Equal(t, "gamma document", first[0].Document.Text)
+
+	assert.Equal(t, "document", sets[1]["query"])
+	assert.Equal(t, 2, sets[1]["count"])
+
+	assert.Equal(t, "alpha", sets[2]["query"])
+	third := sets[2]["results"].([]vectordb.SearchResult)
+	require.NotEmpty(t, third)
+	assert.Equal(t, "alpha document", third[0].Document.Text)
+}
+
+func TestMCPBatchQueryValidatesEveryQuery(t *testing.T) {
+	server := newSeededServer(t, "alpha document")
+	tool := server.Tools["batch_query"]
+
+	_, err := tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "non-empty array")
+
+	_, err = tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{
+			map[string]interface{}{"query": "alpha"},
+			map[string]interface{}{"query": "beta", "limit": float64(-1)},
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queries[1]")
+
+	_, err = tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{map[string]interface{}{"limit": float64(1)}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "queries[0]: query is required")
+
+	tooMany := make([]interface{}, 51)
+	for i := range tooMany {
+		tooMany[i] = map[string]interface{}{"query": "alpha"}
+	}
+	_, err = tool.Handler(context.Background(), map[string]interface{}{"db_name": "docs", "queries": tooMany})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at most 50")
+}
+
+func TestMCPBatchQuerySharesEmbeddings(t *testing.T) {
+	var mu sync.Mutex
+	var inputs [][]string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		inputs = append(inputs, req.Input)
+		mu.Unlock()
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": []float64{1, float64(i), 0, 0}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+
+	cfg := embeddingWarmupConfig(endpoint.URL, false)
+	cfg.MCP.Embedding.Warmup = false
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/0",
+		"text":    "alpha document",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	})
+
+	result := callTool(t, server, "batch_query", map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{
+			map[string]interface{}{"query": "alpha"},
+			map[string]interface{}{"query": "beta", "limit": float64(1)},
+			map[string]interface{}{"query": "alpha", "limit": float64(1)},
+		},
+	}).(map[string]interface{})
+
+	require.Len(t, inputs, 1, "the batch should make a single embedding call")
+	assert.Equal(t, []string{"alpha", "beta"}, inputs[0])
+
+	sets := result["results"].([]map[string]interface{})
+	require.Len(t, sets, 3)
+	for _, set := range sets {
+		assert.NotContains(t, set, "error")
+		assert.Equal(t, 1, set["count"])
+	}
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyDatabase fails CountDocuments while down is set
+type flakyDatabase struct {
+	vectordb.VectorDatabase
+	down  bool
+	calls int
+}
+
+func (f *flakyDatabase) CountDocuments(ctx context.Context) (int, error) {
+	f.calls++
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	return 42, nil
+}
+
+func TestVectorDBCircuitBreakerOpensAndRecovers(t *testing.T) {
+	inner, err := vectordb.NewMilvusDatabase("docs", &config.Config{})
+	require.NoError(t, err)
+	flaky := &flakyDatabase{VectorDatabase: inner, down: true}
+
+	breaker := vectordb.NewCircuitBreakerDatabase(flaky, config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 2,
+		ResetTimeout:     20 * time.Millisecond,
+		MaxResetTimeout:  time.Second,
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err = breaker.CountDocuments(ctx)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, vectordb.ErrBackendUnavailable))
+	}
+	assert.Equal(t, vectordb.CircuitOpen, breaker.State())
+
+	_, err = breaker.CountDocuments(ctx)
+	assert.True(t, errors.Is(err, vectordb.ErrBackendUnavailable))
+	assert.Equal(t, 2, flaky.calls, "open circuit should not reach the backend")
+
+	// A failed probe reopens the circuit with a longer reset timeout
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, vectordb.CircuitHalfOpen, breaker.State())
+	_, err = breaker.CountDocuments(ctx)
+	assert.False(t, errors.Is(err, vectordb.ErrBackendUnavailable))
+	assert.Equal(t, vectordb.CircuitOpen, breaker.State())
+	assert.Equal(t, "40ms", breaker.Stats()["reset_timeout"])
+
+	// A successful probe closes the circuit
+	flaky.down = false
+	time.Sleep(45 * time.Millisecond)
+	count, err := breaker.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+}
+
+// newSetUpBreaker sets up inner and returns it behind a breaker that opens
+// after two failures
+func newSetUpBreaker(t *testing.T, inner vectordb.VectorDatabase) *vectordb.CircuitBreakerDatabase {
+	require.NoError(t, inner.Setup(context.Background(), "default"))
+	return vectordb.NewCircuitBreakerDatabase(inner, config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 2,
+		ResetTimeout:     time.Minute,
+	})
+}
+
+func TestVectorDBCircuitBreakerIgnoresOversizedDocuments(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.MCP.VectorDB.Milvus.Limits = config.DocumentLimitsConfig{MaxTextBytes: 10}
+	inner, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	breaker := newSetUpBreaker(t, inner)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := breaker.WriteDocument(ctx, vectordb.Document{URL: "https://example.com/big", Text: "this text is too long"})
+		assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	}
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+
+	_, err = breaker.WriteDocument(ctx, vectordb.Document{URL: "https://example.com/1", Text: "short"})
+	assert.NoError(t, err)
+}
+
+func TestVectorDBCircuitBreakerIgnoresMetadataSchemaErrors(t *testing.T) {
+	inner, err := vectordb.NewWeaviateDatabase("Docs", weaviateSchemaConfig())
+	require.NoError(t, err)
+	breaker := newSetUpBreaker(t, inner)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := breaker.WriteDocument(ctx, vectordb.Document{
+			URL:      "https://example.com/1",
+			Text:     "paper",
+			Metadata: map[string]interface{}{"year": "last year"},
+		})
+		assert.ErrorIs(t, err, vectordb.ErrMetadataSchema)
+	}
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+
+	_, err = breaker.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/1",
+		Text:     "paper",
+		Metadata: map[string]interface{}{"year": 2024},
+	})
+	assert.NoError(t, err)
+}
+
+func TestVectorDBCircuitBreakerIgnoresOversizedVectors(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			Embedding: config.EmbeddingConfig{VectorSize: 4, MaxVectorSize: 16384},
+		},
+	}
+	inner, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	breaker := newSetUpBreaker(t, inner)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		err := breaker.CreateCollection(ctx, "wide", vectordb.CollectionOptions{VectorSize: 20000})
+		assert.ErrorIs(t, err, vectordb.ErrVectorSizeTooLarge)
+	}
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+	assert.NoError(t, breaker.CreateCollection(ctx, "narrow", vectordb.CollectionOptions{VectorSize: 8}))
+}
+
+func TestVectorDBCircuitBreakerIgnoresInvalidCollectionNames(t *testing.T) {
+	inner, err := vectordb.NewMilvusDatabase("docs", &config.Config{})
+	require.NoError(t, err)
+	breaker := newSetUpBreaker(t, inner)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		err := breaker.CreateCollection(ctx, "bad-name", vectordb.CollectionOptions{})
+		assert.ErrorIs(t, err, vectordb.ErrInvalidRequest)
+		assert.ErrorContains(t, err, "invalid Milvus collection name 'bad-name'")
+	}
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+	assert.NoError(t, breaker.CreateCollection(ctx, "good_name", vectordb.CollectionOptions{}))
+}
+
+func TestMCPHealthReportsCircuitBreakers(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			CircuitBreaker: config.CircuitBreakerConfig{
+				Enabled:          true,
+				FailureThreshold: 5,
+				ResetTimeout:     time.Second,
+			},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"circuit_breakers":{"docs":{"consecutive_failures":0,"state":"closed"}}`)
+}
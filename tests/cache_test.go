@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// callTool invokes a registered tool handler and fails the test on error
+func callTool(t *testing.T, server *mcp.Server, name string, args map[string]interface{}) interface{} {
+	tool, exists := server.Tools[name]
+	require.True(t, exists, "tool %s should be registered", name)
+
+	result, err := tool.Handler(context.Background(), args)
+	require.NoError(t, err)
+	return result
+}
+
+func TestMCPQueryCache(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			QueryCache: config.QueryCacheConfig{
+				Enabled: true,
+				TTL:     time.Minute,
+				MaxSize: 10,
+			},
+		},
+	}
+
+	logger, _ := zap.NewProduction()
+	server, err := mcp.NewServer(cfg, logger)
+	require.NoError(t, err)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "first document",
+	})
+
+	query := map[string]interface{}{"db_name": "docs", "query": "document"}
+	first := callTool(t, server, "query", query)
+	second := callTool(t, server, "query", query)
+	assert.Equal(t, first, second)
+	assert.Contains(t, first.(string), "Found 1 relevant documents")
+
+	// Writes invalidate cached results for the database
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/2",
+		"text":    "second document",
+	})
+	third := callTool(t, server, "query", query)
+	assert.Contains(t, third.(string), "Found 2 relevant documents")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+	stats := health["query_cache"].(map[string]interface{})
+	assert.Equal(t, float64(1), stats["hits"])
+	assert.Equal(t, float64(2), stats["misses"])
+}
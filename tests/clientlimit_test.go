@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockingServer returns a server capped at one tool call per client whose
+// block tool waits until release is closed; started receives once per call
+func newBlockingServer(t *testing.T, limit config.PerClientConcurrencyConfig) (*mcp.Server, chan struct{}, chan struct{}) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout:          15 * time.Second,
+			PerClientConcurrency: limit,
+		},
+	})
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	require.NoError(t, server.RegisterTool(mcp.Tool{
+		Name:        "block",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			started <- struct{}{}
+			<-release
+			return "done", nil
+		},
+	}))
+	return server, started, release
+}
+
+// postClientToolCall calls a tool over HTTP as the client at remoteAddr with the given headers
+func postClientToolCall(server *mcp.Server, name, remoteAddr string, header map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call", strings.NewReader(`{"name": "`+name+`", "arguments": {}}`))
+	request.RemoteAddr = remoteAddr
+	for key, value := range header {
+		request.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestMCPPerClientConcurrencyRejectsExcessCalls(t *testing.T) {
+	server, started, release := newBlockingServer(t, config.PerClientConcurrencyConfig{MaxInFlight: 1})
+
+	done := make(chan int)
+	go func() { done <- postClientToolCall(server, "block", "10.0.0.1:1000", nil).Code }()
+	<-started
+
+	// The same client is over its cap, even on another connection
+	recorder := postClientToolCall(server, "block", "10.0.0.1:2000", nil)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "too many requests")
+
+	// Other clients are unaffected
+	go func() { done <- postClientToolCall(server, "block", "10.0.0.2:1000", nil).Code }()
+	<-started
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	// The slot is free again once the call finishes
+	assert.Equal(t, http.StatusOK, postClientToolCall(server, "block", "10.0.0.1:3000", nil).Code)
+}
+
+func TestMCPPerClientConcurrencyWaitsAndUsesClientHeader(t *testing.T) {
+	server, started, release := newBlockingServer(t, config.PerClientConcurrencyConfig{
+		MaxInFlight:  1,
+		WaitTimeout:  5 * time.Second,
+		ClientHeader: "X-Client-ID",
+	})
+
+	done := make(chan int)
+	go func() {
+		done <- postClientToolCall(server, "block", "10.0.0.1:1000", map[string]string{"X-Client-ID": "alice"}).Code
+	}()
+	<-started
+
+	// bob shares alice's address but is a different client
+	go func() {
+		done <- postClientToolCall(server, "block", "10.0.0.1:2000", map[string]string{"X-Client-ID": "bob"}).Code
+	}()
+	<-started
+
+	// A second call from alice waits for her first to finish instead of failing
+	go func() {
+		done <- postClientToolCall(server, "block", "10.0.0.9:1000", map[string]string{"X-Client-ID": "alice"}).Code
+	}()
+
+	close(release)
+	for range 3 {
+		assert.Equal(t, http.StatusOK, <-done)
+	}
+}
+
+func TestMCPPerClientConcurrencyCoversJSONRPC(t *testing.T) {
+	server, started, release := newBlockingServer(t, config.PerClientConcurrencyConfig{MaxInFlight: 1})
+
+	done := make(chan int)
+	go func() { done <- postClientToolCall(server, "block", "192.0.2.1:1234", nil).Code }()
+	<-started
+	defer func() {
+		close(release)
+		<-done
+	}()
+
+	// httptest requests come from 192.0.2.1, which is at its cap
+	_, response := postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": "block"},
+	})
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, true, result["isError"])
+	assert.Contains(t, result["content"].([]interface{})[0].(map[string]interface{})["text"], "too many requests")
+}
+
+func TestConfigRejectsNegativePerClientConcurrency(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.PerClientConcurrency.MaxInFlight = -1
+	assert.ErrorContains(t, cfg.Validate(), "per_client_concurrency max_in_flight")
+}
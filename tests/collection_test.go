@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBCreateCollectionAppliesOptions(t *testing.T) {
+	cfg := &config.Config{MCP: config.MCPConfig{Embedding: config.EmbeddingConfig{VectorSize: 1536, Metric: "cosine"}}}
+	db, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	require.NoError(t, db.CreateCollection(ctx, "archive", vectordb.CollectionOptions{VectorSize: 384, Metric: "l2"}))
+
+	collections, err := db.ListCollections(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"docs", "archive"}, collections)
+
+	info, err := db.GetCollectionInfo(ctx, "archive")
+	require.NoError(t, err)
+	schema := info["schema"].(map[string]interface{})
+	assert.Equal(t, "l2", schema["metric_type"])
+	assert.Equal(t, "default", schema["embedding"])
+	fields := schema["fields"].([]map[string]interface{})
+	assert.Equal(t, 384, fields[4]["dimension"])
+
+	// The default collection is untouched
+	assert.Equal(t, "docs", db.CollectionName())
+
+	err = db.CreateCollection(ctx, "archive", vectordb.CollectionOptions{})
+	assert.ErrorIs(t, err, vectordb.ErrCollectionExists)
+}
+
+func TestMCPCreateCollection(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	callTool(t, server, "create_collection", map[string]interface{}{
+		"db_name":         "demo",
+		"collection_name": "archive",
+		"vector_size":     float64(384),
+	})
+
+	// Searching the new collection succeeds and finds nothing yet
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "demo",
+		"query":           "anything",
+		"collection_name": "archive",
+	}).(map[string]interface{})
+	assert.Empty(t, result["results"])
+
+	assert.Equal(t, http.StatusConflict,
+		postToolCall(server, `{"name": "create_collection", "arguments": {"db_name": "demo", "collection_name": "archive"}}`))
+	assert.Equal(t, http.StatusBadRequest,
+		postToolCall(server, `{"name": "create_collection", "arguments": {"db_name": "demo", "collection_name": "other", "metric": "hamming"}}`))
+	assert.Equal(t, http.StatusNotFound,
+		postToolCall(server, `{"name": "create_collection", "arguments": {"db_name": "missing", "collection_name": "other"}}`))
+}
+
+func TestMCPGetVectorDimension(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Embedding.VectorSize = 4
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+	callTool(t, server, "create_collection", map[string]interface{}{
+		"db_name":         "demo",
+		"collection_name": "small",
+		"vector_size":     float64(2),
+	})
+
+	result := callTool(t, server, "get_vector_dimension", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 4, result["dimension"])
+	assert.Equal(t, "collection", result["source"])
+	assert.Equal(t, "MaestroDocs", result["collection"])
+
+	result = callTool(t, server, "get_vector_dimension", map[string]interface{}{
+		"db_name":         "demo",
+		"collection_name": "small",
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["dimension"])
+}
+
+func TestMCPWriteRejectsVectorsMismatchingCollection(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Embedding.VectorSize = 4
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	_, err := server.Tools["write_documents"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "demo",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/1", "text": "ok", "vector": []interface{}{0.1, 0.2, 0.3, 0.4}},
+			map[string]interface{}{"url": "https://example.com/2", "text": "wrong model", "vector": []interface{}{0.1, 0.2, 0.3}},
+		},
+	})
+	require.ErrorIs(t, err, vectordb.ErrDimensionMismatch)
+	assert.Contains(t, err.Error(), "collection 'MaestroDocs' expects 4")
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 0, count["count"])
+}
+
+func TestVectorDBTruncateCollectionKeepsSchema(t *testing.T) {
+	ctx := context.Background()
+	for _, dbType := range []string{"milvus", "weaviate"} {
+		db, err := vectordb.CreateVectorDatabase(dbType, "Docs", &config.Config{})
+		require.NoError(t, err)
+		require.NoError(t, db.Setup(ctx, "default"))
+		_, err = db.WriteDocuments(ctx, []vectordb.Document{
+			{URL: "https://example.com/1", Text: "one"},
+			{URL: "https://example.com/2", Text: "two"},
+		})
+		require.NoError(t, err)
+
+		before, err := db.GetCollectionInfo(ctx, "")
+		require.NoError(t, err)
+
+		deleted, err := db.TruncateCollection(ctx, "")
+		require.NoError(t, err, dbType)
+		assert.Equal(t, 2, deleted, dbType)
+
+		count, err := db.CountDocuments(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count, dbType)
+
+		after, err := db.GetCollectionInfo(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, before["schema"], after["schema"], dbType)
+
+		// The emptied collection still accepts writes
+		_, err = db.WriteDocument(ctx, vectordb.Document{URL: "https://example.com/3", Text: "three"})
+		assert.NoError(t, err, dbType)
+	}
+}
+
+func TestMCPTruncateCollection(t *testing.T) {
+	server := newSeededServer(t, "alpha", "beta", "gamma")
+
+	result := callTool(t, server, "truncate_collection", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 3, result["deleted"])
+	assert.Equal(t, "MaestroDocs", result["collection"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 0, count["count"])
+
+	assert.Equal(t, http.StatusNotFound,
+		postToolCall(server, `{"name": "truncate_collection", "arguments": {"db_name": "docs", "collection_name": "Missing"}}`))
+}
+
+func TestMCPCreateCollectionRejectsOversizedVectors(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Embedding.VectorSize = 4
+	cfg.MCP.Embedding.MaxVectorSize = 1024
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	_, err := server.CallTool(context.Background(), "create_collection", map[string]interface{}{
+		"db_name":         "demo",
+		"collection_name": "wide",
+		"vector_size":     float64(4096),
+	})
+	assert.ErrorIs(t, err, vectordb.ErrVectorSizeTooLarge)
+}
+
+func TestConfigRejectsNegativeMaxVectorSize(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.MaxVectorSize = -1
+	assert.ErrorContains(t, cfg.Validate(), "max_vector_size must not be negative")
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPCompressGzipsLargeResponses(t *testing.T) {
+	handler := server.Compress(newSeededServer(t, strings.Repeat("long document ", 100)).Handler(), 256)
+
+	body := `{"name": "list_documents", "arguments": {"db_name": "docs"}}`
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call", strings.NewReader(body))
+	request.Header.Set("Accept-Encoding", "br, gzip")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", recorder.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Contains(t, string(decoded), "long document long document")
+}
+
+func TestMCPCompressSkipsSmallAndUnacceptedResponses(t *testing.T) {
+	handler := server.Compress(newTestServer(t).Handler(), 1024)
+
+	// Below the threshold
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Contains(t, recorder.Body.String(), "healthy")
+
+	// The client does not accept gzip
+	handler = server.Compress(newTestServer(t).Handler(), 0)
+	for _, encoding := range []string{"", "br", "gzip;q=0"} {
+		request = httptest.NewRequest(http.MethodGet, "/mcp/tools/list", nil)
+		request.Header.Set("Accept-Encoding", encoding)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Empty(t, recorder.Header().Get("Content-Encoding"), encoding)
+		assert.Contains(t, recorder.Body.String(), "write_document", encoding)
+	}
+}
+
+func TestMCPCompressSkipsStreamingExport(t *testing.T) {
+	handler := server.Compress(newSeededServer(t, "first", "second").Handler(), 0)
+
+	request := httptest.NewRequest(http.MethodGet, "/mcp/export?db_name=docs", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Contains(t, recorder.Body.String(), "first")
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -165,4 +166,150 @@ func TestConfigIsDevelopment(t *testing.T) {
 	
 	cfg.Logging.Level = "info"
 	assert.False(t, cfg.IsDevelopment())
-}
\ No newline at end of file
+}
+
+func TestConfigLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.yaml")
+	content := "server:\n  host: \"file-host\"\n  port: 9100\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "file-host", cfg.Server.Host)
+	assert.Equal(t, 9100, cfg.Server.Port)
+
+	// Defaults still apply to keys the file leaves out
+	assert.Equal(t, "milvus", cfg.MCP.VectorDB.Type)
+}
+
+func TestConfigLoadFileMissing(t *testing.T) {
+	_, err := config.LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.yaml")
+}
+
+func TestConfigValidationConfiguredDatabases(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			Port: 8030,
+		},
+		Database: config.DatabaseConfig{
+			Type: "postgres",
+		},
+		MCP: config.MCPConfig{
+			VectorDB: config.VectorDBConfig{
+				Type: "milvus",
+				Milvus: config.MilvusConfig{
+					Host: "localhost",
+					Port: 19530,
+				},
+			},
+			Databases: []config.DatabaseInstanceConfig{
+				{Name: "docs", Type: "milvus"},
+				{Name: "kb", Type: "weaviate", Collection: "KnowledgeBase"},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MCP.Databases = append(cfg.MCP.Databases, config.DatabaseInstanceConfig{Name: "docs", Type: "milvus"})
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate database name 'docs'")
+
+	cfg.MCP.Databases = []config.DatabaseInstanceConfig{{Name: "docs", Type: "postgres"}}
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported vector database type")
+}
+
+func TestConfigSecretInterpolation(t *testing.T) {
+	t.Setenv("TEST_EMBEDDING_KEY", "sk-secret")
+	t.Setenv("TEST_MILVUS_PASSWORD", "hunter2")
+
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	content := "mcp:\n" +
+		"  embedding:\n" +
+		"    api_key: \"${TEST_EMBEDDING_KEY}\"\n" +
+		"  vector_db:\n" +
+		"    milvus:\n" +
+		"      password: \"${TEST_MILVUS_PASSWORD}\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-secret", cfg.MCP.Embedding.APIKey)
+	assert.Equal(t, "hunter2", cfg.MCP.VectorDB.Milvus.Password)
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, "[REDACTED]", redacted.MCP.Embedding.APIKey)
+	assert.Equal(t, "[REDACTED]", redacted.MCP.VectorDB.Milvus.Password)
+	assert.Empty(t, redacted.MCP.VectorDB.Weaviate.APIKey)
+
+	// The original configuration is left untouched
+	assert.Equal(t, "sk-secret", cfg.MCP.Embedding.APIKey)
+}
+
+func TestConfigSecretInterpolationMissingVariable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	content := "mcp:\n  embedding:\n    api_key: \"${TEST_UNSET_EMBEDDING_KEY}\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	_, err := config.LoadFile(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "TEST_UNSET_EMBEDDING_KEY")
+}
+
+// writeEnvFile writes a .env file into a fresh working directory and unsets the
+// given keys once the test finishes
+func writeEnvFile(t *testing.T, content string, keys ...string) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte(content), 0o600))
+	t.Chdir(dir)
+
+	t.Cleanup(func() {
+		for _, key := range keys {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestConfigEnvFileParsing(t *testing.T) {
+	writeEnvFile(t, `
+# comment line
+export TEST_ENV_EXPORTED=exported
+TEST_ENV_INLINE=value # trailing comment
+TEST_ENV_HASH=abc#def
+TEST_ENV_DOUBLE="quoted # not a comment" # comment
+TEST_ENV_ESCAPED="say \"hello\""
+TEST_ENV_SINGLE='it\'s here'
+TEST_ENV_EMPTY=
+`,
+		"TEST_ENV_EXPORTED", "TEST_ENV_INLINE", "TEST_ENV_HASH", "TEST_ENV_DOUBLE",
+		"TEST_ENV_ESCAPED", "TEST_ENV_SINGLE", "TEST_ENV_EMPTY")
+
+	_, err := config.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "exported", os.Getenv("TEST_ENV_EXPORTED"))
+	assert.Equal(t, "value", os.Getenv("TEST_ENV_INLINE"))
+	assert.Equal(t, "abc#def", os.Getenv("TEST_ENV_HASH"))
+	assert.Equal(t, "quoted # not a comment", os.Getenv("TEST_ENV_DOUBLE"))
+	assert.Equal(t, `say "hello"`, os.Getenv("TEST_ENV_ESCAPED"))
+	assert.Equal(t, "it's here", os.Getenv("TEST_ENV_SINGLE"))
+
+	value, ok := os.LookupEnv("TEST_ENV_EMPTY")
+	assert.True(t, ok)
+	assert.Empty(t, value)
+}
+
+func TestConfigEnvFileDoesNotOverrideEnvironment(t *testing.T) {
+	t.Setenv("TEST_ENV_PRESET", "from-environment")
+	writeEnvFile(t, "TEST_ENV_PRESET=from-file\nTEST_ENV_FILE_ONLY=from-file\n", "TEST_ENV_FILE_ONLY")
+
+	_, err := config.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-environment", os.Getenv("TEST_ENV_PRESET"))
+	assert.Equal(t, "from-file", os.Getenv("TEST_ENV_FILE_ONLY"))
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDedupServer returns a server with a set-up docs database and the given content dedup config
+func newDedupServer(t *testing.T, dedup config.ContentDedupConfig) *mcp.Server {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout:  15 * time.Second,
+			ContentDedup: dedup,
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	return server
+}
+
+// listDocs returns every document in the docs database
+func listDocs(t *testing.T, server *mcp.Server) []vectordb.Document {
+	result := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	return result["documents"].([]vectordb.Document)
+}
+
+func TestMCPContentDedupSkipsDuplicates(t *testing.T) {
+	server := newDedupServer(t, config.ContentDedupConfig{Mode: config.ContentDedupSkip})
+
+	callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://a.example/1", "text": "shared text"})
+	result := callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://b.example/1", "text": "shared text"}).(map[string]interface{})
+	assert.Equal(t, 1, result["write_stats"].(vectordb.WriteStats).Duplicates)
+
+	// Duplicates of stored documents and of earlier batch members are both dropped
+	result = callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "docs",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://c.example/1", "text": "shared text"},
+			map[string]interface{}{"url": "https://c.example/2", "text": "new text"},
+			map[string]interface{}{"url": "https://c.example/3", "text": "new text"},
+		},
+	}).(map[string]interface{})
+	stats := result["write_stats"].(vectordb.WriteStats)
+	assert.Equal(t, 1, stats.DocumentsWritten)
+	assert.Equal(t, 2, stats.Duplicates)
+	assert.Equal(t, "Wrote 1 documents, skipped 2 duplicates", result["message"])
+
+	docs := listDocs(t, server)
+	require.Len(t, docs, 2)
+	for _, doc := range docs {
+		assert.Equal(t, vectordb.ContentHash(doc.Text), doc.Metadata[vectordb.MetadataContentHash])
+	}
+
+	// The hash is an ordinary metadata field, so duplicates can be found with a filter
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "text",
+		"filter":  map[string]interface{}{"content_hash": vectordb.ContentHash("new text")},
+	}).(map[string]interface{})
+	assert.Equal(t, 1, result["count"])
+}
+
+func TestMCPContentDedupMergesURLs(t *testing.T) {
+	server := newDedupServer(t, config.ContentDedupConfig{Mode: config.ContentDedupMergeURLs})
+
+	callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://a.example/1", "text": "shared text"})
+	for _, url := range []string{"https://b.example/1", "https://b.example/1", "https://a.example/1"} {
+		callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": url, "text": "shared text"})
+	}
+
+	docs := listDocs(t, server)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "https://a.example/1", docs[0].URL)
+	assert.Equal(t, []interface{}{"https://b.example/1"}, docs[0].Metadata[vectordb.MetadataDuplicateURLs])
+}
+
+func TestMCPContentHashWithoutDedup(t *testing.T) {
+	server := newDedupServer(t, config.ContentDedupConfig{StoreHash: true, Mode: config.ContentDedupOff})
+
+	for _, url := range []string{"https://a.example/1", "https://b.example/1"} {
+		callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": url, "text": "shared text"})
+	}
+
+	docs := listDocs(t, server)
+	require.Len(t, docs, 2)
+	assert.Equal(t, docs[0].Metadata[vectordb.MetadataContentHash], docs[1].Metadata[vectordb.MetadataContentHash])
+}
+
+func TestConfigRejectsUnknownContentDedupMode(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.ContentDedup.Mode = "merge"
+	assert.ErrorContains(t, cfg.Validate(), "invalid content_dedup mode")
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBDedupeResults(t *testing.T) {
+	result := func(url string, parent interface{}, score float64) vectordb.SearchResult {
+		doc := vectordb.Document{URL: url, Metadata: map[string]interface{}{}}
+		if parent != nil {
+			doc.Metadata["parent"] = parent
+		}
+		return vectordb.SearchResult{Document: doc, Score: score}
+	}
+	results := []vectordb.SearchResult{
+		result("https://example.com/a", 1.0, 0.9),
+		result("https://example.com/a", float64(1), 0.8),
+		result("https://example.com/b", "1", 0.7),
+		result("https://example.com/c", nil, 0.6),
+		result("https://example.com/d", nil, 0.5),
+		result("https://example.com/e", 2, 0.4),
+		result("https://example.com/a", 1, 0.3),
+	}
+
+	deduped := vectordb.DedupeResults(results, "metadata.parent", 10)
+	require.Len(t, deduped, 5)
+	assert.Equal(t, 0.9, deduped[0].Score)
+	assert.Equal(t, 3, deduped[0].GroupSize, "numbers group by value whatever their type")
+	assert.Equal(t, 1, deduped[1].GroupSize, "a string is distinct from an equal number")
+	assert.Equal(t, 0.6, deduped[2].Score, "results without the key are never merged")
+	assert.Equal(t, 0.5, deduped[3].Score)
+
+	deduped = vectordb.DedupeResults(results, "url", 2)
+	require.Len(t, deduped, 2)
+	assert.Equal(t, 3, deduped[0].GroupSize)
+	assert.Equal(t, "https://example.com/b", deduped[1].Document.URL)
+
+	assert.NoError(t, vectordb.ValidateDedupeKey("url"))
+	assert.NoError(t, vectordb.ValidateDedupeKey("metadata.parent"))
+	assert.Error(t, vectordb.ValidateDedupeKey("metadata."))
+	assert.Error(t, vectordb.ValidateDedupeKey("text"))
+}
+
+func TestMCPSearchDedupeBy(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	chunks := []struct {
+		url, text, parent string
+	}{
+		{"https://example.com/guide#1", "install chunk one", "guide"},
+		{"https://example.com/guide#2", "install chunk two", "guide"},
+		{"https://example.com/guide#3", "install chunk three", "guide"},
+		{"https://example.com/faq#1", "install chunk faq", "faq"},
+	}
+	for _, chunk := range chunks {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      chunk.url,
+			"text":     chunk.text,
+			"metadata": map[string]interface{}{"parent": chunk.parent},
+		})
+	}
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":   "docs",
+		"query":     "install chunk",
+		"limit":     float64(2),
+		"dedupe_by": "metadata.parent",
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["count"])
+
+	results := result["results"].([]vectordb.SearchResult)
+	sizes := map[string]int{}
+	for _, r := range results {
+		sizes[r.Document.Metadata["parent"].(string)] = r.GroupSize
+	}
+	assert.Equal(t, map[string]int{"guide": 3, "faq": 1}, sizes)
+
+	// Without dedupe_by no group sizes are reported
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "install chunk",
+		"limit":   float64(2),
+	}).(map[string]interface{})
+	for _, r := range result["results"].([]vectordb.SearchResult) {
+		assert.Zero(t, r.GroupSize)
+	}
+
+	text := callTool(t, server, "query", map[string]interface{}{
+		"db_name":   "docs",
+		"query":     "install chunk",
+		"limit":     float64(5),
+		"dedupe_by": "metadata.parent",
+	}).(string)
+	assert.Contains(t, text, "Found 2 relevant documents")
+
+	_, err := server.Tools["search"].Handler(context.Background(), map[string]interface{}{
+		"db_name":   "docs",
+		"query":     "install",
+		"dedupe_by": "text",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dedupe_by")
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDiagnosticsServer returns a server whose embedding provider answers with
+// a fixed vector per known text
+func newDiagnosticsServer(t *testing.T, metric string) *mcp.Server {
+	vectors := map[string][]float64{
+		"cat":    {1, 0, 0},
+		"kitten": {3, 4, 0},
+		"car":    {0, 0, 2},
+	}
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i, text := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": vectors[text]}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+
+	return newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        endpoint.URL,
+				Model:      "test-model",
+				VectorSize: 3,
+				Metric:     metric,
+			},
+		},
+	})
+}
+
+func TestMCPEmbedText(t *testing.T) {
+	server := newDiagnosticsServer(t, config.MetricL2)
+
+	result := callTool(t, server, "embed_text", map[string]interface{}{"text": "kitten"}).(map[string]interface{})
+	assert.Equal(t, []float64{3, 4, 0}, result["embedding"])
+	assert.Equal(t, 3, result["dimension"])
+	assert.Equal(t, "test-model", result["model"])
+	assert.NotContains(t, result, "embedded_text")
+
+	_, err := server.CallTool(context.Background(), "embed_text", map[string]interface{}{"text": ""})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+func TestMCPEmbedTextWithoutProvider(t *testing.T) {
+	server := newTestServer(t)
+
+	_, err := server.CallTool(context.Background(), "embed_text", map[string]interface{}{"text": "cat"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.ErrorContains(t, err, "no embedding provider is configured")
+}
+
+func TestMCPCompareTexts(t *testing.T) {
+	server := newDiagnosticsServer(t, config.MetricCosine)
+
+	result := callTool(t, server, "compare_texts", map[string]interface{}{"text_a": "cat", "text_b": "kitten"}).(map[string]interface{})
+	assert.Equal(t, "cosine", result["metric"])
+	assert.Equal(t, "config", result["metric_source"])
+	assert.InDelta(t, 0.6, result["score"], 1e-9)
+	assert.Equal(t, true, result["higher_is_closer"])
+
+	result = callTool(t, server, "compare_texts", map[string]interface{}{"text_a": "cat", "text_b": "kitten", "metric": "l2"}).(map[string]interface{})
+	assert.Equal(t, "argument", result["metric_source"])
+	assert.InDelta(t, 20.0, result["score"], 1e-9)
+	assert.Equal(t, false, result["higher_is_closer"])
+
+	// A collection's own metric wins over the argument
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "create_collection", map[string]interface{}{"db_name": "docs", "collection_name": "Dot", "metric": "ip"})
+
+	result = callTool(t, server, "compare_texts", map[string]interface{}{
+		"text_a":          "cat",
+		"text_b":          "kitten",
+		"metric":          "l2",
+		"db_name":         "docs",
+		"collection_name": "Dot",
+	}).(map[string]interface{})
+	assert.Equal(t, "ip", result["metric"])
+	assert.Equal(t, "collection", result["metric_source"])
+	assert.InDelta(t, 3.0, result["score"], 1e-9)
+}
+
+func TestVectorDBSimilarity(t *testing.T) {
+	score, err := vectordb.Similarity("cosine", []float64{1, 0}, []float64{0, 1})
+	require.NoError(t, err)
+	assert.Zero(t, score)
+
+	_, err = vectordb.Similarity("cosine", []float64{1, 0}, []float64{1})
+	assert.ErrorIs(t, err, vectordb.ErrDimensionMismatch)
+
+	_, err = vectordb.Similarity("hamming", []float64{1}, []float64{1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBBackendLimits(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.MCP.VectorDB.Milvus.Limits = config.DocumentLimitsConfig{MaxURLBytes: 16, MaxMetadataBytes: -1}
+
+	milvus := vectordb.BackendLimits("milvus", cfg)
+	assert.Equal(t, "milvus", milvus.Backend)
+	assert.Equal(t, 65535, milvus.MaxTextBytes)
+	assert.Equal(t, 16, milvus.MaxURLBytes)
+	assert.Zero(t, milvus.MaxMetadataBytes)
+
+	weaviate := vectordb.BackendLimits("weaviate", cfg)
+	assert.Equal(t, 2048, weaviate.MaxURLBytes)
+	assert.Equal(t, 100, weaviate.MaxMetadataFields)
+
+	assert.Equal(t, vectordb.Limits{Backend: "mock"}, vectordb.BackendLimits("mock", cfg))
+}
+
+func TestVectorDBWriteDocumentsChecksLimits(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.MCP.VectorDB.Milvus.Limits = config.DocumentLimitsConfig{MaxTextBytes: 10, MaxMetadataBytes: 20}
+	cfg.MCP.VectorDB.Weaviate.Limits = config.DocumentLimitsConfig{MaxMetadataFields: 1}
+
+	milvus, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	require.NoError(t, milvus.Setup(ctx, "default"))
+
+	_, err = milvus.WriteDocuments(ctx, []vectordb.Document{
+		{URL: "https://example.com/1", Text: "short"},
+		{URL: "https://example.com/2", Text: "this text is too long"},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	assert.EqualError(t, err, "document 1: document exceeds backend limits: text is 21 bytes; milvus allows at most 10")
+
+	_, err = milvus.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/3",
+		Text:     "short",
+		Metadata: map[string]interface{}{"description": strings.Repeat("x", 20)},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	assert.ErrorContains(t, err, "metadata is 38 bytes as JSON; milvus allows at most 20")
+
+	// Nothing from a rejected batch is written
+	count, err := milvus.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	weaviate, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	require.NoError(t, err)
+	require.NoError(t, weaviate.Setup(ctx, "default"))
+
+	_, err = weaviate.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/1",
+		Text:     "this text is fine on weaviate",
+		Metadata: map[string]interface{}{"a": 1, "b": 2},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	assert.ErrorContains(t, err, "metadata has 2 fields; weaviate allows at most 1")
+}
+
+func TestMCPWriteDocumentOverBackendLimitIsInvalidArgument(t *testing.T) {
+	server := newSeededServer(t)
+
+	status := postToolCall(server, `{"name":"write_document","arguments":{"db_name":"docs","url":"https://example.com/`+strings.Repeat("x", 70000)+`","text":"content"}}`)
+	assert.Equal(t, 400, status)
+
+	tool, _ := server.GetTool("write_document")
+	_, err := tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/" + strings.Repeat("x", 70000),
+		"text":    "content",
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	assert.ErrorContains(t, err, "url is 70020 bytes; milvus allows at most 65535")
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingEmbeddingServer returns a server whose embedding provider counts
+// its requests and holds each one until gate is closed
+func newCountingEmbeddingServer(t *testing.T, cache config.EmbeddingCacheConfig, gate chan struct{}, calls *atomic.Int32) *mcp.Server {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		calls.Add(1)
+		<-gate
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": []float64{1, 0, 0}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+
+	return newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        endpoint.URL,
+				Model:      "test-model",
+				VectorSize: 3,
+				Warmup:     true,
+				Cache:      cache,
+			},
+		},
+	})
+}
+
+// embeddingHealth returns the embedding section of the /health response
+func embeddingHealth(t *testing.T, server *mcp.Server) map[string]interface{} {
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+	return health["embedding"].(map[string]interface{})
+}
+
+func TestMCPEmbeddingCoalescesConcurrentCalls(t *testing.T) {
+	gate := make(chan struct{})
+	var calls atomic.Int32
+	server := newCountingEmbeddingServer(t, config.EmbeddingCacheConfig{}, gate, &calls)
+
+	var wg sync.WaitGroup
+	embed := func() {
+		defer wg.Done()
+		result := callTool(t, server, "embed_text", map[string]interface{}{"text": "cat"}).(map[string]interface{})
+		assert.Equal(t, []float64{1, 0, 0}, result["embedding"])
+	}
+
+	wg.Add(1)
+	go embed()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, 5*time.Second, time.Millisecond)
+
+	// A second call for the same text waits for the one in flight
+	wg.Add(1)
+	go embed()
+	require.Eventually(t, func() bool { return embeddingHealth(t, server)["coalesced"] == 1.0 }, 5*time.Second, time.Millisecond)
+
+	close(gate)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Without the cache, a later call goes to the provider again
+	callTool(t, server, "embed_text", map[string]interface{}{"text": "cat"})
+	assert.Equal(t, int32(2), calls.Load())
+	assert.NotContains(t, embeddingHealth(t, server), "cache")
+}
+
+func TestMCPEmbeddingCache(t *testing.T) {
+	gate := make(chan struct{})
+	close(gate)
+	var calls atomic.Int32
+	server := newCountingEmbeddingServer(t, config.EmbeddingCacheConfig{Enabled: true, TTL: time.Minute, MaxSize: 1}, gate, &calls)
+
+	for _, text := range []string{"cat", "cat", "dog", "cat"} {
+		callTool(t, server, "embed_text", map[string]interface{}{"text": text})
+	}
+	assert.Equal(t, int32(3), calls.Load(), "dog evicts cat from a cache of one")
+	assert.Equal(t, map[string]interface{}{"hits": 1.0, "misses": 3.0, "entries": 1.0}, embeddingHealth(t, server)["cache"])
+
+	// Warmups check the provider itself and skip the cache
+	require.NoError(t, server.WarmupEmbedding(context.Background()))
+	require.NoError(t, server.WarmupEmbedding(context.Background()))
+	assert.Equal(t, int32(5), calls.Load())
+}
+
+func TestConfigEmbeddingCacheValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.Cache = config.EmbeddingCacheConfig{Enabled: true, MaxSize: 10}
+	assert.ErrorContains(t, cfg.Validate(), "embedding cache ttl must be positive")
+
+	cfg.MCP.Embedding.Cache = config.EmbeddingCacheConfig{Enabled: true, TTL: time.Minute}
+	assert.ErrorContains(t, cfg.Validate(), "embedding cache max_size must be positive")
+}
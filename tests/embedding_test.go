@@ -0,0 +1,255 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/server"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newEmbeddingEndpoint serves OpenAI-style embeddings of the given dimension,
+// or the given status code when it is not 200
+func newEmbeddingEndpoint(t *testing.T, status, dimension int, calls *int32) *httptest.Server {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// Answer in reverse order to exercise index handling
+		data := make([]map[string]interface{}, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			embedding := make([]float64, dimension)
+			embedding[0] = float64(i)
+			data = append(data, map[string]interface{}{"index": i, "embedding": embedding})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+	return endpoint
+}
+
+func embeddingWarmupConfig(url string, strict bool) *config.Config {
+	return &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:     vectordb.EmbeddingProviderCustomLocal,
+				URL:          url,
+				Model:        "nomic-embed-text",
+				VectorSize:   4,
+				Warmup:       true,
+				WarmupStrict: strict,
+			},
+		},
+	}
+}
+
+func TestVectorDBHTTPEmbedder(t *testing.T) {
+	var calls int32
+	endpoint := newEmbeddingEndpoint(t, http.StatusOK, 4, &calls)
+
+	embedder, err := vectordb.NewEmbedder(config.EmbeddingConfig{
+		Provider: vectordb.EmbeddingProviderCustomLocal,
+		URL:      endpoint.URL,
+	})
+	require.NoError(t, err)
+
+	embeddings, err := embedder.Embed(context.Background(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Len(t, embeddings, 3)
+	for i, embedding := range embeddings {
+		assert.Len(t, embedding, 4)
+		assert.Equal(t, float64(i), embedding[0])
+	}
+}
+
+func TestVectorDBNewEmbedderProviders(t *testing.T) {
+	embedder, err := vectordb.NewEmbedder(config.EmbeddingConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, embedder)
+
+	embedder, err = vectordb.NewEmbedder(config.EmbeddingConfig{Provider: vectordb.EmbeddingProviderOpenAI})
+	require.NoError(t, err)
+	assert.NotNil(t, embedder)
+
+	_, err = vectordb.NewEmbedder(config.EmbeddingConfig{Provider: vectordb.EmbeddingProviderCustomLocal})
+	assert.Error(t, err)
+
+	_, err = vectordb.NewEmbedder(config.EmbeddingConfig{Provider: "carrier-pigeon"})
+	assert.Error(t, err)
+}
+
+func TestMCPEmbeddingWarmup(t *testing.T) {
+	var calls int32
+	endpoint := newEmbeddingEndpoint(t, http.StatusOK, 4, &calls)
+
+	mcpServer := newTestServerWithConfig(t, embeddingWarmupConfig(endpoint.URL, true))
+	require.NoError(t, mcpServer.WarmupEmbedding(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// A dimension that disagrees with vector_size is bad configuration too
+	cfg := embeddingWarmupConfig(endpoint.URL, true)
+	cfg.MCP.Embedding.VectorSize = 8
+	mcpServer = newTestServerWithConfig(t, cfg)
+	err := mcpServer.WarmupEmbedding(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "vector_size is 8")
+
+	// Warmup is opt-in
+	cfg = embeddingWarmupConfig(endpoint.URL, true)
+	cfg.MCP.Embedding.Warmup = false
+	mcpServer = newTestServerWithConfig(t, cfg)
+	require.NoError(t, mcpServer.WarmupEmbedding(context.Background()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestMCPEmbeddingWarmupStrictFailsStartup(t *testing.T) {
+	var calls int32
+	endpoint := newEmbeddingEndpoint(t, http.StatusUnauthorized, 0, &calls)
+	logger, _ := zap.NewProduction()
+
+	_, err := server.New(embeddingWarmupConfig(endpoint.URL, true), logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+
+	// Without strict mode the failure is only logged
+	_, err = server.New(embeddingWarmupConfig(endpoint.URL, false), logger)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestMCPGetEmbeddingInfo(t *testing.T) {
+	var calls int32
+	endpoint := newEmbeddingEndpoint(t, http.StatusOK, 4, &calls)
+	cfg := embeddingWarmupConfig(endpoint.URL, false)
+	cfg.MCP.Embedding.APIKey = "secret"
+	server := newTestServerWithConfig(t, cfg)
+
+	result := callTool(t, server, "get_embedding_info", map[string]interface{}{}).(map[string]interface{})
+	assert.Equal(t, vectordb.EmbeddingProviderCustomLocal, result["provider"])
+	assert.Equal(t, "nomic-embed-text", result["model"])
+	assert.Equal(t, 4, result["dimension"])
+	assert.Equal(t, "[REDACTED]", result["config"].(config.EmbeddingConfig).APIKey)
+	assert.Equal(t, "secret", cfg.MCP.Embedding.APIKey)
+
+	// The endpoint answers a single text with a zero vector
+	check := result["check"].(map[string]interface{})
+	assert.Equal(t, true, check["reachable"])
+	assert.Equal(t, 4, check["dimension"])
+	assert.Equal(t, true, check["dimension_matches"])
+	assert.Equal(t, true, check["all_zeros"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	result = callTool(t, server, "get_embedding_info", map[string]interface{}{"check": false}).(map[string]interface{})
+	assert.NotContains(t, result, "check")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestMCPGetEmbeddingInfoUnreachable(t *testing.T) {
+	var calls int32
+	endpoint := newEmbeddingEndpoint(t, http.StatusServiceUnavailable, 4, &calls)
+	server := newTestServerWithConfig(t, embeddingWarmupConfig(endpoint.URL, false))
+
+	check := callTool(t, server, "get_embedding_info", map[string]interface{}{}).(map[string]interface{})["check"].(map[string]interface{})
+	assert.Equal(t, false, check["reachable"])
+	assert.Contains(t, check["error"], "503")
+}
+
+func TestMCPGetEmbeddingInfoWithoutProvider(t *testing.T) {
+	server := newTestServer(t)
+
+	result := callTool(t, server, "get_embedding_info", map[string]interface{}{}).(map[string]interface{})
+	assert.Equal(t, "none", result["provider"])
+	assert.Contains(t, result, "note")
+	assert.NotContains(t, result, "check")
+}
+
+// embeddingModelConfig returns a config recording the embedding model in collections
+func embeddingModelConfig(onMismatch string) *config.Config {
+	return &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:        vectordb.EmbeddingProviderOpenAI,
+				Model:           "text-embedding-ada-002",
+				VectorSize:      1536,
+				ModelField:      "embedding_model",
+				OnModelMismatch: onMismatch,
+			},
+		},
+	}
+}
+
+func TestMCPEmbeddingModelRecordedAndChecked(t *testing.T) {
+	cfg := embeddingModelConfig(config.ModelMismatchRefuse)
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	info := callTool(t, server, "get_collection_info", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	embeddingModel := info["embedding_model"].(map[string]interface{})
+	assert.Equal(t, vectordb.EmbeddingModel{Provider: "openai", Model: "text-embedding-ada-002", Dimension: 1536}, embeddingModel["recorded"])
+	assert.Equal(t, true, embeddingModel["matches"])
+
+	// The deployment switches models while the collection keeps the old vectors
+	cfg.MCP.Embedding.Model = "text-embedding-3-large"
+	cfg.MCP.Embedding.VectorSize = 3072
+
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	require.ErrorIs(t, err, mcp.ErrEmbeddingModelMismatch)
+	assert.Contains(t, err.Error(), "set up with openai/text-embedding-ada-002 (1536 dimensions)")
+
+	_, err = server.CallTool(context.Background(), "setup_database", map[string]interface{}{"db_name": "docs"})
+	require.ErrorIs(t, err, mcp.ErrEmbeddingModelMismatch)
+
+	info = callTool(t, server, "get_collection_info", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, false, info["embedding_model"].(map[string]interface{})["matches"])
+}
+
+func TestMCPEmbeddingModelMismatchWarns(t *testing.T) {
+	cfg := embeddingModelConfig(config.ModelMismatchWarn)
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	cfg.MCP.Embedding.Model = "text-embedding-3-small"
+
+	result := callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	assert.Equal(t, "ok", result.(map[string]interface{})["status"])
+}
+
+func TestConfigEmbeddingModelValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.ModelField = "embedding"
+	assert.ErrorContains(t, cfg.Validate(), "collides with a collection schema field")
+
+	cfg = milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.OnModelMismatch = "ignore"
+	assert.ErrorContains(t, cfg.Validate(), "invalid embedding on_model_mismatch")
+}
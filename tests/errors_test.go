@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBTypedErrors(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			Embedding: config.EmbeddingConfig{VectorSize: 4},
+		},
+	}
+	ctx := context.Background()
+
+	milvus, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	weaviate, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	require.NoError(t, err)
+
+	for _, db := range []vectordb.VectorDatabase{milvus, weaviate} {
+		t.Run(db.Type(), func(t *testing.T) {
+			_, err := db.CountDocuments(ctx)
+			assert.True(t, errors.Is(err, vectordb.ErrCollectionNotFound), err)
+			assert.Contains(t, err.Error(), "does not exist")
+
+			require.NoError(t, db.Setup(ctx, "default"))
+
+			err = db.DeleteDocument(ctx, "missing")
+			assert.True(t, errors.Is(err, vectordb.ErrDocumentNotFound), err)
+
+			_, err = db.GetDocument(ctx, "missing")
+			assert.True(t, errors.Is(err, vectordb.ErrDocumentNotFound), err)
+
+			_, err = db.WriteDocument(ctx, vectordb.Document{URL: "u", Text: "t", Vector: []float64{1, 2}})
+			assert.True(t, errors.Is(err, vectordb.ErrDimensionMismatch), err)
+
+			require.NoError(t, db.Cleanup(ctx))
+			_, err = db.ListDocuments(ctx, 10, 0)
+			assert.True(t, errors.Is(err, vectordb.ErrDatabaseClosed), err)
+		})
+	}
+}
+
+func TestVectorDBCircuitBreakerIgnoresCallerErrors(t *testing.T) {
+	inner, err := vectordb.NewMilvusDatabase("docs", &config.Config{})
+	require.NoError(t, err)
+
+	breaker := vectordb.NewCircuitBreakerDatabase(inner, config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 1,
+		ResetTimeout:     time.Minute,
+	})
+	ctx := context.Background()
+	require.NoError(t, breaker.Setup(ctx, "default"))
+
+	for i := 0; i < 3; i++ {
+		err = breaker.DeleteDocument(ctx, "missing")
+		assert.True(t, errors.Is(err, vectordb.ErrDocumentNotFound))
+	}
+	assert.Equal(t, vectordb.CircuitClosed, breaker.State())
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingExpander is an expander whose backend is unavailable
+type failingExpander struct{}
+
+func (failingExpander) Expand(ctx context.Context, query string) (string, error) {
+	return "", errors.New("paraphrase service unavailable")
+}
+
+func TestVectorDBSynonymExpander(t *testing.T) {
+	assert.Nil(t, vectordb.NewSynonymExpander(config.ExpansionConfig{}))
+
+	expander := vectordb.NewSynonymExpander(config.ExpansionConfig{Synonyms: map[string][]string{
+		"K8s":    {"kubernetes", "cluster"},
+		"deploy": {"rollout", "cluster"},
+	}})
+	expanded, err := expander.Expand(context.Background(), "How to deploy on k8s?")
+	require.NoError(t, err)
+	assert.Equal(t, "How to deploy on k8s? rollout cluster kubernetes", expanded)
+
+	expanded, err = expander.Expand(context.Background(), "kubernetes k8s")
+	require.NoError(t, err)
+	assert.Equal(t, "kubernetes k8s cluster", expanded, "terms already in the query are not repeated")
+
+	expanded, err = expander.Expand(context.Background(), "nothing known")
+	require.NoError(t, err)
+	assert.Equal(t, "nothing known", expanded)
+}
+
+func TestMCPQueryExpand(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Expansion.Synonyms = map[string][]string{"k8s": {"kubernetes"}}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/kubernetes",
+		"text":    "kubernetes guide",
+	})
+
+	// Without expansion the short query has no overlap with the document
+	text := callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "k8s"}).(string)
+	assert.NotContains(t, text, "Expanded query")
+	assert.Contains(t, text, "(Score: 0.00)")
+
+	text = callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "k8s", "expand": true}).(string)
+	assert.Contains(t, text, "for query 'k8s'")
+	assert.Contains(t, text, "Expanded query: 'k8s kubernetes'")
+	assert.Contains(t, text, "kubernetes guide (Score: 0.50)")
+
+	// A failing expander falls back to the original query
+	server.SetExpander(failingExpander{})
+	text = callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "k8s", "expand": true}).(string)
+	assert.NotContains(t, text, "Expanded query")
+	assert.Contains(t, text, "Note: query expansion failed")
+
+	server.SetExpander(nil)
+	text = callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "k8s", "expand": true}).(string)
+	assert.Contains(t, text, "no expander is configured")
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSwitchableEmbeddingEndpoint returns an embeddings endpoint that fails
+// with 503 while down is set and otherwise answers with 4-dimensional vectors
+func newSwitchableEmbeddingEndpoint(t *testing.T, down *atomic.Bool) *httptest.Server {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": []float64{1, 0, 0, 0}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+	return endpoint
+}
+
+func fallbackConfig(url string, fallback config.EmbeddingFallbackConfig) *config.Config {
+	fallback.FailureThreshold = 1
+	return &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        url,
+				VectorSize: 4,
+				Fallback:   fallback,
+			},
+		},
+	}
+}
+
+// embeddingState returns the state get_embedding_info reports after checking the provider
+func embeddingState(t *testing.T, server *mcp.Server) map[string]interface{} {
+	info := callTool(t, server, "get_embedding_info", map[string]interface{}{}).(map[string]interface{})
+	return info["state"].(map[string]interface{})
+}
+
+func TestMCPEmbeddingFallbackKeywordSearch(t *testing.T) {
+	var down atomic.Bool
+	endpoint := newSwitchableEmbeddingEndpoint(t, &down)
+	server := newTestServerWithConfig(t, fallbackConfig(endpoint.URL, config.EmbeddingFallbackConfig{KeywordSearch: true}))
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "docs",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/1", "text": "Notes on classical music"},
+			map[string]interface{}{"url": "https://example.com/2", "text": "Quantum computing with quantum circuits"},
+			map[string]interface{}{"url": "https://example.com/3", "text": "Cloud computing costs"},
+		},
+	})
+	assert.Equal(t, "up", embeddingState(t, server)["status"])
+
+	down.Store(true)
+	assert.Equal(t, "down", embeddingState(t, server)["status"])
+
+	result := callTool(t, server, "search", map[string]interface{}{"db_name": "docs", "query": "quantum computing"}).(map[string]interface{})
+	assert.Equal(t, "keyword", result["search_mode"])
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 2)
+	assert.Equal(t, "https://example.com/2", results[0].Document.URL)
+	assert.Equal(t, 1.0, results[0].Score)
+	assert.Equal(t, "https://example.com/3", results[1].Document.URL)
+	assert.Equal(t, 0.5, results[1].Score)
+
+	text := callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "classical"}).(string)
+	assert.Contains(t, text, "Found 1 relevant documents")
+	assert.Contains(t, text, "keyword search")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &health))
+	assert.Equal(t, "degraded", health["status"])
+	assert.Equal(t, "down", health["embedding"].(map[string]interface{})["status"])
+
+	// Once the provider answers again, searches go back to the vector database
+	down.Store(false)
+	assert.Equal(t, "up", embeddingState(t, server)["status"])
+	result = callTool(t, server, "search", map[string]interface{}{"db_name": "docs", "query": "quantum computing"}).(map[string]interface{})
+	assert.NotContains(t, result, "search_mode")
+}
+
+func TestMCPEmbeddingFallbackQueuesWrites(t *testing.T) {
+	var down atomic.Bool
+	endpoint := newSwitchableEmbeddingEndpoint(t, &down)
+	queuePath := filepath.Join(t.TempDir(), "embedding_queue.jsonl")
+	cfg := fallbackConfig(endpoint.URL, config.EmbeddingFallbackConfig{QueueWrites: true, QueuePath: queuePath})
+	server := newTestServerWithConfig(t, cfg)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	down.Store(true)
+	assert.Equal(t, "down", embeddingState(t, server)["status"])
+
+	result := callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "docs",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/1", "text": "first"},
+			map[string]interface{}{"url": "https://example.com/2", "text": "second"},
+		},
+	}).(map[string]interface{})
+	assert.Equal(t, "queued", result["status"])
+	assert.Equal(t, 2, result["queued"])
+	assert.Equal(t, 0, documentCount(t, server, "docs"))
+
+	// A document carrying its vector needs no embedding and is written directly
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/3",
+		"text":    "third",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	})
+	assert.Equal(t, 1, documentCount(t, server, "docs"))
+
+	// The queue is persisted, so a restarted server picks it up
+	_, err := os.Stat(queuePath)
+	require.NoError(t, err)
+	restarted := newTestServerWithConfig(t, cfg)
+	queue := embeddingState(t, restarted)["queue"].(map[string]interface{})
+	assert.Equal(t, 1, queue["writes"])
+	assert.Equal(t, 2, queue["documents"])
+
+	// Nothing is flushed while the provider is still down
+	written, err := server.FlushEmbeddingQueue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, written)
+
+	down.Store(false)
+	assert.Equal(t, "up", embeddingState(t, server)["status"])
+	written, err = server.FlushEmbeddingQueue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, written)
+	assert.Equal(t, 3, documentCount(t, server, "docs"))
+
+	_, err = os.Stat(queuePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMCPEmbeddingDownWithoutFallbackFailsBatchQuery(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	endpoint := newSwitchableEmbeddingEndpoint(t, &down)
+	server := newTestServerWithConfig(t, fallbackConfig(endpoint.URL, config.EmbeddingFallbackConfig{}))
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	_, err := server.CallTool(context.Background(), "batch_query", map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{map[string]interface{}{"query": "anything"}},
+	})
+	assert.ErrorContains(t, err, "failed to embed queries")
+	assert.Equal(t, "down", embeddingState(t, server)["status"])
+}
+
+func TestVectorDBKeywordSearchAppliesFilter(t *testing.T) {
+	db, err := vectordb.CreateVectorDatabase("milvus", "Docs", &config.Config{})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+	_, err = db.WriteDocuments(ctx, []vectordb.Document{
+		{URL: "https://example.com/1", Text: "release notes", Metadata: map[string]interface{}{"lang": "en"}},
+		{URL: "https://example.com/2", Text: "release notes", Metadata: map[string]interface{}{"lang": "de"}},
+	})
+	require.NoError(t, err)
+
+	results, err := vectordb.KeywordSearch(vectordb.WithMetadataFilter(ctx, vectordb.MetadataFilter{"lang": "de"}), db, "Release", 5, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/2", results[0].Document.URL)
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPFieldSelection(t *testing.T) {
+	server := newSeededServer(t, "alpha document", "beta document")
+	fields := []interface{}{"url", "id"}
+
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"fields":  fields,
+	}).(map[string]interface{})
+	results := search["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 2)
+	assert.Equal(t, "https://example.com/0", results[0].Document.URL)
+	assert.NotEmpty(t, results[0].Document.ID)
+	assert.Empty(t, results[0].Document.Text)
+	assert.Nil(t, results[0].Document.Metadata)
+
+	// Unselected fields are left out of the JSON entirely
+	encoded, err := json.Marshal(results[0].Document)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "text")
+
+	list := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name": "docs",
+		"fields":  []interface{}{"text"},
+	}).(map[string]interface{})
+	docs := list["documents"].([]vectordb.Document)
+	require.Len(t, docs, 2)
+	assert.Equal(t, vectordb.Document{Text: "alpha document"}, docs[0])
+
+	answer := callTool(t, server, "query", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"fields":  []interface{}{"url"},
+	}).(string)
+	assert.Contains(t, answer, "1. https://example.com/0")
+
+	tool, _ := server.GetTool("search")
+	_, err = tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"fields":  []interface{}{"title"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown document field 'title'")
+}
+
+func TestMCPFieldSelectionHidesTombstones(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "a", "b")
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+
+	// Metadata is not selected, yet the tombstoned document is still excluded
+	list := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name": "docs",
+		"fields":  []interface{}{"url"},
+	}).(map[string]interface{})
+	assert.Equal(t, []vectordb.Document{{URL: "https://example.com/b"}}, list["documents"])
+}
+
+func TestVectorDBFieldSelectionPushdown(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.NamedVectors = map[string]int{"title": 2}
+
+	assert.Nil(t, vectordb.MilvusOutputFields(cfg, nil))
+	assert.Equal(t, []string{"id", "url", "title"}, vectordb.MilvusOutputFields(cfg, []string{"url", "id", "vectors"}))
+	assert.Equal(t, "url text _additional { id }", vectordb.WeaviateFieldSelection([]string{"id", "url", "text"}))
+}
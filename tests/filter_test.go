@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPDeleteByFilter(t *testing.T) {
+	server := newSeededServer(t)
+	for i, category := range []string{"news", "blog", "news"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      "https://example.com/" + category,
+			"text":     category,
+			"metadata": map[string]interface{}{"category": category, "rank": float64(i)},
+		})
+	}
+
+	result := callTool(t, server, "delete_by_filter", map[string]interface{}{
+		"db_name": "docs",
+		"filter":  map[string]interface{}{"category": "news"},
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["deleted"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+
+	// Every condition must match
+	result = callTool(t, server, "delete_by_filter", map[string]interface{}{
+		"db_name": "docs",
+		"filter":  map[string]interface{}{"category": "blog", "rank": float64(5)},
+	}).(map[string]interface{})
+	assert.Equal(t, 0, result["deleted"])
+}
+
+func TestMCPDeleteByFilterRequiresFilter(t *testing.T) {
+	server := newSeededServer(t, "kept")
+	tool, _ := server.GetTool("delete_by_filter")
+
+	for _, args := range []map[string]interface{}{
+		{"db_name": "docs"},
+		{"db_name": "docs", "filter": map[string]interface{}{}},
+		{"db_name": "docs", "filter": map[string]interface{}{"tags": []interface{}{"a"}}},
+	} {
+		_, err := tool.Handler(context.Background(), args)
+		assert.Error(t, err)
+	}
+
+	list := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, list["count"])
+}
+
+func TestVectorDBMetadataFilterTranslation(t *testing.T) {
+	filter, err := vectordb.ParseMetadataFilter(map[string]interface{}{"category": "news", "year": float64(2024), "public": true})
+	require.NoError(t, err)
+
+	assert.Equal(t, `metadata["category"] == "news" && metadata["public"] == true && metadata["year"] == 2024`, filter.MilvusExpr())
+
+	where := filter.WeaviateWhere()
+	assert.Equal(t, "And", where["operator"])
+	operands := where["operands"].([]map[string]interface{})
+	require.Len(t, operands, 3)
+	assert.Equal(t, []string{"metadata", "category"}, operands[0]["path"])
+	assert.Equal(t, "news", operands[0]["valueText"])
+	assert.Equal(t, 2024.0, operands[2]["valueNumber"])
+
+	assert.True(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"category": "news", "year": 2024, "public": true}}))
+	assert.False(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"category": "news"}}))
+
+	_, err = vectordb.ParseMetadataFilter(nil)
+	assert.ErrorIs(t, err, vectordb.ErrInvalidFilter)
+}
+
+func TestVectorDBMetadataFilterNestedKeys(t *testing.T) {
+	filter, err := vectordb.ParseMetadataFilter(map[string]interface{}{"author.name": "Ada"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `metadata["author"]["name"] == "Ada"`, filter.MilvusExpr())
+	assert.Equal(t, []string{"metadata", "author", "name"}, filter.WeaviateWhere()["path"])
+
+	assert.True(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"author": map[string]interface{}{"name": "Ada"}}}))
+	assert.False(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"author": map[string]interface{}{"name": "Grace"}}}))
+	assert.False(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"author": "Ada"}}))
+
+	// A top-level key containing a dot still matches as written
+	assert.True(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"author.name": "Ada"}}))
+}
+
+func TestMCPArrayContainsFilters(t *testing.T) {
+	server := newSeededServer(t)
+	for i, tags := range [][]interface{}{{"ml", "go"}, {"ml"}, {"rust"}} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      fmt.Sprintf("https://example.com/%d", i),
+			"text":     fmt.Sprintf("document %d", i),
+			"metadata": map[string]interface{}{"tags": tags},
+		})
+	}
+
+	search := func(filter map[string]interface{}) int {
+		result := callTool(t, server, "search", map[string]interface{}{
+			"db_name": "docs",
+			"query":   "document",
+			"filter":  filter,
+		}).(map[string]interface{})
+		return result["count"].(int)
+	}
+	assert.Equal(t, 2, search(map[string]interface{}{"tags": map[string]interface{}{"contains": "ml"}}))
+	assert.Equal(t, 2, search(map[string]interface{}{"tags": map[string]interface{}{"contains_any": []interface{}{"go", "rust"}}}))
+	assert.Equal(t, 1, search(map[string]interface{}{"tags": map[string]interface{}{"contains_all": []interface{}{"ml", "go"}}}))
+	assert.Equal(t, 0, search(map[string]interface{}{"tags": map[string]interface{}{"contains": "python"}}))
+
+	result := callTool(t, server, "delete_by_filter", map[string]interface{}{
+		"db_name": "docs",
+		"filter":  map[string]interface{}{"tags": map[string]interface{}{"contains": "ml"}},
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["deleted"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+}
+
+func TestVectorDBArrayFilterTranslation(t *testing.T) {
+	filter, err := vectordb.ParseMetadataFilter(map[string]interface{}{
+		"tags":   map[string]interface{}{"contains": "ml"},
+		"topics": map[string]interface{}{"contains_any": []interface{}{"go", "rust"}},
+		"years":  map[string]interface{}{"contains_all": []interface{}{float64(2023), float64(2024)}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `json_contains(metadata["tags"], "ml") && json_contains_any(metadata["topics"], ["go", "rust"]) && json_contains_all(metadata["years"], [2023, 2024])`, filter.MilvusExpr())
+
+	operands := filter.WeaviateWhere()["operands"].([]map[string]interface{})
+	require.Len(t, operands, 3)
+	assert.Equal(t, "ContainsAny", operands[0]["operator"])
+	assert.Equal(t, []string{"ml"}, operands[0]["valueTextArray"])
+	assert.Equal(t, "ContainsAny", operands[1]["operator"])
+	assert.Equal(t, []string{"go", "rust"}, operands[1]["valueTextArray"])
+	assert.Equal(t, "ContainsAll", operands[2]["operator"])
+	assert.Equal(t, []float64{2023, 2024}, operands[2]["valueNumberArray"])
+
+	// A scalar is not an array, so array conditions never match it
+	assert.False(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"tags": "ml", "topics": []string{"go"}, "years": []interface{}{2023, 2024}}}))
+	assert.True(t, filter.Matches(vectordb.Document{Metadata: map[string]interface{}{"tags": []string{"ml"}, "topics": []string{"go"}, "years": []interface{}{2023, 2024}}}))
+
+	for _, raw := range []map[string]interface{}{
+		{"tags": map[string]interface{}{"contains_any": []interface{}{}}},
+		{"tags": map[string]interface{}{"contains_any": "ml"}},
+		{"tags": map[string]interface{}{"contains_all": []interface{}{"ml", float64(1)}}},
+		{"tags": map[string]interface{}{"contains": map[string]interface{}{}}},
+		{"tags": map[string]interface{}{"overlaps": []interface{}{"ml"}}},
+		{"tags": map[string]interface{}{"contains": "ml", "contains_any": []interface{}{"go"}}},
+	} {
+		_, err := vectordb.ParseMetadataFilter(raw)
+		assert.ErrorIs(t, err, vectordb.ErrInvalidFilter)
+	}
+}
+
+func TestMCPValidateFilterAgainstSchema(t *testing.T) {
+	server := newTestServerWithConfig(t, weaviateSchemaConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "papers", "db_type": "weaviate", "collection_name": "Papers"})
+
+	result := callTool(t, server, "validate_filter", map[string]interface{}{
+		"db_name": "papers",
+		"filter":  map[string]interface{}{"author.name": "Ada", "year": float64(2024)},
+	}).(map[string]interface{})
+	assert.Equal(t, true, result["valid"])
+	assert.Equal(t, true, result["schema_checked"])
+	assert.Empty(t, result["issues"])
+
+	result = callTool(t, server, "validate_filter", map[string]interface{}{
+		"db_name": "papers",
+		"filter": map[string]interface{}{
+			"author":   "Ada",
+			"language": "en",
+			"source":   map[string]interface{}{"contains_any": []interface{}{"arxiv", "journal"}},
+			"year":     "2024",
+		},
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["valid"])
+	issues := result["issues"].([]vectordb.FilterIssue)
+	require.Len(t, issues, 3)
+	assert.Equal(t, "author", issues[0].Field)
+	assert.Contains(t, issues[0].Problem, "is an object")
+	assert.Equal(t, "language", issues[1].Field)
+	assert.Contains(t, issues[1].Problem, "unknown field")
+	assert.Equal(t, "year", issues[2].Field)
+	assert.Contains(t, issues[2].Problem, "type mismatch: field is declared as int, got string 2024")
+}
+
+func TestMCPValidateFilterSyntaxOnly(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	// Milvus metadata is schemaless, so any field is accepted
+	result := callTool(t, server, "validate_filter", map[string]interface{}{
+		"db_name": "docs",
+		"filter":  map[string]interface{}{"anything": "goes"},
+	}).(map[string]interface{})
+	assert.Equal(t, true, result["valid"])
+	assert.Equal(t, false, result["schema_checked"])
+
+	result = callTool(t, server, "validate_filter", map[string]interface{}{
+		"db_name": "docs",
+		"filter":  map[string]interface{}{"tags": map[string]interface{}{"matches": "go"}},
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["valid"])
+	issues := result["issues"].([]vectordb.FilterIssue)
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Problem, "unknown operator 'matches'")
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPHealthToolMatchesHTTP(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	report := callTool(t, server, "health", map[string]interface{}{}).(map[string]interface{})
+	assert.Equal(t, "healthy", report["status"])
+	assert.Equal(t, 1, report["vector_databases"])
+
+	databases := report["databases"].(map[string]map[string]interface{})
+	require.Contains(t, databases, "docs")
+	assert.Equal(t, true, databases["docs"]["reachable"])
+	assert.Equal(t, "milvus", databases["docs"]["type"])
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "healthy", body["status"])
+	for key := range report {
+		assert.Contains(t, body, key)
+	}
+}
+
+func TestMCPHealthToolReportsUnreachableDatabase(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Timeouts:    map[string]time.Duration{"write_single": time.Nanosecond},
+			CircuitBreaker: config.CircuitBreakerConfig{
+				Enabled:          true,
+				FailureThreshold: 1,
+				ResetTimeout:     time.Minute,
+			},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	// A write that times out trips the breaker, so the backend is reported unreachable
+	_, err := server.Tools["write_document"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	require.Error(t, err)
+
+	report := callTool(t, server, "health", map[string]interface{}{}).(map[string]interface{})
+	assert.Equal(t, "degraded", report["status"])
+	databases := report["databases"].(map[string]map[string]interface{})
+	assert.Equal(t, false, databases["docs"]["reachable"])
+	assert.Contains(t, databases["docs"]["error"], "circuit is open")
+}
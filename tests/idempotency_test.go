@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPWriteIdempotencyKey(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Idempotency.Window = time.Hour
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	batch := func() map[string]interface{} {
+		return map[string]interface{}{
+			"db_name":         "docs",
+			"idempotency_key": "batch-1",
+			"documents": []interface{}{
+				map[string]interface{}{"url": "https://example.com/a", "text": "first"},
+				map[string]interface{}{"url": "https://example.com/b", "text": "second"},
+			},
+		}
+	}
+
+	first := callTool(t, server, "write_documents", batch()).(map[string]interface{})
+	assert.Equal(t, false, first["deduplicated"])
+	retry := callTool(t, server, "write_documents", batch()).(map[string]interface{})
+	assert.Equal(t, true, retry["deduplicated"])
+	assert.Equal(t, first["write_stats"], retry["write_stats"], "a retry returns the original response")
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 2, count["count"], "the retry wrote nothing")
+
+	// Reusing the key for different documents is a conflict
+	changed := batch()
+	changed["documents"] = []interface{}{map[string]interface{}{"url": "https://example.com/c", "text": "third"}}
+	_, err := server.CallTool(context.Background(), "write_documents", changed)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, mcp.ErrIdempotencyConflict))
+	assert.Equal(t, http.StatusConflict, postToolCall(server,
+		`{"name": "write_documents", "arguments": {"db_name": "docs", "idempotency_key": "batch-1", "documents": [{"url": "https://example.com/c", "text": "third"}]}}`))
+
+	// Keys are scoped per tool, and writes without a key are not flagged
+	single := callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":         "docs",
+		"idempotency_key": "batch-1",
+		"url":             "https://example.com/d",
+		"text":            "fourth",
+	}).(map[string]interface{})
+	assert.Equal(t, false, single["deduplicated"])
+	plain := callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/e",
+		"text":    "fifth",
+	}).(map[string]interface{})
+	assert.NotContains(t, plain, "deduplicated")
+}
+
+func TestMCPWriteIdempotencyFailedWriteNotRemembered(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Idempotency.Window = time.Hour
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	args := map[string]interface{}{
+		"db_name":         "docs",
+		"idempotency_key": "doc-1",
+		"url":             "https://example.com/a",
+		"text":            "first",
+	}
+
+	// The collection is not set up yet, so the first attempt fails
+	_, err := server.CallTool(context.Background(), "write_document", args)
+	require.Error(t, err)
+
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	result := callTool(t, server, "write_document", args).(map[string]interface{})
+	assert.Equal(t, false, result["deduplicated"], "the retry of a failed write runs")
+}
+
+func TestMCPWriteIdempotencyConcurrentRetries(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Idempotency.Window = time.Hour
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	var wg sync.WaitGroup
+	flags := make([]bool, 8)
+	for i := range flags {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+				"db_name":         "docs",
+				"idempotency_key": "same",
+				"url":             "https://example.com/a",
+				"text":            "first",
+			})
+			require.NoError(t, err)
+			flags[i] = result.(map[string]interface{})["deduplicated"].(bool)
+		}(i)
+	}
+	wg.Wait()
+
+	fresh := 0
+	for _, deduplicated := range flags {
+		if !deduplicated {
+			fresh++
+		}
+	}
+	assert.Equal(t, 1, fresh, "exactly one of the concurrent calls writes")
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+}
+
+func TestMCPWriteIdempotencyDisabled(t *testing.T) {
+	server := newSeededServer(t)
+
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name":         "docs",
+		"idempotency_key": "k",
+		"url":             "https://example.com/a",
+		"text":            "first",
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, mcp.ErrInvalidArgument))
+	assert.Contains(t, err.Error(), "mcp.idempotency.window")
+}
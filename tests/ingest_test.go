@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ingestMessage is an acknowledgement or the closing summary of an ingest stream
+type ingestMessage struct {
+	Batch        int  `json:"batch"`
+	Written      int  `json:"written"`
+	TotalWritten int  `json:"total_written"`
+	FirstRow     int  `json:"first_row"`
+	LastRow      int  `json:"last_row"`
+	Done         bool `json:"done"`
+	Failed       int  `json:"failed"`
+	Errors       []struct {
+		Row   int    `json:"row"`
+		Error string `json:"error"`
+	} `json:"errors"`
+}
+
+func TestMCPIngestStreamAcknowledgesBatches(t *testing.T) {
+	server := newSeededServer(t)
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	body, feed := io.Pipe()
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Post(httpServer.URL+"/mcp/ingest?db_name=docs&batch_size=2&flush_interval=1h", "application/x-ndjson", body)
+		if err != nil {
+			body.CloseWithError(err)
+			close(responses)
+			return
+		}
+		responses <- resp
+	}()
+
+	line := func(n int) string {
+		return fmt.Sprintf(`{"url": "https://example.com/%d", "text": "document %d"}`+"\n", n, n)
+	}
+	_, err := io.WriteString(feed, line(1)+line(2))
+	require.NoError(t, err)
+
+	resp, ok := <-responses
+	require.True(t, ok)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	acks := bufio.NewScanner(resp.Body)
+
+	// The first batch is acknowledged while the stream is still open
+	require.True(t, acks.Scan())
+	var ack ingestMessage
+	require.NoError(t, json.Unmarshal(acks.Bytes(), &ack))
+	assert.Equal(t, ingestMessage{Batch: 1, Written: 2, TotalWritten: 2, FirstRow: 1, LastRow: 2}, ack)
+
+	_, err = io.WriteString(feed, "not json\n"+line(4))
+	require.NoError(t, err)
+	require.NoError(t, feed.Close())
+
+	require.True(t, acks.Scan())
+	ack = ingestMessage{}
+	require.NoError(t, json.Unmarshal(acks.Bytes(), &ack))
+	assert.Equal(t, 2, ack.Batch)
+	assert.Equal(t, 1, ack.Written)
+	require.Len(t, ack.Errors, 1)
+	assert.Equal(t, 3, ack.Errors[0].Row)
+	assert.Contains(t, ack.Errors[0].Error, "invalid JSON")
+
+	require.True(t, acks.Scan())
+	var summary ingestMessage
+	require.NoError(t, json.Unmarshal(acks.Bytes(), &summary))
+	assert.True(t, summary.Done)
+	assert.Equal(t, 3, summary.Written)
+	assert.Equal(t, 1, summary.Failed)
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+}
+
+func TestMCPIngestRejectsBadRequests(t *testing.T) {
+	server := newSeededServer(t)
+	ingest := func(method, target string) int {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader("")))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, ingest(http.MethodGet, "/mcp/ingest?db_name=docs"))
+	assert.Equal(t, http.StatusBadRequest, ingest(http.MethodPost, "/mcp/ingest"))
+	assert.Equal(t, http.StatusBadRequest, ingest(http.MethodPost, "/mcp/ingest?db_name=docs&flush_interval=soon"))
+	assert.Equal(t, http.StatusNotFound, ingest(http.MethodPost, "/mcp/ingest?db_name=missing"))
+
+	// An empty stream still ends with a summary
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=docs", strings.NewReader("")))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"done": true, "batches": 0, "written": 0, "failed": 0}`, recorder.Body.String())
+}
+
+func TestMCPIngestWritesLikeWriteDocuments(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Audit = config.AuditConfig{Enabled: true, Sink: config.AuditSinkMemory, MaxEntries: 100}
+	cfg.MCP.PerClientConcurrency = config.PerClientConcurrencyConfig{MaxInFlight: 1}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":  "content",
+		"db_type":  "mock",
+		"route_by": "metadata.type",
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "content"})
+
+	body := `{"url": "https://example.com/faq", "text": "How do I reset my password", "metadata": {"type": "faq"}}
+{"url": "https://example.com/misc", "text": "Unrouted document without a type"}
+`
+	request := httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=content", strings.NewReader(body))
+	request.Header.Set("X-Request-ID", "ingest-1")
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// Documents are routed as write_documents routes them
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "content"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "content",
+		"query":           "password",
+		"collection_name": "faq",
+	}).(map[string]interface{})
+	assert.Len(t, result["results"], 1)
+
+	// Each micro-batch is audited as a write_documents call
+	audit := callTool(t, server, "get_audit_log", map[string]interface{}{}).(map[string]interface{})
+	require.Equal(t, 1, audit["count"])
+	entry := audit["entries"].([]mcp.AuditEntry)[0]
+	assert.Equal(t, "write_documents", entry.Tool)
+	assert.Equal(t, "content", entry.DBName)
+	assert.Equal(t, "ingest-1", entry.RequestID)
+	assert.Equal(t, "success", entry.Status)
+
+	// An open stream holds its client's slot
+	body2, feed := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=content", body2))
+	}()
+	require.Eventually(t, func() bool {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=content", strings.NewReader("")))
+		return recorder.Code == http.StatusTooManyRequests
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, feed.Close())
+	<-done
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postRPC sends a JSON-RPC request to /mcp and returns the status and decoded body
+func postRPC(t *testing.T, server *mcp.Server, request map[string]interface{}) (int, map[string]interface{}) {
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(body)))
+	if recorder.Body.Len() == 0 {
+		return recorder.Code, nil
+	}
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return recorder.Code, response
+}
+
+func TestMCPInitializeReportsCapabilities(t *testing.T) {
+	server := newResourceServer(t, "first")
+
+	status, response := postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]interface{}{
+			"protocolVersion": "2025-03-26",
+			"capabilities":    map[string]interface{}{},
+			"clientInfo":      map[string]interface{}{"name": "test-client", "version": "1.0"},
+		},
+	})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "2.0", response["jsonrpc"])
+	assert.Equal(t, float64(1), response["id"])
+
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, "2025-03-26", result["protocolVersion"])
+	assert.Equal(t, "maestro-mcp", result["serverInfo"].(map[string]interface{})["name"])
+
+	capabilities := result["capabilities"].(map[string]interface{})
+	assert.Contains(t, capabilities, "tools")
+	assert.Contains(t, capabilities, "resources")
+	assert.Contains(t, capabilities, "prompts")
+	assert.Contains(t, capabilities["experimental"], "streaming")
+}
+
+func TestMCPInitializeOmitsDisabledCapabilities(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout:   15 * time.Second,
+			DisabledTools: []string{"query"},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	_, response := postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "init",
+		"method":  "initialize",
+		"params":  map[string]interface{}{"protocolVersion": "1999-01-01"},
+	})
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, "2025-06-18", result["protocolVersion"])
+
+	capabilities := result["capabilities"].(map[string]interface{})
+	assert.Contains(t, capabilities, "tools")
+	assert.NotContains(t, capabilities, "resources")
+	// The only prompt retrieves documents, which needs the query tool
+	assert.NotContains(t, capabilities, "prompts")
+
+	_, response = postRPC(t, server, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "resources/list"})
+	assert.Equal(t, float64(-32601), response["error"].(map[string]interface{})["code"])
+}
+
+func TestMCPJSONRPCToolsCall(t *testing.T) {
+	server := newSeededServer(t, "first", "second")
+
+	_, response := postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      7,
+		"method":  "tools/call",
+		"params": map[string]interface{}{
+			"name":      "count_documents",
+			"arguments": map[string]interface{}{"db_name": "docs"},
+		},
+	})
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, false, result["isError"])
+	content := result["content"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "text", content["type"])
+	assert.Contains(t, content["text"], `"count":2`)
+
+	// A failing tool reports the failure in its result
+	_, response = postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      8,
+		"method":  "tools/call",
+		"params": map[string]interface{}{
+			"name":      "count_documents",
+			"arguments": map[string]interface{}{"db_name": "missing"},
+		},
+	})
+	result = response["result"].(map[string]interface{})
+	assert.Equal(t, true, result["isError"])
+
+	// An unknown tool is an invalid params error
+	_, response = postRPC(t, server, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      9,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": "no_such_tool"},
+	})
+	assert.Equal(t, float64(-32602), response["error"].(map[string]interface{})["code"])
+}
+
+func TestMCPJSONRPCNotificationsAndErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	status, response := postRPC(t, server, map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Nil(t, response)
+
+	_, response = postRPC(t, server, map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "ping"})
+	assert.Equal(t, map[string]interface{}{}, response["result"])
+
+	_, response = postRPC(t, server, map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "no/such/method"})
+	assert.Equal(t, float64(-32601), response["error"].(map[string]interface{})["code"])
+
+	_, response = postRPC(t, server, map[string]interface{}{"id": 3, "method": "ping"})
+	assert.Equal(t, float64(-32600), response["error"].(map[string]interface{})["code"])
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDatabase holds CountDocuments until release is closed
+type blockingDatabase struct {
+	vectordb.VectorDatabase
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingDatabase) CountDocuments(ctx context.Context) (int, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return 7, nil
+}
+
+func newBlockingDatabase(t *testing.T) *blockingDatabase {
+	inner, err := vectordb.NewMilvusDatabase("docs", &config.Config{})
+	require.NoError(t, err)
+	return &blockingDatabase{
+		VectorDatabase: inner,
+		started:        make(chan struct{}, 4),
+		release:        make(chan struct{}),
+	}
+}
+
+func TestVectorDBConcurrencyLimitFailsFast(t *testing.T) {
+	blocking := newBlockingDatabase(t)
+	limited := vectordb.NewConcurrencyLimitedDatabase(blocking, config.ConcurrencyConfig{
+		MaxConcurrentOperations: 1,
+	})
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := limited.CountDocuments(ctx)
+		done <- err
+	}()
+	<-blocking.started
+	assert.Equal(t, 1, limited.Stats()["in_flight"])
+
+	_, err := limited.CountDocuments(ctx)
+	assert.True(t, errors.Is(err, vectordb.ErrBackendUnavailable))
+	assert.EqualValues(t, 1, limited.Stats()["rejected"])
+
+	close(blocking.release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, limited.Stats()["in_flight"])
+}
+
+func TestVectorDBConcurrencyLimitWaitsForSlot(t *testing.T) {
+	blocking := newBlockingDatabase(t)
+	limited := vectordb.NewConcurrencyLimitedDatabase(blocking, config.ConcurrencyConfig{
+		MaxConcurrentOperations: 1,
+		WaitTimeout:             5 * time.Second,
+	})
+	ctx := context.Background()
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := limited.CountDocuments(ctx)
+			results <- err
+		}()
+	}
+
+	// Only one call reaches the backend until the first finishes
+	<-blocking.started
+	select {
+	case <-blocking.started:
+		t.Fatal("second call should wait for a free slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.EqualValues(t, 1, limited.Stats()["waiting"])
+
+	close(blocking.release)
+	require.NoError(t, <-results)
+	require.NoError(t, <-results)
+	assert.EqualValues(t, 0, limited.Stats()["rejected"])
+}
+
+func TestVectorDBConcurrencyLimitHonoursContext(t *testing.T) {
+	blocking := newBlockingDatabase(t)
+	defer close(blocking.release)
+	limited := vectordb.NewConcurrencyLimitedDatabase(blocking, config.ConcurrencyConfig{
+		MaxConcurrentOperations: 1,
+		WaitTimeout:             time.Minute,
+	})
+
+	go func() {
+		_, _ = limited.CountDocuments(context.Background())
+	}()
+	<-blocking.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := limited.CountDocuments(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMCPHealthReportsConcurrency(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			CircuitBreaker: config.CircuitBreakerConfig{
+				Enabled:          true,
+				FailureThreshold: 5,
+				ResetTimeout:     time.Second,
+			},
+			Concurrency: config.ConcurrencyConfig{MaxConcurrentOperations: 4},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"concurrency":{"docs":{"in_flight":0,"max_concurrent_operations":4,"rejected":0,"waiting":0}}`)
+	assert.Contains(t, recorder.Body.String(), `"circuit_breakers":{"docs":`)
+}
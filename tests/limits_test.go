@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedServer returns a seeded server with small configured result limits
+func newLimitedServer(t *testing.T) *mcp.Server {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Search:      config.LimitConfig{DefaultLimit: 2, MaxLimit: 3},
+			List:        config.LimitConfig{DefaultLimit: 1, MaxLimit: 4},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	for _, text := range []string{"one document", "two document", "three document", "four document", "five document"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name": "docs",
+			"url":     "https://example.com/" + strings.Fields(text)[0],
+			"text":    text,
+		})
+	}
+	return server
+}
+
+func TestConfigValidationLimits(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Port: 8030},
+		Database: config.DatabaseConfig{Type: "postgres"},
+		MCP: config.MCPConfig{
+			VectorDB: config.VectorDBConfig{Type: "mock", AllowMock: true},
+			Search:   config.LimitConfig{DefaultLimit: 5, MaxLimit: 100},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MCP.Search.DefaultLimit = 200
+	assert.ErrorContains(t, cfg.Validate(), "exceeds max_limit")
+
+	cfg.MCP.Search.DefaultLimit = 5
+	cfg.MCP.List.MaxLimit = -1
+	assert.ErrorContains(t, cfg.Validate(), "must not be negative")
+}
+
+func TestMCPSearchLimitsDefaultAndClamp(t *testing.T) {
+	server := newLimitedServer(t)
+
+	result := callTool(t, server, "search", map[string]interface{}{"db_name": "docs", "query": "document"}).(map[string]interface{})
+	assert.Equal(t, 2, result["count"])
+	assert.NotContains(t, result, "note")
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"limit":   float64(1000000),
+	}).(map[string]interface{})
+	assert.Equal(t, 3, result["count"])
+	assert.Contains(t, result["note"], "exceeds the maximum of 3")
+
+	answer := callTool(t, server, "query", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"limit":   float64(50),
+	}).(string)
+	assert.Contains(t, answer, "Found 3 relevant documents")
+	assert.Contains(t, answer, "Note: limit 50 exceeds the maximum of 3")
+}
+
+func TestMCPListLimitsDefaultAndClamp(t *testing.T) {
+	server := newLimitedServer(t)
+
+	result := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, result["count"])
+
+	result = callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "limit": float64(10)}).(map[string]interface{})
+	assert.Equal(t, 4, result["count"])
+	assert.Contains(t, result["note"], "exceeds the maximum of 4")
+}
+
+func TestMCPNegativeLimitRejected(t *testing.T) {
+	server := newLimitedServer(t)
+
+	for _, tool := range []string{"query", "search", "list_documents"} {
+		_, err := server.Tools[tool].Handler(context.Background(), map[string]interface{}{
+			"db_name": "docs",
+			"query":   "document",
+			"limit":   float64(-1),
+		})
+		require.ErrorIs(t, err, mcp.ErrInvalidArgument, tool)
+		assert.Contains(t, err.Error(), "limit must not be negative")
+	}
+	assert.Equal(t, http.StatusBadRequest,
+		postToolCall(server, `{"name": "search", "arguments": {"db_name": "docs", "query": "x", "limit": -5}}`))
+}
+
+func TestMCPListDocumentsPagination(t *testing.T) {
+	server := newLimitedServer(t)
+
+	page := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "limit": float64(2)}).(map[string]interface{})
+	assert.Equal(t, 2, page["count"])
+	assert.Equal(t, 0, page["offset"])
+	assert.Equal(t, 2, page["limit"])
+	assert.Equal(t, true, page["has_more"])
+	assert.NotContains(t, page, "total")
+
+	page = callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name": "docs",
+		"limit":   float64(2),
+		"offset":  float64(4),
+	}).(map[string]interface{})
+	assert.Equal(t, 1, page["count"])
+	assert.Equal(t, false, page["has_more"])
+
+	page = callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "limit": float64(4)}).(map[string]interface{})
+	assert.Equal(t, true, page["has_more"])
+
+	// Everything fits on the first page, so the total is known
+	page = callTool(t, newSeededServer(t, "alpha", "beta"), "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, false, page["has_more"])
+	assert.Equal(t, 2, page["total"])
+
+	_, err := server.CallTool(context.Background(), "list_documents", map[string]interface{}{"db_name": "docs", "offset": float64(-1)})
+	require.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "offset must not be negative")
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMCPLimitListenerRejectsExcessConnections(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener := server.LimitListener(inner, 1, zap.NewNop())
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	first, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	held := <-accepted
+
+	// The second connection is over the cap and is closed by the server
+	second, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	// Closing the held connection frees its slot for the next client
+	require.NoError(t, held.Close())
+	third, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection was not accepted after a slot was freed")
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMCPToolLogLevels(t *testing.T) {
+	cfg := &config.Config{
+		Logging: config.LoggingConfig{
+			ToolLevels: map[string]string{"count_documents": "debug", "write_document": "warn"},
+		},
+		MCP: config.MCPConfig{ToolTimeout: 15 * time.Second},
+	}
+	core, logs := observer.New(zapcore.InfoLevel)
+	server, err := mcp.NewServer(cfg, zap.New(core))
+	require.NoError(t, err)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	// Overrides follow the tool name CallTool puts in the context
+	ctx := context.Background()
+	_, err = server.CallTool(ctx, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://example.com/1", "text": "one"})
+	require.NoError(t, err)
+	_, err = server.CallTool(ctx, "count_documents", map[string]interface{}{"db_name": "docs"})
+	require.NoError(t, err)
+
+	// Tools without an override keep logging at info
+	require.Len(t, logs.FilterMessage("Set up vector database").All(), 1)
+	assert.Equal(t, zapcore.InfoLevel, logs.FilterMessage("Set up vector database").All()[0].Level)
+
+	written := logs.FilterMessage("Wrote document").All()
+	require.Len(t, written, 1)
+	assert.Equal(t, zapcore.WarnLevel, written[0].Level)
+	assert.Contains(t, written[0].ContextMap(), "processing_time")
+
+	// count_documents logs at debug, below the logger's level
+	assert.Empty(t, logs.FilterMessage("Counted documents").All())
+}
+
+func TestConfigRejectsUnknownToolLogLevel(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.Logging.ToolLevels = map[string]string{"search": "verbose"}
+	assert.ErrorContains(t, cfg.Validate(), "invalid logging tool_levels for 'search'")
+}
@@ -1,7 +1,11 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
 	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
@@ -103,6 +107,37 @@ func TestMCPServerCreateVectorDatabase(t *testing.T) {
 	assert.Contains(t, result.(string), "Successfully created")
 }
 
+func TestMCPServerConcurrentCreateVectorDatabase(t *testing.T) {
+	server := newTestServer(t)
+
+	const creators = 8
+	var wg sync.WaitGroup
+	errs := make([]error, creators)
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = server.CallTool(context.Background(), "create_vector_database",
+				map[string]interface{}{"db_name": "race_db", "db_type": "milvus"})
+		}(i)
+	}
+	wg.Wait()
+
+	// Exactly one create wins; the rest see the name as taken
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.True(t, errors.Is(err, mcp.ErrDatabaseExists), "unexpected error: %v", err)
+	}
+	assert.Equal(t, 1, created)
+
+	result := callTool(t, server, "list_databases", map[string]interface{}{})
+	assert.Len(t, result.(map[string]interface{})["databases"], 1)
+}
+
 func TestMCPServerListDatabasesEmpty(t *testing.T) {
 	cfg := &config.Config{
 		MCP: config.MCPConfig{
@@ -154,10 +189,10 @@ func TestMCPServerInvalidArguments(t *testing.T) {
 	
 	_, err = createTool.Handler(nil, map[string]interface{}{
 		"db_name": "test_db",
-		// Missing db_type
+		"db_type": "pinecone",
 	})
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "db_type is required")
+	assert.Contains(t, err.Error(), "unsupported vector database type 'pinecone'")
 	
 	_, err = createTool.Handler(nil, map[string]interface{}{
 		"db_type": "milvus",
@@ -165,4 +200,60 @@ func TestMCPServerInvalidArguments(t *testing.T) {
 	})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "db_name is required")
-}
\ No newline at end of file
+}
+
+func TestMCPServerCreateVectorDatabaseDefaultType(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			VectorDB:    config.VectorDBConfig{Type: "weaviate"},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	// db_type defaults to the configured type
+	result := callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "defaulted"})
+	assert.Contains(t, result.(string), "Successfully created weaviate vector database 'defaulted'")
+
+	// An explicit db_type still wins
+	result = callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "explicit", "db_type": "milvus"})
+	assert.Contains(t, result.(string), "Successfully created milvus vector database 'explicit'")
+
+	// Without a configured type, db_type is required
+	server = newTestServer(t)
+	_, err := server.CallTool(context.Background(), "create_vector_database", map[string]interface{}{"db_name": "untyped"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "db_type is required")
+}
+
+func TestMCPServerRegistersConfiguredDatabases(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15,
+			Databases: []config.DatabaseInstanceConfig{
+				{Name: "docs", Type: "milvus", Collection: "docs"},
+				{Name: "kb", Type: "weaviate"},
+			},
+		},
+	}
+
+	logger, _ := zap.NewProduction()
+	server, err := mcp.NewServer(cfg, logger)
+	require.NoError(t, err)
+
+	listTool, exists := server.Tools["list_databases"]
+	require.True(t, exists)
+
+	result, err := listTool.Handler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+
+	databases := result.(map[string]interface{})["databases"].([]map[string]interface{})
+	assert.Len(t, databases, 2)
+
+	collections := map[string]interface{}{}
+	for _, db := range databases {
+		collections[db["name"].(string)] = db["collection"]
+	}
+	assert.Equal(t, "docs", collections["docs"])
+	assert.Equal(t, "MaestroDocs", collections["kb"])
+}
@@ -0,0 +1,257 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestServer(t *testing.T) *mcp.Server {
+	return newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+		},
+	})
+}
+
+func newTestServerWithConfig(t *testing.T, cfg *config.Config) *mcp.Server {
+	logger, _ := zap.NewProduction()
+	server, err := mcp.NewServer(cfg, logger)
+	require.NoError(t, err)
+	return server
+}
+
+func TestMCPMiddlewareWrapsToolCalls(t *testing.T) {
+	server := newTestServer(t)
+
+	var calls []string
+	server.RegisterMiddleware(func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			calls = append(calls, "outer:"+mcp.ToolNameFromContext(ctx))
+			return next(ctx, args)
+		}
+	})
+	server.RegisterMiddleware(func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			calls = append(calls, "inner:"+mcp.ToolNameFromContext(ctx))
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline, "built-in timeout middleware should run first")
+			return next(ctx, args)
+		}
+	})
+
+	result, err := server.CallTool(context.Background(), "list_databases", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "No vector databases are currently active", result)
+	assert.Equal(t, []string{"outer:list_databases", "inner:list_databases"}, calls)
+
+	// HTTP dispatch goes through the same chain
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call",
+		strings.NewReader(`{"name": "list_databases", "arguments": {}}`))
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, calls, 4)
+}
+
+func TestMCPMiddlewareCanRejectCalls(t *testing.T) {
+	server := newTestServer(t)
+
+	server.RegisterMiddleware(func(next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, errors.New("access denied")
+		}
+	})
+
+	_, err := server.CallTool(context.Background(), "list_databases", map[string]interface{}{})
+	assert.EqualError(t, err, "access denied")
+
+	_, err = server.CallTool(context.Background(), "no_such_tool", nil)
+	assert.ErrorIs(t, err, mcp.ErrToolNotFound)
+}
+
+func TestMCPRegisterAndUnregisterTool(t *testing.T) {
+	server := newTestServer(t)
+
+	echo := mcp.Tool{
+		Name:        "echo",
+		Description: "Echo the message argument",
+		InputSchema: map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return args["message"], nil
+		},
+	}
+
+	require.NoError(t, server.RegisterTool(echo))
+	assert.Error(t, server.RegisterTool(echo), "duplicate names should be rejected")
+	assert.Error(t, server.RegisterTool(mcp.Tool{Name: "no_handler"}))
+
+	result, err := server.CallTool(context.Background(), "echo", map[string]interface{}{"message": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", result)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/tools/list", nil))
+	assert.Contains(t, recorder.Body.String(), `"name":"echo"`)
+
+	assert.True(t, server.UnregisterTool("echo"))
+	assert.False(t, server.UnregisterTool("echo"))
+
+	_, err = server.CallTool(context.Background(), "echo", nil)
+	assert.ErrorIs(t, err, mcp.ErrToolNotFound)
+}
+
+// TestMCPConcurrentToolRegistration exercises registration and dispatch in
+// parallel; run with -race to verify the tools registry is properly guarded.
+func TestMCPConcurrentToolRegistration(t *testing.T) {
+	server := newTestServer(t)
+	handler := server.Handler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("dynamic_%d", i)
+			assert.NoError(t, server.RegisterTool(mcp.Tool{
+				Name: name,
+				Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+					return name, nil
+				},
+			}))
+			server.UnregisterTool(name)
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			_, err := server.CallTool(context.Background(), "list_databases", map[string]interface{}{})
+			assert.NoError(t, err)
+		}()
+
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/tools/list", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}()
+	}
+	wg.Wait()
+
+	_, exists := server.GetTool("list_databases")
+	assert.True(t, exists)
+}
+
+func TestMCPToolPanicRecovery(t *testing.T) {
+	server := newTestServer(t)
+	require.NoError(t, server.RegisterTool(mcp.Tool{
+		Name: "explode",
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			var docs map[string]string
+			docs["boom"] = "nil map write"
+			return nil, nil
+		},
+	}))
+
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call",
+		strings.NewReader(`{"name": "explode", "arguments": {}}`))
+	request.Header.Set("X-Request-ID", "req-123")
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "req-123", recorder.Header().Get("X-Request-ID"))
+	assert.Contains(t, recorder.Body.String(), "tool 'explode' failed with an internal error")
+
+	// The server keeps serving other tools afterwards
+	result, err := server.CallTool(context.Background(), "list_databases", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "No vector databases are currently active", result)
+}
+
+func TestMCPDrainWaitsForInFlightCalls(t *testing.T) {
+	server := newTestServer(t)
+	handler := server.Handler()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, server.RegisterTool(mcp.Tool{
+		Name: "slow",
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "done", nil
+		},
+	}))
+
+	slowRecorder := httptest.NewRecorder()
+	callDone := make(chan struct{})
+	go func() {
+		defer close(callDone)
+		handler.ServeHTTP(slowRecorder, httptest.NewRequest(http.MethodPost, "/mcp/tools/call",
+			strings.NewReader(`{"name": "slow", "arguments": {}}`)))
+	}()
+	<-started
+
+	drainDone := make(chan error, 1)
+	go func() {
+		drainDone <- server.Drain(context.Background())
+	}()
+
+	// New calls are rejected while draining
+	require.Eventually(t, func() bool {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/tools/call",
+			strings.NewReader(`{"name": "list_databases", "arguments": {}}`)))
+		return recorder.Code == http.StatusServiceUnavailable
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-drainDone:
+		t.Fatal("drain returned before the in-flight call finished")
+	default:
+	}
+
+	close(release)
+	<-callDone
+	require.NoError(t, <-drainDone)
+	assert.Equal(t, http.StatusOK, slowRecorder.Code)
+	assert.Contains(t, slowRecorder.Body.String(), "done")
+}
+
+func TestMCPDrainTimeout(t *testing.T) {
+	server := newTestServer(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	require.NoError(t, server.RegisterTool(mcp.Tool{
+		Name: "stuck",
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		},
+	}))
+
+	go server.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp/tools/call",
+		strings.NewReader(`{"name": "stuck", "arguments": {}}`)))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.Drain(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPMigrateDocuments(t *testing.T) {
+	server := newSeededServer(t, "first", "second", "third", "fourth", "fifth")
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "archive", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "archive"})
+
+	result := callTool(t, server, "migrate_documents", map[string]interface{}{
+		"source_db":  "docs",
+		"target_db":  "archive",
+		"batch_size": float64(2),
+	}).(map[string]interface{})
+	assert.Equal(t, 5, result["migrated"])
+	assert.Equal(t, 5, result["next_offset"])
+	assert.Equal(t, true, result["completed"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "archive"}).(map[string]interface{})
+	assert.Equal(t, 5, count["count"])
+}
+
+func TestMCPMigrateDocumentsResume(t *testing.T) {
+	server := newSeededServer(t, "first", "second", "third")
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "archive", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "archive"})
+
+	result := callTool(t, server, "migrate_documents", map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "archive",
+		"offset":    float64(2),
+	}).(map[string]interface{})
+	assert.Equal(t, 1, result["migrated"])
+	assert.Equal(t, 3, result["next_offset"])
+}
+
+func TestMCPMigrateDocumentsDimensionGuard(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding:   config.EmbeddingConfig{VectorSize: 3},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/vector",
+		"text":    "document with a short vector",
+		"vector":  []interface{}{0.1, 0.2, 0.3},
+	})
+
+	// The target is set up after switching to a larger embedding model
+	cfg.MCP.Embedding.VectorSize = 8
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "archive", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "archive"})
+
+	result := callTool(t, server, "migrate_documents", map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "archive",
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["completed"])
+	assert.Equal(t, 0, result["migrated"])
+	assert.Contains(t, result["error"], "set reembed")
+
+	// Without write embedding there is nothing to re-embed the text with
+	_, err := server.CallTool(context.Background(), "migrate_documents", map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "archive",
+		"reembed":   true,
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "reembed requires mcp.embedding.writes")
+
+	// The check follows the target collection's dimension, not the configured vector_size
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "wide", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "wide"})
+	cfg.MCP.Embedding.VectorSize = 3
+	result = callTool(t, server, "migrate_documents", map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "wide",
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["completed"])
+	assert.Contains(t, result["error"], "has a 3-dimensional vector but the target expects 8")
+
+	_, err = server.Tools["migrate_documents"].Handler(context.Background(), map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "docs",
+	})
+	require.Error(t, err)
+
+	_, err = server.CallTool(context.Background(), "migrate_documents", map[string]interface{}{
+		"source_db": "docs",
+		"target_db": "wide",
+		"offset":    float64(-1),
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+func TestMCPMigrateDocumentsReembeds(t *testing.T) {
+	var calls [][]string
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        newFlakyEmbeddingEndpoint(t, &calls).URL,
+				VectorSize: 3,
+				Writes:     config.EmbeddingWritesConfig{Enabled: true, BatchSize: 2, MaxRetries: 1, RetryBackoff: time.Millisecond},
+				Fallback:   config.EmbeddingFallbackConfig{FailureThreshold: 100},
+			},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	for i, text := range []string{"first", "broken second"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name": "docs",
+			"url":     fmt.Sprintf("https://example.com/%d", i+1),
+			"text":    text,
+			"vector":  []interface{}{0.1, 0.2, 0.3},
+		})
+	}
+
+	// The target is set up for the provider's 4-dimensional vectors
+	cfg.MCP.Embedding.VectorSize = 4
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "archive", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "archive"})
+
+	// A document that cannot be re-embedded stops the migration at its batch
+	result := callTool(t, server, "migrate_documents", map[string]interface{}{
+		"source_db":  "docs",
+		"target_db":  "archive",
+		"batch_size": float64(1),
+		"reembed":    true,
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["completed"])
+	assert.Equal(t, 1, result["migrated"])
+	assert.Equal(t, 1, result["next_offset"])
+	assert.Contains(t, result["error"], "failed to re-embed document")
+
+	// The migrated document is searchable by its new vector
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "archive",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	}).(map[string]interface{})
+	results := search["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/1", results[0].Document.URL)
+	assert.Equal(t, 1.0, results[0].Score)
+}
@@ -0,0 +1,393 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCACert writes a self-signed CA certificate in PEM format and returns its path
+func writeTestCACert(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "maestro-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return path
+}
+
+func milvusConfig(tls config.MilvusTLSConfig) *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{
+			Port: 8030,
+		},
+		Database: config.DatabaseConfig{
+			Type: "postgres",
+		},
+		MCP: config.MCPConfig{
+			VectorDB: config.VectorDBConfig{
+				Type: "milvus",
+				Milvus: config.MilvusConfig{
+					Host: "milvus.example.com",
+					Port: 19530,
+					TLS:  tls,
+				},
+			},
+		},
+	}
+}
+
+func TestVectorDBMilvusTLSDisabled(t *testing.T) {
+	db, err := vectordb.NewMilvusDatabase("docs", milvusConfig(config.MilvusTLSConfig{}))
+	require.NoError(t, err)
+	assert.Nil(t, db.TLSConfig())
+}
+
+func TestVectorDBMilvusTLSWithCACert(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{
+		Enabled:    true,
+		CACert:     writeTestCACert(t),
+		ServerName: "milvus.example.com",
+	})
+	require.NoError(t, cfg.Validate())
+
+	db, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	require.NotNil(t, db.TLSConfig())
+	assert.Equal(t, "milvus.example.com", db.TLSConfig().ServerName)
+	assert.NotNil(t, db.TLSConfig().RootCAs)
+	assert.False(t, db.TLSConfig().InsecureSkipVerify)
+}
+
+func TestVectorDBMilvusTLSValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{Enabled: true})
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tls.ca_cert is required")
+
+	cfg.MCP.VectorDB.Milvus.TLS.InsecureSkipVerify = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MCP.VectorDB.Milvus.TLS = config.MilvusTLSConfig{Enabled: true, CACert: filepath.Join(t.TempDir(), "missing.pem")}
+	_, err = vectordb.NewMilvusDatabase("docs", cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read CA certificate")
+}
+
+// sizeLimitedMilvusClient rejects inserts of more than maxDocs documents as too large
+type sizeLimitedMilvusClient struct {
+	*vectordb.MockMilvusClient
+	maxDocs int
+	batches []int
+}
+
+func (c *sizeLimitedMilvusClient) Insert(ctx context.Context, collectionName string, documents []vectordb.Document) error {
+	c.batches = append(c.batches, len(documents))
+	if len(documents) > c.maxDocs {
+		return fmt.Errorf("rpc error: %w", vectordb.ErrMessageTooLarge)
+	}
+	return c.MockMilvusClient.Insert(ctx, collectionName, documents)
+}
+
+func TestVectorDBMilvusInsertSubBatches(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.MaxInsertBytes = 420
+
+	client := &sizeLimitedMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), maxDocs: 2}
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", cfg, client)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	docs := make([]vectordb.Document, 10)
+	for i := range docs {
+		docs[i] = vectordb.Document{URL: fmt.Sprintf("https://example.com/%d", i), Text: strings.Repeat("x", 80)}
+	}
+
+	stats, err := db.WriteDocuments(ctx, docs)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stats.DocumentsWritten)
+	assert.Equal(t, 5, stats.Batches)
+
+	// The size estimate allows 4 documents per insert; the first is rejected and retried as 2
+	assert.Equal(t, []int{4, 2, 2, 2, 2, 2}, client.batches)
+
+	count, err := db.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+}
+
+func TestVectorDBMilvusIndexParams(t *testing.T) {
+	ctx := context.Background()
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.VectorSize = 4
+	cfg.MCP.VectorDB.Milvus.Index = config.MilvusIndexConfig{
+		MilvusIndexParams: config.MilvusIndexParams{Type: "IVF_FLAT", NList: 256},
+		Collections: map[string]config.MilvusIndexParams{
+			// Configuration keys arrive lowercased
+			"largedocs": {Type: "hnsw", M: 32},
+		},
+	}
+
+	indexOf := func(collectionName string) map[string]interface{} {
+		db, err := vectordb.NewMilvusDatabase(collectionName, cfg)
+		require.NoError(t, err)
+		require.NoError(t, db.Setup(ctx, "default"))
+		info, err := db.GetCollectionInfo(ctx, collectionName)
+		require.NoError(t, err)
+		return info["schema"].(map[string]interface{})["index"].(map[string]interface{})
+	}
+
+	index := indexOf("Docs")
+	assert.Equal(t, "IVF_FLAT", index["index_type"])
+	assert.Equal(t, "vector", index["field_name"])
+	assert.Equal(t, map[string]interface{}{"nlist": 256}, index["params"])
+
+	index = indexOf("LargeDocs")
+	assert.Equal(t, "HNSW", index["index_type"])
+	assert.Equal(t, map[string]interface{}{"M": 32, "efConstruction": config.DefaultMilvusEfConstruction}, index["params"])
+
+	// Without configuration the previous IVF_FLAT index is built
+	assert.Equal(t, config.MilvusIndexParams{Type: "IVF_FLAT", NList: 1024}, config.MilvusIndexConfig{}.ForCollection("Docs"))
+}
+
+func TestConfigMilvusIndexValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  config.MilvusIndexParams
+		message string
+	}{
+		{"unknown type", config.MilvusIndexParams{Type: "DISKANN"}, "unsupported index type"},
+		{"nlist out of range", config.MilvusIndexParams{Type: "IVF_FLAT", NList: 70000}, "nlist must be between 1 and 65536"},
+		{"m out of range", config.MilvusIndexParams{Type: "HNSW", M: 1}, "m must be between 2 and 2048"},
+		{"negative ef_construction", config.MilvusIndexParams{Type: "HNSW", EfConstruction: -1}, "ef_construction must be positive"},
+		{"hnsw parameters on ivf", config.MilvusIndexParams{Type: "IVF_FLAT", M: 16}, "only apply to HNSW"},
+		{"nlist on hnsw", config.MilvusIndexParams{Type: "HNSW", NList: 128}, "only applies to IVF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := milvusConfig(config.MilvusTLSConfig{})
+			cfg.MCP.VectorDB.Milvus.Index.Collections = map[string]config.MilvusIndexParams{"docs": tt.params}
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "collection 'docs'")
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.Index.MilvusIndexParams = config.MilvusIndexParams{Type: "HNSW", M: 48, EfConstruction: 400}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestVectorDBMilvusReadConsistencyLevel(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	db, err := vectordb.NewMilvusDatabase("Docs", cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assert.Empty(t, db.ReadConsistencyLevel(ctx))
+
+	cfg.MCP.VectorDB.Milvus.ConsistencyLevel = "Bounded"
+	assert.Equal(t, config.ConsistencyBounded, db.ReadConsistencyLevel(ctx))
+
+	// A level asked for by the call wins over the configured one
+	strong := vectordb.WithConsistencyLevel(ctx, "Strong")
+	assert.Equal(t, config.ConsistencyStrong, db.ReadConsistencyLevel(strong))
+	assert.Equal(t, "Strong", vectordb.MilvusConsistencyLevel(db.ReadConsistencyLevel(strong)))
+}
+
+func TestConfigMilvusConsistencyLevelValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.ConsistencyLevel = "eventually"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MCP.VectorDB.Milvus.ConsistencyLevel = "linearizable"
+	assert.ErrorContains(t, cfg.Validate(), "invalid milvus consistency_level")
+}
+
+func TestMCPReadConsistencyLevelArgument(t *testing.T) {
+	server := newSeededServer(t, "alpha")
+	ctx := context.Background()
+
+	for _, tool := range []string{"count_documents", "search", "query"} {
+		args := map[string]interface{}{"db_name": "docs", "query": "alpha", "consistency_level": "Strong"}
+		_, err := server.CallTool(ctx, tool, args)
+		assert.NoError(t, err, tool)
+
+		args["consistency_level"] = "linearizable"
+		_, err = server.CallTool(ctx, tool, args)
+		assert.ErrorIs(t, err, mcp.ErrInvalidArgument, tool)
+	}
+}
+
+// staleStatsMilvusClient reports collection statistics that lag behind the stored rows
+type staleStatsMilvusClient struct {
+	*vectordb.MockMilvusClient
+	rowCount int
+}
+
+func (c *staleStatsMilvusClient) EstimateDocuments(ctx context.Context, collectionName string) (int, error) {
+	return c.rowCount, nil
+}
+
+func TestVectorDBMilvusEstimateDocumentsUsesStatistics(t *testing.T) {
+	client := &staleStatsMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), rowCount: 7}
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", milvusConfig(config.MilvusTLSConfig{}), client)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+	_, err = db.WriteDocument(ctx, vectordb.Document{URL: "https://example.com/1", Text: "one"})
+	require.NoError(t, err)
+
+	estimate, err := db.EstimateDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 7, estimate)
+
+	count, err := db.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestMCPCountDocumentsApproximate(t *testing.T) {
+	server := newSeededServer(t, "one", "two", "three")
+
+	result := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs", "approximate": true}).(map[string]interface{})
+	assert.Equal(t, 3, result["count"])
+	assert.Equal(t, true, result["approximate"])
+
+	result = callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, false, result["approximate"], "exact counts stay the default")
+
+	_, err := server.CallTool(context.Background(), "count_documents", map[string]interface{}{
+		"db_name":           "docs",
+		"approximate":       true,
+		"consistency_level": "strong",
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+// slowLoadMilvusClient reports a new collection as still loading for the first pendingPolls state checks
+type slowLoadMilvusClient struct {
+	*vectordb.MockMilvusClient
+	pendingPolls int
+	polls        int
+}
+
+func (c *slowLoadMilvusClient) CollectionState(ctx context.Context, collectionName string) (vectordb.MilvusCollectionState, error) {
+	c.polls++
+	if c.polls <= c.pendingPolls {
+		return vectordb.MilvusCollectionState{TotalRows: 1}, nil
+	}
+	return c.MockMilvusClient.CollectionState(ctx, collectionName)
+}
+
+func TestVectorDBMilvusSetupWaitsForReadiness(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: 5 * time.Second, Interval: time.Millisecond}
+	client := &slowLoadMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), pendingPolls: 3}
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", cfg, client)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Setup(context.Background(), "default"))
+	assert.Equal(t, 4, client.polls)
+}
+
+func TestVectorDBMilvusSetupReadinessTimeout(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: 20 * time.Millisecond, Interval: time.Millisecond}
+	client := &slowLoadMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), pendingPolls: 1 << 30}
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", cfg, client)
+	require.NoError(t, err)
+
+	err = db.Setup(context.Background(), "default")
+	assert.ErrorIs(t, err, vectordb.ErrCollectionNotReady)
+	assert.Contains(t, err.Error(), "0 of 1 rows indexed")
+
+	// Without a timeout the collection is not polled at all
+	cfg.MCP.VectorDB.Milvus.Readiness.Timeout = 0
+	client = &slowLoadMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), pendingPolls: 1 << 30}
+	db, err = vectordb.NewMilvusDatabaseWithClient("docs", cfg, client)
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(context.Background(), "default"))
+	assert.Zero(t, client.polls)
+}
+
+func TestVectorDBMilvusSetupReadinessBoundedByDeadline(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: time.Minute, Interval: time.Millisecond}
+	client := &slowLoadMilvusClient{MockMilvusClient: vectordb.NewMockMilvusClient(), pendingPolls: 1 << 30}
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", cfg, client)
+	require.NoError(t, err)
+
+	// A caller's deadline shorter than the readiness timeout still reports the collection as not ready
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = db.Setup(ctx, "default")
+	assert.ErrorIs(t, err, vectordb.ErrCollectionNotReady)
+	assert.ErrorContains(t, err, "0 of 1 rows indexed, loaded: false")
+}
+
+func TestMCPSetupDatabaseUsesSetupTimeout(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Timeouts:    map[string]time.Duration{"tool_call": time.Nanosecond, "setup_database": time.Minute},
+		},
+	})
+	_, err := server.Tools["create_vector_database"].Handler(context.Background(), map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	require.NoError(t, err)
+
+	// setup_database and create_collection are bounded by setup_database, not tool_call
+	_, err = server.CallTool(context.Background(), "setup_database", map[string]interface{}{"db_name": "docs"})
+	require.NoError(t, err)
+	_, err = server.CallTool(context.Background(), "create_collection", map[string]interface{}{"db_name": "docs", "collection_name": "Other"})
+	require.NoError(t, err)
+}
+
+func TestConfigMilvusReadinessValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: -time.Second}
+	assert.ErrorContains(t, cfg.Validate(), "milvus readiness timeout must not be negative")
+
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: time.Second}
+	assert.ErrorContains(t, cfg.Validate(), "milvus readiness interval must be positive")
+
+	// The wait must fit in the setup_database timeout
+	cfg.MCP.Timeouts = map[string]time.Duration{"setup_database": 10 * time.Second}
+	cfg.MCP.VectorDB.Milvus.Readiness = config.MilvusReadinessConfig{Timeout: time.Minute, Interval: time.Second}
+	assert.ErrorContains(t, cfg.Validate(), "milvus readiness timeout of 1m0s must not exceed mcp.timeouts.setup_database of 10s")
+}
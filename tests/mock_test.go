@@ -206,10 +206,10 @@ func TestMockVectorDatabaseIntegration(t *testing.T) {
 	assert.Equal(t, 1, count)
 	
 	// Test Weaviate database
-	weaviateDB, err := vectordb.NewWeaviateDatabase("test_weaviate", cfg)
+	weaviateDB, err := vectordb.NewWeaviateDatabase("TestWeaviate", cfg)
 	require.NoError(t, err)
 	assert.Equal(t, "weaviate", weaviateDB.Type())
-	assert.Equal(t, "test_weaviate", weaviateDB.CollectionName())
+	assert.Equal(t, "TestWeaviate", weaviateDB.CollectionName())
 	
 	// Test setup
 	err = weaviateDB.Setup(ctx, "default")
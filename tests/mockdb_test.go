@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBackendConfig returns a server config with the mock backend enabled
+func mockBackendConfig() *config.Config {
+	return &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			VectorDB:    config.VectorDBConfig{AllowMock: true},
+		},
+	}
+}
+
+func TestConfigValidationMockBackend(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Port: 8030},
+		Database: config.DatabaseConfig{Type: "postgres"},
+		MCP: config.MCPConfig{
+			VectorDB: config.VectorDBConfig{Type: "mock"},
+		},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "allow_mock")
+
+	cfg.MCP.VectorDB.AllowMock = true
+	assert.NoError(t, cfg.Validate())
+
+	// Development mode allows the mock without the flag
+	cfg.MCP.VectorDB.AllowMock = false
+	cfg.Logging.Level = "debug"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestVectorDBMockDatabaseRequiresOptIn(t *testing.T) {
+	_, err := vectordb.CreateVectorDatabase("mock", "docs", &config.Config{})
+	assert.Error(t, err)
+
+	db, err := vectordb.CreateVectorDatabase("mock", "docs", mockBackendConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "mock", db.Type())
+}
+
+func TestMCPMockBackendEndToEnd(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "demo",
+		"url":      "https://example.com/go",
+		"text":     "Go is a statically typed language",
+		"metadata": map[string]interface{}{"topic": "go"},
+	})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "demo",
+		"url":     "https://example.com/coffee",
+		"text":    "Brewing coffee with a French press",
+	})
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 2, count["count"])
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "demo",
+		"query":   "typed language",
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 2)
+	assert.Equal(t, "https://example.com/go", results[0].Document.URL)
+
+	deleted := callTool(t, server, "delete_by_filter", map[string]interface{}{
+		"db_name": "demo",
+		"filter":  map[string]interface{}{"topic": "go"},
+	}).(map[string]interface{})
+	assert.Equal(t, 1, deleted["deleted"])
+}
+
+func TestMCPMockBackendDisabledByDefault(t *testing.T) {
+	server := newTestServer(t)
+
+	_, err := server.Tools["create_vector_database"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "demo",
+		"db_type": "mock",
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Equal(t, http.StatusBadRequest,
+		postToolCall(server, `{"name": "create_vector_database", "arguments": {"db_name": "demo", "db_type": "mock"}}`))
+}
+
+func TestVectorDBMockDatabaseWriteDocumentsSharesStore(t *testing.T) {
+	db, err := vectordb.CreateVectorDatabase("mock", "docs", mockBackendConfig())
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	stats, err := db.WriteDocuments(ctx, []vectordb.Document{
+		{URL: "https://example.com/1", Text: "milvus stores vectors"},
+		{URL: "https://example.com/2", Text: "weaviate stores objects"},
+		{URL: "https://example.com/3", Text: "bread recipes"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.DocumentsWritten)
+
+	count, err := db.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	docs, err := db.ListDocuments(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, docs, 3)
+
+	results, err := db.Search(ctx, "bread", 1, "")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/3", results[0].Document.URL)
+}
+
+func TestMCPMockBackendWriteDocumentsBatch(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	result := callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "demo",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/go", "text": "Go is a statically typed language"},
+			map[string]interface{}{"url": "https://example.com/rust", "text": "Rust is a memory safe systems language"},
+			map[string]interface{}{"url": "https://example.com/coffee", "text": "Brewing coffee with a French press"},
+		},
+	}).(map[string]interface{})
+	stats := result["write_stats"].(vectordb.WriteStats)
+	assert.Equal(t, 3, stats.DocumentsWritten)
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 3, listed["count"])
+
+	searched := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "demo",
+		"query":   "coffee press",
+	}).(map[string]interface{})
+	results := searched["results"].([]vectordb.SearchResult)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "https://example.com/coffee", results[0].Document.URL)
+}
+
+func TestMCPWriteDocumentsValidatesEveryDocument(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "demo", "db_type": "mock"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "demo"})
+
+	_, err := server.Tools["write_documents"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "demo",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/ok", "text": "fine"},
+			map[string]interface{}{"url": "https://example.com/empty", "text": ""},
+		},
+	})
+	require.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "documents[1]")
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "demo"}).(map[string]interface{})
+	assert.Equal(t, 0, count["count"])
+}
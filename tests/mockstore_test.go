@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockSequentialIDs(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{{Text: "a"}, {Text: "b"}}))
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{{Text: "c"}, {ID: "kept", Text: "d"}}))
+
+	docs, err := client.ListDocuments(ctx, "docs", 10, 0)
+	require.NoError(t, err)
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	assert.Equal(t, []string{"doc_1", "doc_2", "doc_3", "kept"}, ids)
+}
+
+func TestMockInjectableIDGenAndClock(t *testing.T) {
+	client := vectordb.NewMockWeaviateClient()
+	next := 0
+	client.IDGen = func() string {
+		next++
+		return fmt.Sprintf("fixed-%d", next)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	client.Clock = func() time.Time { return created }
+
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "Docs", nil))
+	require.NoError(t, client.Insert(ctx, "Docs", []vectordb.Document{{Text: "a"}}))
+
+	doc, err := client.GetDocument(ctx, "Docs", "fixed-1")
+	require.NoError(t, err)
+	assert.Equal(t, "a", doc.Text)
+
+	info, err := client.GetCollectionInfo(ctx, "Docs")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-02T03:04:05Z", info["created_at"])
+}
+
+func TestMockQueryTruncatesMultibyteText(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+
+	// 99 ASCII characters followed by multi-byte runes straddle the byte-based cut
+	text := strings.Repeat("a", 99) + "日本語のテキスト"
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{{Text: text}}))
+
+	result, err := client.Query(ctx, "docs", "テキスト", 1)
+	require.NoError(t, err)
+	summary := result.(string)
+	assert.True(t, utf8.ValidString(summary))
+	assert.Contains(t, summary, strings.Repeat("a", 99)+"日 (Score:")
+	assert.NotContains(t, summary, "本")
+}
+
+func TestMockQuerySummarizesShortText(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{
+		{Text: "Grüße"},
+		{URL: "https://example.com/untitled"},
+	}))
+
+	result, err := client.Query(ctx, "docs", "grüße", 2)
+	require.NoError(t, err)
+	assert.Contains(t, result, "1. Grüße (Score: 1.00)")
+	assert.Contains(t, result, "2. https://example.com/untitled (Score: 0.00)")
+}
+
+func TestMockHonoursCancelledContext(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	require.NoError(t, client.CreateCollection(context.Background(), "docs", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.Insert(ctx, "docs", []vectordb.Document{{Text: "a"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = client.Search(ctx, "docs", "a", 5)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = client.CountDocuments(ctx, "docs")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	count, err := client.CountDocuments(context.Background(), "docs")
+	require.NoError(t, err)
+	assert.Zero(t, count, "a cancelled insert must not write")
+}
+
+func TestMockDelayRespectsDeadline(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	require.NoError(t, client.CreateCollection(context.Background(), "docs", nil))
+	client.Delay = time.Second
+
+	db, err := vectordb.NewMilvusDatabaseWithClient("docs", &config.Config{}, client)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = db.CountDocuments(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestMockFailEveryNthCall(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+
+	injected := errors.New("injected")
+	client.Fault = vectordb.FailEveryNth(3, injected)
+
+	// The collection was created on call 1, so calls 3 and 6 fail
+	var failures []int
+	for call := 2; call <= 7; call++ {
+		if _, err := client.CountDocuments(ctx, "docs"); err != nil {
+			assert.ErrorIs(t, err, injected)
+			failures = append(failures, call)
+		}
+	}
+	assert.Equal(t, []int{3, 6}, failures)
+}
+
+func TestMockFailOperationAndLatency(t *testing.T) {
+	client := vectordb.NewMockWeaviateClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "Docs", nil))
+
+	client.Fault = vectordb.FailOperation("Insert", vectordb.ErrMessageTooLarge)
+	client.Latency = map[string]time.Duration{"Search": time.Second}
+
+	err := client.Insert(ctx, "Docs", []vectordb.Document{{Text: "a"}})
+	assert.ErrorIs(t, err, vectordb.ErrMessageTooLarge)
+	_, err = client.CountDocuments(ctx, "Docs")
+	assert.NoError(t, err)
+
+	deadline, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Search(deadline, "Docs", "a", 5)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestVectorDBCircuitBreakerWithInjectedFaults(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+
+	inner, err := vectordb.NewMilvusDatabaseWithClient("docs", &config.Config{}, client)
+	require.NoError(t, err)
+	breaker := vectordb.NewCircuitBreakerDatabase(inner, config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 2,
+		ResetTimeout:     time.Minute,
+	})
+
+	client.Fault = vectordb.FailOperation("CountDocuments", errors.New("connection reset"))
+	for i := 0; i < 2; i++ {
+		_, err = breaker.CountDocuments(ctx)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, vectordb.CircuitOpen, breaker.State())
+
+	client.Fault = nil
+	_, err = breaker.CountDocuments(ctx)
+	assert.ErrorIs(t, err, vectordb.ErrBackendUnavailable)
+}
+
+func TestMockDeleteDocumentsReportsEachID(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{{ID: "a"}, {ID: "b"}, {ID: "c"}}))
+
+	// A missing ID does not stop the IDs after it
+	report, err := client.DeleteDocuments(ctx, "docs", []string{"a", "missing", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, report.Deleted)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, "missing", report.Failed[0].ID)
+	assert.Contains(t, report.Failed[0].Error, "not found")
+
+	count, err := client.CountDocuments(ctx, "docs")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNamedVectorServer returns a server with a set-up Milvus database "docs"
+// declaring title and body vector spaces
+func newNamedVectorServer(t *testing.T) *mcp.Server {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				NamedVectors: map[string]int{"title": 2, "body": 3},
+			},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	return server
+}
+
+func TestMCPNamedVectorSearch(t *testing.T) {
+	server := newNamedVectorServer(t)
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/both",
+		"text":    "titled document",
+		"vectors": map[string]interface{}{
+			"title": []interface{}{0.1, 0.2},
+			"body":  []interface{}{0.1, 0.2, 0.3},
+		},
+	})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/body",
+		"text":    "body only document",
+		"vectors": map[string]interface{}{"body": []interface{}{0.3, 0.2, 0.1}},
+	})
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":     "docs",
+		"query":       "document",
+		"vector_name": "title",
+	}).(map[string]interface{})
+	require.Equal(t, 1, result["count"])
+	assert.Equal(t, "titled document", result["results"].([]vectordb.SearchResult)[0].Document.Text)
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "docs",
+		"query":           "document",
+		"vector_name":     "body",
+		"include_vectors": true,
+	}).(map[string]interface{})
+	require.Equal(t, 2, result["count"])
+	assert.Equal(t, []float64{0.1, 0.2, 0.3}, result["results"].([]vectordb.SearchResult)[0].Document.Vectors["body"])
+
+	// Without vector_name the default vector space is searched
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["count"])
+	assert.Nil(t, result["results"].([]vectordb.SearchResult)[0].Document.Vectors)
+
+	tool, _ := server.GetTool("search")
+	_, err := tool.Handler(context.Background(), map[string]interface{}{
+		"db_name":     "docs",
+		"query":       "document",
+		"vector_name": "summary",
+	})
+	assert.ErrorIs(t, err, vectordb.ErrUnknownVector)
+}
+
+func TestMCPNamedVectorValidation(t *testing.T) {
+	server := newNamedVectorServer(t)
+	tool, _ := server.GetTool("write_document")
+
+	_, err := tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/short",
+		"text":    "wrong dimension",
+		"vectors": map[string]interface{}{"title": []interface{}{0.1, 0.2, 0.3}},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDimensionMismatch)
+
+	_, err = tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/unknown",
+		"text":    "unknown space",
+		"vectors": map[string]interface{}{"summary": []interface{}{0.1, 0.2}},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrUnknownVector)
+}
+
+func TestVectorDBNamedVectorSchema(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.NamedVectors = map[string]int{"title": 2, "body": 3}
+
+	ctx := context.Background()
+	db, err := vectordb.NewMilvusDatabase("docs", cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	info, err := db.GetCollectionInfo(ctx, "docs")
+	require.NoError(t, err)
+	fields := info["schema"].(map[string]interface{})["fields"].([]map[string]interface{})
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field["name"].(string)
+	}
+	assert.Equal(t, []string{"id", "url", "text", "metadata", "vector", "body", "title", "created_at", "updated_at", "embedding_text"}, names)
+	assert.Equal(t, 3, fields[5]["dimension"])
+}
+
+func TestConfigNamedVectorValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Embedding.NamedVectors = map[string]int{"title": 0}
+	assert.Error(t, cfg.Validate())
+
+	cfg.MCP.Embedding.NamedVectors = map[string]int{"vector": 8}
+	assert.Error(t, cfg.Validate())
+
+	cfg.MCP.Embedding.NamedVectors = map[string]int{"title": 8}
+	assert.NoError(t, cfg.Validate())
+}
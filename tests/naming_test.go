@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVectorDBValidateCollectionName(t *testing.T) {
+	assert.NoError(t, vectordb.ValidateCollectionName("milvus", "maestro_docs"))
+	assert.NoError(t, vectordb.ValidateCollectionName("milvus", "_Docs2"))
+	assert.NoError(t, vectordb.ValidateCollectionName("weaviate", "MaestroDocs"))
+
+	err := vectordb.ValidateCollectionName("milvus", "my-docs")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Milvus collection name")
+
+	err = vectordb.ValidateCollectionName("milvus", "1docs")
+	assert.Error(t, err)
+
+	err = vectordb.ValidateCollectionName("weaviate", "maestroDocs")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Weaviate class names must start with an uppercase letter")
+
+	err = vectordb.ValidateCollectionName("weaviate", "Maestro Docs")
+	assert.Error(t, err)
+
+	err = vectordb.ValidateCollectionName("milvus", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "collection name is required")
+}
+
+func TestMCPServerCreateVectorDatabaseInvalidNames(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15,
+		},
+	}
+
+	logger, _ := zap.NewProduction()
+	server, err := mcp.NewServer(cfg, logger)
+	require.NoError(t, err)
+
+	createTool, exists := server.Tools["create_vector_database"]
+	require.True(t, exists)
+
+	_, err = createTool.Handler(nil, map[string]interface{}{
+		"db_name":         "docs",
+		"db_type":         "weaviate",
+		"collection_name": "lowercase",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must start with an uppercase letter")
+
+	_, err = createTool.Handler(nil, map[string]interface{}{
+		"db_name": "bad name!",
+		"db_type": "milvus",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid db_name")
+}
+
+func TestVectorDBRenderCollectionName(t *testing.T) {
+	name, err := vectordb.RenderCollectionName("milvus", "tenant_{tenant}_docs", map[string]string{"tenant": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant_acme_docs", name)
+
+	_, err = vectordb.RenderCollectionName("milvus", "tenant_{tenant}_docs", map[string]string{})
+	assert.ErrorContains(t, err, "needs a value for {tenant}")
+
+	_, err = vectordb.RenderCollectionName("milvus", "tenant_{tenant}_docs", map[string]string{"tenant": "acme-corp"})
+	assert.ErrorContains(t, err, "may only contain letters, digits, and underscores")
+
+	// The rendered name must still follow the backend's rules
+	_, err = vectordb.RenderCollectionName("weaviate", "{tenant}Docs", map[string]string{"tenant": "acme"})
+	assert.ErrorContains(t, err, "must start with an uppercase letter")
+}
+
+func TestMCPCollectionTemplate(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.ToolTimeout = 15 * time.Second
+	cfg.MCP.VectorDB.CollectionTemplate = "tenant_{tenant}_{db_name}"
+	server := newTestServerWithConfig(t, cfg)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":         "docs",
+		"db_type":         "mock",
+		"collection_vars": map[string]interface{}{"tenant": "acme"},
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	result := callTool(t, server, "get_vector_dimension", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, "tenant_acme_docs", result["collection"])
+
+	message := callTool(t, server, "create_collection", map[string]interface{}{
+		"db_name":         "docs",
+		"collection_vars": map[string]interface{}{"tenant": "globex"},
+	})
+	assert.Contains(t, message, "'tenant_globex_docs'")
+
+	// An explicit collection_name still wins over the template
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":         "other",
+		"db_type":         "mock",
+		"collection_name": "Fixed",
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "other"})
+	result = callTool(t, server, "get_vector_dimension", map[string]interface{}{"db_name": "other"}).(map[string]interface{})
+	assert.Equal(t, "Fixed", result["collection"])
+
+	_, err := server.CallTool(context.Background(), "create_vector_database", map[string]interface{}{
+		"db_name": "missing",
+		"db_type": "mock",
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.ErrorContains(t, err, "needs a value for {tenant}")
+}
+
+func TestConfigRejectsMalformedCollectionTemplate(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.VectorDB.CollectionTemplate = "tenant_{tenant-id}_docs"
+	assert.ErrorContains(t, cfg.Validate(), "invalid vector_db collection_template")
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVectorDBPreprocessorSteps(t *testing.T) {
+	p, err := vectordb.NewPreprocessor(config.PreprocessConfig{
+		Steps: []string{"strip_html", "normalize_whitespace", "lowercase"},
+	})
+	require.NoError(t, err)
+
+	raw := "<html><style>p { color: red }</style><p>Install  the\n<b>CLI</b></p><p>Fish &amp; Chips</p><!-- hidden --></html>"
+	assert.Equal(t, "install the cli fish & chips", p.Preprocess(raw))
+
+	doc := vectordb.PreprocessDocument(p, vectordb.Document{Text: raw})
+	assert.Equal(t, raw, doc.Text, "the original text is kept")
+	assert.Equal(t, "install the cli fish & chips", doc.TextForEmbedding())
+
+	doc = vectordb.PreprocessDocument(p, vectordb.Document{Text: "already clean"})
+	assert.Empty(t, doc.EmbeddingText, "unchanged text is not stored twice")
+
+	none, err := vectordb.NewPreprocessor(config.PreprocessConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	_, err = vectordb.NewPreprocessor(config.PreprocessConfig{Steps: []string{"stem"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown preprocess step 'stem'")
+}
+
+func TestMCPPreprocessedTextUsedForEmbedding(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Preprocess.Steps = []string{"strip_html", "normalize_whitespace", "lowercase"}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	html := "<div class=\"install\"><h1>Install</h1><p>Run the INSTALLER</p></div>"
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/install",
+		"text":    html,
+	})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/other",
+		"text":    "unrelated notes about div class markup",
+	})
+
+	// Markup no longer matches, while the cleaned words do in any case
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "Run   the <em>Installer</em>",
+		"limit":   float64(1),
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/install", results[0].Document.URL)
+	assert.Equal(t, html, results[0].Document.Text, "results return the original text")
+
+	text := callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "INSTALLER"}).(string)
+	assert.Contains(t, text, "for query 'INSTALLER'")
+
+	batch := callTool(t, server, "batch_query", map[string]interface{}{
+		"db_name": "docs",
+		"queries": []interface{}{map[string]interface{}{"query": "Installer", "limit": float64(1)}},
+	}).(map[string]interface{})
+	first := batch["results"].([]map[string]interface{})[0]
+	assert.Equal(t, "https://example.com/install", first["results"].([]vectordb.SearchResult)[0].Document.URL)
+}
+
+func TestMCPSetTextPreprocessor(t *testing.T) {
+	server := newSeededServer(t)
+	server.SetTextPreprocessor(vectordb.PreprocessorFunc(func(text string) string {
+		return strings.ReplaceAll(text, "k8s", "kubernetes")
+	}))
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/k8s",
+		"text":    "deploy on k8s",
+	})
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "kubernetes",
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "deploy on k8s", results[0].Document.Text)
+	assert.Greater(t, results[0].Score, 0.0)
+
+	cfg := mockBackendConfig()
+	cfg.MCP.Preprocess.Steps = []string{"stem"}
+	_, err := mcp.NewServer(cfg, zap.NewNop())
+	require.Error(t, err)
+}
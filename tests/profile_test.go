@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigEmbeddingProfile(t *testing.T) {
+	unknown := config.EmbeddingConfig{Model: "in-house-model"}
+	profile, source := unknown.ResolvedProfile()
+	assert.Equal(t, config.EmbeddingProfile{Metric: config.MetricCosine}, profile)
+	assert.Equal(t, config.ProfileSourceDefault, source)
+
+	bge := config.EmbeddingConfig{Model: "BAAI/bge-base-en-v1.5"}
+	profile, source = bge.ResolvedProfile()
+	assert.Equal(t, config.EmbeddingProfile{Metric: config.MetricIP, Normalize: true}, profile)
+	assert.Equal(t, config.ProfileSourceModel, source)
+	assert.True(t, bge.RequiresNormalization())
+
+	// An explicit metric overrides the model's recommendation
+	bge.Metric = "L2"
+	profile, source = bge.ResolvedProfile()
+	assert.Equal(t, config.MetricL2, profile.Metric)
+	assert.Equal(t, config.ProfileSourceConfig, source)
+	assert.False(t, bge.RequiresNormalization(), "l2 never normalizes")
+
+	dot := config.EmbeddingConfig{Model: "sentence-transformers/multi-qa-mpnet-base-dot-v1"}
+	assert.Equal(t, config.MetricIP, dot.EffectiveMetric())
+	assert.False(t, dot.RequiresNormalization(), "dot-product models keep their magnitudes")
+	dot.Normalize = true
+	assert.True(t, dot.RequiresNormalization())
+}
+
+func TestMCPGetCollectionInfoReportsProfile(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Embedding.Model = "baai/bge-small-en-v1.5"
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	result := callTool(t, server, "get_collection_info", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", result["collection"])
+	assert.Equal(t, map[string]interface{}{
+		"model":     "baai/bge-small-en-v1.5",
+		"metric":    config.MetricIP,
+		"normalize": true,
+		"source":    config.ProfileSourceModel,
+	}, result["embedding_profile"])
+
+	// The profile's metric is what setup created the collection with
+	info := result["info"].(map[string]interface{})
+	schema := info["schema"].(map[string]interface{})
+	assert.Equal(t, config.MetricIP, schema["metric_type"])
+
+	dimension := callTool(t, server, "get_vector_dimension", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, config.MetricIP, dimension["metric"])
+
+	_, err := server.Tools["get_collection_info"].Handler(context.Background(), map[string]interface{}{"db_name": "missing"})
+	require.Error(t, err)
+}
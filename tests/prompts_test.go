@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPPromptsList(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Prompts: []config.PromptConfig{{
+				Name:      "summarize",
+				Arguments: []config.PromptArgumentConfig{{Name: "topic", Required: true}},
+				Template:  "Summarize what is known about {topic}.",
+			}},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/prompts/list", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Prompts []mcp.Prompt `json:"prompts"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Prompts, 2)
+	assert.Equal(t, "answer_with_documents", response.Prompts[0].Name)
+	assert.Equal(t, "summarize", response.Prompts[1].Name)
+	assert.Equal(t, []mcp.PromptArgument{{Name: "topic", Required: true}}, response.Prompts[1].Arguments)
+}
+
+func TestMCPPromptsGetRetrievesDocuments(t *testing.T) {
+	server := newSeededServer(t, "Qubits hold superposed states", "Gates act on qubits")
+
+	body, err := json.Marshal(map[string]interface{}{
+		"name":      "answer_with_documents",
+		"arguments": map[string]string{"db_name": "docs", "query": "qubits", "limit": "1"},
+	})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/prompts/get", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result mcp.PromptResult
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "user", result.Messages[0].Role)
+	assert.Equal(t, "text", result.Messages[0].Content["type"])
+	text := result.Messages[0].Content["text"]
+	assert.Contains(t, text, "retrieved from docs")
+	assert.Contains(t, text, "Question: qubits")
+	assert.Contains(t, text, "Found 1 relevant documents for query 'qubits'")
+}
+
+func TestMCPPromptsGetConfiguredTemplate(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Prompts: []config.PromptConfig{{
+				Name:      "summarize",
+				Arguments: []config.PromptArgumentConfig{{Name: "topic", Required: true}},
+				Template:  "Summarize what is known about {topic}.",
+			}},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	result, err := server.GetPrompt(context.Background(), "summarize", map[string]string{"topic": "error correction"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize what is known about error correction.", result.Messages[0].Content["text"])
+
+	_, err = server.GetPrompt(context.Background(), "summarize", map[string]string{})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+
+	_, err = server.GetPrompt(context.Background(), "summarize", map[string]string{"topic": "x", "tone": "formal"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+
+	_, err = server.GetPrompt(context.Background(), "missing", nil)
+	assert.ErrorIs(t, err, mcp.ErrPromptNotFound)
+}
+
+func TestMCPPromptsGetUnknownDatabase(t *testing.T) {
+	server := newTestServer(t)
+
+	_, err := server.GetPrompt(context.Background(), "answer_with_documents", map[string]string{"db_name": "missing", "query": "q"})
+	assert.ErrorIs(t, err, mcp.ErrDatabaseNotFound)
+}
+
+func TestConfigPromptValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt config.PromptConfig
+		errMsg string
+	}{
+		{
+			name:   "undeclared placeholder",
+			prompt: config.PromptConfig{Name: "p", Template: "About {topic}"},
+			errMsg: "{topic} is not a declared argument",
+		},
+		{
+			name: "documents without query",
+			prompt: config.PromptConfig{
+				Name:      "p",
+				Arguments: []config.PromptArgumentConfig{{Name: "db_name"}},
+				Template:  "{documents}",
+			},
+			errMsg: "must declare db_name and query",
+		},
+		{
+			name:   "missing template",
+			prompt: config.PromptConfig{Name: "p"},
+			errMsg: "template is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := milvusConfig(config.MilvusTLSConfig{})
+			cfg.MCP.Prompts = []config.PromptConfig{tt.prompt}
+			assert.ErrorContains(t, cfg.Validate(), tt.errMsg)
+		})
+	}
+}
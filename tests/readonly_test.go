@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPReadOnlyDatabaseRejectsMutations(t *testing.T) {
+	server := newSeededServer(t, "alpha document")
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":   "kb",
+		"db_type":   "milvus",
+		"read_only": true,
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "kb"})
+
+	mutations := map[string]map[string]interface{}{
+		"write_document":      {"db_name": "kb", "url": "https://example.com/x", "text": "x"},
+		"write_documents":     {"db_name": "kb", "documents": []interface{}{map[string]interface{}{"url": "https://example.com/x", "text": "x"}}},
+		"delete_document":     {"db_name": "kb", "document_id": "missing"},
+		"delete_by_filter":    {"db_name": "kb", "filter": map[string]interface{}{"source": "wiki"}},
+		"truncate_collection": {"db_name": "kb"},
+		"purge":               {"db_name": "kb"},
+		"migrate_documents":   {"source_db": "docs", "target_db": "kb"},
+		"cleanup":             {"db_name": "kb"},
+	}
+	for name, args := range mutations {
+		_, err := server.CallTool(context.Background(), name, args)
+		require.Error(t, err, name)
+		assert.True(t, errors.Is(err, mcp.ErrReadOnly), "%s: %v", name, err)
+	}
+
+	assert.Equal(t, http.StatusForbidden, postToolCall(server,
+		`{"name": "write_document", "arguments": {"db_name": "kb", "url": "https://example.com/x", "text": "x"}}`))
+
+	// Reads still work, and other databases are unaffected
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "kb"}).(map[string]interface{})
+	assert.Equal(t, 0, count["count"])
+	callTool(t, server, "search", map[string]interface{}{"db_name": "kb", "query": "alpha"})
+	callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://example.com/y", "text": "y"})
+
+	databases := callTool(t, server, "list_databases", map[string]interface{}{}).(map[string]interface{})
+	flags := map[string]bool{}
+	for _, db := range databases["databases"].([]map[string]interface{}) {
+		flags[db["name"].(string)] = db["read_only"].(bool)
+	}
+	assert.Equal(t, map[string]bool{"docs": false, "kb": true}, flags)
+}
+
+func TestMCPConfiguredReadOnlyDatabase(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.Databases = []config.DatabaseInstanceConfig{
+		{Name: "kb", Type: "mock", ReadOnly: true},
+	}
+	server := newTestServerWithConfig(t, cfg)
+
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "kb",
+		"url":     "https://example.com/x",
+		"text":    "x",
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, mcp.ErrReadOnly))
+	assert.Contains(t, err.Error(), "read-only")
+
+	deleted, err := server.SweepExpired(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBBoostRecency(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	halfLife := 30 * 24 * time.Hour
+	results := []vectordb.SearchResult{
+		{Document: vectordb.Document{ID: "old", CreatedAt: now.Add(-halfLife)}, Score: 0.9},
+		{Document: vectordb.Document{ID: "unstamped"}, Score: 0.8},
+		{Document: vectordb.Document{ID: "new", CreatedAt: now}, Score: 0.85},
+	}
+
+	boosted := vectordb.BoostRecency(results, 0.5, halfLife, now)
+	require.Len(t, boosted, 3)
+	assert.Equal(t, "new", boosted[0].Document.ID)
+	assert.InDelta(t, 0.925, boosted[0].Score, 1e-9)
+	assert.Equal(t, "unstamped", boosted[1].Document.ID, "documents without timestamps keep their score")
+	assert.InDelta(t, 0.8, boosted[1].Score, 1e-9)
+	assert.InDelta(t, 0.7, boosted[2].Score, 1e-9, "a document one half-life old keeps half the recency part")
+
+	// The input is left untouched and a zero weight is a no-op
+	assert.Equal(t, 0.9, results[0].Score)
+	assert.Equal(t, results, vectordb.BoostRecency(results, 0, halfLife, now))
+
+	assert.NoError(t, vectordb.ValidateRecencyWeight(0.3))
+	assert.Error(t, vectordb.ValidateRecencyWeight(-0.1))
+	assert.Error(t, vectordb.ValidateRecencyWeight(1.5))
+}
+
+func TestMCPSearchRecencyWeight(t *testing.T) {
+	server := newSeededServer(t)
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":    "docs",
+		"url":        "https://example.com/old",
+		"text":       "release notes",
+		"created_at": "2015-01-01T00:00:00Z",
+	})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/new",
+		"text":    "release notes and more",
+	})
+
+	// The old document matches better, so it wins on similarity alone
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "release notes",
+		"limit":   float64(1),
+	}).(map[string]interface{})
+	assert.Equal(t, "https://example.com/old", result["results"].([]vectordb.SearchResult)[0].Document.URL)
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name":        "docs",
+		"query":          "release notes",
+		"limit":          float64(1),
+		"recency_weight": 0.2,
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/new", results[0].Document.URL)
+
+	text := callTool(t, server, "query", map[string]interface{}{
+		"db_name":        "docs",
+		"query":          "release notes",
+		"limit":          float64(2),
+		"recency_weight": 0.2,
+	}).(string)
+	assert.Contains(t, text, "Found 2 relevant documents")
+
+	_, err := server.Tools["search"].Handler(context.Background(), map[string]interface{}{
+		"db_name":        "docs",
+		"query":          "release notes",
+		"recency_weight": 2.0,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recency_weight must be between 0 and 1")
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reingestDocuments builds the documents argument of reingest from texts
+func reingestDocuments(texts ...string) []interface{} {
+	documents := make([]interface{}, len(texts))
+	for i, text := range texts {
+		documents[i] = map[string]interface{}{"url": "https://example.com/new/" + text, "text": text}
+	}
+	return documents
+}
+
+// documentCount returns count_documents for an instance
+func documentCount(t *testing.T, server *mcp.Server, dbName string) int {
+	result := callTool(t, server, "count_documents", map[string]interface{}{"db_name": dbName}).(map[string]interface{})
+	return result["count"].(int)
+}
+
+func TestMCPReingestShadowSwapsCollection(t *testing.T) {
+	server := newSeededServer(t, "old one", "old two", "old three")
+
+	result := callTool(t, server, "reingest", map[string]interface{}{
+		"db_name":    "docs",
+		"documents":  reingestDocuments("new", "newer"),
+		"shadow":     true,
+		"batch_size": float64(1),
+	}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", result["previous_collection"])
+	assert.Equal(t, "MaestroDocs_v2", result["collection"])
+	assert.Equal(t, 2, result["written"])
+	assert.Equal(t, 2, result["document_count"])
+	assert.NotContains(t, result, "status")
+
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs_v2", "pending": false}, schemaVersion(t, server, "docs"))
+	assert.Equal(t, 2, documentCount(t, server, "docs"))
+}
+
+func TestMCPReingestShadowFailureKeepsServingCollection(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			VectorDB: config.VectorDBConfig{
+				Milvus: config.MilvusConfig{Limits: config.DocumentLimitsConfig{MaxTextBytes: 10}},
+			},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_documents", map[string]interface{}{"db_name": "docs", "documents": reingestDocuments("a", "b", "c")})
+
+	// The third document is over the text limit, so the load fails part way
+	_, err := server.CallTool(context.Background(), "reingest", map[string]interface{}{
+		"db_name":    "docs",
+		"documents":  reingestDocuments("d", "e", "far too long for the limit"),
+		"shadow":     true,
+		"batch_size": float64(1),
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDocumentTooLarge)
+	assert.ErrorContains(t, err, "after writing 2 of 3 documents")
+
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs", "pending": false}, schemaVersion(t, server, "docs"))
+	assert.Equal(t, 3, documentCount(t, server, "docs"))
+}
+
+func TestMCPReingestKeepPreviousCanRollBack(t *testing.T) {
+	server := newSeededServer(t, "old one", "old two", "old three")
+
+	result := callTool(t, server, "reingest", map[string]interface{}{
+		"db_name":       "docs",
+		"documents":     reingestDocuments("new"),
+		"shadow":        true,
+		"keep_previous": true,
+	}).(map[string]interface{})
+	assert.Equal(t, "pending_confirmation", result["status"])
+	assert.Equal(t, 1, documentCount(t, server, "docs"))
+
+	// The previous collection is pending, so another shadow reingest waits
+	_, err := server.CallTool(context.Background(), "reingest", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("newest"),
+		"shadow":    true,
+	})
+	assert.ErrorIs(t, err, mcp.ErrMigrationInProgress)
+
+	callTool(t, server, "rollback_migration", map[string]interface{}{"db_name": "docs"})
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs", "pending": false}, schemaVersion(t, server, "docs"))
+	assert.Equal(t, 3, documentCount(t, server, "docs"))
+}
+
+func TestMCPReingestInPlace(t *testing.T) {
+	server := newSeededServer(t, "old one", "old two", "old three")
+
+	result := callTool(t, server, "reingest", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("new", "newer"),
+		"shadow":    false,
+	}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", result["collection"])
+	assert.Equal(t, 3, result["removed"])
+	assert.Equal(t, 2, result["written"])
+	assert.Equal(t, 2, documentCount(t, server, "docs"))
+
+	_, err := server.CallTool(context.Background(), "reingest", map[string]interface{}{
+		"db_name":       "docs",
+		"documents":     reingestDocuments("new"),
+		"shadow":        false,
+		"keep_previous": true,
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
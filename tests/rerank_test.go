@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reverseReranker scores candidates in reverse of their vector rank
+type reverseReranker struct {
+	candidates int
+}
+
+func (r *reverseReranker) Rerank(ctx context.Context, query string, results []vectordb.SearchResult) ([]vectordb.SearchResult, error) {
+	r.candidates = len(results)
+	reranked := make([]vectordb.SearchResult, len(results))
+	for i, result := range results {
+		result.Score = float64(i+1) / 10
+		reranked[len(results)-1-i] = result
+	}
+	return reranked, nil
+}
+
+func TestMCPSearchRerankFallsBackToVectorScores(t *testing.T) {
+	server := newSeededServer(t, "alpha", "beta", "gamma")
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "beta",
+		"limit":   float64(2),
+		"rerank":  true,
+	}).(map[string]interface{})
+
+	assert.Equal(t, false, result["reranked"])
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 2)
+	assert.Equal(t, "beta", results[0].Document.Text)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-9)
+}
+
+func TestMCPSearchRerankWithCustomReranker(t *testing.T) {
+	server := newSeededServer(t, "alpha", "beta", "gamma")
+	reranker := &reverseReranker{}
+	server.SetReranker(reranker)
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "greek",
+		"limit":   float64(3),
+		"rerank":  true,
+	}).(map[string]interface{})
+
+	assert.Equal(t, true, result["reranked"])
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 3)
+	assert.Equal(t, "gamma", results[0].Document.Text)
+	assert.InDelta(t, 0.3, results[0].Score, 1e-9)
+
+	// Without the flag the vector ranking is returned untouched
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "greek",
+	}).(map[string]interface{})
+	assert.Equal(t, false, result["reranked"])
+	assert.Equal(t, "alpha", result["results"].([]vectordb.SearchResult)[0].Document.Text)
+
+	answer := callTool(t, server, "query", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "greek",
+		"rerank":  true,
+	}).(string)
+	assert.Contains(t, answer, "1. gamma (Score: 0.30)")
+}
+
+func TestVectorDBHTTPReranker(t *testing.T) {
+	var received map[string]interface{}
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"results": [{"index": 0, "relevance_score": 0.2}, {"index": 1, "relevance_score": 0.8}]}`))
+	}))
+	defer endpoint.Close()
+
+	reranker := vectordb.NewHTTPReranker(config.RerankConfig{
+		URL:     endpoint.URL,
+		APIKey:  "secret",
+		Model:   "cross-encoder",
+		Timeout: time.Second,
+	})
+
+	results, err := reranker.Rerank(context.Background(), "which", []vectordb.SearchResult{
+		{Document: vectordb.Document{ID: "a", Text: "first"}, Score: 0.9},
+		{Document: vectordb.Document{ID: "b", Text: "second"}, Score: 0.8},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "b", results[0].Document.ID)
+	assert.InDelta(t, 0.8, results[0].Score, 1e-9)
+	assert.Equal(t, "which", received["query"])
+	assert.Equal(t, "cross-encoder", received["model"])
+	assert.Equal(t, []interface{}{"first", "second"}, received["documents"])
+}
+
+func TestVectorDBHTTPRerankerError(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer endpoint.Close()
+
+	reranker := vectordb.NewHTTPReranker(config.RerankConfig{URL: endpoint.URL, Timeout: time.Second})
+	_, err := reranker.Rerank(context.Background(), "q", []vectordb.SearchResult{{Document: vectordb.Document{Text: "x"}}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+}
+
+func TestConfigRerankValidation(t *testing.T) {
+	cfg := milvusConfig(config.MilvusTLSConfig{})
+	cfg.MCP.Rerank.URL = "http://reranker:8080/rerank"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rerank candidates")
+
+	cfg.MCP.Rerank.Candidates = 50
+	assert.NoError(t, cfg.Validate())
+}
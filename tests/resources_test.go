@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResourceServer returns a seeded server with resources enabled
+func newResourceServer(t *testing.T, texts ...string) *mcp.Server {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Resources:   config.ResourcesConfig{Enabled: true},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_documents", map[string]interface{}{"db_name": "docs", "documents": reingestDocuments(texts...)})
+	return server
+}
+
+// readResource posts a resources/read request and returns the status and decoded body
+func readResource(t *testing.T, server *mcp.Server, uri string) (int, map[string]interface{}) {
+	body, err := json.Marshal(map[string]interface{}{"uri": uri})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/resources/read", bytes.NewReader(body)))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return recorder.Code, response
+}
+
+func TestMCPResourcesListCollections(t *testing.T) {
+	server := newResourceServer(t, "first", "second")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/resources/list", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Resources []mcp.Resource `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Resources, 1)
+	assert.Equal(t, "vectordb://docs/MaestroDocs", response.Resources[0].URI)
+	assert.Equal(t, "docs/MaestroDocs", response.Resources[0].Name)
+	assert.Equal(t, "application/json", response.Resources[0].MimeType)
+}
+
+func TestMCPResourcesListDocuments(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Resources:   config.ResourcesConfig{Enabled: true, ListDocuments: true, MaxDocuments: 2},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_documents", map[string]interface{}{"db_name": "docs", "documents": reingestDocuments("a", "b", "c")})
+
+	resources, err := server.ListResources(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+	assert.Equal(t, "vectordb://docs/MaestroDocs", resources[0].URI)
+	for _, resource := range resources[1:] {
+		assert.Regexp(t, `^vectordb://docs/MaestroDocs/.+$`, resource.URI)
+	}
+}
+
+func TestMCPResourcesReadDocument(t *testing.T) {
+	server := newResourceServer(t, "first", "second")
+	docs := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})["documents"].([]vectordb.Document)
+	require.NotEmpty(t, docs)
+	uri := "vectordb://docs/MaestroDocs/" + docs[0].ID
+
+	status, response := readResource(t, server, uri)
+	require.Equal(t, http.StatusOK, status)
+	contents := response["contents"].([]interface{})
+	require.Len(t, contents, 1)
+	content := contents[0].(map[string]interface{})
+	assert.Equal(t, uri, content["uri"])
+	assert.Equal(t, "application/json", content["mimeType"])
+
+	var doc vectordb.Document
+	require.NoError(t, json.Unmarshal([]byte(content["text"].(string)), &doc))
+	assert.Equal(t, docs[0].ID, doc.ID)
+	assert.Equal(t, docs[0].Text, doc.Text)
+	assert.Empty(t, doc.Vector)
+}
+
+func TestMCPResourcesReadCollection(t *testing.T) {
+	server := newResourceServer(t, "first", "second")
+
+	contents, err := server.ReadResource(context.Background(), "vectordb://docs/MaestroDocs")
+	require.NoError(t, err)
+
+	var info map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &info))
+	assert.Equal(t, "MaestroDocs", info["name"])
+}
+
+func TestMCPResourcesReadErrors(t *testing.T) {
+	server := newResourceServer(t, "first")
+
+	tests := []struct {
+		uri    string
+		status int
+	}{
+		{"https://docs/MaestroDocs", http.StatusBadRequest},
+		{"vectordb://docs", http.StatusBadRequest},
+		{"vectordb://docs/MaestroDocs/a/b", http.StatusBadRequest},
+		{"vectordb://missing/MaestroDocs", http.StatusNotFound},
+		{"vectordb://docs/MaestroDocs/no-such-document", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			status, response := readResource(t, server, tt.uri)
+			assert.Equal(t, tt.status, status)
+			assert.NotEmpty(t, response["error"])
+		})
+	}
+}
+
+func TestMCPResourcesDisabled(t *testing.T) {
+	server := newSeededServer(t, "first")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/resources/list", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPRouteByWritesToRoutedCollection(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":  "content",
+		"db_type":  "mock",
+		"route_by": "metadata.type",
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "content"})
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "content",
+		"url":      "https://example.com/faq",
+		"text":     "How do I reset my password",
+		"metadata": map[string]interface{}{"type": "faq"},
+	})
+	callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "content",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/blog", "text": "Release notes for the spring update", "metadata": map[string]interface{}{"type": "blog"}},
+			map[string]interface{}{"url": "https://example.com/faq2", "text": "How do I change my email address", "metadata": map[string]interface{}{"type": "faq"}},
+			map[string]interface{}{"url": "https://example.com/misc", "text": "Unrouted document without a type"},
+		},
+	})
+
+	// Untyped documents stay in the instance's own collection
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "content"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "content",
+		"query":           "how do I",
+		"collection_name": "faq",
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, "faq", r.Document.Metadata["type"])
+	}
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "content",
+		"query":           "release notes",
+		"collection_name": "blog",
+	}).(map[string]interface{})
+	results = result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/blog", results[0].Document.URL)
+}
+
+func TestMCPRouteByRejectsInvalidRules(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+
+	_, err := server.Tools["create_vector_database"].Handler(context.Background(), map[string]interface{}{
+		"db_name":  "content",
+		"db_type":  "mock",
+		"route_by": "type",
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":  "content",
+		"db_type":  "mock",
+		"route_by": "metadata.type",
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "content"})
+
+	_, err = server.Tools["write_document"].Handler(context.Background(), map[string]interface{}{
+		"db_name":  "content",
+		"url":      "https://example.com/1",
+		"text":     "numeric type",
+		"metadata": map[string]interface{}{"type": float64(3)},
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
@@ -0,0 +1,281 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSeededServer returns a server with a set-up Milvus database named "docs"
+// holding the given texts
+func newSeededServer(t *testing.T, texts ...string) *mcp.Server {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	for i, text := range texts {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name": "docs",
+			"url":     fmt.Sprintf("https://example.com/%d", i),
+			"text":    text,
+		})
+	}
+	return server
+}
+
+func TestMCPSearchMinScore(t *testing.T) {
+	server := newSeededServer(t, "alpha document", "beta document", "gamma document")
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"limit":   float64(5),
+	}).(map[string]interface{})
+	assert.Equal(t, 3, result["count"])
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name":   "docs",
+		"query":     "alpha document",
+		"limit":     float64(5),
+		"min_score": 0.75,
+	}).(map[string]interface{})
+	require.Equal(t, 1, result["count"])
+
+	for _, r := range result["results"].([]vectordb.SearchResult) {
+		assert.GreaterOrEqual(t, r.Score, 0.75)
+	}
+}
+
+func TestMCPSearchRanksByTextSimilarity(t *testing.T) {
+	server := newSeededServer(t,
+		"the weather is sunny today",
+		"vector databases store embeddings",
+		"searching large vector databases with embeddings",
+	)
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "Vector databases and embeddings",
+		"limit":   float64(3),
+	}).(map[string]interface{})
+
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 3)
+	assert.Equal(t, "vector databases store embeddings", results[0].Document.Text)
+	assert.Equal(t, "searching large vector databases with embeddings", results[1].Document.Text)
+	assert.Equal(t, "the weather is sunny today", results[2].Document.Text)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	assert.Zero(t, results[2].Score)
+
+	// The limit keeps the best matches rather than the first inserted
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "sunny weather",
+		"limit":   float64(1),
+	}).(map[string]interface{})
+	results = result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "the weather is sunny today", results[0].Document.Text)
+}
+
+func TestMCPIncludeVectors(t *testing.T) {
+	server := newSeededServer(t)
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/vector",
+		"text":    "document with a vector",
+		"vector":  []interface{}{0.1, 0.2, 0.3},
+	})
+
+	list := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Nil(t, list["documents"].([]vectordb.Document)[0].Vector)
+
+	list = callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name":         "docs",
+		"include_vectors": true,
+	}).(map[string]interface{})
+	assert.Equal(t, []float64{0.1, 0.2, 0.3}, list["documents"].([]vectordb.Document)[0].Vector)
+
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "vector",
+	}).(map[string]interface{})
+	assert.Nil(t, search["results"].([]vectordb.SearchResult)[0].Document.Vector)
+
+	search = callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "docs",
+		"query":           "vector",
+		"include_vectors": true,
+	}).(map[string]interface{})
+	assert.Equal(t, []float64{0.1, 0.2, 0.3}, search["results"].([]vectordb.SearchResult)[0].Document.Vector)
+}
+
+func TestMockSearchRanksByProvidedVectors(t *testing.T) {
+	client := vectordb.NewMockMilvusClient()
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.CreateCollection(ctx, "docs", nil))
+	require.NoError(t, client.Insert(ctx, "docs", []vectordb.Document{
+		{ID: "east", Text: "east", Vector: []float64{1, 0}},
+		{ID: "north", Text: "north", Vector: []float64{0, 1}},
+		{ID: "northeast", Text: "northeast", Vector32: []float32{1, 1}},
+		{ID: "unembedded", Text: "no vector"},
+	}))
+
+	results, err := client.Search(vectordb.WithQueryVector(ctx, []float64{0, 2}), "docs", "", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 3, "documents without a vector are not searched")
+	assert.Equal(t, "north", results[0].Document.ID)
+	assert.Equal(t, "northeast", results[1].Document.ID)
+	assert.Equal(t, "east", results[2].Document.ID)
+	assert.InDelta(t, 1.0, results[0].Score, 1e-9)
+	assert.InDelta(t, 0.0, results[2].Score, 1e-9)
+}
+
+func TestMockSearchAppliesMetadataFilter(t *testing.T) {
+	client := vectordb.NewMockWeaviateClient()
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.CreateCollection(ctx, "Docs", nil))
+	require.NoError(t, client.Insert(ctx, "Docs", []vectordb.Document{
+		{ID: "1", Text: "release notes", Metadata: map[string]interface{}{"source": "wiki", "year": float64(2024)}},
+		{ID: "2", Text: "release notes", Metadata: map[string]interface{}{"source": "blog", "year": float64(2024)}},
+		{ID: "3", Text: "release notes", Metadata: map[string]interface{}{"source": "wiki", "year": float64(2023)}},
+	}))
+
+	filter, err := vectordb.ParseMetadataFilter(map[string]interface{}{"source": "wiki", "year": 2024})
+	require.NoError(t, err)
+	results, err := client.Search(vectordb.WithMetadataFilter(ctx, filter), "Docs", "release", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1", results[0].Document.ID)
+}
+
+func TestMCPSearchWithFilterAndVector(t *testing.T) {
+	server := newSeededServer(t)
+	for i, source := range []string{"wiki", "blog", "wiki"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      fmt.Sprintf("https://example.com/%s/%d", source, i),
+			"text":     fmt.Sprintf("%s document %d", source, i),
+			"metadata": map[string]interface{}{"source": source},
+			"vector":   []interface{}{float64(i), 1.0},
+		})
+	}
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"filter":  map[string]interface{}{"source": "wiki"},
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["count"])
+
+	result = callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"vector":  []interface{}{1.0, 0.0},
+		"filter":  map[string]interface{}{"source": "wiki"},
+		"limit":   float64(1),
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "wiki document 2", results[0].Document.Text)
+
+	_, err := server.Tools["search"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"query":   "document",
+		"filter":  map[string]interface{}{},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrInvalidFilter)
+
+	_, err = server.Tools["search"].Handler(context.Background(), map[string]interface{}{"db_name": "docs"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+func TestMCPQueryCacheKeysOnFilter(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			QueryCache:  config.QueryCacheConfig{Enabled: true, TTL: time.Minute, MaxSize: 10},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	for _, source := range []string{"wiki", "blog"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      "https://example.com/" + source,
+			"text":     source + " article",
+			"metadata": map[string]interface{}{"source": source},
+		})
+	}
+
+	wiki := callTool(t, server, "query", map[string]interface{}{
+		"db_name": "docs", "query": "article", "filter": map[string]interface{}{"source": "wiki"},
+	})
+	blog := callTool(t, server, "query", map[string]interface{}{
+		"db_name": "docs", "query": "article", "filter": map[string]interface{}{"source": "blog"},
+	})
+	assert.Contains(t, wiki, "wiki article")
+	assert.NotContains(t, wiki, "blog article")
+	assert.Contains(t, blog, "blog article")
+}
+
+func TestMCPSearchFilterStrategy(t *testing.T) {
+	server := newTestServer(t)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	for i := 0; i < 5; i++ {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "docs",
+			"url":      fmt.Sprintf("https://example.com/en/%d", i),
+			"text":     "vector search guide",
+			"metadata": map[string]interface{}{"lang": "en"},
+		})
+	}
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "docs",
+		"url":      "https://example.com/fr",
+		"text":     "notes de cuisine",
+		"metadata": map[string]interface{}{"lang": "fr"},
+	})
+
+	search := func(strategy string, limit int) []vectordb.SearchResult {
+		args := map[string]interface{}{
+			"db_name": "docs",
+			"query":   "vector search",
+			"limit":   float64(limit),
+			"filter":  map[string]interface{}{"lang": "fr"},
+			"fields":  []interface{}{"url"},
+		}
+		if strategy != "" {
+			args["filter_strategy"] = strategy
+		}
+		return callTool(t, server, "search", args).(map[string]interface{})["results"].([]vectordb.SearchResult)
+	}
+
+	// Pre-filtering, the default, finds the rare match
+	for _, strategy := range []string{"", "pre"} {
+		results := search(strategy, 1)
+		require.Len(t, results, 1, strategy)
+		assert.Equal(t, "https://example.com/fr", results[0].Document.URL)
+	}
+
+	// Post-filtering only sees the nearest candidates, so a rare match can be missed
+	assert.Empty(t, search("post", 1))
+
+	results := search("post", 2)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/fr", results[0].Document.URL)
+	assert.Nil(t, results[0].Document.Metadata, "the filter's metadata is not returned unless selected")
+
+	assert.Equal(t, http.StatusBadRequest, postToolCall(server,
+		`{"name": "search", "arguments": {"db_name": "docs", "query": "x", "filter_strategy": "sideways"}}`))
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listedIDs returns the IDs of the documents in a list_documents response
+func listedIDs(result map[string]interface{}) []string {
+	var ids []string
+	for _, doc := range result["documents"].([]vectordb.Document) {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+func TestMCPListDocumentsSnapshotIsStableUnderWrites(t *testing.T) {
+	server := newSeededServer(t, "one", "two", "three", "four", "five")
+
+	first := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name":  "docs",
+		"limit":    float64(2),
+		"snapshot": true,
+	}).(map[string]interface{})
+	snapshotID := first["snapshot_id"].(string)
+	require.NotEmpty(t, snapshotID)
+	assert.Equal(t, 5, first["total"])
+	assert.Equal(t, "strong", first["consistency_level"])
+	assert.Equal(t, true, first["has_more"])
+	seen := listedIDs(first)
+
+	// Deleting a listed document would shift a plain offset scan past an unread one
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": seen[0]})
+	callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://example.com/new", "text": "six"})
+
+	second := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name":     "docs",
+		"limit":       float64(2),
+		"offset":      float64(2),
+		"snapshot_id": snapshotID,
+	}).(map[string]interface{})
+	seen = append(seen, listedIDs(second)...)
+
+	// A document deleted after the snapshot is skipped and reported as missing
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": "doc_5"})
+	third := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name":     "docs",
+		"limit":       float64(2),
+		"offset":      float64(4),
+		"snapshot_id": snapshotID,
+	}).(map[string]interface{})
+	assert.Empty(t, listedIDs(third))
+	assert.Equal(t, 1, third["missing"])
+	assert.Equal(t, false, third["has_more"])
+
+	assert.Equal(t, []string{"doc_1", "doc_2", "doc_3", "doc_4"}, seen)
+}
+
+func TestMCPListDocumentsUnknownSnapshot(t *testing.T) {
+	server := newSeededServer(t, "one")
+
+	_, err := server.CallTool(context.Background(), "list_documents", map[string]interface{}{
+		"db_name":     "docs",
+		"snapshot_id": "missing",
+	})
+	assert.ErrorIs(t, err, mcp.ErrSnapshotExpired)
+	assert.Equal(t, http.StatusGone, postToolCall(server, `{"name": "list_documents", "arguments": {"db_name": "docs", "snapshot_id": "missing"}}`))
+
+	_, err = server.CallTool(context.Background(), "list_documents", map[string]interface{}{
+		"db_name":           "docs",
+		"snapshot":          true,
+		"consistency_level": "linearizable",
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+func TestMCPListDocumentsSnapshotMaxDocuments(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Snapshot:    config.SnapshotConfig{MaxDocuments: 2},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	for _, text := range []string{"one", "two", "three"} {
+		callTool(t, server, "write_document", map[string]interface{}{"db_name": "docs", "url": "https://example.com/" + text, "text": text})
+	}
+
+	_, err := server.CallTool(context.Background(), "list_documents", map[string]interface{}{
+		"db_name":  "docs",
+		"snapshot": true,
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.ErrorContains(t, err, "mcp.snapshot.max_documents")
+}
+
+func TestMCPExportDocumentsWithSnapshot(t *testing.T) {
+	server := newSeededServer(t, "one", "two", "three")
+
+	result := callTool(t, server, "export_documents", map[string]interface{}{
+		"db_name":           "docs",
+		"batch_size":        float64(2),
+		"snapshot":          true,
+		"consistency_level": "eventually",
+	}).(map[string]interface{})
+	assert.Equal(t, 3, result["count"])
+	assert.Equal(t, "eventually", result["consistency_level"])
+}
+
+func TestConfigSnapshotConsistencyLevel(t *testing.T) {
+	assert.NoError(t, config.ValidateConsistencyLevel("Bounded"))
+	assert.Error(t, config.ValidateConsistencyLevel("linearizable"))
+}
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSoftDeleteServer returns a server with soft delete enabled and a "docs"
+// database holding the given texts, along with their document IDs
+func newSoftDeleteServer(t *testing.T, texts ...string) (*mcp.Server, []string) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			SoftDelete:  config.SoftDeleteConfig{Enabled: true, PurgeAfter: time.Hour},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	ids := make([]string, 0, len(texts))
+	for i, text := range texts {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name": "docs",
+			"url":     "https://example.com/" + text,
+			"text":    text,
+		})
+		listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "offset": float64(i)}).(map[string]interface{})
+		ids = append(ids, listed["documents"].([]vectordb.Document)[0].ID)
+	}
+	return server, ids
+}
+
+func TestMCPSoftDeleteAndRestore(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "alpha", "beta")
+
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, listed["count"])
+
+	listed = callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "include_deleted": true}).(map[string]interface{})
+	require.Equal(t, 2, listed["count"])
+	tombstone := listed["documents"].([]vectordb.Document)[0]
+	assert.True(t, vectordb.IsTombstoned(tombstone))
+	_, hasTimestamp := vectordb.DeletedAt(tombstone)
+	assert.True(t, hasTimestamp)
+
+	searched := callTool(t, server, "search", map[string]interface{}{"db_name": "docs", "query": "alpha"}).(map[string]interface{})
+	assert.Equal(t, 1, searched["count"])
+
+	callTool(t, server, "restore_document", map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+	listed = callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 2, listed["count"])
+
+	_, err := server.Tools["restore_document"].Handler(context.Background(), map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not deleted")
+}
+
+func TestMCPSoftDeletedDocumentsAreNotCountedOrExported(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "alpha", "beta", "gamma")
+
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 2, count["count"])
+	count = callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs", "include_deleted": true}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+
+	exported := callTool(t, server, "export_documents", map[string]interface{}{"db_name": "docs", "batch_size": float64(1)}).(map[string]interface{})
+	assert.Equal(t, 2, exported["count"])
+	assert.NotContains(t, exported["data"], "alpha")
+	exported = callTool(t, server, "export_documents", map[string]interface{}{"db_name": "docs", "include_deleted": true}).(map[string]interface{})
+	assert.Equal(t, 3, exported["count"])
+
+	// Pages are cut after tombstones are skipped, so the first page is not empty
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "limit": float64(1)}).(map[string]interface{})
+	require.Equal(t, 1, listed["count"])
+	assert.Equal(t, "beta", listed["documents"].([]vectordb.Document)[0].Text)
+	assert.Equal(t, true, listed["has_more"])
+
+	searched := callTool(t, server, "search", map[string]interface{}{"db_name": "docs", "query": "alpha", "limit": float64(3)}).(map[string]interface{})
+	for _, result := range searched["results"].([]vectordb.SearchResult) {
+		assert.NotEqual(t, ids[0], result.Document.ID)
+	}
+}
+
+func TestMCPPurgeDeletedDocuments(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "alpha", "beta", "gamma")
+
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": ids[0]})
+	callTool(t, server, "delete_document", map[string]interface{}{"db_name": "docs", "document_id": ids[1], "hard": true})
+
+	// Tombstones younger than purge_after are kept
+	purged := callTool(t, server, "purge", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 0, purged["purged"])
+
+	purged = callTool(t, server, "purge", map[string]interface{}{"db_name": "docs", "older_than": "0s"}).(map[string]interface{})
+	assert.Equal(t, 1, purged["purged"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+}
+
+func TestMCPDeleteDocumentsReportsPartialFailure(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "alpha", "beta", "gamma")
+
+	result := callTool(t, server, "delete_documents", map[string]interface{}{
+		"db_name":      "docs",
+		"document_ids": []interface{}{ids[0], "missing", ids[2]},
+		"hard":         true,
+	}).(map[string]interface{})
+	assert.Equal(t, "partial", result["status"])
+	assert.Equal(t, "Deleted 2 of 3 documents", result["message"])
+	assert.Equal(t, []string{ids[0], ids[2]}, result["deleted"])
+	failed := result["failed"].([]vectordb.DeleteFailure)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "missing", failed[0].ID)
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "include_deleted": true}).(map[string]interface{})
+	assert.Equal(t, 1, listed["count"])
+}
+
+func TestMCPDeleteDocumentsSoftDeletes(t *testing.T) {
+	server, ids := newSoftDeleteServer(t, "alpha", "beta")
+
+	result := callTool(t, server, "delete_documents", map[string]interface{}{
+		"db_name":      "docs",
+		"document_ids": []interface{}{ids[0], ids[1]},
+	}).(map[string]interface{})
+	assert.Equal(t, "ok", result["status"])
+	assert.Equal(t, true, result["soft_deleted"])
+	assert.Empty(t, result["failed"])
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 0, listed["count"])
+	listed = callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "include_deleted": true}).(map[string]interface{})
+	assert.Equal(t, 2, listed["count"])
+}
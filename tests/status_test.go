@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+)
+
+// postToolCall sends a tool call over HTTP and returns the response status code
+func postToolCall(server *mcp.Server, body string) int {
+	request := httptest.NewRequest(http.MethodPost, "/mcp/tools/call", strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestMCPToolCallStatusCodes(t *testing.T) {
+	server := newSeededServer(t, "alpha")
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"success", `{"name": "count_documents", "arguments": {"db_name": "docs"}}`, http.StatusOK},
+		{"unknown tool", `{"name": "nope", "arguments": {}}`, http.StatusNotFound},
+		{"unknown database", `{"name": "count_documents", "arguments": {"db_name": "missing"}}`, http.StatusNotFound},
+		{"unknown document", `{"name": "delete_document", "arguments": {"db_name": "docs", "document_id": "missing"}}`, http.StatusNotFound},
+		{"missing argument", `{"name": "query", "arguments": {"db_name": "docs"}}`, http.StatusBadRequest},
+		{"invalid collection name", `{"name": "create_vector_database", "arguments": {"db_name": "w", "db_type": "weaviate", "collection_name": "lower"}}`, http.StatusBadRequest},
+		{"duplicate database", `{"name": "create_vector_database", "arguments": {"db_name": "docs", "db_type": "milvus"}}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, postToolCall(server, tt.body))
+		})
+	}
+}
+
+func TestMCPToolCallStatusCodesForBackendErrors(t *testing.T) {
+	server := newTestServer(t)
+
+	errs := map[string]error{
+		"unavailable": fmt.Errorf("failed to query: %w", vectordb.ErrBackendUnavailable),
+		"timeout":     fmt.Errorf("failed to query: %w", context.DeadlineExceeded),
+		"dimension":   fmt.Errorf("failed to write: %w", vectordb.ErrDimensionMismatch),
+		"unexpected":  fmt.Errorf("boom"),
+	}
+	for name, err := range errs {
+		assert.NoError(t, server.RegisterTool(mcp.Tool{
+			Name: name,
+			Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				return nil, err
+			},
+		}))
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, postToolCall(server, `{"name": "unavailable"}`))
+	assert.Equal(t, http.StatusGatewayTimeout, postToolCall(server, `{"name": "timeout"}`))
+	assert.Equal(t, http.StatusBadRequest, postToolCall(server, `{"name": "dimension"}`))
+	assert.Equal(t, http.StatusInternalServerError, postToolCall(server, `{"name": "unexpected"}`))
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMCPBulkToolsUseTheirOwnTimeout(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Timeouts:    map[string]time.Duration{"tool_call": time.Nanosecond},
+		},
+	})
+	// Set up through the handlers directly, which the middleware chain does not bound
+	_, err := server.Tools["create_vector_database"].Handler(context.Background(), map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	require.NoError(t, err)
+	_, err = server.Tools["setup_database"].Handler(context.Background(), map[string]interface{}{"db_name": "docs"})
+	require.NoError(t, err)
+
+	// count_documents has no category of its own, so tool_call bounds it and runs out at once
+	_, err = server.CallTool(context.Background(), "count_documents", map[string]interface{}{"db_name": "docs"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "the tool_call timeout of 1ns ran out")
+
+	// write_documents is bounded by write_bulk instead
+	result := callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("first", "second"),
+	})
+	assert.Equal(t, "ok", result.(map[string]interface{})["status"])
+
+	// and the single-document tools by write_single and delete_single
+	_, err = server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	require.NoError(t, err)
+	_, err = server.CallTool(context.Background(), "delete_document", map[string]interface{}{
+		"db_name":     "docs",
+		"document_id": listDocs(t, server)[0].ID,
+	})
+	require.NoError(t, err)
+}
+
+func TestMCPTimeoutErrorsNameTheirCategory(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Timeouts: map[string]time.Duration{
+				"write_single": time.Nanosecond,
+				"write_bulk":   time.Nanosecond,
+			},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "hello",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the write_single timeout of 1ns ran out; raise mcp.timeouts.write_single")
+
+	_, err = server.CallTool(context.Background(), "write_documents", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("first"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the write_bulk timeout of 1ns ran out")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestConfigRenamedTimeoutsCarryOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.yaml")
+	content := "mcp:\n  timeouts:\n    write: \"120s\"\n    delete: \"90s\"\n    delete_bulk: \"600s\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 120*time.Second, cfg.GetTimeout("write_bulk"))
+	assert.Equal(t, 60*time.Second, cfg.GetTimeout("write_single"), "write never covered single writes")
+	assert.Equal(t, 90*time.Second, cfg.GetTimeout("delete_single"))
+	assert.Equal(t, 600*time.Second, cfg.GetTimeout("delete_bulk"), "the new name wins when both are set")
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	_, err = mcp.NewServer(cfg, zap.New(core))
+	require.NoError(t, err)
+	assert.Equal(t, 2, logs.FilterMessage("Timeout category is deprecated; set its replacements instead").Len())
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBDocumentTimestamps(t *testing.T) {
+	db, err := vectordb.CreateVectorDatabase("mock", "docs", mockBackendConfig())
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	historical := time.Date(2019, 3, 14, 9, 26, 53, 0, time.UTC)
+	before := time.Now().Add(-time.Second)
+	_, err = db.WriteDocuments(ctx, []vectordb.Document{
+		{ID: "new", URL: "https://example.com/new", Text: "fresh"},
+		{ID: "old", URL: "https://example.com/old", Text: "archived", CreatedAt: historical},
+	})
+	require.NoError(t, err)
+
+	fresh, err := db.GetDocument(ctx, "new")
+	require.NoError(t, err)
+	assert.True(t, fresh.CreatedAt.After(before))
+	assert.Equal(t, fresh.CreatedAt, fresh.UpdatedAt)
+
+	archived, err := db.GetDocument(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, historical, archived.CreatedAt, "a caller-supplied creation time is kept")
+	assert.True(t, archived.UpdatedAt.After(before))
+
+	// Updates refresh updated_at and keep created_at
+	archived.Text = "revised"
+	archived.UpdatedAt = time.Time{}
+	require.NoError(t, db.UpdateDocument(ctx, archived))
+	revised, err := db.GetDocument(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, historical, revised.CreatedAt)
+	assert.False(t, revised.UpdatedAt.IsZero())
+
+	encoded, err := json.Marshal(revised)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"created_at":"2019-03-14T09:26:53Z"`)
+
+	encoded, err = json.Marshal(vectordb.Document{URL: "https://example.com/unsaved"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "created_at")
+}
+
+func TestMCPWriteDocumentCreatedAt(t *testing.T) {
+	server := newSeededServer(t)
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":    "docs",
+		"url":        "https://example.com/history",
+		"text":       "from the archive",
+		"created_at": "2020-01-02T03:04:05Z",
+	})
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{
+		"db_name": "docs",
+		"fields":  []interface{}{"url", "created_at"},
+	}).(map[string]interface{})
+	docs := listed["documents"].([]vectordb.Document)
+	require.Len(t, docs, 1)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), docs[0].CreatedAt)
+	assert.True(t, docs[0].UpdatedAt.IsZero(), "unselected fields are omitted")
+
+	text := callTool(t, server, "query", map[string]interface{}{"db_name": "docs", "query": "archive"}).(string)
+	assert.Contains(t, text, "[updated "+time.Now().UTC().Format(time.DateOnly)+"]")
+
+	_, err := server.Tools["write_document"].Handler(context.Background(), map[string]interface{}{
+		"db_name":    "docs",
+		"url":        "https://example.com/bad",
+		"text":       "bad",
+		"created_at": "yesterday",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "created_at must be an RFC 3339 time")
+}
+
+func TestMCPImportDocumentsCSVCreatedAt(t *testing.T) {
+	server := newSeededServer(t)
+
+	result := callTool(t, server, "import_documents", map[string]interface{}{
+		"db_name": "docs",
+		"format":  "csv",
+		"data": "url,text,published\n" +
+			"https://example.com/a,first,2021-06-01T00:00:00Z\n" +
+			"https://example.com/b,second,June 2021\n",
+		"columns": map[string]interface{}{"created_at": "published"},
+	}).(map[string]interface{})
+	assert.Equal(t, 1, result["imported"])
+	assert.Equal(t, 1, result["failed"])
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	docs := listed["documents"].([]vectordb.Document)
+	require.Len(t, docs, 1)
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), docs[0].CreatedAt)
+	assert.NotContains(t, docs[0].Metadata, "published", "the created_at column is not copied into metadata")
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listedTools returns the names served by /mcp/tools/list
+func listedTools(t *testing.T, server *mcp.Server) map[string]bool {
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/tools/list", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	names := make(map[string]bool, len(response.Tools))
+	for _, tool := range response.Tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+func TestConfigToolEnabled(t *testing.T) {
+	cfg := &config.Config{}
+	assert.True(t, cfg.ToolEnabled("cleanup"))
+
+	cfg.MCP.DisabledTools = []string{"cleanup"}
+	assert.False(t, cfg.ToolEnabled("cleanup"))
+	assert.True(t, cfg.ToolEnabled("query"))
+
+	cfg.MCP.EnabledTools = []string{"query", "cleanup"}
+	assert.True(t, cfg.ToolEnabled("query"))
+	assert.False(t, cfg.ToolEnabled("search"))
+	assert.False(t, cfg.ToolEnabled("cleanup"), "disabled_tools wins over enabled_tools")
+}
+
+func TestConfigToolFiltersFromEnvironment(t *testing.T) {
+	t.Setenv("MAESTRO_MCP_MCP_DISABLED_TOOLS", "cleanup,purge")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cleanup", "purge"}, cfg.MCP.DisabledTools)
+}
+
+func TestMCPDisabledToolsHiddenAndRejected(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.DisabledTools = []string{"cleanup", "delete_document"}
+	server := newTestServerWithConfig(t, cfg)
+
+	tools := listedTools(t, server)
+	assert.True(t, tools["query"])
+	assert.False(t, tools["cleanup"])
+	assert.False(t, tools["delete_document"])
+
+	_, err := server.CallTool(context.Background(), "cleanup", map[string]interface{}{"db_name": "docs"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, mcp.ErrToolDisabled))
+	assert.Contains(t, err.Error(), "tool disabled")
+
+	assert.Equal(t, http.StatusForbidden, postToolCall(server, `{"name": "cleanup", "arguments": {"db_name": "docs"}}`))
+	assert.Equal(t, http.StatusNotFound, postToolCall(server, `{"name": "nope", "arguments": {}}`))
+}
+
+func TestMCPEnabledToolsAllowList(t *testing.T) {
+	cfg := mockBackendConfig()
+	cfg.MCP.EnabledTools = []string{"list_databases", "search"}
+	server := newTestServerWithConfig(t, cfg)
+
+	assert.Equal(t, map[string]bool{"list_databases": true, "search": true}, listedTools(t, server))
+
+	_, err := server.CallTool(context.Background(), "list_databases", map[string]interface{}{})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, postToolCall(server, `{"name": "create_vector_database", "arguments": {"db_name": "docs", "db_type": "mock"}}`))
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPExportImportRoundTrip(t *testing.T) {
+	server := newSeededServer(t, "first", "second", "third")
+
+	exported := callTool(t, server, "export_documents", map[string]interface{}{
+		"db_name":    "docs",
+		"batch_size": float64(2),
+	}).(map[string]interface{})
+	assert.Equal(t, 3, exported["count"])
+
+	data := exported["data"].(string)
+	assert.Len(t, strings.Split(strings.TrimSpace(data), "\n"), 3)
+	assert.Contains(t, data, `"text":"second"`)
+
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "restore", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "restore"})
+
+	imported := callTool(t, server, "import_documents", map[string]interface{}{
+		"db_name": "restore",
+		"data":    data,
+	}).(map[string]interface{})
+	assert.Equal(t, 3, imported["imported"])
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "restore"}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+}
+
+func TestMCPImportDocumentsReportsRowErrors(t *testing.T) {
+	server := newSeededServer(t)
+
+	result := callTool(t, server, "import_documents", map[string]interface{}{
+		"db_name": "docs",
+		"data":    "{\"url\":\"a\",\"text\":\"ok\"}\nnot json\n{\"url\":\"b\"}\n",
+	}).(map[string]interface{})
+	assert.Equal(t, 1, result["imported"])
+	assert.Equal(t, 2, result["failed"])
+
+	errs := fmt.Sprintf("%v", result["errors"])
+	assert.Contains(t, errs, "{2 invalid JSON")
+	assert.Contains(t, errs, "{3 text must not be empty (set mcp.write_validation.allow_empty_text to store metadata-only records)}")
+}
+
+func TestMCPImportDocumentsCSV(t *testing.T) {
+	server := newSeededServer(t)
+
+	data := "link,body,author,year\n" +
+		"https://example.com/a,first,alice,2023\n" +
+		"https://example.com/b,,bob,2024\n" +
+		"https://example.com/c,third,carol,2025\n"
+
+	result := callTool(t, server, "import_documents", map[string]interface{}{
+		"db_name": "docs",
+		"format":  "csv",
+		"data":    data,
+		"columns": map[string]interface{}{
+			"url":      "link",
+			"text":     "body",
+			"metadata": []interface{}{"author"},
+		},
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["imported"])
+	assert.Equal(t, 1, result["failed"])
+	assert.Contains(t, fmt.Sprintf("%v", result["errors"]), "{3 text must not be empty (set mcp.write_validation.allow_empty_text to store metadata-only records)}")
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	docs := listed["documents"].([]vectordb.Document)
+	require.Len(t, docs, 2)
+	assert.Equal(t, map[string]interface{}{"author": "alice"}, docs[0].Metadata)
+
+	_, err := server.Tools["import_documents"].Handler(nil, map[string]interface{}{
+		"db_name": "docs",
+		"format":  "csv",
+		"data":    "url,content\nhttps://example.com,hello\n",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no text column 'text'")
+}
+
+func TestMCPExportEndpointStreamsJSONL(t *testing.T) {
+	server := newSeededServer(t, "first", "second")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/export?db_name=docs&batch_size=1", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(recorder.Body.String()), "\n"), 2)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/mcp/export?db_name=missing", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
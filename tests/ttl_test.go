@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorDBDeleteExpired(t *testing.T) {
+	db, err := vectordb.NewMilvusDatabase("docs", &config.Config{})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	now := time.Now()
+	_, err = db.WriteDocuments(ctx, []vectordb.Document{
+		vectordb.WithTTL(vectordb.Document{URL: "a", Text: "expired"}, time.Minute, now.Add(-time.Hour)),
+		vectordb.WithTTL(vectordb.Document{URL: "b", Text: "fresh"}, time.Hour, now),
+		{URL: "c", Text: "no ttl"},
+	})
+	require.NoError(t, err)
+
+	deleted, err := db.DeleteExpired(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	count, err := db.CountDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMCPTTLSweep(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			TTLSweep:    config.TTLSweepConfig{Enabled: true, Interval: 10 * time.Millisecond},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/news",
+		"text":    "short-lived news",
+		"ttl":     "1s",
+	})
+
+	listed := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	doc := listed["documents"].([]vectordb.Document)[0]
+	assert.Equal(t, int64(1), doc.Metadata[vectordb.MetadataTTL])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.RunTTLSweeper(ctx)
+
+	require.Eventually(t, func() bool {
+		count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+		return count["count"] == 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Contains(t, recorder.Body.String(), `"next_sweep"`)
+
+	_, err := server.Tools["write_document"].Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs", "url": "u", "text": "t", "ttl": "soon",
+	})
+	assert.Error(t, err)
+}
+
+func TestMCPTTLSweepCoversRoutedCollections(t *testing.T) {
+	server := newTestServerWithConfig(t, mockBackendConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{
+		"db_name":  "content",
+		"db_type":  "mock",
+		"route_by": "metadata.type",
+	})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "content"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "content",
+		"url":      "https://example.com/faq",
+		"text":     "short-lived answer",
+		"metadata": map[string]interface{}{"type": "faq"},
+		"ttl":      "1s",
+	})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "content",
+		"url":      "https://example.com/faq2",
+		"text":     "lasting answer",
+		"metadata": map[string]interface{}{"type": "faq"},
+	})
+
+	// The expired document lives in the routed faq collection, not the instance's own
+	deleted := 0
+	require.Eventually(t, func() bool {
+		n, err := server.SweepExpired(context.Background())
+		require.NoError(t, err)
+		deleted += n
+		return deleted == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name":         "content",
+		"query":           "answer",
+		"collection_name": "faq",
+	}).(map[string]interface{})
+	results := result["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/faq2", results[0].Document.URL)
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPWriteDocumentRejectsEmptyContent(t *testing.T) {
+	server := newSeededServer(t)
+	tool, _ := server.GetTool("write_document")
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"empty text", map[string]interface{}{"url": "https://example.com", "text": "  "}, "text must not be empty"},
+		{"empty url", map[string]interface{}{"url": "", "text": "content"}, "url must not be empty"},
+		{"empty vector", map[string]interface{}{"url": "https://example.com", "text": "content", "vector": []interface{}{}}, "vector must not be empty"},
+		{"zero vector", map[string]interface{}{"url": "https://example.com", "text": "content", "vector": []interface{}{0.0, 0.0}}, "vector must not be all zeros"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["db_name"] = "docs"
+			_, err := tool.Handler(context.Background(), tt.args)
+			assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestMCPWriteDocumentAllowsMetadataOnlyRecords(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout:     15 * time.Second,
+			WriteValidation: config.WriteValidationConfig{AllowEmptyText: true, AllowEmptyURL: true},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name":  "docs",
+		"url":      "",
+		"text":     "",
+		"metadata": map[string]interface{}{"sku": "A-1"},
+	})
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 1, count["count"])
+
+	// Vectors are still checked when provided
+	tool, _ := server.GetTool("write_document")
+	_, err := tool.Handler(context.Background(), map[string]interface{}{
+		"db_name": "docs",
+		"url":     "",
+		"text":    "",
+		"vector":  []interface{}{0.0},
+	})
+	assert.ErrorContains(t, err, "all zeros")
+}
+
+func TestMCPImportAndIngestFollowWriteValidation(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout:     15 * time.Second,
+			WriteValidation: config.WriteValidationConfig{AllowEmptyText: true, AllowEmptyURL: true},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+
+	// Metadata-only records are allowed, but empty and all-zero vectors are not
+	data := `{"metadata":{"sku":"A-1"}}
+{"url":"https://example.com/1","text":"zeros","vector":[0,0,0]}
+{"url":"https://example.com/2","text":"empty","vector":[]}
+`
+	result := callTool(t, server, "import_documents", map[string]interface{}{"db_name": "docs", "data": data}).(map[string]interface{})
+	assert.Equal(t, 1, result["imported"])
+	errs := fmt.Sprintf("%v", result["errors"])
+	assert.Contains(t, errs, "{2 vector must not be all zeros}")
+	assert.Contains(t, errs, "{3 vector must not be empty}")
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=docs", strings.NewReader(data)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"written":1`)
+	assert.Contains(t, recorder.Body.String(), `{"row":2,"error":"vector must not be all zeros"}`)
+	assert.Contains(t, recorder.Body.String(), `{"row":3,"error":"vector must not be empty"}`)
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 2, count["count"])
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vectorLength returns the L2 length of v
+func vectorLength(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+func TestVectorDBNormalizeVector(t *testing.T) {
+	normalized := vectordb.NormalizeVector([]float64{3, 4})
+	assert.InDeltaSlice(t, []float64{0.6, 0.8}, normalized, 1e-9)
+	assert.InDelta(t, 1.0, vectorLength(normalized), 1e-9)
+
+	assert.Equal(t, []float64{0, 0}, vectordb.NormalizeVector([]float64{0, 0}))
+}
+
+func TestVectorDBWriteNormalizesVectors(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			Embedding: config.EmbeddingConfig{
+				VectorSize: 3,
+				Metric:     config.MetricCosine,
+				Normalize:  true,
+			},
+		},
+	}
+	ctx := context.Background()
+
+	db, err := vectordb.NewMilvusDatabase("normalized", cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	original := []float64{1, 2, 2}
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:    "https://example.com/vector",
+		Text:   "vector document",
+		Vector: original,
+	})
+	require.NoError(t, err)
+
+	docs, err := db.ListDocuments(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.InDelta(t, 1.0, vectorLength(docs[0].Vector), 1e-9)
+	assert.Equal(t, []float64{1, 2, 2}, original, "the caller's vector is not modified")
+
+	// L2 collections keep raw vectors
+	cfg.MCP.Embedding.Metric = config.MetricL2
+	raw, err := vectordb.NewMilvusDatabase("raw", cfg)
+	require.NoError(t, err)
+	require.NoError(t, raw.Setup(ctx, "default"))
+	_, err = raw.WriteDocument(ctx, vectordb.Document{URL: "u", Text: "t", Vector: original})
+	require.NoError(t, err)
+
+	docs, err = raw.ListDocuments(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, original, docs[0].Vector)
+}
+
+func TestVectorDBWriteDocumentsNilMetadata(t *testing.T) {
+	ctx := context.Background()
+	for _, dbType := range []string{"milvus", "weaviate", "mock"} {
+		t.Run(dbType, func(t *testing.T) {
+			cfg := &config.Config{
+				MCP: config.MCPConfig{
+					VectorDB: config.VectorDBConfig{
+						AllowMock: true,
+						Weaviate:  config.WeaviateConfig{URL: "http://localhost:8080"},
+					},
+				},
+			}
+			db, err := vectordb.CreateVectorDatabase(dbType, "Docs", cfg)
+			require.NoError(t, err)
+			require.NoError(t, db.Setup(ctx, "default"))
+
+			stats, err := db.WriteDocuments(ctx, []vectordb.Document{{URL: "https://example.com/nil", Text: "no metadata"}})
+			require.NoError(t, err)
+			assert.Equal(t, 1, stats.DocumentsWritten)
+
+			docs, err := db.ListDocuments(ctx, 10, 0)
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			require.NotNil(t, docs[0].Metadata)
+			encoded, err := json.Marshal(docs[0].Metadata)
+			require.NoError(t, err)
+			assert.NotEqual(t, "null", string(encoded))
+		})
+	}
+}
+
+func TestVectorDBWriteFloat32Vectors(t *testing.T) {
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			Embedding: config.EmbeddingConfig{
+				VectorSize: 2,
+				Metric:     config.MetricCosine,
+				Normalize:  true,
+			},
+		},
+	}
+	ctx := context.Background()
+
+	db, err := vectordb.NewMilvusDatabase("float32", cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/float32",
+		Text:     "float32 document",
+		Vector32: []float32{3, 4},
+	})
+	require.NoError(t, err)
+
+	docs, err := db.ListDocuments(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, []float32{0.6, 0.8}, docs[0].Vector32)
+	assert.Nil(t, docs[0].Vector)
+
+	// Responses encode the float32 vector under the usual key
+	encoded, err := json.Marshal(docs[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"vector":[0.6`)
+
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/short",
+		Text:     "wrong dimension",
+		Vector32: []float32{1, 2, 3},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrDimensionMismatch)
+}
+
+func TestVectorDBFloat32Conversion(t *testing.T) {
+	assert.Equal(t, []float32{0.5, 1}, vectordb.Document{Vector: []float64{0.5, 1}}.Float32Vector())
+	assert.Equal(t, []float32{2}, vectordb.Document{Vector: []float64{1}, Vector32: []float32{2}}.Float32Vector())
+	assert.Nil(t, vectordb.ToFloat32(nil))
+	assert.Equal(t, []float32{0, 0}, vectordb.NormalizeVector32([]float32{0, 0}))
+}
+
+func TestMCPWriteDocumentAcceptsNumericVectorTypes(t *testing.T) {
+	server := newSeededServer(t)
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/mixed",
+		"text":    "mixed numbers",
+		"vector":  []interface{}{float32(0.5), 1, json.Number("0.25")},
+	})
+	require.NoError(t, err)
+
+	list := callTool(t, server, "list_documents", map[string]interface{}{"db_name": "docs", "include_vectors": true}).(map[string]interface{})
+	assert.Equal(t, []float64{0.5, 1, 0.25}, list["documents"].([]vectordb.Document)[0].Vector)
+}
+
+func TestVectorDBSetupRejectsOversizedVectors(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			Embedding: config.EmbeddingConfig{VectorSize: 100000, MaxVectorSize: 16384},
+		},
+	}
+
+	db, err := vectordb.NewMilvusDatabase("huge", cfg)
+	require.NoError(t, err)
+	err = db.Setup(ctx, "default")
+	assert.ErrorIs(t, err, vectordb.ErrVectorSizeTooLarge)
+	assert.ErrorContains(t, err, "100000 dimensions exceeds mcp.embedding.max_vector_size of 16384")
+
+	// Collections are checked with their own dimension
+	cfg.MCP.Embedding.VectorSize = 4
+	require.NoError(t, db.Setup(ctx, "default"))
+	err = db.CreateCollection(ctx, "wide", vectordb.CollectionOptions{VectorSize: 20000})
+	assert.ErrorIs(t, err, vectordb.ErrVectorSizeTooLarge)
+
+	// A zero limit disables the check
+	cfg.MCP.Embedding.MaxVectorSize = 0
+	assert.NoError(t, db.CreateCollection(ctx, "wide", vectordb.CollectionOptions{VectorSize: 20000}))
+}
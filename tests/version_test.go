@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigBuildInfoPrefersLinkTimeValues(t *testing.T) {
+	original := []string{config.Version, config.GitCommit, config.BuildTime}
+	t.Cleanup(func() {
+		config.Version, config.GitCommit, config.BuildTime = original[0], original[1], original[2]
+	})
+	config.Version, config.GitCommit, config.BuildTime = "1.2.3", "abc123", "2024-01-02T03:04:05Z"
+
+	info := (&config.Config{Version: "0.0.1"}).BuildInfo()
+	assert.Equal(t, "1.2.3", info.Version)
+	assert.Equal(t, "abc123", info.GitCommit)
+	assert.Equal(t, "2024-01-02T03:04:05Z", info.BuildTime)
+	assert.NotEmpty(t, info.GoVersion)
+}
+
+func TestConfigBuildInfoFallsBackToConfiguredVersion(t *testing.T) {
+	info := (&config.Config{Version: "0.0.1"}).BuildInfo()
+	assert.Equal(t, "0.0.1", info.Version)
+	assert.NotEmpty(t, info.GitCommit)
+	assert.NotEmpty(t, info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
+
+func TestMCPVersionEndpoint(t *testing.T) {
+	server := newTestServerWithConfig(t, &config.Config{Version: "0.0.1"})
+
+	request := httptest.NewRequest(http.MethodGet, "/version", nil)
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var info config.BuildInfo
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &info))
+	assert.Equal(t, "0.0.1", info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+
+	request = httptest.NewRequest(http.MethodPost, "/version", nil)
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestMCPServerInfoTool(t *testing.T) {
+	server := newTestServer(t)
+
+	result := callTool(t, server, "server_info", map[string]interface{}{})
+	info, ok := result.(config.BuildInfo)
+	require.True(t, ok)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaVersion returns the schema version get_collection_info reports for an instance
+func schemaVersion(t *testing.T, server *mcp.Server, dbName string) map[string]interface{} {
+	info := callTool(t, server, "get_collection_info", map[string]interface{}{"db_name": dbName}).(map[string]interface{})
+	require.Contains(t, info, "schema_version")
+	return map[string]interface{}{
+		"collection": info["collection"],
+		"pending":    info["migration_pending"],
+	}
+}
+
+func TestMCPMigrateCollectionRepointsInstance(t *testing.T) {
+	server := newSeededServer(t, "first document", "second document", "third document")
+
+	result := callTool(t, server, "migrate_collection", map[string]interface{}{
+		"db_name":     "docs",
+		"vector_size": float64(8),
+		"metric":      "ip",
+		"batch_size":  float64(2),
+	}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", result["previous_collection"])
+	assert.Equal(t, 1, result["previous_version"])
+	assert.Equal(t, "MaestroDocs_v2", result["collection"])
+	assert.Equal(t, 2, result["version"])
+	assert.Equal(t, 3, result["copied"])
+	assert.Equal(t, 3, result["document_count"])
+	assert.Equal(t, "pending_confirmation", result["status"])
+
+	// Queries are served from the new version right away
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs_v2", "pending": true}, schemaVersion(t, server, "docs"))
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+
+	// A second migration waits for the first to be confirmed
+	_, err := server.CallTool(context.Background(), "migrate_collection", map[string]interface{}{"db_name": "docs"})
+	assert.ErrorIs(t, err, mcp.ErrMigrationInProgress)
+
+	confirmed := callTool(t, server, "confirm_migration", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", confirmed["dropped_collection"])
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs_v2", "pending": false}, schemaVersion(t, server, "docs"))
+
+	// The next version replaces the suffix rather than stacking it
+	result = callTool(t, server, "migrate_collection", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs_v3", result["collection"])
+	assert.Equal(t, 3, result["version"])
+}
+
+func TestMCPRollbackMigration(t *testing.T) {
+	server := newSeededServer(t, "first document", "second document")
+
+	callTool(t, server, "migrate_collection", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/new",
+		"text":    "written after the migration",
+	})
+
+	result := callTool(t, server, "rollback_migration", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, "MaestroDocs", result["collection"])
+	assert.Equal(t, 1, result["version"])
+	assert.Equal(t, "MaestroDocs_v2", result["dropped_collection"])
+
+	// The instance is back on the original collection, without the later write
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs", "pending": false}, schemaVersion(t, server, "docs"))
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 2, count["count"])
+
+	_, err := server.CallTool(context.Background(), "confirm_migration", map[string]interface{}{"db_name": "docs"})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+}
+
+func TestMCPMigrateCollectionDimensionChange(t *testing.T) {
+	var calls [][]string
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        newFlakyEmbeddingEndpoint(t, &calls).URL,
+				VectorSize: 3,
+				Writes:     config.EmbeddingWritesConfig{Enabled: true, BatchSize: 2, MaxRetries: 1, RetryBackoff: time.Millisecond},
+				Fallback:   config.EmbeddingFallbackConfig{FailureThreshold: 100},
+			},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/vector",
+		"text":    "document with a short vector",
+		"vector":  []interface{}{0.1, 0.2, 0.3},
+	})
+
+	// The embedding model is switched to one with 4-dimensional vectors
+	cfg.MCP.Embedding.VectorSize = 4
+
+	// A failed copy leaves the instance on its current version
+	_, err := server.CallTool(context.Background(), "migrate_collection", map[string]interface{}{
+		"db_name":     "docs",
+		"vector_size": float64(4),
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "set reembed")
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs", "pending": false}, schemaVersion(t, server, "docs"))
+
+	result := callTool(t, server, "migrate_collection", map[string]interface{}{
+		"db_name":     "docs",
+		"vector_size": float64(4),
+		"reembed":     true,
+	}).(map[string]interface{})
+	assert.Equal(t, 1, result["copied"])
+	assert.Equal(t, 1, result["reembedded"])
+
+	info := callTool(t, server, "get_vector_dimension", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 4, info["dimension"])
+
+	// The copied document carries the new vector and is found by it
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	}).(map[string]interface{})
+	results := search["results"].([]vectordb.SearchResult)
+	require.Len(t, results, 1)
+	assert.Equal(t, "https://example.com/vector", results[0].Document.URL)
+	assert.Equal(t, 1.0, results[0].Score)
+}
+
+func TestMCPMigrateCollectionReembedFailure(t *testing.T) {
+	var calls [][]string
+	cfg := &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        newFlakyEmbeddingEndpoint(t, &calls).URL,
+				VectorSize: 3,
+				Writes:     config.EmbeddingWritesConfig{Enabled: true, BatchSize: 2, MaxRetries: 1, RetryBackoff: time.Millisecond},
+				Fallback:   config.EmbeddingFallbackConfig{FailureThreshold: 100},
+			},
+		},
+	}
+	server := newTestServerWithConfig(t, cfg)
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	callTool(t, server, "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/broken",
+		"text":    "broken document",
+		"vector":  []interface{}{0.1, 0.2, 0.3},
+	})
+	cfg.MCP.Embedding.VectorSize = 4
+
+	// A document that cannot be re-embedded fails the copy
+	_, err := server.CallTool(context.Background(), "migrate_collection", map[string]interface{}{
+		"db_name":     "docs",
+		"vector_size": float64(4),
+		"reembed":     true,
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, mcp.ErrEmbeddingUnavailable)
+	assert.Contains(t, err.Error(), "failed to re-embed document")
+	assert.Equal(t, map[string]interface{}{"collection": "MaestroDocs", "pending": false}, schemaVersion(t, server, "docs"))
+
+	// Without write embedding there is nothing to re-embed the text with
+	plain := newSeededServer(t, "first")
+	_, err = plain.CallTool(context.Background(), "migrate_collection", map[string]interface{}{
+		"db_name":     "docs",
+		"vector_size": float64(8),
+		"reembed":     true,
+	})
+	assert.ErrorIs(t, err, mcp.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "reembed requires mcp.embedding.writes")
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// waitForBackendsConfig declares one database. When unreachable is set its
+// probes get a deadline that has already passed, so every probe fails.
+func waitForBackendsConfig(unreachable bool) *config.Config {
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			Host: "localhost",
+			WaitForBackends: config.WaitForBackendsConfig{
+				Enabled:  true,
+				Timeout:  100 * time.Millisecond,
+				Interval: 10 * time.Millisecond,
+			},
+		},
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Databases: []config.DatabaseInstanceConfig{
+				{Name: "docs", Type: "milvus"},
+			},
+		},
+	}
+	if unreachable {
+		cfg.MCP.Timeouts = map[string]time.Duration{"health": -time.Second}
+	}
+	return cfg
+}
+
+func TestMCPWaitForBackendsReachable(t *testing.T) {
+	server := newTestServerWithConfig(t, waitForBackendsConfig(false))
+	assert.NoError(t, server.WaitForBackends(context.Background(), time.Second, 10*time.Millisecond))
+}
+
+func TestMCPWaitForBackendsTimesOut(t *testing.T) {
+	server := newTestServerWithConfig(t, waitForBackendsConfig(true))
+
+	start := time.Now()
+	err := server.WaitForBackends(context.Background(), 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vector databases not reachable")
+	assert.Contains(t, err.Error(), "docs")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestMCPWaitForBackendsFailsStartup(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	srv, err := server.New(waitForBackendsConfig(true), logger)
+	require.NoError(t, err)
+
+	// Start gives up before it listens, so it returns without a shutdown
+	err = srv.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "startup gate failed")
+}
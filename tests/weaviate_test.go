@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func weaviateConfig(weaviate config.WeaviateConfig) *config.Config {
+	return &config.Config{
+		MCP: config.MCPConfig{
+			VectorDB: config.VectorDBConfig{
+				Type:     "weaviate",
+				Weaviate: weaviate,
+			},
+		},
+	}
+}
+
+func TestVectorDBWeaviateAnonymousClient(t *testing.T) {
+	cfg := weaviateConfig(config.WeaviateConfig{URL: "http://localhost:8080"})
+
+	db, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	require.NoError(t, err)
+	assert.Empty(t, db.AuthHeaders())
+	assert.Equal(t, config.WeaviateAuthNone, cfg.MCP.VectorDB.Weaviate.ResolvedAuthType())
+}
+
+func TestVectorDBWeaviateAPIKeyClient(t *testing.T) {
+	cfg := weaviateConfig(config.WeaviateConfig{URL: "http://localhost:8080", APIKey: "secret"})
+
+	db, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-API-Key": "secret"}, db.AuthHeaders())
+}
+
+func TestVectorDBWeaviateBearerClient(t *testing.T) {
+	cfg := weaviateConfig(config.WeaviateConfig{
+		URL:         "http://localhost:8080",
+		AuthType:    "bearer",
+		BearerToken: "token",
+	})
+
+	db, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, db.AuthHeaders())
+}
+
+func TestVectorDBWeaviateIncompleteAuthConfig(t *testing.T) {
+	cfg := weaviateConfig(config.WeaviateConfig{URL: "http://localhost:8080", AuthType: "bearer"})
+
+	_, err := vectordb.NewWeaviateDatabase("Docs", cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bearer_token is required")
+
+	cfg.MCP.VectorDB.Weaviate.AuthType = "kerberos"
+	_, err = vectordb.NewWeaviateDatabase("Docs", cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported weaviate auth_type")
+}
+
+// weaviateSchemaConfig declares a metadata schema with a nested author object
+func weaviateSchemaConfig() *config.Config {
+	cfg := weaviateConfig(config.WeaviateConfig{
+		URL: "http://localhost:8080",
+		MetadataSchema: map[string]interface{}{
+			"source": "text",
+			"year":   "int",
+			"author": map[string]interface{}{
+				"name":        "text",
+				"affiliation": "text",
+			},
+		},
+	})
+	cfg.MCP.ToolTimeout = 15 * time.Second
+	return cfg
+}
+
+func TestVectorDBWeaviateMetadataSchemaNestedProperties(t *testing.T) {
+	ctx := context.Background()
+	db, err := vectordb.NewWeaviateDatabase("Docs", weaviateSchemaConfig())
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	info, err := db.GetCollectionInfo(ctx, "Docs")
+	require.NoError(t, err)
+
+	var metadata map[string]interface{}
+	for _, property := range info["schema"].(map[string]interface{})["properties"].([]map[string]interface{}) {
+		if property["name"] == "metadata" {
+			metadata = property
+		}
+	}
+	require.NotNil(t, metadata)
+	assert.Equal(t, []string{"object"}, metadata["dataType"])
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"name":     "author",
+			"dataType": []string{"object"},
+			"nestedProperties": []map[string]interface{}{
+				{"name": "affiliation", "dataType": []string{"text"}},
+				{"name": "name", "dataType": []string{"text"}},
+			},
+		},
+		{"name": "source", "dataType": []string{"text"}},
+		{"name": "year", "dataType": []string{"int"}},
+	}, metadata["nestedProperties"])
+}
+
+func TestVectorDBWeaviateMetadataSchemaRejectsMismatchedWrites(t *testing.T) {
+	ctx := context.Background()
+	db, err := vectordb.NewWeaviateDatabase("Docs", weaviateSchemaConfig())
+	require.NoError(t, err)
+	require.NoError(t, db.Setup(ctx, "default"))
+
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/1",
+		Text:     "paper",
+		Metadata: map[string]interface{}{"author": "Ada"},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrMetadataSchema)
+	assert.ErrorContains(t, err, "'author' must be an object")
+
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/1",
+		Text:     "paper",
+		Metadata: map[string]interface{}{"year": 2024.5, "extra": []interface{}{"undeclared", "fields", "are", "kept"}},
+	})
+	assert.ErrorIs(t, err, vectordb.ErrMetadataSchema)
+	assert.ErrorContains(t, err, "'year' must be of type int")
+
+	_, err = db.WriteDocument(ctx, vectordb.Document{
+		URL:      "https://example.com/1",
+		Text:     "paper",
+		Metadata: map[string]interface{}{"year": float64(2024), "extra": "undeclared fields are not checked"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestVectorDBWeaviateInvalidMetadataSchema(t *testing.T) {
+	for name, schema := range map[string]map[string]interface{}{
+		"unknown type": {"year": "integer"},
+		"empty object": {"author": map[string]interface{}{}},
+		"not a type":   {"year": 5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := vectordb.NewWeaviateDatabase("Docs", weaviateConfig(config.WeaviateConfig{MetadataSchema: schema}))
+			assert.ErrorContains(t, err, "invalid Weaviate metadata schema")
+		})
+	}
+}
+
+// weaviateURLProperty returns the url property of a collection's class definition
+func weaviateURLProperty(t *testing.T, db *vectordb.WeaviateDatabase) map[string]interface{} {
+	info, err := db.GetCollectionInfo(context.Background(), db.CollectionName())
+	require.NoError(t, err)
+
+	for _, property := range info["schema"].(map[string]interface{})["properties"].([]map[string]interface{}) {
+		if property["name"] == "url" {
+			return property
+		}
+	}
+	t.Fatal("url property not found")
+	return nil
+}
+
+func TestVectorDBWeaviateURLDataTypeFollowsDetectedVersion(t *testing.T) {
+	db, err := vectordb.NewWeaviateDatabase("Docs", weaviateConfig(config.WeaviateConfig{URL: "http://localhost:8080"}))
+	require.NoError(t, err)
+	assert.Empty(t, db.ServerVersion())
+
+	require.NoError(t, db.Setup(context.Background(), "default"))
+	assert.Equal(t, vectordb.MockWeaviateVersion, db.ServerVersion())
+
+	url := weaviateURLProperty(t, db)
+	assert.Equal(t, []string{"text"}, url["dataType"])
+	assert.Equal(t, "field", url["tokenization"])
+}
+
+func TestVectorDBWeaviateURLDataTypeFollowsPinnedVersion(t *testing.T) {
+	for version, dataType := range map[string]string{
+		"1.18.2":     "string",
+		"v1.19.0":    "text",
+		"1.24.0-rc1": "text",
+	} {
+		t.Run(version, func(t *testing.T) {
+			db, err := vectordb.NewWeaviateDatabase("Docs", weaviateConfig(config.WeaviateConfig{
+				URL:           "http://localhost:8080",
+				ServerVersion: version,
+			}))
+			require.NoError(t, err)
+			require.NoError(t, db.Setup(context.Background(), "default"))
+
+			assert.Equal(t, []string{dataType}, weaviateURLProperty(t, db)["dataType"])
+		})
+	}
+
+	_, err := vectordb.NewWeaviateDatabase("Docs", weaviateConfig(config.WeaviateConfig{ServerVersion: "latest"}))
+	assert.ErrorContains(t, err, "invalid Weaviate server_version")
+}
+
+func TestMockWeaviateClientMeta(t *testing.T) {
+	client := vectordb.NewMockWeaviateClient()
+	client.Version = "1.18.0"
+
+	meta, err := client.Meta(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.18.0", meta["version"])
+
+	require.NoError(t, client.Close())
+	_, err = client.Meta(context.Background())
+	assert.ErrorIs(t, err, vectordb.ErrDatabaseClosed)
+}
+
+func TestMCPWeaviateSearchFiltersOnNestedMetadata(t *testing.T) {
+	server := newTestServerWithConfig(t, weaviateSchemaConfig())
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "papers", "db_type": "weaviate", "collection_name": "Papers"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "papers"})
+
+	for i, author := range []string{"Ada", "Grace", "Ada"} {
+		callTool(t, server, "write_document", map[string]interface{}{
+			"db_name":  "papers",
+			"url":      fmt.Sprintf("https://example.com/%d", i),
+			"text":     "quantum paper",
+			"metadata": map[string]interface{}{"author": map[string]interface{}{"name": author}},
+		})
+	}
+
+	result := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "papers",
+		"query":   "quantum paper",
+		"limit":   float64(10),
+		"filter":  map[string]interface{}{"author.name": "Ada"},
+	}).(map[string]interface{})
+	require.Equal(t, 2, result["count"])
+	for _, r := range result["results"].([]vectordb.SearchResult) {
+		assert.Equal(t, "Ada", r.Document.Metadata["author"].(map[string]interface{})["name"])
+	}
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AI4quantum/maestro-mcp/src/pkg/config"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/mcp"
+	"github.com/AI4quantum/maestro-mcp/src/pkg/vectordb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyEmbeddingEndpoint returns an embeddings endpoint that fails the first
+// call for any input containing "flaky" and every call for input containing
+// "broken". It records the inputs of each call.
+func newFlakyEmbeddingEndpoint(t *testing.T, calls *[][]string) *httptest.Server {
+	var mutex sync.Mutex
+	flaked := false
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mutex.Lock()
+		*calls = append(*calls, req.Input)
+		fail := false
+		for _, text := range req.Input {
+			if strings.Contains(text, "broken") || (strings.Contains(text, "flaky") && !flaked) {
+				fail = true
+			}
+			if strings.Contains(text, "flaky") {
+				flaked = true
+			}
+		}
+		mutex.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": []float64{1, 0, 0, 0}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+	t.Cleanup(endpoint.Close)
+	return endpoint
+}
+
+// newWriteEmbeddingServer returns a server with a set-up docs database that
+// embeds writes against url in sub-batches of two, retrying once
+func newWriteEmbeddingServer(t *testing.T, url string) *mcp.Server {
+	server := newTestServerWithConfig(t, &config.Config{
+		MCP: config.MCPConfig{
+			ToolTimeout: 15 * time.Second,
+			Embedding: config.EmbeddingConfig{
+				Provider:   vectordb.EmbeddingProviderCustomLocal,
+				URL:        url,
+				VectorSize: 4,
+				Writes:     config.EmbeddingWritesConfig{Enabled: true, BatchSize: 2, MaxRetries: 1, RetryBackoff: time.Millisecond},
+				Fallback:   config.EmbeddingFallbackConfig{FailureThreshold: 100},
+			},
+		},
+	})
+	callTool(t, server, "create_vector_database", map[string]interface{}{"db_name": "docs", "db_type": "milvus"})
+	callTool(t, server, "setup_database", map[string]interface{}{"db_name": "docs"})
+	return server
+}
+
+func TestMCPWriteEmbeddingRetriesSubBatches(t *testing.T) {
+	var calls [][]string
+	server := newWriteEmbeddingServer(t, newFlakyEmbeddingEndpoint(t, &calls).URL)
+
+	result := callTool(t, server, "write_documents", map[string]interface{}{
+		"db_name": "docs",
+		"documents": []interface{}{
+			map[string]interface{}{"url": "https://example.com/1", "text": "first"},
+			map[string]interface{}{"url": "https://example.com/2", "text": "second"},
+			map[string]interface{}{"url": "https://example.com/3", "text": "flaky third"},
+			map[string]interface{}{"url": "https://example.com/4", "text": "fourth"},
+			map[string]interface{}{"url": "https://example.com/5", "text": "broken fifth"},
+			map[string]interface{}{"url": "https://example.com/6", "text": "sixth", "vector": []interface{}{0.0, 1.0, 0.0, 0.0}},
+		},
+	}).(map[string]interface{})
+
+	assert.Equal(t, "partial", result["status"])
+	stats := result["write_stats"].(vectordb.WriteStats)
+	assert.Equal(t, 5, stats.DocumentsWritten)
+	require.Len(t, stats.Errors, 1)
+	assert.Contains(t, stats.Errors[0], "https://example.com/5")
+
+	// The flaky sub-batch succeeded on its retry, the broken one used up its
+	// retries, and the document with a vector was never sent
+	assert.Equal(t, [][]string{
+		{"first", "second"},
+		{"flaky third", "fourth"},
+		{"flaky third", "fourth"},
+		{"broken fifth"},
+		{"broken fifth"},
+	}, calls)
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 5, count["count"])
+}
+
+func TestMCPWriteEmbeddingFailsSingleWrite(t *testing.T) {
+	var calls [][]string
+	server := newWriteEmbeddingServer(t, newFlakyEmbeddingEndpoint(t, &calls).URL)
+
+	_, err := server.CallTool(context.Background(), "write_document", map[string]interface{}{
+		"db_name": "docs",
+		"url":     "https://example.com/1",
+		"text":    "broken",
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, mcp.ErrEmbeddingUnavailable)
+	assert.Contains(t, err.Error(), "https://example.com/1")
+}
+
+func TestMCPImportDocumentsEmbedsAndChecksVectors(t *testing.T) {
+	var calls [][]string
+	server := newWriteEmbeddingServer(t, newFlakyEmbeddingEndpoint(t, &calls).URL)
+
+	data := `{"url":"https://example.com/1","text":"first"}
+{"url":"https://example.com/2","text":"second"}
+{"url":"https://example.com/3","text":"broken third"}
+{"url":"https://example.com/4","text":"short vector","vector":[1,0,0]}
+{"url":"https://example.com/5","text":"own vector","vector":[0,1,0,0]}
+`
+	result := callTool(t, server, "import_documents", map[string]interface{}{"db_name": "docs", "data": data}).(map[string]interface{})
+	assert.Equal(t, 3, result["imported"])
+	assert.Equal(t, 2, result["failed"])
+
+	errs := fmt.Sprintf("%v", result["errors"])
+	assert.Contains(t, errs, "{3 failed to embed")
+	assert.Contains(t, errs, "{4 vector dimension mismatch: vector has 3 dimensions but collection 'MaestroDocs' expects 4}")
+
+	// Only the rows without a vector were sent to the provider
+	assert.Equal(t, [][]string{{"first", "second"}, {"broken third"}, {"broken third"}}, calls)
+
+	count := callTool(t, server, "count_documents", map[string]interface{}{"db_name": "docs"}).(map[string]interface{})
+	assert.Equal(t, 3, count["count"])
+}
+
+func TestMCPIngestEmbedsAndChecksRows(t *testing.T) {
+	var calls [][]string
+	server := newWriteEmbeddingServer(t, newFlakyEmbeddingEndpoint(t, &calls).URL)
+
+	body := `{"url":"https://example.com/1","text":"first"}
+{"url":"https://example.com/2","text":"second"}
+{"url":"https://example.com/3","text":"broken third"}
+{"url":"https://example.com/4","text":"short vector","vector":[1,0,0]}
+`
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp/ingest?db_name=docs", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var ack ingestMessage
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &ack))
+	assert.Equal(t, 2, ack.Written)
+	require.Len(t, ack.Errors, 2)
+	assert.Equal(t, 3, ack.Errors[0].Row)
+	assert.Contains(t, ack.Errors[0].Error, "failed to embed")
+	assert.Equal(t, 4, ack.Errors[1].Row)
+	assert.Contains(t, ack.Errors[1].Error, "vector dimension mismatch")
+
+	// The ingested documents were written with their embeddings
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	}).(map[string]interface{})
+	assert.Len(t, search["results"], 2)
+}
+
+func TestMCPReingestEmbedsDocuments(t *testing.T) {
+	var calls [][]string
+	server := newWriteEmbeddingServer(t, newFlakyEmbeddingEndpoint(t, &calls).URL)
+
+	result := callTool(t, server, "reingest", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("first", "second"),
+		"shadow":    true,
+	}).(map[string]interface{})
+	assert.Equal(t, 2, result["written"])
+
+	search := callTool(t, server, "search", map[string]interface{}{
+		"db_name": "docs",
+		"vector":  []interface{}{1.0, 0.0, 0.0, 0.0},
+	}).(map[string]interface{})
+	assert.Len(t, search["results"], 2)
+
+	// A document that cannot be embedded fails the load and keeps the old collection
+	_, err := server.CallTool(context.Background(), "reingest", map[string]interface{}{
+		"db_name":   "docs",
+		"documents": reingestDocuments("third", "broken"),
+		"shadow":    true,
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to embed")
+	assert.Equal(t, 2, documentCount(t, server, "docs"))
+}